@@ -1,33 +1,22 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
-	"log"
-	"net/http"
-
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
+	"log"
+	"time"
 )
 
 // addCardCmd represents the addCard command
 var addCardCmd = &cobra.Command{
 	Use:   "add-card",
 	Short: "Add bank card info to goph-keeper.",
-	Long: `Add bank card info (bank name, card number, cv, password and metadata) to goph-keeper database for
+	Long: `Add bank card info (bank name, card number, cv, password, expiry month and metadata) to goph-keeper database for
 long-term storage. Only authorized users can use this command. Password and cv are stored in the database in the encrypted form.`,
-	Example: "goph-keeper  add-card --user user-name --bank alpha --number 1111222233334444 --cv 123 --password 1243",
+	Example: "goph-keeper  add-card --user user-name --bank alpha --number 1111222233334444 --cv 123 --password 1243 --expires 2028-05",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error occured while loading envs from file: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while processing envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		bank, _ := cmd.Flags().GetString("bank")
@@ -35,7 +24,7 @@ long-term storage. Only authorized users can use this command. Password and cv a
 		cv, _ := cmd.Flags().GetString("cv")
 		password, _ := cmd.Flags().GetString("password")
 		metadata, _ := cmd.Flags().GetString("metadata")
-
+		expires, _ := cmd.Flags().GetString("expires")
 		if bank == "" || userName == "" || number == "" || cv == "" || password == "" {
 			log.Fatalln("user name, bank name, card number, cv and password should not be empty")
 		}
@@ -45,31 +34,32 @@ long-term storage. Only authorized users can use this command. Password and cv a
 		if len(cv) != 3 {
 			log.Fatalln("the cv code of the plastic card must consist of 3 digits.")
 		}
-		requestCard := internal.Card{
-			UserName: userName,
+		fields, err := fieldFlags(cmd)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		card := client.Card{
 			BankName: &bank,
 			Number:   &number,
 			CV:       &cv,
 			Password: &password,
+			Fields:   fields,
+			Rotation: client.Rotation{RotationDays: rotationFlag(cmd)},
 		}
 		if metadata != "" {
-			requestCard.Metadata = &metadata
+			card.Metadata = &metadata
 		}
-		body, err := json.Marshal(requestCard)
-		if err != nil {
-			log.Fatalf(err.Error())
+		if expires != "" {
+			if _, err = time.Parse("2006-01", expires); err != nil {
+				log.Fatalln("the expiry month of the plastic card must be in YYYY-MM format.")
+			}
+			card.ExpiresOn = &expires
 		}
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/save/card", cfg.ApplicationHost, cfg.ApplicationPort))
+		saved, err := userClient(cfg, userName).SaveCard(context.Background(), card)
 		if err != nil {
-			log.Printf(err.Error())
-		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+			exitWithError(err)
 		}
-		fmt.Println(resp.String())
+		printJSON(saved)
 	},
 }
 
@@ -81,6 +71,9 @@ func init() {
 	addCardCmd.Flags().String("cv", "", "card cv")
 	addCardCmd.Flags().String("password", "", "card password")
 	addCardCmd.Flags().String("metadata", "", "metadata")
+	addCardCmd.Flags().String("expires", "", "last month of the card validity, YYYY-MM")
+	addRotationFlag(addCardCmd)
+	addFieldFlag(addCardCmd)
 	addCardCmd.MarkFlagRequired("user")
 	addCardCmd.MarkFlagRequired("bank")
 	addCardCmd.MarkFlagRequired("number")
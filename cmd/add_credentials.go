@@ -1,15 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
 	"log"
-	"net/http"
 )
 
 // addCredentialsCmd represents the add-credentials command
@@ -18,44 +13,33 @@ var addCredentialsCmd = &cobra.Command{
 	Short: "Add a pair of login/password to goph-keeper.",
 	Long: `Add a pair of login/password to goph-keeper database for
 long-term storage. Only authorized users can use this command. The password is stored in the database in encrypted form.`,
-	Example: "goph-keeper add-credentials --user <user-name> --login <user-login> --password <password to store> --metadata <some description>",
+	Example: "goph-keeper add-credentials --user <user-name> --login <user-login> --password <password to store> --metadata <some description> --field \"PIN=1234:hidden\"\ngoph-keeper add-credentials --user <user-name> --login <user-login> --generate --length 24 --exclude-ambiguous",
 
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		login, _ := cmd.Flags().GetString("login")
-		password, _ := cmd.Flags().GetString("password")
+		password := passwordFlag(cmd)
 		metadata, _ := cmd.Flags().GetString("metadata")
-		requestCredentials := internal.Credentials{
-			UserName: userName,
+		fields, err := fieldFlags(cmd)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		creds := client.Credentials{
 			Login:    &login,
 			Password: &password,
+			Fields:   fields,
+			Rotation: client.Rotation{RotationDays: rotationFlag(cmd)},
 		}
 		if metadata != "" {
-			requestCredentials.Metadata = &metadata
+			creds.Metadata = &metadata
 		}
-		body, err := json.Marshal(requestCredentials)
+		saved, err := userClient(cfg, userName).SaveCredentials(context.Background(), creds)
 		if err != nil {
-			log.Fatalf(err.Error())
-		}
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/save/credentials", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
-		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+			exitWithError(err)
 		}
-		fmt.Println(resp.String())
+		printJSON(saved)
 	},
 }
 
@@ -64,8 +48,11 @@ func init() {
 	addCredentialsCmd.Flags().String("user", "", "user name")
 	addCredentialsCmd.Flags().String("login", "", "user login")
 	addCredentialsCmd.Flags().String("password", "", "user password")
+	addCredentialsCmd.Flags().Bool("generate", false, "generate the password, see the generate command for the options")
+	addGenerateFlags(addCredentialsCmd)
 	addCredentialsCmd.Flags().String("metadata", "", "metadata")
+	addRotationFlag(addCredentialsCmd)
+	addFieldFlag(addCredentialsCmd)
 	addCredentialsCmd.MarkFlagRequired("user")
 	addCredentialsCmd.MarkFlagRequired("login")
-	addCredentialsCmd.MarkFlagRequired("password")
 }
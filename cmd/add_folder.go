@@ -0,0 +1,63 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// addFolderCmd represents the add-folder command
+var addFolderCmd = &cobra.Command{
+	Use:   "add-folder",
+	Short: "Add a folder for user's items to goph-keeper storage",
+	Long: `Add a folder for user's items to goph-keeper storage. Subfolders are separated with slashes,
+missing parent folders are created too`,
+	Example: "goph-keeper add-folder --user <user-name> --path work/servers",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		path, _ := cmd.Flags().GetString("path")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		folders, err := c.GetFolders(ctx)
+		if err != nil {
+			exitWithError(err)
+		}
+		existing := make(map[string]int64, len(folders))
+		for id, folderPath := range folderPaths(folders) {
+			existing[folderPath] = id
+		}
+
+		var created client.Folder
+		var parentID *int64
+		var names []string
+		for _, name := range strings.Split(strings.Trim(path, "/"), "/") {
+			names = append(names, name)
+			if id, ok := existing[strings.Join(names, "/")]; ok {
+				parentID = &id
+				continue
+			}
+			created, err = c.CreateFolder(ctx, client.Folder{Name: name, ParentID: parentID})
+			if err != nil {
+				exitWithError(err)
+			}
+			parentID = &created.ID
+		}
+		if created.ID == 0 {
+			exitWithError(&client.Error{Code: internal.ErrorCodeDuplicateItem, Message: fmt.Sprintf("folder %q already exists", path)})
+		}
+		printJSON(created)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addFolderCmd)
+	addFolderCmd.Flags().String("user", "", "user name")
+	addFolderCmd.Flags().String("path", "", "path of the folder")
+	addFolderCmd.MarkFlagRequired("user")
+	addFolderCmd.MarkFlagRequired("path")
+}
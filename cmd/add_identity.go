@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// addIdentityCmd represents the add-identity command
+var addIdentityCmd = &cobra.Command{
+	Use:   "add-identity",
+	Short: "Add an identity document to goph-keeper.",
+	Long: `Add an identity document (passport, ID card, driver's licence, tax ID) to goph-keeper database.
+The number of the document is stored in the database in encrypted form. Scans are the names
+of the files with the scanned pages, the files should be uploaded before.`,
+	Example: "goph-keeper add-identity --user <user-name> --name passport --type passport --number AB1234567 " +
+		"--full-name \"Arya Stark\" --country GB --issued 2020-05-01 --expires 2030-04-30 --scan passport.jpg",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		number, _ := cmd.Flags().GetString("number")
+		issued, _ := cmd.Flags().GetString("issued")
+		expires, _ := cmd.Flags().GetString("expires")
+		metadata, _ := cmd.Flags().GetString("metadata")
+		identity := client.Identity{Name: &name, Number: &number}
+		identity.DocumentType, _ = cmd.Flags().GetString("type")
+		identity.FullName, _ = cmd.Flags().GetString("full-name")
+		identity.Country, _ = cmd.Flags().GetString("country")
+		identity.Scans, _ = cmd.Flags().GetStringArray("scan")
+		if issued != "" {
+			identity.IssuedOn = &issued
+		}
+		if expires != "" {
+			identity.ExpiresOn = &expires
+		}
+		if metadata != "" {
+			identity.Metadata = &metadata
+		}
+		saved, err := userClient(cfg, userName).SaveIdentity(context.Background(), identity)
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(saved)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addIdentityCmd)
+	addIdentityCmd.Flags().String("user", "", "user name")
+	addIdentityCmd.Flags().String("name", "", "name of the document")
+	addIdentityCmd.Flags().String("type", client.DocumentTypeOther, "passport, id_card, drivers_licence, tax_id or other")
+	addIdentityCmd.Flags().String("number", "", "number of the document")
+	addIdentityCmd.Flags().String("full-name", "", "full name of the holder")
+	addIdentityCmd.Flags().String("country", "", "two-letter code of the issuing country")
+	addIdentityCmd.Flags().String("issued", "", "issue date, YYYY-MM-DD")
+	addIdentityCmd.Flags().String("expires", "", "expiry date, YYYY-MM-DD")
+	addIdentityCmd.Flags().StringArray("scan", nil, "name of the uploaded file with the scan, repeat the flag for several scans")
+	addIdentityCmd.Flags().String("metadata", "", "metadata")
+	addIdentityCmd.MarkFlagRequired("user")
+	addIdentityCmd.MarkFlagRequired("name")
+	addIdentityCmd.MarkFlagRequired("number")
+}
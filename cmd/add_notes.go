@@ -4,16 +4,10 @@ Copyright © 2023 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
-	"log"
-	"net/http"
-
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
+	"log"
 )
 
 // addNotesCmd represents the add-notes command
@@ -24,41 +18,29 @@ var addNotesCmd = &cobra.Command{
 Only authorized users can use this command. The note content is stored in the database in encrypted form.`,
 	Example: "goph-keeper add-note --user <user-name> --title <note title> --content <note content> --metadata <note metadata>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		title, _ := cmd.Flags().GetString("title")
 		content, _ := cmd.Flags().GetString("content")
 		metadata, _ := cmd.Flags().GetString("metadata")
-		requestNote := internal.Note{
-			UserName: userName,
-			Title:    &title,
-			Content:  &content,
+		fields, err := fieldFlags(cmd)
+		if err != nil {
+			log.Fatalln(err.Error())
 		}
-		if metadata != "" {
-			requestNote.Metadata = &metadata
+		note := client.Note{
+			Title:   &title,
+			Content: &content,
+			Fields:  fields,
 		}
-		body, err := json.Marshal(requestNote)
-		if err != nil {
-			log.Fatalf(err.Error())
+		if metadata != "" {
+			note.Metadata = &metadata
 		}
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/save/note", cfg.ApplicationHost, cfg.ApplicationPort))
+		saved, err := userClient(cfg, userName).SaveNote(context.Background(), note)
 		if err != nil {
-			log.Printf(err.Error())
-		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+			exitWithError(err)
 		}
-		fmt.Println(resp.String())
+		printJSON(saved)
 	},
 }
 
@@ -68,6 +50,7 @@ func init() {
 	addNotesCmd.Flags().String("title", "", "user login")
 	addNotesCmd.Flags().String("content", "", "user password")
 	addNotesCmd.Flags().String("metadata", "", "metadata")
+	addFieldFlag(addNotesCmd)
 	addNotesCmd.MarkFlagRequired("user")
 	addNotesCmd.MarkFlagRequired("title")
 	addNotesCmd.MarkFlagRequired("content")
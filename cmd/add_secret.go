@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"context"
+	"github.com/joho/godotenv"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+	"strings"
+)
+
+// addSecretCmd represents the add-secret command
+var addSecretCmd = &cobra.Command{
+	Use:   "add-secret",
+	Short: "Add a secret with key/value pairs to goph-keeper.",
+	Long: `Add a secret with key/value pairs (API tokens, environment of a deployment) to goph-keeper database.
+The pairs are given with --set or imported from a .env file, the keys are names of environment variables.
+The values are stored in the database in encrypted form, so the .env file can be deleted afterwards.`,
+	Example: "goph-keeper add-secret --user <user-name> --name deploy --set API_TOKEN=s3cr3t --set REGION=eu-west-1\n" +
+		"goph-keeper add-secret --user <user-name> --name staging --from-env-file .env.staging",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		pairs, _ := cmd.Flags().GetStringArray("set")
+		envFile, _ := cmd.Flags().GetString("from-env-file")
+		metadata, _ := cmd.Flags().GetString("metadata")
+		values := make(map[string]string)
+		if envFile != "" {
+			var err error
+			if values, err = godotenv.Read(envFile); err != nil {
+				log.Fatalf("error while reading %s: %s", envFile, err)
+			}
+		}
+		for _, pair := range pairs {
+			key, value, ok := strings.Cut(pair, "=")
+			if !ok || key == "" {
+				log.Fatalf("value %q should be KEY=VALUE", pair)
+			}
+			values[key] = value
+		}
+		if len(values) == 0 {
+			log.Fatalln("either --set or --from-env-file is required")
+		}
+		secret := client.Secret{Name: &name, Values: values}
+		if metadata != "" {
+			secret.Metadata = &metadata
+		}
+		saved, err := userClient(cfg, userName).SaveSecret(context.Background(), secret)
+		if err != nil {
+			exitWithError(err)
+		}
+		saved.Values = nil
+		printJSON(saved)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addSecretCmd)
+	addSecretCmd.Flags().String("user", "", "user name")
+	addSecretCmd.Flags().String("name", "", "name of the secret")
+	addSecretCmd.Flags().StringArray("set", nil, "pair KEY=VALUE, repeat the flag for several pairs")
+	addSecretCmd.Flags().String("from-env-file", "", ".env file to import the pairs from")
+	addSecretCmd.Flags().String("metadata", "", "metadata")
+	addSecretCmd.MarkFlagRequired("user")
+	addSecretCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal/sshkey"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+)
+
+// addSSHKeyCmd represents the add-ssh-key command
+var addSSHKeyCmd = &cobra.Command{
+	Use:   "add-ssh-key",
+	Short: "Add an SSH key to goph-keeper.",
+	Long: `Add an SSH key to goph-keeper database, either imported from the unencrypted private key file
+or generated like ssh-keygen does. The private key is stored in the database in encrypted form,
+the public key and the fingerprint are derived from it and are printed.`,
+	Example: "goph-keeper add-ssh-key --user <user-name> --name github --file ~/.ssh/id_ed25519\n" +
+		"goph-keeper add-ssh-key --user <user-name> --name deploy --generate rsa --bits 4096 --comment deploy@ci",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		file, _ := cmd.Flags().GetString("file")
+		keyType, _ := cmd.Flags().GetString("generate")
+		bits, _ := cmd.Flags().GetInt("bits")
+		comment, _ := cmd.Flags().GetString("comment")
+		metadata, _ := cmd.Flags().GetString("metadata")
+		if (file == "") == (keyType == "") {
+			log.Fatalln("either --file or --generate is required")
+		}
+		var privateKey string
+		if file != "" {
+			data, err := os.ReadFile(file)
+			if err != nil {
+				log.Fatalf("error while reading private key: %s", err)
+			}
+			privateKey = string(data)
+		} else {
+			var err error
+			if privateKey, err = sshkey.Generate(keyType, bits, comment); err != nil {
+				log.Fatalln(err.Error())
+			}
+		}
+		key := client.SSHKey{Name: &name, PrivateKey: &privateKey, Comment: comment}
+		if metadata != "" {
+			key.Metadata = &metadata
+		}
+		saved, err := userClient(cfg, userName).SaveSSHKey(context.Background(), key)
+		if err != nil {
+			exitWithError(err)
+		}
+		saved.PrivateKey = nil
+		printJSON(saved)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addSSHKeyCmd)
+	addSSHKeyCmd.Flags().String("user", "", "user name")
+	addSSHKeyCmd.Flags().String("name", "", "name of the key")
+	addSSHKeyCmd.Flags().String("file", "", "unencrypted private key file to import")
+	addSSHKeyCmd.Flags().String("generate", "", "generate a new key of the type, ed25519 or rsa")
+	addSSHKeyCmd.Flags().Int("bits", 0, "size of generated rsa keys, 3072 by default")
+	addSSHKeyCmd.Flags().String("comment", "", "comment of the public key")
+	addSSHKeyCmd.Flags().String("metadata", "", "metadata")
+	addSSHKeyCmd.MarkFlagRequired("user")
+	addSSHKeyCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// addTOTPCmd represents the add-totp command
+var addTOTPCmd = &cobra.Command{
+	Use:   "add-totp",
+	Short: "Add a TOTP authenticator to goph-keeper.",
+	Long: `Add a time-based one-time password authenticator (2FA seed) to goph-keeper database, either from
+the otpauth:// URI of the QR code or from the secret. The secret is stored in the database in encrypted form.
+The name defaults to the issuer or the account of the authenticator.`,
+	Example: "goph-keeper add-totp --user <user-name> --uri \"otpauth://totp/GitHub:octocat?secret=JBSWY3DPEHPK3PXP&issuer=GitHub\"\n" +
+		"goph-keeper add-totp --user <user-name> --name github --secret JBSWY3DPEHPK3PXP --digits 6 --period 30",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		uri, _ := cmd.Flags().GetString("uri")
+		secret, _ := cmd.Flags().GetString("secret")
+		metadata, _ := cmd.Flags().GetString("metadata")
+		if (uri == "") == (secret == "") {
+			log.Fatalln("either --uri or --secret is required")
+		}
+		totp := client.TOTP{URI: uri}
+		totp.Issuer, _ = cmd.Flags().GetString("issuer")
+		totp.Account, _ = cmd.Flags().GetString("account")
+		totp.Digits, _ = cmd.Flags().GetInt("digits")
+		totp.Period, _ = cmd.Flags().GetInt("period")
+		totp.Algorithm, _ = cmd.Flags().GetString("algorithm")
+		if name != "" {
+			totp.Name = &name
+		}
+		if secret != "" {
+			totp.Secret = &secret
+		}
+		if metadata != "" {
+			totp.Metadata = &metadata
+		}
+		saved, err := userClient(cfg, userName).SaveTOTP(context.Background(), totp)
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(saved)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(addTOTPCmd)
+	addTOTPCmd.Flags().String("user", "", "user name")
+	addTOTPCmd.Flags().String("name", "", "name of the authenticator")
+	addTOTPCmd.Flags().String("uri", "", "otpauth://totp/ URI to import")
+	addTOTPCmd.Flags().String("secret", "", "base32 encoded secret")
+	addTOTPCmd.Flags().String("issuer", "", "issuer of the secret")
+	addTOTPCmd.Flags().String("account", "", "account of the secret")
+	addTOTPCmd.Flags().Int("digits", 0, "number of digits of the codes, 6 by default")
+	addTOTPCmd.Flags().Int("period", 0, "period of the codes in seconds, 30 by default")
+	addTOTPCmd.Flags().String("algorithm", "", "SHA1 (default), SHA256 or SHA512")
+	addTOTPCmd.Flags().String("metadata", "", "metadata")
+	addTOTPCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Report password hygiene of credentials",
+	Long: `Report the credentials with weak passwords (zxcvbn strength score below --min-score), passwords reused
+across the credentials, passwords unchanged for --max-age-days, URL fields using plain http and, if the server
+has the breached passwords source, passwords which have appeared in known data breaches.
+The passwords are checked by the server and never printed. Only authorized users can use this command.`,
+	Example: "goph-keeper audit --user <user-name>\n" +
+		"goph-keeper audit --user <user-name> --max-age-days 180 --format text",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" && format != "text" {
+			log.Fatalf("unknown format %q, use json or text", format)
+		}
+		var opts client.HealthOptions
+		if cmd.Flags().Changed("min-score") {
+			minScore, _ := cmd.Flags().GetInt("min-score")
+			opts.MinScore = &minScore
+		}
+		opts.MaxAgeDays, _ = cmd.Flags().GetInt("max-age-days")
+		report, err := userClient(cfg, userName).GetHealthReport(context.Background(), opts)
+		if err != nil {
+			exitWithError(err)
+		}
+		if format == "json" {
+			printJSON(report)
+			return
+		}
+		breached := "not checked for breaches"
+		if report.BreachChecked {
+			breached = fmt.Sprintf("%d breached", report.Summary.Breached)
+		}
+		fmt.Printf("%d credentials checked: %d weak, %d reused, %d older than %d days, %d with plain http URLs, %s\n",
+			report.Total, report.Summary.Weak, report.Summary.Reused, report.Summary.Old, report.MaxAgeDays, report.Summary.InsecureURLs, breached)
+		if len(report.Credentials) == 0 {
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tLOGIN\tSCORE\tAGE\tISSUES\tDETAILS")
+		for _, item := range report.Credentials {
+			fmt.Fprintf(w, "%d\t%s\t%d\t%dd\t%s\t%s\n", item.ID, item.Login, item.Score, item.PasswordAgeDays,
+				strings.Join(item.Issues, ","), healthDetails(item))
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+	auditCmd.Flags().String("user", "", "user name")
+	auditCmd.Flags().Int("min-score", 3, "passwords with a lower zxcvbn strength score (0-4) are weak")
+	auditCmd.Flags().Int("max-age-days", 365, "passwords unchanged for the number of days are old")
+	auditCmd.Flags().String("format", "json", "output format, json or text")
+	auditCmd.MarkFlagRequired("user")
+}
+
+// healthDetails describes the reused and breached passwords and the insecure URLs of the credentials for the text output.
+func healthDetails(item client.CredentialsHealth) string {
+	var details []string
+	if len(item.ReusedWith) > 0 {
+		ids := make([]string, 0, len(item.ReusedWith))
+		for _, id := range item.ReusedWith {
+			ids = append(ids, strconv.FormatInt(id, 10))
+		}
+		details = append(details, "same password as "+strings.Join(ids, ","))
+	}
+	if item.BreachCount != nil && *item.BreachCount > 0 {
+		details = append(details, fmt.Sprintf("seen %d times in data breaches", *item.BreachCount))
+	}
+	details = append(details, item.InsecureURLs...)
+	return strings.Join(details, "; ")
+}
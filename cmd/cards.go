@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// cardsCmd represents the cards command
+var cardsCmd = &cobra.Command{
+	Use:   "cards",
+	Short: "Reports on bank cards.",
+	Long:  `Reports on the bank cards of the user, see add-card, get-cards and update-card for managing them.`,
+}
+
+// cardsExpiringCmd represents the cards expiring command
+var cardsExpiringCmd = &cobra.Command{
+	Use:   "expiring",
+	Short: "List expiring bank cards",
+	Long: `List the bank cards which won't be valid within the number of days, including the expired ones,
+ordered by the expiry month (--expires of add-card). A card is valid through its expiry month.
+Only the last digits of the card numbers are shown. Only authorized users can use this command.`,
+	Example: "goph-keeper cards expiring --user <user-name>\n" +
+		"goph-keeper cards expiring --user <user-name> --within 90 --format text",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		within, _ := cmd.Flags().GetInt("within")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" && format != "text" {
+			log.Fatalf("unknown format %q, use json or text", format)
+		}
+		cards, err := userClient(cfg, userName).GetExpiringCards(context.Background(), within)
+		if err != nil {
+			exitWithError(err)
+		}
+		if format == "json" {
+			printJSON(cards)
+			return
+		}
+		if len(cards) == 0 {
+			fmt.Println("no bank cards are expiring")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "ID\tBANK\tNUMBER\tVALID THROUGH\tSTATE")
+		for _, card := range cards {
+			state := "expiring"
+			if !card.ExpiresAt.After(time.Now()) {
+				state = "expired"
+			}
+			fmt.Fprintf(w, "%d\t%s\t*%s\t%s\t%s\n", card.ID, card.BankName, card.LastDigits, card.ExpiresOn, state)
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(cardsCmd)
+	cardsCmd.AddCommand(cardsExpiringCmd)
+	cardsExpiringCmd.Flags().String("user", "", "user name")
+	cardsExpiringCmd.Flags().Int("within", 30, "list the cards which won't be valid within the number of days")
+	cardsExpiringCmd.Flags().String("format", "json", "output format, json or text")
+	cardsExpiringCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/kelseyhightower/envconfig"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"io/fs"
+	"log"
+	"os"
+	"time"
+)
+
+// loadConfig reads the client configuration from the environment and .env file if there is one,
+// the variables of the environment take precedence.
+func loadConfig() internal.Params {
+	if err := godotenv.Load(".env"); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		log.Fatalf("error while getting envs: %s", err)
+	}
+	var cfg internal.Params
+	if err := envconfig.Process("", &cfg); err != nil {
+		log.Fatalf("error while loading envs: %s\n", err)
+	}
+	return cfg
+}
+
+// newClient creates the client of the configured server.
+func newClient(cfg internal.Params, opts ...client.Option) *client.Client {
+	return client.New(fmt.Sprintf("http://%s:%s", cfg.ApplicationHost, cfg.ApplicationPort), opts...)
+}
+
+// userClient creates the client with the saved session of the user, refreshed tokens are saved too.
+func userClient(cfg internal.Params, userName string) *client.Client {
+	s, err := loadSessions(cfg)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	token, ok := s.Tokens[userName]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "error: user %q is not logged in\n", userName)
+		fmt.Fprintln(os.Stderr, errorHints[internal.ErrorCodeUnauthorized])
+		os.Exit(exitUnauthorized)
+	}
+	return newClient(cfg,
+		client.WithToken(token),
+		client.WithTokenRefresh(5*time.Minute, func(token string) {
+			if err := saveSession(cfg, userName, token); err != nil {
+				log.Println(err.Error())
+			}
+		}),
+	)
+}
+
+// openSession saves the token of the client after login, so the following commands are authorized.
+func openSession(cfg internal.Params, userName string, c *client.Client) {
+	if err := saveSession(cfg, userName, c.Token()); err != nil {
+		log.Fatalln(err.Error())
+	}
+}
+
+// printJSON prints the items returned by the server.
+func printJSON(v any) {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	fmt.Println(string(data))
+}
@@ -0,0 +1,32 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// clipboardCommands are the clipboard tools of the platforms, the first one found in PATH is used.
+var clipboardCommands = map[string][][]string{
+	"darwin":  {{"pbcopy"}},
+	"windows": {{"clip"}},
+	"linux":   {{"wl-copy"}, {"xclip", "-selection", "clipboard"}, {"xsel", "--clipboard", "--input"}},
+}
+
+// copyToClipboard puts the text to the system clipboard with the clipboard tool of the platform.
+func copyToClipboard(text string) error {
+	for _, command := range clipboardCommands[runtime.GOOS] {
+		if _, err := exec.LookPath(command[0]); err != nil {
+			continue
+		}
+		copyCmd := exec.Command(command[0], command[1:]...)
+		copyCmd.Stdin = strings.NewReader(text)
+		if err := copyCmd.Run(); err != nil {
+			return fmt.Errorf("error while copying to clipboard: %w", err)
+		}
+		return nil
+	}
+	return errors.New("no clipboard tool found, install wl-clipboard, xclip or xsel")
+}
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// deleteAccountCmd represents the delete-account command
+var deleteAccountCmd = &cobra.Command{
+	Use:   "delete-account",
+	Short: "Delete the goph-keeper account with all stored data.",
+	Long: `Delete the goph-keeper account with all stored credentials, notes and cards. This can't be undone.
+The password is required, users with enabled two-factor authentication should also provide the code from the authenticator app.`,
+	Example: "goph-keeper delete-account --login <user-system-login> --password <user-system-password>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		login, _ := cmd.Flags().GetString("login")
+		password, _ := cmd.Flags().GetString("password")
+		code, _ := cmd.Flags().GetString("code")
+		if yes, _ := cmd.Flags().GetBool("yes"); !yes {
+			answer, err := prompt(fmt.Sprintf("All data of user %q will be deleted, type the login to confirm: ", login))
+			if err != nil {
+				log.Fatalln(err.Error())
+			}
+			if answer != login {
+				log.Fatalln("account deletion is cancelled")
+			}
+		}
+		err := newClient(cfg).DeleteAccount(context.Background(), client.AccountRequest{
+			Login:    login,
+			Password: password,
+			Code:     code,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+		if err = saveSession(cfg, login, ""); err != nil {
+			log.Println(err.Error())
+		}
+		fmt.Printf("account of user %q was deleted\n", login)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteAccountCmd)
+	deleteAccountCmd.Flags().String("login", "", "user login")
+	deleteAccountCmd.Flags().String("password", "", "user password")
+	deleteAccountCmd.Flags().String("code", "", "two-factor authentication code")
+	deleteAccountCmd.Flags().Bool("yes", false, "delete without confirmation")
+	deleteAccountCmd.MarkFlagRequired("login")
+	deleteAccountCmd.MarkFlagRequired("password")
+}
@@ -1,15 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
-	"log"
-	"net/http"
 )
 
 // deleteCredentialsCmd represents the deleteCredentials command
@@ -18,39 +13,25 @@ var deleteCredentialsCmd = &cobra.Command{
 	Short:   "Delete credentials for user from goph-keeper storage",
 	Example: "goph-keeper delete-credentials --user <user-name> --login <user-login>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		login, _ := cmd.Flags().GetString("login")
-		requestUserCredentials := internal.Credentials{
-			UserName: userName,
-		}
-		if login != "" {
-			requestUserCredentials.Login = &login
-		}
-		body, err := json.Marshal(requestUserCredentials)
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		creds, err := c.GetCredentials(ctx, client.CredentialsFilter{Login: login})
 		if err != nil {
-			log.Fatalln(err.Error())
+			exitWithError(err)
 		}
-		log.Println(string(body))
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/delete/credentials", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
+		if len(creds) == 0 {
+			exitNoItems("there are no credentials for user %q", userName)
 		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+		for _, item := range creds {
+			if err = c.DeleteCredentials(ctx, item.ID); err != nil {
+				exitWithError(err)
+			}
 		}
-		log.Printf(resp.String())
+		fmt.Printf("%d credentials of user %q were deleted\n", len(creds), userName)
 	},
 }
 
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// deleteFolderCmd represents the delete-folder command
+var deleteFolderCmd = &cobra.Command{
+	Use:     "delete-folder",
+	Short:   "Delete user's folder with its subfolders, the items of the folders are kept",
+	Example: "goph-keeper delete-folder --user <user-name> --path work/servers",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		path, _ := cmd.Flags().GetString("path")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		id, err := findFolder(ctx, c, path)
+		if err != nil {
+			exitNoItems("%s", err)
+		}
+		if err = c.DeleteFolder(ctx, id); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("folder %q of user %q was deleted\n", path, userName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteFolderCmd)
+	deleteFolderCmd.Flags().String("user", "", "user name")
+	deleteFolderCmd.Flags().String("path", "", "path of the folder")
+	deleteFolderCmd.MarkFlagRequired("user")
+	deleteFolderCmd.MarkFlagRequired("path")
+}
@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// deleteIdentityCmd represents the delete-identity command
+var deleteIdentityCmd = &cobra.Command{
+	Use:     "delete-identity",
+	Short:   "Delete user's identity document",
+	Long:    `Delete user's identity document, the files with its scans are kept.`,
+	Example: "goph-keeper delete-identity --user <user-name> --name passport",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		identities, err := c.GetIdentities(ctx, client.IdentitiesFilter{Name: name})
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(identities) == 0 {
+			exitNoItems("there is no identity document with name %q", name)
+		}
+		if err = c.DeleteIdentity(ctx, identities[0].ID); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("identity document %q of user %q was deleted\n", name, userName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteIdentityCmd)
+	deleteIdentityCmd.Flags().String("user", "", "user name")
+	deleteIdentityCmd.Flags().String("name", "", "name of the document")
+	deleteIdentityCmd.MarkFlagRequired("user")
+	deleteIdentityCmd.MarkFlagRequired("name")
+}
@@ -1,15 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
-	"log"
-	"net/http"
 )
 
 // deleteNotesCmd represents the deleteNotes command
@@ -18,38 +13,25 @@ var deleteNotesCmd = &cobra.Command{
 	Short:   "Delete user's notes from goph-keeper storage",
 	Example: "goph-keeper delete-note --user <user-name> --title <note title>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		title, _ := cmd.Flags().GetString("title")
-		requestNotes := internal.Note{
-			UserName: userName,
-		}
-		if title != "" {
-			requestNotes.Title = &title
-		}
-		body, err := json.Marshal(requestNotes)
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		notes, err := c.GetNotes(ctx, client.NotesFilter{Title: title})
 		if err != nil {
-			log.Fatalln(err.Error())
+			exitWithError(err)
 		}
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/delete/note", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
+		if len(notes) == 0 {
+			exitNoItems("there are no notes for user %q", userName)
 		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+		for _, note := range notes {
+			if err = c.DeleteNote(ctx, note.ID); err != nil {
+				exitWithError(err)
+			}
 		}
-		log.Printf(resp.String())
+		fmt.Printf("%d notes of user %q were deleted\n", len(notes), userName)
 	},
 }
 
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// deleteSecretCmd represents the delete-secret command
+var deleteSecretCmd = &cobra.Command{
+	Use:     "delete-secret",
+	Short:   "Delete user's secret",
+	Example: "goph-keeper delete-secret --user <user-name> --name deploy",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		secrets, err := c.GetSecrets(ctx, name)
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(secrets) == 0 {
+			exitNoItems("there is no secret with name %q", name)
+		}
+		if err = c.DeleteSecret(ctx, secrets[0].ID); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("secret %q of user %q was deleted\n", name, userName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteSecretCmd)
+	deleteSecretCmd.Flags().String("user", "", "user name")
+	deleteSecretCmd.Flags().String("name", "", "name of the secret")
+	deleteSecretCmd.MarkFlagRequired("user")
+	deleteSecretCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// deleteSSHKeyCmd represents the delete-ssh-key command
+var deleteSSHKeyCmd = &cobra.Command{
+	Use:     "delete-ssh-key",
+	Short:   "Delete user's SSH key",
+	Example: "goph-keeper delete-ssh-key --user <user-name> --name deploy",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		keys, err := c.GetSSHKeys(ctx, name)
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(keys) == 0 {
+			exitNoItems("there is no ssh key with name %q", name)
+		}
+		if err = c.DeleteSSHKey(ctx, keys[0].ID); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("ssh key %q of user %q was deleted\n", name, userName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteSSHKeyCmd)
+	deleteSSHKeyCmd.Flags().String("user", "", "user name")
+	deleteSSHKeyCmd.Flags().String("name", "", "name of the key")
+	deleteSSHKeyCmd.MarkFlagRequired("user")
+	deleteSSHKeyCmd.MarkFlagRequired("name")
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// deleteTOTPCmd represents the delete-totp command
+var deleteTOTPCmd = &cobra.Command{
+	Use:     "delete-totp",
+	Short:   "Delete user's TOTP authenticator",
+	Example: "goph-keeper delete-totp --user <user-name> --name github",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		authenticators, err := c.GetTOTP(ctx, name)
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(authenticators) == 0 {
+			exitNoItems("there is no authenticator with name %q", name)
+		}
+		if err = c.DeleteTOTP(ctx, authenticators[0].ID); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("authenticator %q of user %q was deleted\n", name, userName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(deleteTOTPCmd)
+	deleteTOTPCmd.Flags().String("user", "", "user name")
+	deleteTOTPCmd.Flags().String("name", "", "name of the authenticator")
+	deleteTOTPCmd.MarkFlagRequired("user")
+	deleteTOTPCmd.MarkFlagRequired("name")
+}
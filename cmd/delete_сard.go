@@ -1,15 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
-	"log"
-	"net/http"
-
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
 )
 
@@ -19,42 +13,26 @@ var deleteCardCmd = &cobra.Command{
 	Short:   "Delete card info from goph-keeper storage",
 	Example: "goph-keeper  delete-card --user user-name --bank alpha",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		bank, _ := cmd.Flags().GetString("bank")
 		number, _ := cmd.Flags().GetString("number")
-		requestCard := internal.Card{
-			UserName: userName,
-		}
-		if bank != "" {
-			requestCard.BankName = &bank
-		}
-		if number != "" {
-			requestCard.Number = &number
-		}
-		body, err := json.Marshal(requestCard)
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		cards, err := c.GetCards(ctx, client.CardsFilter{BankName: bank, Number: number})
 		if err != nil {
-			log.Fatalln(err.Error())
+			exitWithError(err)
 		}
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/delete/card", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
+		if len(cards) == 0 {
+			exitNoItems("there are no cards for user %q", userName)
 		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+		for _, card := range cards {
+			if err = c.DeleteCard(ctx, card.ID); err != nil {
+				exitWithError(err)
+			}
 		}
-		log.Printf(resp.String())
+		fmt.Printf("%d cards of user %q were deleted\n", len(cards), userName)
 	},
 }
 
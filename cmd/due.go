@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+)
+
+// dueCmd represents the due command
+var dueCmd = &cobra.Command{
+	Use:   "due",
+	Short: "List passwords due for rotation",
+	Long: `List the credentials and the bank cards with the passwords due for a change according to their
+rotation policies (--rotation-days of add-credentials, update-credentials and add-card), ordered by the due time.
+--within also lists the passwords which become due in the number of days.`,
+	Example: "goph-keeper due --user <user-name>\n" +
+		"goph-keeper due --user <user-name> --within 14 --format text",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		within, _ := cmd.Flags().GetInt("within")
+		format, _ := cmd.Flags().GetString("format")
+		if format != "json" && format != "text" {
+			log.Fatalf("unknown format %q, use json or text", format)
+		}
+		items, err := userClient(cfg, userName).GetDueItems(context.Background(), within)
+		if err != nil {
+			exitWithError(err)
+		}
+		if format == "json" {
+			printJSON(items)
+			return
+		}
+		if len(items) == 0 {
+			fmt.Println("no passwords are due for rotation")
+			return
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "TYPE\tID\tNAME\tCHANGED\tDUE")
+		for _, item := range items {
+			fmt.Fprintf(w, "%s\t%d\t%s\t%s\t%s\n", item.Type, item.ID, item.Name,
+				item.PasswordChangedAt.Local().Format(time.DateOnly), item.DueAt.Local().Format(time.DateOnly))
+		}
+		_ = w.Flush()
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(dueCmd)
+	dueCmd.Flags().String("user", "", "user name")
+	dueCmd.Flags().Int("within", 0, "also list the passwords which become due within the number of days")
+	dueCmd.Flags().String("format", "json", "output format, json or text")
+	dueCmd.MarkFlagRequired("user")
+}
+
+// addRotationFlag adds the flag with the rotation policy of the password.
+func addRotationFlag(cmd *cobra.Command) {
+	cmd.Flags().Int("rotation-days", 0, "remind to change the password after the number of days, 0 disables the reminders")
+}
+
+// rotationFlag returns the rotation days if the flag is set, otherwise nil.
+func rotationFlag(cmd *cobra.Command) *int {
+	if !cmd.Flags().Changed("rotation-days") {
+		return nil
+	}
+	days, _ := cmd.Flags().GetInt("rotation-days")
+	return &days
+}
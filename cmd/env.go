@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/joho/godotenv"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/spf13/cobra"
+	"log"
+	"sort"
+	"strings"
+)
+
+// envCmd represents the env command
+var envCmd = &cobra.Command{
+	Use:   "env <name>...",
+	Short: "Print the secrets as environment variables",
+	Long: `Print the key/value pairs of the secrets as export lines for the shell or as a .env file.
+The pairs of the later secrets override the earlier ones.`,
+	Example: "eval \"$(goph-keeper env deploy --user <user-name>)\"\n" +
+		"goph-keeper env deploy --user <user-name> --format dotenv > .env",
+	Args: cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		format, _ := cmd.Flags().GetString("format")
+		values := secretValues(cfg, userName, args)
+		switch format {
+		case "export":
+			keys := make([]string, 0, len(values))
+			for key := range values {
+				keys = append(keys, key)
+			}
+			sort.Strings(keys)
+			for _, key := range keys {
+				fmt.Printf("export %s=%s\n", key, shellQuote(values[key]))
+			}
+		case "dotenv":
+			data, err := godotenv.Marshal(values)
+			if err != nil {
+				log.Fatalln(err.Error())
+			}
+			fmt.Println(data)
+		default:
+			log.Fatalf("unknown format %q, use export or dotenv", format)
+		}
+	},
+}
+
+// secretValues returns the key/value pairs of the user's secrets merged in the order of the names.
+func secretValues(cfg internal.Params, userName string, names []string) map[string]string {
+	c := userClient(cfg, userName)
+	values := make(map[string]string)
+	for _, name := range names {
+		secrets, err := c.GetSecrets(context.Background(), name)
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(secrets) == 0 {
+			exitNoItems("there is no secret with name %q", name)
+		}
+		for key, value := range secrets[0].Values {
+			values[key] = value
+		}
+	}
+	return values
+}
+
+// shellQuote quotes the value for POSIX shells, single quotes inside are closed, escaped and reopened.
+func shellQuote(value string) string {
+	return "'" + strings.ReplaceAll(value, "'", `'\''`) + "'"
+}
+
+func init() {
+	rootCmd.AddCommand(envCmd)
+	envCmd.Flags().String("user", "", "user name")
+	envCmd.Flags().String("format", "export", "output format, export or dotenv")
+	envCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"os"
+)
+
+// Exit codes of the commands, scripts can rely on them to tell the failures apart.
+const (
+	// exitFailure is returned for client-side failures and unexpected responses.
+	exitFailure = 1
+	// exitInvalidRequest is returned when the server rejects the request data.
+	exitInvalidRequest = 2
+	// exitUnauthorized is returned for wrong credentials and expired or revoked sessions.
+	exitUnauthorized = 3
+	// exitNotFound is returned when there is no requested data.
+	exitNotFound = 4
+	// exitConflict is returned when the user or the item already exists.
+	exitConflict = 5
+	// exitTooManyRequests is returned when login attempts are throttled.
+	exitTooManyRequests = 6
+	// exitServerError is returned for internal server errors.
+	exitServerError = 7
+)
+
+// errorHints are shown after the server message to tell the user what to do next.
+var errorHints = map[string]string{
+	internal.ErrorCodeUnauthorized:    "login first: goph-keeper login --login <login> --password <password>",
+	internal.ErrorCodeInvalidToken:    "login again: goph-keeper login --login <login> --password <password>",
+	internal.ErrorCodeTokenExpired:    "your session has expired, login again: goph-keeper login --login <login> --password <password>",
+	internal.ErrorCodeSessionRevoked:  "you have logged in from another place or changed the password, login again",
+	internal.ErrorCodeWeakPassword:    "choose a longer password which is not based on your login or dictionary words",
+	internal.ErrorCodeDuplicateItem:   "use the update command to change the saved item",
+	internal.ErrorCodeTooManyRequests: "wait before the next attempt or ask the administrator to unlock the account",
+	internal.ErrorCodeInternal:        "something went wrong on the server, please report the request id",
+}
+
+// exitWithError prints the error and exits, errors returned by the server get the exit code matching their code.
+func exitWithError(err error) {
+	var apiErr *client.Error
+	if !errors.As(err, &apiErr) {
+		fmt.Fprintf(os.Stderr, "error: %s\n", err)
+		os.Exit(exitFailure)
+	}
+	fmt.Fprintf(os.Stderr, "error: %s\n", apiErr.Message)
+	if hint, ok := errorHints[apiErr.Code]; ok {
+		fmt.Fprintln(os.Stderr, hint)
+	}
+	if apiErr.RequestID != "" {
+		fmt.Fprintf(os.Stderr, "request id: %s\n", apiErr.RequestID)
+	}
+	os.Exit(exitCode(apiErr.Code))
+}
+
+// exitNoItems exits with the code of item_not_found error when no saved item matches the flags.
+func exitNoItems(format string, args ...any) {
+	exitWithError(&client.Error{Code: internal.ErrorCodeItemNotFound, Message: fmt.Sprintf(format, args...)})
+}
+
+// exitCode returns the exit code for the error code from the catalogue.
+func exitCode(code string) int {
+	switch code {
+	case internal.ErrorCodeInvalidRequest, internal.ErrorCodeWeakPassword:
+		return exitInvalidRequest
+	case internal.ErrorCodeInvalidCredentials,
+		internal.ErrorCodeInvalidSecondFactor,
+		internal.ErrorCodeInvalidRecoveryKey,
+		internal.ErrorCodePublicKeyLoginFailed,
+		internal.ErrorCodeUnauthorized,
+		internal.ErrorCodeInvalidToken,
+		internal.ErrorCodeTokenExpired,
+		internal.ErrorCodeSessionRevoked,
+		internal.ErrorCodeForbidden:
+		return exitUnauthorized
+	case internal.ErrorCodeItemNotFound:
+		return exitNotFound
+	case internal.ErrorCodeUserAlreadyExists,
+		internal.ErrorCodeDuplicateItem,
+		internal.ErrorCodeTwoFactorEnabled,
+		internal.ErrorCodeKeyAlreadyExists:
+		return exitConflict
+	case internal.ErrorCodeTooManyRequests:
+		return exitTooManyRequests
+	case internal.ErrorCodeInternal:
+		return exitServerError
+	}
+	return exitFailure
+}
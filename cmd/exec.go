@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"os/exec"
+	"os/signal"
+	"syscall"
+)
+
+// execCmd represents the exec command
+var execCmd = &cobra.Command{
+	Use:   "exec --secret <name> -- <command> [args...]",
+	Short: "Run a command with the secrets in its environment",
+	Long: `Run a command with the key/value pairs of the secrets injected as environment variables,
+so scripts don't need plaintext .env files. The pairs override the variables of the current environment
+and the pairs of the earlier secrets. Signals are passed to the command, its exit code is returned.`,
+	Example: "goph-keeper exec --user <user-name> --secret deploy -- ./deploy.sh production",
+	Args:    cobra.MinimumNArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		names, _ := cmd.Flags().GetStringArray("secret")
+		values := secretValues(cfg, userName, names)
+
+		child := exec.Command(args[0], args[1:]...)
+		child.Stdin, child.Stdout, child.Stderr = os.Stdin, os.Stdout, os.Stderr
+		child.Env = os.Environ()
+		for key, value := range values {
+			child.Env = append(child.Env, fmt.Sprintf("%s=%s", key, value))
+		}
+		if err := child.Start(); err != nil {
+			log.Fatalf("error while starting %s: %s", args[0], err)
+		}
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM, syscall.SIGHUP)
+		go func() {
+			for sig := range ch {
+				_ = child.Process.Signal(sig)
+			}
+		}()
+
+		err := child.Wait()
+		var exitErr *exec.ExitError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.ExitCode())
+		}
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(execCmd)
+	execCmd.Flags().String("user", "", "user name")
+	execCmd.Flags().StringArray("secret", nil, "name of the secret, repeat the flag for several secrets")
+	execCmd.MarkFlagRequired("user")
+	execCmd.MarkFlagRequired("secret")
+}
@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+	"strings"
+)
+
+// fieldTypes are the types accepted in the suffix of --field values.
+var fieldTypes = map[string]bool{
+	client.FieldTypeText:    true,
+	client.FieldTypeHidden:  true,
+	client.FieldTypeBoolean: true,
+	client.FieldTypeURL:     true,
+	client.FieldTypeDate:    true,
+}
+
+// textAttributes is the order of the item attributes in text output, the custom fields go after them.
+var textAttributes = []string{"id", "login", "password", "title", "content", "bank_name", "number", "cv", "metadata",
+	"tags", "folder_id", "favorite", "created_at", "updated_at", "rotation_days", "password_changed_at", "expires_on",
+	"breach_count"}
+
+// addFieldFlag adds the repeatable flag with the custom fields of the item.
+func addFieldFlag(cmd *cobra.Command) {
+	cmd.Flags().StringArray("field", nil, "custom field NAME=VALUE[:TYPE], TYPE is text (default), hidden, boolean, url or date; "+
+		"repeat the flag for several fields")
+}
+
+// fieldFlags returns the custom fields from --field flags, the type suffix is only cut when it's a known type,
+// so "site=https://example.com" is a text field.
+func fieldFlags(cmd *cobra.Command) ([]client.Field, error) {
+	values, _ := cmd.Flags().GetStringArray("field")
+	fields := make([]client.Field, 0, len(values))
+	for _, value := range values {
+		name, fieldValue, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("field %q should be NAME=VALUE[:TYPE]", value)
+		}
+		field := client.Field{Name: name, Type: client.FieldTypeText, Value: fieldValue}
+		if i := strings.LastIndex(fieldValue, ":"); i >= 0 && fieldTypes[fieldValue[i+1:]] {
+			field.Value, field.Type = fieldValue[:i], fieldValue[i+1:]
+		}
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+// addOutputFlags adds the flags choosing how the items are printed.
+func addOutputFlags(cmd *cobra.Command) {
+	cmd.Flags().String("format", "json", "output format, json or text")
+	cmd.Flags().Bool("reveal", false, "show the values of hidden fields in text format")
+}
+
+// printItems prints the items in the format chosen by the output flags. Text format shows an attribute per line
+// with the custom fields after them, values of hidden fields are masked unless --reveal is set.
+func printItems[T any](cmd *cobra.Command, items []T) {
+	format, _ := cmd.Flags().GetString("format")
+	reveal, _ := cmd.Flags().GetBool("reveal")
+	switch format {
+	case "json":
+		printJSON(items)
+		return
+	case "text":
+	default:
+		log.Fatalf("unknown format %q, use json or text", format)
+	}
+	for i, item := range items {
+		if i > 0 {
+			fmt.Println()
+		}
+		var fields struct {
+			Fields []client.Field `json:"fields"`
+		}
+		var attributes map[string]json.RawMessage
+		data, err := json.Marshal(item)
+		if err == nil {
+			err = json.Unmarshal(data, &attributes)
+		}
+		if err == nil {
+			err = json.Unmarshal(data, &fields)
+		}
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		for _, name := range textAttributes {
+			if value, ok := attributes[name]; ok {
+				fmt.Printf("%s: %s\n", name, textValue(value))
+			}
+		}
+		for _, field := range fields.Fields {
+			value := field.Value
+			if field.Type == client.FieldTypeHidden && !reveal {
+				value = "********"
+			}
+			fmt.Printf("%s (%s): %s\n", field.Name, field.Type, value)
+		}
+	}
+}
+
+// textValue prints strings without quotes and lists separated with commas.
+func textValue(value json.RawMessage) string {
+	var s string
+	if json.Unmarshal(value, &s) == nil {
+		return s
+	}
+	var list []string
+	if json.Unmarshal(value, &list) == nil {
+		return strings.Join(list, ", ")
+	}
+	return string(value)
+}
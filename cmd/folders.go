@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"strings"
+)
+
+// folderPaths returns the paths of the folders, the names of the parents are separated with slashes: "work/servers".
+func folderPaths(folders []client.Folder) map[int64]string {
+	byID := make(map[int64]client.Folder, len(folders))
+	for _, folder := range folders {
+		byID[folder.ID] = folder
+	}
+	paths := make(map[int64]string, len(folders))
+	for _, folder := range folders {
+		names := []string{folder.Name}
+		for parent := folder.ParentID; parent != nil; parent = byID[*parent].ParentID {
+			if _, ok := byID[*parent]; !ok {
+				break
+			}
+			names = append([]string{byID[*parent].Name}, names...)
+		}
+		paths[folder.ID] = strings.Join(names, "/")
+	}
+	return paths
+}
+
+// findFolder returns the id of the folder by its path.
+func findFolder(ctx context.Context, c *client.Client, path string) (int64, error) {
+	folders, err := c.GetFolders(ctx)
+	if err != nil {
+		return 0, err
+	}
+	path = strings.Trim(path, "/")
+	for id, folderPath := range folderPaths(folders) {
+		if folderPath == path {
+			return id, nil
+		}
+	}
+	return 0, fmt.Errorf("folder %q not found", path)
+}
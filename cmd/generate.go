@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// generateCmd represents the generate command
+var generateCmd = &cobra.Command{
+	Use:   "generate",
+	Short: "Generate a random password or a diceware passphrase.",
+	Long: `Generate a random password or a diceware passphrase with crypto/rand locally, no login is required.
+The password has all the character classes unless some of them are chosen. With --words the passphrase
+of the words from the embedded BIP39 English list is generated instead.`,
+	Example: "goph-keeper generate --length 24 --exclude-ambiguous\ngoph-keeper generate --words 6 --separator \" \"",
+	Run: func(cmd *cobra.Command, args []string) {
+		generated, err := password.Generate(generateOptions(cmd))
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		fmt.Println(generated)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(generateCmd)
+	addGenerateFlags(generateCmd)
+}
+
+// addGenerateFlags adds the flags describing the generated password.
+func addGenerateFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("length", password.DefaultGenerateLength, "number of characters of the generated password")
+	cmd.Flags().Bool("lower", false, "use lower case letters, all the classes are used if none is chosen")
+	cmd.Flags().Bool("upper", false, "use upper case letters")
+	cmd.Flags().Bool("digits", false, "use digits")
+	cmd.Flags().Bool("symbols", false, "use symbols")
+	cmd.Flags().Bool("exclude-ambiguous", false, "exclude similar looking characters like l, 1, O and 0")
+	cmd.Flags().Int("words", 0, "generate a passphrase of the number of words instead")
+	cmd.Flags().String("separator", password.DefaultSeparator, "separator of the passphrase words")
+}
+
+// generateOptions returns the options of the generated password from the flags.
+func generateOptions(cmd *cobra.Command) password.GenerateOptions {
+	var opts password.GenerateOptions
+	opts.Length, _ = cmd.Flags().GetInt("length")
+	opts.Lower, _ = cmd.Flags().GetBool("lower")
+	opts.Upper, _ = cmd.Flags().GetBool("upper")
+	opts.Digits, _ = cmd.Flags().GetBool("digits")
+	opts.Symbols, _ = cmd.Flags().GetBool("symbols")
+	opts.ExcludeAmbiguous, _ = cmd.Flags().GetBool("exclude-ambiguous")
+	opts.Words, _ = cmd.Flags().GetInt("words")
+	opts.Separator, _ = cmd.Flags().GetString("separator")
+	return opts
+}
+
+// passwordFlag returns --password or the password generated with --generate, one of them is required.
+func passwordFlag(cmd *cobra.Command) string {
+	value, _ := cmd.Flags().GetString("password")
+	generate, _ := cmd.Flags().GetBool("generate")
+	switch {
+	case generate && value != "":
+		log.Fatalln("--password and --generate can't be used together")
+	case generate:
+		generated, err := password.Generate(generateOptions(cmd))
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		return generated
+	case value == "":
+		log.Fatalln("--password or --generate is required")
+	}
+	return value
+}
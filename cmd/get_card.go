@@ -1,15 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
-	"log"
-	"net/http"
 )
 
 // getCardCmd represents the getCard command
@@ -18,43 +12,19 @@ var getCardCmd = &cobra.Command{
 	Short:   "Get card info from goph-keeper storage",
 	Example: "goph-keeper  get-card --user <user-name> --number <card number>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		bank, _ := cmd.Flags().GetString("bank")
 		number, _ := cmd.Flags().GetString("number")
-		requestCard := internal.Card{
-			UserName: userName,
-		}
-		if bank != "" {
-			requestCard.BankName = &bank
-		}
-		if number != "" {
-			requestCard.Number = &number
-		}
-		body, err := json.Marshal(requestCard)
+		c := userClient(cfg, userName)
+		cards, err := listItems(cmd, c, func(ctx context.Context, opts client.ListOptions) ([]client.Card, string, error) {
+			return c.ListCards(ctx, client.CardsFilter{BankName: bank, Number: number}, opts)
+		})
 		if err != nil {
-			log.Fatalln(err.Error())
-		}
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/get/card", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
-		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+			exitWithError(err)
 		}
-		log.Printf(resp.String())
+		printItems(cmd, cards)
 	},
 }
 
@@ -63,5 +33,7 @@ func init() {
 	getCardCmd.Flags().String("user", "", "user name")
 	getCardCmd.Flags().String("bank", "", "bank")
 	getCardCmd.Flags().String("number", "", "number")
+	addListFlags(getCardCmd)
+	addOutputFlags(getCardCmd)
 	getCardCmd.MarkFlagRequired("user")
 }
@@ -1,15 +1,9 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
-	"log"
-	"net/http"
 )
 
 // getCredentialsCmd represents the get-credentials command
@@ -17,42 +11,25 @@ var getCredentialsCmd = &cobra.Command{
 	Use:   "get-credentials",
 	Short: "Get a pair of login/password for specified user",
 	Long: `Get a pair of login/password for specified user from goph-keeper storage. 
+With --breach-check the server also reports how many times each password has appeared in known data breaches
+(breach_count), only SHA-1 hash prefixes of the passwords are sent to the breached passwords API.
 Only authorized users can use this command`,
-	Example: "goph-keeper get-credentials --user user_name",
+	Example: "goph-keeper get-credentials --user user_name\n" +
+		"goph-keeper get-credentials --user user_name --breach-check",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		userLogin, _ := cmd.Flags().GetString("login")
-		requestUserCredentials := internal.Credentials{
-			UserName: userName,
-		}
-		if userLogin != "" {
-			requestUserCredentials.Login = &userLogin
-		}
-		body, err := json.Marshal(requestUserCredentials)
+		breachCheck, _ := cmd.Flags().GetBool("breach-check")
+		c := userClient(cfg, userName)
+		creds, err := listItems(cmd, c, func(ctx context.Context, opts client.ListOptions) ([]client.Credentials, string, error) {
+			return c.ListCredentials(ctx, client.CredentialsFilter{Login: userLogin, BreachCheck: breachCheck}, opts)
+		})
 		if err != nil {
-			log.Fatalln(err.Error())
-		}
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/get/credentials", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
-		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+			exitWithError(err)
 		}
-		log.Printf(resp.String())
+		printItems(cmd, creds)
 	},
 }
 
@@ -60,5 +37,8 @@ func init() {
 	rootCmd.AddCommand(getCredentialsCmd)
 	getCredentialsCmd.Flags().String("user", "", "user name")
 	getCredentialsCmd.Flags().String("login", "", "user login")
+	getCredentialsCmd.Flags().Bool("breach-check", false, "check the passwords against known data breaches")
+	addListFlags(getCredentialsCmd)
+	addOutputFlags(getCredentialsCmd)
 	getCredentialsCmd.MarkFlagRequired("user")
 }
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// folderView is the folder printed with its path.
+type folderView struct {
+	client.Folder
+	Path string `json:"path"`
+}
+
+// getFoldersCmd represents the get-folders command
+var getFoldersCmd = &cobra.Command{
+	Use:     "get-folders",
+	Short:   "Get user's folders from goph-keeper storage",
+	Example: "goph-keeper get-folders --user <user-name>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		folders, err := userClient(cfg, userName).GetFolders(context.Background())
+		if err != nil {
+			exitWithError(err)
+		}
+		paths := folderPaths(folders)
+		views := make([]folderView, 0, len(folders))
+		for _, folder := range folders {
+			views = append(views, folderView{Folder: folder, Path: paths[folder.ID]})
+		}
+		printJSON(views)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getFoldersCmd)
+	getFoldersCmd.Flags().String("user", "", "user name")
+	getFoldersCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+	"time"
+)
+
+// getIdentitiesCmd represents the get-identities command
+var getIdentitiesCmd = &cobra.Command{
+	Use:   "get-identities",
+	Short: "Get identity documents of the user",
+	Long: `Get identity documents of the user with their numbers. --expires-before or --expiring-within
+select the documents which expire before the date or within the number of days, ordered by the expiry date.`,
+	Example: "goph-keeper get-identities --user <user-name> --type passport\n" +
+		"goph-keeper get-identities --user <user-name> --expiring-within 90",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		days, _ := cmd.Flags().GetInt("expiring-within")
+		var filter client.IdentitiesFilter
+		filter.Name, _ = cmd.Flags().GetString("name")
+		filter.DocumentType, _ = cmd.Flags().GetString("type")
+		filter.ExpiresBefore, _ = cmd.Flags().GetString("expires-before")
+		if days > 0 {
+			if filter.ExpiresBefore != "" {
+				log.Fatalln("either --expires-before or --expiring-within can be set")
+			}
+			filter.ExpiresBefore = time.Now().AddDate(0, 0, days+1).Format(time.DateOnly)
+		}
+		identities, err := userClient(cfg, userName).GetIdentities(context.Background(), filter)
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(identities)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getIdentitiesCmd)
+	getIdentitiesCmd.Flags().String("user", "", "user name")
+	getIdentitiesCmd.Flags().String("name", "", "name of the document")
+	getIdentitiesCmd.Flags().String("type", "", "type of the documents")
+	getIdentitiesCmd.Flags().String("expires-before", "", "only documents expiring before the date, YYYY-MM-DD")
+	getIdentitiesCmd.Flags().Int("expiring-within", 0, "only documents expiring within the number of days")
+	getIdentitiesCmd.MarkFlagRequired("user")
+}
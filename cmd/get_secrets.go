@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+)
+
+// getSecretsCmd represents the get-secrets command
+var getSecretsCmd = &cobra.Command{
+	Use:     "get-secrets",
+	Short:   "Get secrets of the user with their key/value pairs",
+	Example: "goph-keeper get-secrets --user <user-name> --name deploy",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		secrets, err := userClient(cfg, userName).GetSecrets(context.Background(), name)
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(secrets)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getSecretsCmd)
+	getSecretsCmd.Flags().String("user", "", "user name")
+	getSecretsCmd.Flags().String("name", "", "name of the secret")
+	getSecretsCmd.MarkFlagRequired("user")
+}
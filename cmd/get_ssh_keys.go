@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/spf13/cobra"
+)
+
+// getSSHKeysCmd represents the get-ssh-keys command
+var getSSHKeysCmd = &cobra.Command{
+	Use:   "get-ssh-keys",
+	Short: "Get SSH keys of the user with their private keys",
+	Long: `Get SSH keys of the user with their private keys, --public only prints the public keys
+in authorized_keys format.`,
+	Example: "goph-keeper get-ssh-keys --user <user-name> --name deploy\n" +
+		"goph-keeper get-ssh-keys --user <user-name> --public >> ~/.ssh/authorized_keys",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		public, _ := cmd.Flags().GetBool("public")
+		keys, err := userClient(cfg, userName).GetSSHKeys(context.Background(), name)
+		if err != nil {
+			exitWithError(err)
+		}
+		if !public {
+			printJSON(keys)
+			return
+		}
+		for _, key := range keys {
+			fmt.Println(key.PublicKey)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getSSHKeysCmd)
+	getSSHKeysCmd.Flags().String("user", "", "user name")
+	getSSHKeysCmd.Flags().String("name", "", "name of the key")
+	getSSHKeysCmd.Flags().Bool("public", false, "print only the public keys")
+	getSSHKeysCmd.MarkFlagRequired("user")
+}
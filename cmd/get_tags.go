@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+)
+
+// getTagsCmd represents the get-tags command
+var getTagsCmd = &cobra.Command{
+	Use:     "get-tags",
+	Short:   "Get all tags of user's items from goph-keeper storage",
+	Example: "goph-keeper get-tags --user <user-name>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		tags, err := userClient(cfg, userName).GetTags(context.Background())
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(tags)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getTagsCmd)
+	getTagsCmd.Flags().String("user", "", "user name")
+	getTagsCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,31 @@
+package cmd
+
+import (
+	"context"
+	"github.com/spf13/cobra"
+)
+
+// getTOTPCmd represents the get-totp command
+var getTOTPCmd = &cobra.Command{
+	Use:     "get-totp",
+	Short:   "Get TOTP authenticators of the user with their secrets",
+	Example: "goph-keeper get-totp --user <user-name> --name github",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		name, _ := cmd.Flags().GetString("name")
+		authenticators, err := userClient(cfg, userName).GetTOTP(context.Background(), name)
+		if err != nil {
+			exitWithError(err)
+		}
+		printJSON(authenticators)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(getTOTPCmd)
+	getTOTPCmd.Flags().String("user", "", "user name")
+	getTOTPCmd.Flags().String("name", "", "name of the authenticator")
+	getTOTPCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"io"
+	"log"
+	"os"
+)
+
+// injectCmd represents the inject command
+var injectCmd = &cobra.Command{
+	Use:   "inject",
+	Short: "Render a config template with the values from goph-keeper",
+	Long: `Render the template replacing the references to the vault items with their values.
+The template may call {{ keeper "<type>/<name>" "<field>" }} or contain keeper://<type>/<name>/<field> references,
+e.g. {{ keeper "credentials/db-prod" "password" }} or keeper://notes/vpn/content. Types are credentials, notes,
+cards, totp, ssh_keys, identities and secrets; fields are the attributes of the items, the names of the custom fields
+or the keys of the secrets, the fields and the keys named as the attributes are referred with fields. and values.
+prefixes. The items matching the name ambiguously are referred by id, e.g. keeper://cards/#5/cv.
+The output file is only readable by the current user.`,
+	Example: "goph-keeper inject --user <user-name> -i config.tpl -o config.yaml\n" +
+		"cat config.tpl | goph-keeper inject --user <user-name> > config.yaml",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		in, _ := cmd.Flags().GetString("in")
+		out, _ := cmd.Flags().GetString("out")
+		name := in
+		var text []byte
+		var err error
+		if in == "" || in == "-" {
+			name = "stdin"
+			text, err = io.ReadAll(os.Stdin)
+		} else {
+			text, err = os.ReadFile(in)
+		}
+		if err != nil {
+			log.Fatalf("error while reading template: %s", err)
+		}
+		rendered, err := client.NewResolver(userClient(cfg, userName)).Render(context.Background(), name, string(text))
+		if err != nil {
+			exitWithError(err)
+		}
+		if out == "" || out == "-" {
+			fmt.Print(rendered)
+			return
+		}
+		if err = os.WriteFile(out, []byte(rendered), 0o600); err != nil {
+			log.Fatalf("error while writing %s: %s", out, err)
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(injectCmd)
+	injectCmd.Flags().String("user", "", "user name")
+	injectCmd.Flags().StringP("in", "i", "", "template file, standard input by default")
+	injectCmd.Flags().StringP("out", "o", "", "output file, standard output by default")
+	injectCmd.MarkFlagRequired("user")
+}
@@ -1,15 +1,12 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
 	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
 	"log"
-	"net/http"
 )
 
 // loginCmd represents the login command
@@ -17,50 +14,87 @@ var loginCmd = &cobra.Command{
 	Use:   "login",
 	Short: "Login to the goph-keeper system",
 	Long: `Login to the goph-keeper system with specified login and password. 
-Only registered users can run this command`,
+Only registered users can run this command. If two-factor authentication is enabled,
+the code from authenticator app is asked interactively unless provided with --code or --recovery-code.
+Users who registered a public key with register-key can login with --key instead of password,
+the second factor is still required then`,
 	Example: "goph-keeper login --login <user-system-login> --password <user-system-password>`",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		login, _ := cmd.Flags().GetString("login")
 		password, _ := cmd.Flags().GetString("password")
-		userCreds := internal.User{
-			Login:    login,
-			Password: password,
+		withKey, _ := cmd.Flags().GetBool("key")
+		if !withKey && password == "" {
+			log.Fatalln("password should not be empty, use --key to login with the registered public key")
 		}
 
-		body, err := json.Marshal(userCreds)
-		if err != nil {
-			log.Fatalf(err.Error())
+		ctx := context.Background()
+		c := newClient(cfg)
+		var challenge string
+		var err error
+		if withKey {
+			challenge, err = loginWithKey(ctx, cfg, c, login)
+		} else {
+			challenge, err = c.Login(ctx, login, password)
 		}
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/auth/login", cfg.ApplicationHost, cfg.ApplicationPort))
 		if err != nil {
-			log.Printf(err.Error())
+			exitWithError(err)
 		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
-			fmt.Println(resp.String())
-			return
+		if challenge != "" {
+			// two-factor authentication is enabled for the user, the key replaces the password only
+			if err = loginTwoFactor(ctx, cmd, c, challenge); err != nil {
+				exitWithError(err)
+			}
 		}
+		openSession(cfg, login, c)
 		fmt.Printf("user %q was successfully logined in goph-keeper", login)
 	},
 }
 
+// loginTwoFactor exchanges the login challenge for a token using the code from authenticator app
+// or a recovery code. The code is asked interactively if it wasn't provided with flags.
+func loginTwoFactor(ctx context.Context, cmd *cobra.Command, c *client.Client, challenge string) error {
+	code, _ := cmd.Flags().GetString("code")
+	recoveryCode, _ := cmd.Flags().GetString("recovery-code")
+	if code == "" && recoveryCode == "" {
+		var err error
+		if code, err = prompt("Enter the code from the authenticator app: "); err != nil {
+			log.Fatalln(err.Error())
+		}
+	}
+	return c.LoginTwoFactor(ctx, challenge, code, recoveryCode)
+}
+
+// loginWithKey signs the server challenge with the key from the software authenticator,
+// the challenge of two-factor authentication is returned if it's enabled for the user.
+func loginWithKey(ctx context.Context, cfg internal.Params, c *client.Client, login string) (string, error) {
+	authenticator, err := loadAuthenticator(cfg)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	challenge, err := c.KeyChallenge(ctx, login)
+	if err != nil {
+		return "", err
+	}
+
+	assertion, err := authenticator.Sign(login, challenge)
+	if err != nil {
+		log.Fatalln(err.Error())
+	}
+	// the sign counter must be persisted even if the login fails
+	if err = authenticator.Save(); err != nil {
+		log.Fatalln(err.Error())
+	}
+	return c.KeyLogin(ctx, login, assertion)
+}
+
 func init() {
 	rootCmd.AddCommand(loginCmd)
 	loginCmd.Flags().String("login", "", "user login")
 	loginCmd.Flags().String("password", "", "user password")
+	loginCmd.Flags().String("code", "", "one-time code from authenticator app")
+	loginCmd.Flags().String("recovery-code", "", "one of the two-factor authentication recovery codes")
+	loginCmd.Flags().Bool("key", false, "login with the public key registered by register-key instead of password")
 	loginCmd.MarkFlagRequired("login")
-	loginCmd.MarkFlagRequired("password")
 }
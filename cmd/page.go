@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// addListFlags adds the flags selecting the page of the listed items and filtering them by labels.
+func addListFlags(cmd *cobra.Command) {
+	cmd.Flags().Int("limit", 0, "number of items on the page, all items are shown by default")
+	cmd.Flags().Int("page", 1, "number of the page to show, starting from 1")
+	cmd.Flags().String("sort", "", "sort by name, created or updated, the minus prefix reverses the order")
+	cmd.Flags().String("metadata", "", "show only items with the metadata containing the value")
+	cmd.Flags().StringArray("tag", nil, "show only items with the tag, repeat the flag for several tags")
+	cmd.Flags().String("folder", "", "show only items of the folder and its subfolders, for example work/servers")
+	cmd.Flags().Bool("favorite", false, "show only favourite items, --favorite=false shows the others")
+}
+
+// listItems returns the items selected by the list flags. The server pages are walked with the cursors
+// up to the requested page, without --limit all the items are returned.
+func listItems[T any](cmd *cobra.Command, c *client.Client, list func(ctx context.Context, opts client.ListOptions) ([]T, string, error)) ([]T, error) {
+	limit, _ := cmd.Flags().GetInt("limit")
+	page, _ := cmd.Flags().GetInt("page")
+	sort, _ := cmd.Flags().GetString("sort")
+	metadata, _ := cmd.Flags().GetString("metadata")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	folder, _ := cmd.Flags().GetString("folder")
+	if limit < 0 || page < 1 {
+		return nil, fmt.Errorf("--limit must not be negative and --page must be positive")
+	}
+	if limit == 0 && page > 1 {
+		return nil, fmt.Errorf("--page requires --limit")
+	}
+
+	opts := client.ListOptions{Limit: limit, Metadata: metadata, Tags: tags}
+	opts.Sort, opts.Descending = strings.CutPrefix(sort, "-")
+	if folder != "" {
+		folderID, err := findFolder(context.Background(), c, folder)
+		if err != nil {
+			return nil, err
+		}
+		opts.FolderID = &folderID
+	}
+	if cmd.Flags().Changed("favorite") {
+		favorite, _ := cmd.Flags().GetBool("favorite")
+		opts.Favorite = &favorite
+	}
+	var items []T
+	for current := 1; ; current++ {
+		found, next, err := list(context.Background(), opts)
+		if err != nil {
+			return nil, err
+		}
+		if limit == 0 {
+			items = append(items, found...)
+		} else if current == page {
+			return found, nil
+		}
+		if next == "" {
+			return items, nil
+		}
+		opts.Cursor = next
+	}
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	keeperPassword "github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/vault"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// passwdCmd represents the passwd command
+var passwdCmd = &cobra.Command{
+	Use:   "passwd",
+	Short: "Change password of the goph-keeper account.",
+	Long: `Change password of the goph-keeper account. The current password is required,
+users with enabled two-factor authentication should also provide the code from the authenticator app.
+Other sessions of the user are closed after the change. The vault key used by the password recovery
+is wrapped with the new password, the items themselves are not encrypted with it.`,
+	Example: "goph-keeper passwd --login <user-system-login> --password <current-password> --new-password <new-password>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		login, _ := cmd.Flags().GetString("login")
+		password, _ := cmd.Flags().GetString("password")
+		newPassword, _ := cmd.Flags().GetString("new-password")
+		code, _ := cmd.Flags().GetString("code")
+		policy := keeperPassword.Policy{MinLength: cfg.PasswordMinLength, MinScore: cfg.PasswordMinScore}
+		if err := policy.Check(newPassword, login); err != nil {
+			log.Fatalf("new password doesn't satisfy the policy: %s", err)
+		}
+		request := client.AccountRequest{
+			Login:       login,
+			Password:    password,
+			NewPassword: newPassword,
+			Code:        code,
+		}
+		// the vault key is re-wrapped with the new password locally
+		ctx := context.Background()
+		c := newClient(cfg)
+		rewrapped, err := rewrapVaultKey(ctx, c, request)
+		if err != nil {
+			exitWithError(err)
+		}
+		request.Vault = rewrapped
+		if err = c.ChangePassword(ctx, request); err != nil {
+			exitWithError(err)
+		}
+		// the old session is closed by the server
+		openSession(cfg, login, c)
+		fmt.Printf("password of user %q was successfully changed", login)
+	},
+}
+
+// rewrapVaultKey gets the user's vault key wrapped with the current password and wraps it with the new one.
+// Nil is returned for users registered without the vault key.
+func rewrapVaultKey(ctx context.Context, c *client.Client, request client.AccountRequest) (*client.VaultKey, error) {
+	wrapped, err := c.GetVaultKey(ctx, client.AccountRequest{Login: request.Login, Password: request.Password, Code: request.Code})
+	if errors.Is(err, client.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	vaultKey, err := vault.UnwrapWithPassword(wrapped, request.Password)
+	if err != nil {
+		return nil, err
+	}
+	rewrapped, err := vault.WrapWithPassword(vaultKey, request.NewPassword)
+	if err != nil {
+		return nil, err
+	}
+	return &rewrapped, nil
+}
+
+func init() {
+	rootCmd.AddCommand(passwdCmd)
+	passwdCmd.Flags().String("login", "", "user login")
+	passwdCmd.Flags().String("password", "", "current user password")
+	passwdCmd.Flags().String("new-password", "", "new user password")
+	passwdCmd.Flags().String("code", "", "two-factor authentication code")
+	passwdCmd.MarkFlagRequired("login")
+	passwdCmd.MarkFlagRequired("password")
+	passwdCmd.MarkFlagRequired("new-password")
+}
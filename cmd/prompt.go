@@ -0,0 +1,18 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// prompt prints the message and reads a single line from the standard input.
+func prompt(message string) (string, error) {
+	fmt.Print(message)
+	line, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil && line == "" {
+		return "", fmt.Errorf("error while reading input: %w", err)
+	}
+	return strings.TrimSpace(line), nil
+}
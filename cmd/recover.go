@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	keeperPassword "github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/vault"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// recoverCmd represents the recover command
+var recoverCmd = &cobra.Command{
+	Use:   "recover",
+	Short: "Set a new password with the recovery key.",
+	Long: `Set a new password for the goph-keeper account with the recovery key shown on registration.
+The recovery key never leaves the client: it unwraps the vault key locally, the vault key is wrapped
+with the new password and only a value derived from the recovery key is sent to prove its knowledge.
+If the recovery key isn't provided with the flag, it is asked interactively.
+This is a password reset mechanism only, the items are not encrypted with the vault key.`,
+	Example: `goph-keeper recover --login <user-system-login> --new-password <new-password> --recovery-key "<twelve words>"`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		login, _ := cmd.Flags().GetString("login")
+		newPassword, _ := cmd.Flags().GetString("new-password")
+		phrase, _ := cmd.Flags().GetString("recovery-key")
+		policy := keeperPassword.Policy{MinLength: cfg.PasswordMinLength, MinScore: cfg.PasswordMinScore}
+		if err := policy.Check(newPassword, login); err != nil {
+			log.Fatalf("new password doesn't satisfy the policy: %s", err)
+		}
+		if phrase == "" {
+			var err error
+			if phrase, err = prompt("Enter the recovery key: "); err != nil {
+				log.Fatalln(err.Error())
+			}
+		}
+		recoveryKey, err := vault.ParseRecoveryKey(phrase)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		auth, err := recoveryKey.Auth()
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+
+		// get the vault key wrapped with the recovery key
+		ctx := context.Background()
+		c := newClient(cfg)
+		wrapped, err := c.GetRecoveryKey(ctx, client.RecoveryRequest{Login: login, RecoveryAuth: auth})
+		if err != nil {
+			exitWithError(err)
+		}
+
+		// re-wrap the vault key with the new password and reset the password
+		vaultKey, err := vault.UnwrapWithRecoveryKey(wrapped, recoveryKey)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		rewrapped, err := vault.WrapWithPassword(vaultKey, newPassword)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		err = c.Recover(ctx, client.RecoveryRequest{
+			Login:        login,
+			RecoveryAuth: auth,
+			NewPassword:  newPassword,
+			Vault:        &rewrapped,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+		openSession(cfg, login, c)
+		fmt.Printf("password of user %q was successfully reset", login)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(recoverCmd)
+	recoverCmd.Flags().String("login", "", "user login")
+	recoverCmd.Flags().String("new-password", "", "new user password")
+	recoverCmd.Flags().String("recovery-key", "", "recovery key shown on registration")
+	recoverCmd.MarkFlagRequired("login")
+	recoverCmd.MarkFlagRequired("new-password")
+}
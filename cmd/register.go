@@ -1,58 +1,52 @@
 package cmd
 
 import (
-	"encoding/json"
+	"context"
 	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	keeperPassword "github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/vault"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
 	"log"
-	"net/http"
 )
 
 // registerCmd represents the register command
 var registerCmd = &cobra.Command{
-	Use:     "register",
-	Short:   "Register in the goph-keeper system.",
-	Long:    `Register in the goph-keeper system with provided login and password`,
+	Use:   "register",
+	Short: "Register in the goph-keeper system.",
+	Long: `Register in the goph-keeper system with provided login and password.
+The recovery key shown on registration resets the password if it is lost. The items are not encrypted
+with the vault key yet: the vault key only backs the password recovery.`,
 	Example: "goph-keeper register --login <user-system-login> --password <user-system-password>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		login, _ := cmd.Flags().GetString("login")
 		password, _ := cmd.Flags().GetString("password")
-		userCreds := internal.User{
-			Login:    login,
-			Password: password,
+		// the server checks the policy too, local check saves a round trip
+		policy := keeperPassword.Policy{MinLength: cfg.PasswordMinLength, MinScore: cfg.PasswordMinScore}
+		if err := policy.Check(password, login); err != nil {
+			log.Fatalf("password doesn't satisfy the policy: %s", err)
 		}
-
-		body, err := json.Marshal(userCreds)
+		// the vault key is wrapped locally, the recovery key is shown once and never sent to the server
+		vaultKey, recoveryKey, err := vault.New(password)
 		if err != nil {
-			log.Fatalf(err.Error())
+			log.Fatalf("error while creating vault key: %s", err)
 		}
-
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/auth/register", cfg.ApplicationHost, cfg.ApplicationPort))
+		c := newClient(cfg)
+		err = c.Register(context.Background(), client.User{
+			Login:    login,
+			Password: password,
+			Vault:    &vaultKey,
+		})
 		if err != nil {
-			log.Printf(err.Error())
-		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
-			fmt.Println(resp.String())
-			return
+			exitWithError(err)
 		}
-		fmt.Printf("user %q was successfully registered in goph-keeper", login)
+		openSession(cfg, login, c)
+		fmt.Printf("user %q was successfully registered in goph-keeper\n\n", login)
+		fmt.Println("Your recovery key, write it down and keep it in a safe place.")
+		fmt.Println("It is the only way to restore access if you forget the password, it can't be shown again:")
+		fmt.Printf("\n    %s\n", recoveryKey)
 	},
 }
 
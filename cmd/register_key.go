@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// registerKeyCmd represents the register-key command
+var registerKeyCmd = &cobra.Command{
+	Use:   "register-key",
+	Short: "Register a public key for login without password.",
+	Long: `Generate an Ed25519 key in the local software authenticator and register its public key in goph-keeper.
+After that the user can login with "goph-keeper login --key". The private key never leaves the authenticator file
+(KEEPER_AUTHENTICATOR_FILE, ~/.goph-keeper/authenticator.json by default). Only authorized users can use this command,
+the current password and the two-factor authentication code if it is enabled are required.`,
+	Example: "goph-keeper register-key --user <user-name> --password <user-password>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		password, _ := cmd.Flags().GetString("password")
+		code, _ := cmd.Flags().GetString("code")
+		authenticator, err := loadAuthenticator(cfg)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		credential, err := authenticator.Create(userName)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		err = userClient(cfg, userName).RegisterKey(context.Background(), client.KeyRegistration{
+			PublicKey: client.PublicKey{
+				KeyID:     credential.KeyID,
+				PublicKey: credential.PublicKey(),
+				Algorithm: webauthn.AlgorithmEdDSA,
+			},
+			Password: password,
+			Code:     code,
+		})
+		if err != nil {
+			exitWithError(err)
+		}
+		// the key is stored only after the server has accepted it
+		if err = authenticator.Save(); err != nil {
+			log.Fatalln(err.Error())
+		}
+		fmt.Printf("registered public key for user %q\n", userName)
+	},
+}
+
+// loadAuthenticator reads the software authenticator from the configured or default location.
+func loadAuthenticator(cfg internal.Params) (*webauthn.Authenticator, error) {
+	path := cfg.AuthenticatorFile
+	if path == "" {
+		defaultPath, err := webauthn.DefaultPath()
+		if err != nil {
+			return nil, err
+		}
+		path = defaultPath
+	}
+	return webauthn.Load(path)
+}
+
+func init() {
+	rootCmd.AddCommand(registerKeyCmd)
+	registerKeyCmd.Flags().String("user", "", "user name")
+	registerKeyCmd.Flags().String("password", "", "current user password")
+	registerKeyCmd.Flags().String("code", "", "two-factor authentication code")
+	registerKeyCmd.MarkFlagRequired("user")
+	registerKeyCmd.MarkFlagRequired("password")
+}
@@ -6,7 +6,11 @@ import (
 	"github.com/kelseyhightower/envconfig"
 	"github.com/kontik-pk/goph-keeper/internal"
 	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/handlers/handler"
 	router2 "github.com/kontik-pk/goph-keeper/internal/handlers/router"
+	"github.com/kontik-pk/goph-keeper/internal/notify"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/reminders"
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 	"log"
@@ -43,6 +47,13 @@ func run(sugar *zap.SugaredLogger) error {
 	if err := envconfig.Process("", &cfg); err != nil {
 		log.Fatalf("error while loading envs: %s\n", err)
 	}
+	// zero interval disables the reminders, negative values can only be mistakes
+	if cfg.ReminderInterval < 0 {
+		return fmt.Errorf("KEEPER_REMINDER_INTERVAL should not be negative, got %s", cfg.ReminderInterval)
+	}
+	if cfg.CardExpiryDays < 0 {
+		return fmt.Errorf("KEEPER_CARD_EXPIRY_DAYS should not be negative, got %d", cfg.CardExpiryDays)
+	}
 	pg, err := database.New(cfg)
 	if err != nil {
 		return fmt.Errorf("error while trying to setup DB: %w", err)
@@ -54,13 +65,45 @@ func run(sugar *zap.SugaredLogger) error {
 	if err != nil {
 		return fmt.Errorf("error while trying to listen: %w", err)
 	}
-	router := router2.New(pg, sugar)
+	router, grpcServer := router2.NewServers(pg, sugar,
+		handler.WithAdminToken(cfg.AdminToken),
+		handler.WithPasswordPolicy(password.Policy{MinLength: cfg.PasswordMinLength, MinScore: cfg.PasswordMinScore}),
+		handler.WithBreachChecker(breachChecker(cfg)),
+	)
 	server := &http.Server{
 		Handler: router,
 	}
 	go func() {
 		server.Serve(listener)
 	}()
+	// gRPC API is served on its own port if it's configured
+	if cfg.GRPCPort != "" {
+		grpcListener, err := net.Listen("tcp", fmt.Sprintf(":%s", cfg.GRPCPort))
+		if err != nil {
+			return fmt.Errorf("error while trying to listen for gRPC: %w", err)
+		}
+		go func() {
+			grpcServer.Serve(grpcListener)
+		}()
+		defer grpcServer.GracefulStop()
+		sugar.Infof("Started gRPC server on %s", cfg.GRPCPort)
+	}
+	// reminders are checked in background
+	notifier, err := newNotifier(cfg, sugar)
+	if err != nil {
+		return err
+	}
+	remindersCtx, stopReminders := context.WithCancel(context.Background())
+	defer stopReminders()
+	if cfg.ReminderInterval > 0 {
+		go reminders.Run(remindersCtx, cfg.ReminderInterval, sugar,
+			reminders.RotationCheck(pg, notifier),
+			reminders.CardExpiryCheck(pg, notifier, cfg.CardExpiryDays),
+		)
+		sugar.Infof("Started reminders with %s interval", cfg.ReminderInterval)
+	} else {
+		sugar.Infof("Reminders are disabled")
+	}
 	// graceful shutdown
 	defer func() {
 		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
@@ -80,3 +123,52 @@ func run(sugar *zap.SugaredLogger) error {
 
 	return nil
 }
+
+// breachChecker creates breached password checker from the configured source, nil if there is none.
+// The ranges of the range API are cached, so repeated checks of the vaults don't hit the API.
+func breachChecker(cfg internal.Params) *password.BreachChecker {
+	switch {
+	case cfg.BreachedPasswordsURL != "":
+		source := password.NewHTTPSource(cfg.BreachedPasswordsURL, &http.Client{Timeout: 5 * time.Second})
+		return password.NewBreachChecker(password.NewCachedSource(source, cfg.BreachCacheTTL))
+	case cfg.BreachedPasswordsFile != "":
+		return password.NewBreachChecker(password.NewFileSource(cfg.BreachedPasswordsFile))
+	default:
+		return nil
+	}
+}
+
+// newNotifier creates the notifier of the reminders from the configured names, by default the webhook is used
+// if its URL is set, otherwise the notifications are logged.
+func newNotifier(cfg internal.Params, sugar *zap.SugaredLogger) (notify.Notifier, error) {
+	names := cfg.Notifiers
+	if len(names) == 0 {
+		names = []string{"log"}
+		if cfg.NotifyWebhookURL != "" {
+			names = []string{"webhook"}
+		}
+	}
+	notifiers := make([]notify.Notifier, 0, len(names))
+	for _, name := range names {
+		switch name {
+		case "webhook":
+			if cfg.NotifyWebhookURL == "" {
+				return nil, fmt.Errorf("webhook notifier requires KEEPER_NOTIFY_WEBHOOK_URL")
+			}
+			notifiers = append(notifiers, notify.NewWebhook(cfg.NotifyWebhookURL, &http.Client{Timeout: 10 * time.Second}))
+		case "smtp":
+			if cfg.SMTPAddr == "" || cfg.SMTPFrom == "" {
+				return nil, fmt.Errorf("smtp notifier requires KEEPER_SMTP_ADDR and KEEPER_SMTP_FROM")
+			}
+			notifiers = append(notifiers, notify.NewSMTP(cfg.SMTPAddr, cfg.SMTPFrom, cfg.SMTPTo, cfg.SMTPUser, cfg.SMTPPassword))
+		case "log":
+			notifiers = append(notifiers, notify.NewLog(sugar))
+		default:
+			return nil, fmt.Errorf("unknown notifier %q, available notifiers are webhook, smtp and log", name)
+		}
+	}
+	if len(notifiers) == 1 {
+		return notifiers[0], nil
+	}
+	return notify.Multi(notifiers...), nil
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"strings"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search",
+	Short: "Search user's items in goph-keeper storage",
+	Long: `Search user's credentials, notes, cards, files and SSH keys by the words or the part of their names and metadata,
+credentials are also found by their URL fields, SSH keys by their public keys and fingerprints.
+Secret fields are not searched by the server, --content also searches the content of the notes on the client side.
+The notes are downloaded for that, there is no offline cache yet`,
+	Example: "goph-keeper search --user <user-name> --query \"staging vpn\"",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		query, _ := cmd.Flags().GetString("query")
+		limit, _ := cmd.Flags().GetInt("limit")
+		content, _ := cmd.Flags().GetBool("content")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		results, err := c.Search(ctx, query, limit)
+		if err != nil {
+			exitWithError(err)
+		}
+		if content {
+			notes, err := c.GetNotes(ctx, client.NotesFilter{})
+			if err != nil {
+				exitWithError(err)
+			}
+			results = append(results, searchContent(results, notes, query)...)
+		}
+		if len(results) == 0 {
+			exitNoItems("nothing was found for %q", query)
+		}
+		printJSON(results)
+	},
+}
+
+// searchContent returns the notes with the content containing the query, which were not found by the server.
+func searchContent(found []client.SearchResult, notes []client.Note, query string) []client.SearchResult {
+	seen := make(map[int64]bool)
+	for _, res := range found {
+		if res.Type == client.ItemTypeNote {
+			seen[res.ID] = true
+		}
+	}
+	query = strings.ToLower(query)
+	var results []client.SearchResult
+	for _, note := range notes {
+		if seen[note.ID] || note.Content == nil || !strings.Contains(strings.ToLower(*note.Content), query) {
+			continue
+		}
+		results = append(results, client.SearchResult{Type: client.ItemTypeNote, ID: note.ID, Name: *note.Title, Metadata: note.Metadata})
+	}
+	return results
+}
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+	searchCmd.Flags().String("user", "", "user name")
+	searchCmd.Flags().String("query", "", "words or part of the item name or metadata")
+	searchCmd.Flags().Int("limit", 0, "maximum number of items found by the server")
+	searchCmd.Flags().Bool("content", false, "also search the content of the notes")
+	searchCmd.MarkFlagRequired("user")
+	searchCmd.MarkFlagRequired("query")
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"os"
+	"path/filepath"
+)
+
+// sessions keeps the tokens of logged in users, so the commands run after login are authorized
+// without the password. The file is readable only by the owner.
+type sessions struct {
+	path   string
+	Tokens map[string]string `json:"tokens"`
+}
+
+// loadSessions reads the sessions from the configured or default location.
+// Missing file means that nobody has logged in.
+func loadSessions(cfg internal.Params) (*sessions, error) {
+	path := cfg.SessionFile
+	if path == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("error while getting home directory: %w", err)
+		}
+		path = filepath.Join(home, ".goph-keeper", "sessions.json")
+	}
+	s := &sessions{path: path, Tokens: make(map[string]string)}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return s, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while reading session file: %w", err)
+	}
+	if err = json.Unmarshal(data, s); err != nil {
+		return nil, fmt.Errorf("error while parsing session file: %w", err)
+	}
+	if s.Tokens == nil {
+		s.Tokens = make(map[string]string)
+	}
+	return s, nil
+}
+
+// save writes the sessions to their file.
+func (s *sessions) save() error {
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(s.path), 0o700); err != nil {
+		return fmt.Errorf("error while creating session directory: %w", err)
+	}
+	if err = os.WriteFile(s.path, data, 0o600); err != nil {
+		return fmt.Errorf("error while writing session file: %w", err)
+	}
+	return nil
+}
+
+// saveSession remembers the token of the user, empty token removes the session.
+func saveSession(cfg internal.Params, userName string, token string) error {
+	s, err := loadSessions(cfg)
+	if err != nil {
+		return err
+	}
+	if token == "" {
+		delete(s.Tokens, userName)
+	} else {
+		s.Tokens[userName] = token
+	}
+	return s.save()
+}
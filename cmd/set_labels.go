@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+)
+
+// setLabelsCmd represents the set-labels command
+var setLabelsCmd = &cobra.Command{
+	Use:   "set-labels",
+	Short: "Set tags, folder and favourite flag of user's item",
+	Long: `Set tags, folder and favourite flag of user's credentials, note or card by id.
+The labels are replaced: the item gets exactly the tags and the folder from the flags`,
+	Example: "goph-keeper set-labels --user <user-name> --type notes --id 5 --tag vpn --tag staging --folder work --favorite",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		itemType, _ := cmd.Flags().GetString("type")
+		id, _ := cmd.Flags().GetInt64("id")
+		tags, _ := cmd.Flags().GetStringArray("tag")
+		folder, _ := cmd.Flags().GetString("folder")
+		favorite, _ := cmd.Flags().GetBool("favorite")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		labels := client.Labels{Tags: tags, Favorite: favorite}
+		if folder != "" {
+			folderID, err := findFolder(ctx, c, folder)
+			if err != nil {
+				exitNoItems("%s", err)
+			}
+			labels.FolderID = &folderID
+		}
+		if err := c.SetLabels(ctx, itemType, id, labels); err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("labels of %s %d of user %q were set\n", itemType, id, userName)
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(setLabelsCmd)
+	setLabelsCmd.Flags().String("user", "", "user name")
+	setLabelsCmd.Flags().String("type", "", "type of the item: credentials, notes or cards")
+	setLabelsCmd.Flags().Int64("id", 0, "id of the item")
+	setLabelsCmd.Flags().StringArray("tag", nil, "tag of the item, repeat the flag for several tags")
+	setLabelsCmd.Flags().String("folder", "", "path of the folder, for example work/servers")
+	setLabelsCmd.Flags().Bool("favorite", false, "mark the item as favourite")
+	setLabelsCmd.MarkFlagRequired("user")
+	setLabelsCmd.MarkFlagRequired("type")
+	setLabelsCmd.MarkFlagRequired("id")
+}
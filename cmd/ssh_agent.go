@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal/sshkey"
+	"github.com/spf13/cobra"
+	"log"
+	"net"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"syscall"
+)
+
+// sshAgentCmd represents the ssh-agent command
+var sshAgentCmd = &cobra.Command{
+	Use:   "ssh-agent",
+	Short: "Serve SSH keys of the user from goph-keeper as an SSH agent",
+	Long: `Serve SSH keys of the user over a Unix socket with the SSH agent protocol, so ssh and git
+use them without writing the private keys to disk. The keys are loaded from goph-keeper when the agent starts,
+they can't be added or removed with ssh-add. With --confirm the agent asks on the terminal before each use of a key.
+The agent runs until it's interrupted, point SSH_AUTH_SOCK to the socket to use it.`,
+	Example: "goph-keeper ssh-agent --user <user-name> --socket /tmp/keeper.sock --confirm\n" +
+		"SSH_AUTH_SOCK=/tmp/keeper.sock ssh git@github.com",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		socket, _ := cmd.Flags().GetString("socket")
+		confirm, _ := cmd.Flags().GetBool("confirm")
+		names, _ := cmd.Flags().GetStringSlice("name")
+		if socket == "" {
+			socket = filepath.Join(os.TempDir(), fmt.Sprintf("goph-keeper-%d.sock", os.Getpid()))
+		}
+
+		keys, err := userClient(cfg, userName).GetSSHKeys(context.Background(), "")
+		if err != nil {
+			exitWithError(err)
+		}
+		served := make([]sshkey.Key, 0, len(keys))
+		for _, key := range keys {
+			if len(names) > 0 && !slices.Contains(names, *key.Name) {
+				continue
+			}
+			served = append(served, sshkey.Key{Name: *key.Name, PrivateKey: *key.PrivateKey})
+		}
+		if len(served) == 0 {
+			exitNoItems("there are no ssh keys of user %q to serve", userName)
+		}
+		var confirmUse sshkey.ConfirmFunc
+		if confirm {
+			confirmUse = confirmKeyUse()
+		}
+		sshAgent, err := sshkey.NewAgent(served, confirmUse)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+
+		listener, err := listenSocket(socket)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		ch := make(chan os.Signal, 1)
+		signal.Notify(ch, syscall.SIGINT, syscall.SIGTERM)
+		go func() {
+			<-ch
+			_ = listener.Close()
+		}()
+
+		fmt.Fprintf(os.Stderr, "serving %d ssh keys of user %q\n", len(served), userName)
+		fmt.Printf("SSH_AUTH_SOCK=%s; export SSH_AUTH_SOCK;\n", socket)
+		if err = sshkey.Serve(listener, sshAgent); err != nil {
+			log.Fatalln(err.Error())
+		}
+	},
+}
+
+// listenSocket listens on the Unix socket only accessible by the current user, the socket file is removed on close.
+// The socket is created with the private umask, so other users can't connect between its creation and chmod.
+func listenSocket(path string) (net.Listener, error) {
+	var listener net.Listener
+	err := withPrivateUmask(func() error {
+		var err error
+		listener, err = net.Listen("unix", path)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("error while listening on %s: %w", path, err)
+	}
+	if err = os.Chmod(path, 0o600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("error while changing permissions of %s: %w", path, err)
+	}
+	return listener, nil
+}
+
+// confirmKeyUse asks on the terminal before each use of a key, one question at a time.
+func confirmKeyUse() sshkey.ConfirmFunc {
+	var mu sync.Mutex
+	return func(name string, fingerprint string) bool {
+		mu.Lock()
+		defer mu.Unlock()
+		answer, err := prompt(fmt.Sprintf("allow use of ssh key %q (%s)? [y/N]: ", name, fingerprint))
+		if err != nil {
+			return false
+		}
+		answer = strings.ToLower(answer)
+		return answer == "y" || answer == "yes"
+	}
+}
+
+func init() {
+	rootCmd.AddCommand(sshAgentCmd)
+	sshAgentCmd.Flags().String("user", "", "user name")
+	sshAgentCmd.Flags().String("socket", "", "path of the agent socket, a new one in the temporary directory by default")
+	sshAgentCmd.Flags().StringSlice("name", nil, "names of the served keys, all keys by default")
+	sshAgentCmd.Flags().Bool("confirm", false, "ask for confirmation before each use of a key")
+	sshAgentCmd.MarkFlagRequired("user")
+}
@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal/otp"
+	"github.com/spf13/cobra"
+	"log"
+	"time"
+)
+
+// totpCmd represents the totp command
+var totpCmd = &cobra.Command{
+	Use:   "totp <name>",
+	Short: "Print the current code of the TOTP authenticator",
+	Long: `Print the current one-time password of the saved authenticator and the seconds it remains valid.
+The code is generated locally from the secret, --copy puts it to the clipboard as well.`,
+	Example: "goph-keeper totp github --user <user-name> --copy",
+	Args:    cobra.ExactArgs(1),
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		copyCode, _ := cmd.Flags().GetBool("copy")
+		authenticators, err := userClient(cfg, userName).GetTOTP(context.Background(), args[0])
+		if err != nil {
+			exitWithError(err)
+		}
+		if len(authenticators) == 0 {
+			exitNoItems("there is no authenticator with name %q", args[0])
+		}
+		totp := authenticators[0]
+		key := otp.Key{Secret: *totp.Secret, Digits: totp.Digits, Period: totp.Period, Algorithm: totp.Algorithm}
+		now := time.Now()
+		code, err := key.Code(now)
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+		fmt.Printf("%s (%ds remaining)\n", code, int(key.Remaining(now).Seconds()))
+		if copyCode {
+			if err = copyToClipboard(code); err != nil {
+				log.Fatalln(err.Error())
+			}
+		}
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(totpCmd)
+	totpCmd.Flags().String("user", "", "user name")
+	totpCmd.Flags().Bool("copy", false, "copy the code to the clipboard")
+	totpCmd.MarkFlagRequired("user")
+}
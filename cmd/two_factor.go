@@ -0,0 +1,66 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/mdp/qrterminal/v3"
+	"github.com/spf13/cobra"
+	"log"
+	"os"
+	"strings"
+)
+
+// twoFactorCmd represents the 2fa command
+var twoFactorCmd = &cobra.Command{
+	Use:   "2fa",
+	Short: "Manage two-factor authentication.",
+	Long: `Manage two-factor authentication with time-based one-time passwords (TOTP).
+Once enabled, login requires a code from an authenticator app or one of the recovery codes.`,
+}
+
+// twoFactorEnableCmd represents the 2fa enable command
+var twoFactorEnableCmd = &cobra.Command{
+	Use:   "enable",
+	Short: "Enable two-factor authentication.",
+	Long: `Enable two-factor authentication for the user. The current password of the user is required.
+The command shows a QR code and an otpauth:// URI for an authenticator app, asks for the current code
+to confirm the setup and prints one-time recovery codes. Only authorized users can use this command.`,
+	Example: "goph-keeper 2fa enable --user <user-name> --password <user-password>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+
+		userName, _ := cmd.Flags().GetString("user")
+		password, _ := cmd.Flags().GetString("password")
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		setup, err := c.EnableTwoFactor(ctx, password)
+		if err != nil {
+			exitWithError(err)
+		}
+
+		fmt.Println("Scan the QR code with your authenticator app or add the key manually.")
+		qrterminal.GenerateHalfBlock(setup.URI, qrterminal.L, os.Stdout)
+		fmt.Printf("URI: %s\nKey: %s\n", setup.URI, setup.Secret)
+		code, err := prompt("Enter the code from the authenticator app: ")
+		if err != nil {
+			log.Fatalln(err.Error())
+		}
+
+		codes, err := c.ConfirmTwoFactor(ctx, code)
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Printf("two-factor authentication was enabled for user %q\n", userName)
+		fmt.Println("Save these recovery codes in a safe place, each of them can be used only once:")
+		fmt.Println(strings.Join(codes, "\n"))
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(twoFactorCmd)
+	twoFactorCmd.AddCommand(twoFactorEnableCmd)
+	twoFactorEnableCmd.Flags().String("user", "", "user name")
+	twoFactorEnableCmd.Flags().String("password", "", "current user password")
+	twoFactorEnableCmd.MarkFlagRequired("user")
+	twoFactorEnableCmd.MarkFlagRequired("password")
+}
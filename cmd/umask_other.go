@@ -0,0 +1,8 @@
+//go:build !unix
+
+package cmd
+
+// withPrivateUmask runs f, there is no umask on this platform.
+func withPrivateUmask(f func() error) error {
+	return f()
+}
@@ -0,0 +1,13 @@
+//go:build unix
+
+package cmd
+
+import "syscall"
+
+// withPrivateUmask runs f with the umask denying access of the group and others,
+// so the files created by f are never accessible by other users, even for a moment.
+func withPrivateUmask(f func() error) error {
+	previous := syscall.Umask(0o077)
+	defer syscall.Umask(previous)
+	return f()
+}
@@ -0,0 +1,41 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
+	"github.com/spf13/cobra"
+	"log"
+)
+
+// unlockCmd represents the unlock command
+var unlockCmd = &cobra.Command{
+	Use:   "unlock",
+	Short: "Unlock an account locked after too many failed logins.",
+	Long: `Reset failed login attempts for the account and/or the client address, so the user can login again
+without waiting for the lockout to expire. This is an admin command, it requires KEEPER_ADMIN_TOKEN.`,
+	Example: "goph-keeper unlock --login <user-system-login> --address <client-ip>",
+	Run: func(cmd *cobra.Command, args []string) {
+		cfg := loadConfig()
+		if cfg.AdminToken == "" {
+			log.Fatalln("KEEPER_ADMIN_TOKEN should be set to use admin commands")
+		}
+
+		login, _ := cmd.Flags().GetString("login")
+		address, _ := cmd.Flags().GetString("address")
+		if login == "" && address == "" {
+			log.Fatalln("login or address should not be empty")
+		}
+		err := newClient(cfg).Unlock(context.Background(), cfg.AdminToken, client.UnlockRequest{Login: login, Address: address})
+		if err != nil {
+			exitWithError(err)
+		}
+		fmt.Println("failed login attempts were reset")
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(unlockCmd)
+	unlockCmd.Flags().String("login", "", "user login")
+	unlockCmd.Flags().String("address", "", "client ip address")
+}
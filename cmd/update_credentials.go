@@ -1,57 +1,56 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
-	"log"
-	"net/http"
-
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
+	"log"
 )
 
 // updateCredentialsCmd represents the updateCredentials command
 var updateCredentialsCmd = &cobra.Command{
 	Use:     "update-credentials",
 	Short:   "Update user credentials for provided login.",
-	Example: "goph-keeper update-credentials --user <user-name> --login <saved-login> --password <new-password>",
+	Example: "goph-keeper update-credentials --user <user-name> --login <saved-login> --password <new-password>\ngoph-keeper update-credentials --user <user-name> --login <saved-login> --generate --words 5",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		login, _ := cmd.Flags().GetString("login")
-		password, _ := cmd.Flags().GetString("password")
+		password := passwordFlag(cmd)
 		metadata, _ := cmd.Flags().GetString("metadata")
-		requestCredentials := internal.Credentials{
-			UserName: userName,
-			Login:    &login,
-			Password: &password,
-			Metadata: &metadata,
-		}
-		body, err := json.Marshal(requestCredentials)
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		creds, err := c.GetCredentials(ctx, client.CredentialsFilter{Login: login})
 		if err != nil {
-			log.Fatalf(err.Error())
+			exitWithError(err)
 		}
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/update/credentials", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
+		if len(creds) == 0 {
+			exitNoItems("there are no credentials with login %q", login)
+		}
+		// the fields are replaced only if they are provided
+		fields := creds[0].Fields
+		if cmd.Flags().Changed("field") {
+			if fields, err = fieldFlags(cmd); err != nil {
+				log.Fatalln(err.Error())
+			}
+		}
+		// the rotation policy is replaced only if it's provided as well
+		rotationDays := rotationFlag(cmd)
+		if rotationDays == nil {
+			rotationDays = creds[0].RotationDays
 		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+		updated, err := c.UpdateCredentials(ctx, client.Credentials{
+			ID:       creds[0].ID,
+			Password: &password,
+			Metadata: &metadata,
+			Fields:   fields,
+			Rotation: client.Rotation{RotationDays: rotationDays},
+		})
+		if err != nil {
+			exitWithError(err)
 		}
-		log.Println(resp.String())
+		printJSON(updated)
 	},
 }
 
@@ -60,8 +59,11 @@ func init() {
 	updateCredentialsCmd.Flags().String("user", "", "user name")
 	updateCredentialsCmd.Flags().String("login", "", "user login")
 	updateCredentialsCmd.Flags().String("password", "", "user password")
+	updateCredentialsCmd.Flags().Bool("generate", false, "generate the password, see the generate command for the options")
+	addGenerateFlags(updateCredentialsCmd)
 	updateCredentialsCmd.Flags().String("metadata", "", "metadata")
+	addRotationFlag(updateCredentialsCmd)
+	addFieldFlag(updateCredentialsCmd)
 	updateCredentialsCmd.MarkFlagRequired("user")
 	updateCredentialsCmd.MarkFlagRequired("login")
-	updateCredentialsCmd.MarkFlagRequired("password")
 }
@@ -1,15 +1,10 @@
 package cmd
 
 import (
-	"encoding/json"
-	"fmt"
-	"github.com/go-resty/resty/v2"
-	"github.com/joho/godotenv"
-	"github.com/kelseyhightower/envconfig"
-	"github.com/kontik-pk/goph-keeper/internal"
+	"context"
+	"github.com/kontik-pk/goph-keeper/pkg/client"
 	"github.com/spf13/cobra"
 	"log"
-	"net/http"
 )
 
 // updateNotesCmd represents the updateNotes command
@@ -18,39 +13,38 @@ var updateNotesCmd = &cobra.Command{
 	Short:   "Update user notes.",
 	Example: "goph-keeper update-notes --user <user-name> --title <note-title> --content <new-content>",
 	Run: func(cmd *cobra.Command, args []string) {
-		if err := godotenv.Load(".env"); err != nil {
-			log.Fatalf("error while getting envs: %s", err)
-		}
-		var cfg internal.Params
-		if err := envconfig.Process("", &cfg); err != nil {
-			log.Fatalf("error while loading envs: %s\n", err)
-		}
+		cfg := loadConfig()
 
 		userName, _ := cmd.Flags().GetString("user")
 		title, _ := cmd.Flags().GetString("title")
 		content, _ := cmd.Flags().GetString("content")
 		metadata, _ := cmd.Flags().GetString("metadata")
-		requestNote := internal.Note{
-			UserName: userName,
-			Title:    &title,
-			Content:  &content,
-			Metadata: &metadata,
-		}
-		body, err := json.Marshal(requestNote)
+		ctx := context.Background()
+		c := userClient(cfg, userName)
+		notes, err := c.GetNotes(ctx, client.NotesFilter{Title: title})
 		if err != nil {
-			log.Fatalf(err.Error())
+			exitWithError(err)
 		}
-		resp, err := resty.New().R().
-			SetHeader("Content-type", "application/json").
-			SetBody(body).
-			Post(fmt.Sprintf("http://%s:%s/update/note", cfg.ApplicationHost, cfg.ApplicationPort))
-		if err != nil {
-			log.Printf(err.Error())
+		if len(notes) == 0 {
+			exitNoItems("there is no note with title %q", title)
+		}
+		// the fields are replaced only if they are provided
+		fields := notes[0].Fields
+		if cmd.Flags().Changed("field") {
+			if fields, err = fieldFlags(cmd); err != nil {
+				log.Fatalln(err.Error())
+			}
 		}
-		if resp.StatusCode() != http.StatusOK {
-			log.Printf("status code is not OK: %s\n", resp.Status())
+		updated, err := c.UpdateNote(ctx, client.Note{
+			ID:       notes[0].ID,
+			Content:  &content,
+			Metadata: &metadata,
+			Fields:   fields,
+		})
+		if err != nil {
+			exitWithError(err)
 		}
-		log.Println(resp.String())
+		printJSON(updated)
 	},
 }
 
@@ -60,6 +54,7 @@ func init() {
 	updateNotesCmd.Flags().String("title", "", "title of the note")
 	updateNotesCmd.Flags().String("content", "", "new note's content")
 	updateNotesCmd.Flags().String("metadata", "", "metadata")
+	addFieldFlag(updateNotesCmd)
 	updateNotesCmd.MarkFlagRequired("user")
 	updateNotesCmd.MarkFlagRequired("title")
 	updateNotesCmd.MarkFlagRequired("content")
@@ -0,0 +1,2 @@
+drop table recovery_codes;
+drop table two_factor;
\ No newline at end of file
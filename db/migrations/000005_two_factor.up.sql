@@ -0,0 +1,10 @@
+create table if not exists two_factor (
+    login text primary key references registered_users (login) on delete cascade,
+    secret text not null,
+    enabled boolean not null default false
+);
+create table if not exists recovery_codes (
+    login text not null references registered_users (login) on delete cascade,
+    code text not null,
+    primary key (login, code)
+);
@@ -0,0 +1 @@
+drop table public_keys;
\ No newline at end of file
@@ -0,0 +1,8 @@
+create table if not exists public_keys (
+    login text not null references registered_users (login) on delete cascade,
+    key_id text not null,
+    public_key text not null,
+    algorithm text not null,
+    sign_count bigint not null default 0,
+    primary key (login, key_id)
+);
@@ -0,0 +1 @@
+drop table vault_keys;
\ No newline at end of file
@@ -0,0 +1,7 @@
+create table if not exists vault_keys (
+    login text primary key references registered_users (login) on delete cascade,
+    password_salt text not null,
+    password_wrapped_key text not null,
+    recovery_wrapped_key text not null,
+    recovery_verifier text not null
+);
\ No newline at end of file
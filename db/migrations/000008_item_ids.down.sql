@@ -0,0 +1,2 @@
+alter table notes drop column id;
+alter table cards drop column id;
\ No newline at end of file
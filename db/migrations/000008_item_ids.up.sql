@@ -0,0 +1,2 @@
+alter table notes add column if not exists id serial;
+alter table cards add column if not exists id serial;
\ No newline at end of file
@@ -0,0 +1 @@
+drop table files;
\ No newline at end of file
@@ -0,0 +1,9 @@
+create table if not exists files (
+    id serial,
+    user_name text not null,
+    name text not null,
+    content text not null,
+    size bigint not null,
+    metadata text,
+    primary key (user_name, name)
+);
\ No newline at end of file
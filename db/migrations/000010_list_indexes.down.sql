@@ -0,0 +1,19 @@
+drop index if exists credentials_name_idx;
+drop index if exists credentials_created_idx;
+drop index if exists credentials_updated_idx;
+drop index if exists credentials_metadata_idx;
+drop index if exists notes_name_idx;
+drop index if exists notes_created_idx;
+drop index if exists notes_updated_idx;
+drop index if exists notes_metadata_idx;
+drop index if exists cards_name_idx;
+drop index if exists cards_created_idx;
+drop index if exists cards_updated_idx;
+drop index if exists cards_metadata_idx;
+
+alter table credentials drop column created_at;
+alter table credentials drop column updated_at;
+alter table notes drop column created_at;
+alter table notes drop column updated_at;
+alter table cards drop column created_at;
+alter table cards drop column updated_at;
\ No newline at end of file
@@ -0,0 +1,21 @@
+create extension if not exists pg_trgm;
+
+alter table credentials add column if not exists created_at timestamptz not null default now();
+alter table credentials add column if not exists updated_at timestamptz not null default now();
+alter table notes add column if not exists created_at timestamptz not null default now();
+alter table notes add column if not exists updated_at timestamptz not null default now();
+alter table cards add column if not exists created_at timestamptz not null default now();
+alter table cards add column if not exists updated_at timestamptz not null default now();
+
+create index if not exists credentials_name_idx on credentials (user_name, login, id);
+create index if not exists credentials_created_idx on credentials (user_name, created_at, id);
+create index if not exists credentials_updated_idx on credentials (user_name, updated_at, id);
+create index if not exists credentials_metadata_idx on credentials using gin (metadata gin_trgm_ops);
+create index if not exists notes_name_idx on notes (user_name, title, id);
+create index if not exists notes_created_idx on notes (user_name, created_at, id);
+create index if not exists notes_updated_idx on notes (user_name, updated_at, id);
+create index if not exists notes_metadata_idx on notes using gin (metadata gin_trgm_ops);
+create index if not exists cards_name_idx on cards (user_name, bank_name, number, id);
+create index if not exists cards_created_idx on cards (user_name, created_at, id);
+create index if not exists cards_updated_idx on cards (user_name, updated_at, id);
+create index if not exists cards_metadata_idx on cards using gin (metadata gin_trgm_ops);
\ No newline at end of file
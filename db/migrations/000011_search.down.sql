@@ -0,0 +1,8 @@
+drop index if exists credentials_search_fts_idx;
+drop index if exists credentials_search_trgm_idx;
+drop index if exists notes_search_fts_idx;
+drop index if exists notes_search_trgm_idx;
+drop index if exists cards_search_fts_idx;
+drop index if exists cards_search_trgm_idx;
+drop index if exists files_search_fts_idx;
+drop index if exists files_search_trgm_idx;
\ No newline at end of file
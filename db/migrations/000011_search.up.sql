@@ -0,0 +1,8 @@
+create index if not exists credentials_search_fts_idx on credentials using gin (to_tsvector('simple', login || ' ' || coalesce(metadata, '')));
+create index if not exists credentials_search_trgm_idx on credentials using gin ((login || ' ' || coalesce(metadata, '')) gin_trgm_ops);
+create index if not exists notes_search_fts_idx on notes using gin (to_tsvector('simple', title || ' ' || coalesce(metadata, '')));
+create index if not exists notes_search_trgm_idx on notes using gin ((title || ' ' || coalesce(metadata, '')) gin_trgm_ops);
+create index if not exists cards_search_fts_idx on cards using gin (to_tsvector('simple', bank_name || ' ' || coalesce(metadata, '')));
+create index if not exists cards_search_trgm_idx on cards using gin ((bank_name || ' ' || coalesce(metadata, '')) gin_trgm_ops);
+create index if not exists files_search_fts_idx on files using gin (to_tsvector('simple', name || ' ' || coalesce(metadata, '')));
+create index if not exists files_search_trgm_idx on files using gin ((name || ' ' || coalesce(metadata, '')) gin_trgm_ops);
\ No newline at end of file
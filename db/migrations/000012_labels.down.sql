@@ -0,0 +1,14 @@
+drop trigger if exists credentials_tags_trigger on credentials;
+drop trigger if exists notes_tags_trigger on notes;
+drop trigger if exists cards_tags_trigger on cards;
+drop function if exists delete_item_tags();
+
+alter table credentials drop column favorite;
+alter table credentials drop column folder_id;
+alter table notes drop column favorite;
+alter table notes drop column folder_id;
+alter table cards drop column favorite;
+alter table cards drop column folder_id;
+
+drop table if exists item_tags;
+drop table if exists folders;
\ No newline at end of file
@@ -0,0 +1,42 @@
+create table if not exists folders (
+    id serial primary key,
+    user_name text not null references registered_users (login) on delete cascade,
+    name text not null,
+    parent_id integer references folders (id) on delete cascade
+);
+create unique index if not exists folders_name_idx on folders (user_name, coalesce(parent_id, 0), name);
+
+create table if not exists item_tags (
+    user_name text not null references registered_users (login) on delete cascade,
+    item_type text not null,
+    item_id integer not null,
+    tag text not null,
+    primary key (user_name, item_type, item_id, tag)
+);
+create index if not exists item_tags_tag_idx on item_tags (user_name, item_type, tag);
+
+alter table credentials add column if not exists favorite boolean not null default false;
+alter table credentials add column if not exists folder_id integer references folders (id) on delete set null;
+alter table notes add column if not exists favorite boolean not null default false;
+alter table notes add column if not exists folder_id integer references folders (id) on delete set null;
+alter table cards add column if not exists favorite boolean not null default false;
+alter table cards add column if not exists folder_id integer references folders (id) on delete set null;
+
+create index if not exists credentials_folder_idx on credentials (user_name, folder_id);
+create index if not exists credentials_favorite_idx on credentials (user_name) where favorite;
+create index if not exists notes_folder_idx on notes (user_name, folder_id);
+create index if not exists notes_favorite_idx on notes (user_name) where favorite;
+create index if not exists cards_folder_idx on cards (user_name, folder_id);
+create index if not exists cards_favorite_idx on cards (user_name) where favorite;
+
+-- tags reference items of several tables, so they are deleted with the items by the trigger
+create or replace function delete_item_tags() returns trigger as $$
+begin
+    delete from item_tags where user_name = old.user_name and item_type = tg_argv[0] and item_id = old.id;
+    return old;
+end;
+$$ language plpgsql;
+
+create trigger credentials_tags_trigger after delete on credentials for each row execute function delete_item_tags('credentials');
+create trigger notes_tags_trigger after delete on notes for each row execute function delete_item_tags('notes');
+create trigger cards_tags_trigger after delete on cards for each row execute function delete_item_tags('cards');
\ No newline at end of file
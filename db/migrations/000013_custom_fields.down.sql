@@ -0,0 +1,6 @@
+drop trigger if exists credentials_fields_trigger on credentials;
+drop trigger if exists notes_fields_trigger on notes;
+drop trigger if exists cards_fields_trigger on cards;
+drop function if exists delete_item_fields();
+
+drop table if exists custom_fields;
\ No newline at end of file
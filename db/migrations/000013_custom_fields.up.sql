@@ -0,0 +1,22 @@
+create table if not exists custom_fields (
+    user_name text not null references registered_users (login) on delete cascade,
+    item_type text not null,
+    item_id integer not null,
+    position integer not null,
+    name text not null,
+    type text not null,
+    value text not null,
+    primary key (user_name, item_type, item_id, position)
+);
+
+-- fields reference items of several tables, so they are deleted with the items by the trigger
+create or replace function delete_item_fields() returns trigger as $$
+begin
+    delete from custom_fields where user_name = old.user_name and item_type = tg_argv[0] and item_id = old.id;
+    return old;
+end;
+$$ language plpgsql;
+
+create trigger credentials_fields_trigger after delete on credentials for each row execute function delete_item_fields('credentials');
+create trigger notes_fields_trigger after delete on notes for each row execute function delete_item_fields('notes');
+create trigger cards_fields_trigger after delete on cards for each row execute function delete_item_fields('cards');
\ No newline at end of file
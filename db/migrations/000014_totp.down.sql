@@ -0,0 +1 @@
+drop table if exists totp;
\ No newline at end of file
@@ -0,0 +1,15 @@
+create table if not exists totp (
+    id serial,
+    user_name text not null references registered_users (login) on delete cascade,
+    name text not null,
+    issuer text,
+    account text,
+    secret text not null,
+    digits integer not null,
+    period integer not null,
+    algorithm text not null,
+    metadata text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+    primary key (user_name, name)
+);
\ No newline at end of file
@@ -0,0 +1 @@
+drop table if exists ssh_keys;
\ No newline at end of file
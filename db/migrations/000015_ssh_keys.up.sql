@@ -0,0 +1,16 @@
+create table if not exists ssh_keys (
+    id serial,
+    user_name text not null references registered_users (login) on delete cascade,
+    name text not null,
+    type text not null,
+    public_key text not null,
+    fingerprint text not null,
+    private_key text not null,
+    comment text,
+    metadata text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+    primary key (user_name, name)
+);
+create index if not exists ssh_keys_search_fts_idx on ssh_keys using gin (to_tsvector('simple', name || ' ' || fingerprint || ' ' || public_key || ' ' || coalesce(metadata, '')));
+create index if not exists ssh_keys_search_trgm_idx on ssh_keys using gin ((name || ' ' || fingerprint || ' ' || public_key || ' ' || coalesce(metadata, '')) gin_trgm_ops);
\ No newline at end of file
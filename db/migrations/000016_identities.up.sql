@@ -0,0 +1,17 @@
+create table if not exists identities (
+    id serial,
+    user_name text not null references registered_users (login) on delete cascade,
+    name text not null,
+    document_type text not null,
+    full_name text,
+    number text not null,
+    country text,
+    issued_on date,
+    expires_on date,
+    scans text[] not null default '{}',
+    metadata text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+    primary key (user_name, name)
+);
+create index if not exists identities_expires_on_idx on identities (user_name, expires_on);
\ No newline at end of file
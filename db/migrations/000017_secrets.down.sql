@@ -0,0 +1 @@
+drop table if exists secrets;
\ No newline at end of file
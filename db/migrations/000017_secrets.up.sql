@@ -0,0 +1,10 @@
+create table if not exists secrets (
+    id serial,
+    user_name text not null references registered_users (login) on delete cascade,
+    name text not null,
+    data text not null,
+    metadata text,
+    created_at timestamptz not null default now(),
+    updated_at timestamptz not null default now(),
+    primary key (user_name, name)
+);
\ No newline at end of file
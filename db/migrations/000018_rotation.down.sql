@@ -0,0 +1,9 @@
+drop index if exists credentials_rotation_idx;
+drop index if exists cards_rotation_idx;
+
+alter table credentials drop column rotation_days;
+alter table credentials drop column password_changed_at;
+alter table credentials drop column rotation_notified_at;
+alter table cards drop column rotation_days;
+alter table cards drop column password_changed_at;
+alter table cards drop column rotation_notified_at;
\ No newline at end of file
@@ -0,0 +1,9 @@
+alter table credentials add column if not exists rotation_days integer check (rotation_days > 0);
+alter table credentials add column if not exists password_changed_at timestamptz not null default now();
+alter table credentials add column if not exists rotation_notified_at timestamptz;
+alter table cards add column if not exists rotation_days integer check (rotation_days > 0);
+alter table cards add column if not exists password_changed_at timestamptz not null default now();
+alter table cards add column if not exists rotation_notified_at timestamptz;
+
+create index if not exists credentials_rotation_idx on credentials (user_name, password_changed_at) where rotation_days is not null;
+create index if not exists cards_rotation_idx on cards (user_name, password_changed_at) where rotation_days is not null;
\ No newline at end of file
@@ -0,0 +1,4 @@
+drop index if exists cards_expiry_idx;
+
+alter table cards drop column expires_on;
+alter table cards drop column expiry_notified_on;
\ No newline at end of file
@@ -0,0 +1,4 @@
+alter table cards add column if not exists expires_on date;
+alter table cards add column if not exists expiry_notified_on date;
+
+create index if not exists cards_expiry_idx on cards (expires_on) where expires_on is not null;
\ No newline at end of file
@@ -0,0 +1,2 @@
+drop index if exists custom_fields_url_fts_idx;
+drop index if exists custom_fields_url_trgm_idx;
\ No newline at end of file
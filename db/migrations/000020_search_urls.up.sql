@@ -0,0 +1,2 @@
+create index if not exists custom_fields_url_fts_idx on custom_fields using gin (to_tsvector('simple', value)) where type = 'url';
+create index if not exists custom_fields_url_trgm_idx on custom_fields using gin (value gin_trgm_ops) where type = 'url';
\ No newline at end of file
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// ChangePassword is a method for replacing the password of registered user with the new one.
+// Like on registration only bcrypt hash of the password is stored. If the vault key is provided,
+// its password wrapping is replaced in the same transaction.
+func (d *db) ChangePassword(ctx context.Context, login string, newPassword string, key *internal.VaultKey) error {
+	hash, err := bcrypt.GenerateFromPassword([]byte(newPassword), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("this password is not allowed: %w", err)
+	}
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	changePasswordQuery := "update registered_users set password = $1 where login = $2"
+	res, err := tx.ExecContext(ctx, changePasswordQuery, hash, login)
+	if err != nil {
+		return fmt.Errorf("error while changing password for user %q: %w", login, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while changing password for user %q: %w", login, err)
+	}
+	if affected == 0 {
+		return ErrNoSuchUser
+	}
+	if key != nil {
+		rewrapKeyQuery := "update vault_keys set password_salt = $1, password_wrapped_key = $2 where login = $3"
+		if _, err = tx.ExecContext(ctx, rewrapKeyQuery, key.PasswordSalt, key.PasswordWrappedKey, login); err != nil {
+			return fmt.Errorf("error while updating vault key for user %q: %w", login, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return nil
+}
+
+// DeleteAccount is a method for deleting registered user with all his credentials, notes, cards and files.
+// Everything is deleted in a single transaction, two-factor settings and keys are removed by cascade.
+func (d *db) DeleteAccount(ctx context.Context, login string) error {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	for _, table := range []string{"credentials", "notes", "cards", "files"} {
+		if _, err = tx.ExecContext(ctx, fmt.Sprintf("delete from %s where user_name = $1", table), login); err != nil {
+			return fmt.Errorf("error while deleting %s for user %q: %w", table, login, err)
+		}
+	}
+	res, err := tx.ExecContext(ctx, "delete from registered_users where login = $1", login)
+	if err != nil {
+		return fmt.Errorf("error while deleting user %q: %w", login, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while deleting user %q: %w", login, err)
+	}
+	if affected == 0 {
+		return ErrNoSuchUser
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return nil
+}
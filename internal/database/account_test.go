@@ -0,0 +1,149 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDb_ChangePassword(t *testing.T) {
+	login := "brienne"
+	key := &internal.VaultKey{PasswordSalt: "c2FsdA==", PasswordWrappedKey: "d3JhcHBlZA=="}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		key           *internal.VaultKey
+		result        int64
+		storageError  error
+		expectedError string
+	}{
+		{
+			name:   "positive: password changed",
+			result: 1,
+		},
+		{
+			name:   "positive: password changed with vault key",
+			key:    key,
+			result: 1,
+		},
+		{
+			name:          "negative: no such user",
+			expectedError: ErrNoSuchUser.Error(),
+		},
+		{
+			name:          "negative: update error",
+			storageError:  errors.New("update error"),
+			expectedError: `error while changing password for user "brienne": update error`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer mockDB.Close()
+
+			mock.ExpectBegin()
+			expectation := mock.ExpectExec("update registered_users set password").
+				WithArgs(sqlmock.AnyArg(), login)
+			if tt.storageError != nil {
+				expectation.WillReturnError(tt.storageError)
+			} else {
+				expectation.WillReturnResult(sqlmock.NewResult(0, tt.result))
+			}
+			if tt.key != nil {
+				mock.ExpectExec("update vault_keys set password_salt").
+					WithArgs(tt.key.PasswordSalt, tt.key.PasswordWrappedKey, login).
+					WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+			if tt.expectedError != "" {
+				mock.ExpectRollback()
+			} else {
+				mock.ExpectCommit()
+			}
+
+			pg := db{
+				conn: mockDB,
+			}
+			err = pg.ChangePassword(ctx, login, "oathkeeper", tt.key)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDb_DeleteAccount(t *testing.T) {
+	login := "brienne"
+	ctx := context.Background()
+
+	t.Run("positive: account deleted", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("delete from credentials").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("delete from notes").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("delete from cards").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from files").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("delete from registered_users").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		pg := db{
+			conn: mockDB,
+		}
+		assert.NoError(t, pg.DeleteAccount(ctx, login))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: no such user", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("delete from credentials").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("delete from notes").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("delete from cards").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("delete from files").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("delete from registered_users").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn: mockDB,
+		}
+		assert.ErrorIs(t, pg.DeleteAccount(ctx, login), ErrNoSuchUser)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: nothing is deleted on error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("delete from credentials").WithArgs(login).WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("delete from notes").WithArgs(login).WillReturnError(errors.New("delete error"))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn: mockDB,
+		}
+		err = pg.DeleteAccount(ctx, login)
+		assert.EqualError(t, err, `error while deleting notes for user "brienne": delete error`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
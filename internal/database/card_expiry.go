@@ -0,0 +1,91 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/lib/pq"
+	"time"
+)
+
+// monthLayout is the format of the card expiry months.
+const monthLayout = "2006-01"
+
+// expiringCardsQuery selects the cards which are not valid at $1: a card is valid through the month of expires_on.
+const expiringCardsQuery = "select id, user_name, bank_name, right(number, 4), expires_on, (expires_on + interval '1 month')::timestamptz " +
+	"from cards where expires_on is not null and expires_on + interval '1 month' <= $1"
+
+// expiryMonth converts the expiry month (YYYY-MM) to the first day of the month for the queries.
+func expiryMonth(month *string) any {
+	if month == nil {
+		return nil
+	}
+	return *month + "-01"
+}
+
+// nullMonth converts the scanned expiry date to YYYY-MM month.
+func nullMonth(date sql.NullTime) *string {
+	if !date.Valid {
+		return nil
+	}
+	month := date.Time.Format(monthLayout)
+	return &month
+}
+
+// GetExpiringCards is a method for getting the bank cards of provided user which are not valid at the time,
+// ordered by the expiry month.
+func (d *db) GetExpiringCards(ctx context.Context, userName string, before time.Time) ([]internal.ExpiringCard, error) {
+	cards, err := d.expiringCards(ctx, expiringCardsQuery+" and user_name = $2 order by expires_on, bank_name, id", before, userName)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting expiring cards for user %q: %w", userName, err)
+	}
+	return cards, nil
+}
+
+// GetPendingCardExpiries is a method for getting the bank cards of all users which are not valid at the time,
+// whose owners were not notified about the current expiry month. Cards are ordered by user and expiry month.
+func (d *db) GetPendingCardExpiries(ctx context.Context, before time.Time) ([]internal.ExpiringCard, error) {
+	cards, err := d.expiringCards(ctx, expiringCardsQuery+" and expiry_notified_on is distinct from expires_on "+
+		"order by user_name, expires_on, id", before)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting pending card expiries: %w", err)
+	}
+	return cards, nil
+}
+
+// SetCardExpiryNotified is a method for marking the owners of the cards as notified about their expiry.
+// The cards are pending again once the expiry month changes.
+func (d *db) SetCardExpiryNotified(ctx context.Context, cards []internal.ExpiringCard) error {
+	ids := make([]int64, 0, len(cards))
+	for _, card := range cards {
+		ids = append(ids, card.ID)
+	}
+	if _, err := d.conn.ExecContext(ctx, "update cards set expiry_notified_on = expires_on where id = any($1)", pq.Array(ids)); err != nil {
+		return fmt.Errorf("error while marking cards as notified about expiry: %w", err)
+	}
+	return nil
+}
+
+func (d *db) expiringCards(ctx context.Context, query string, args ...any) ([]internal.ExpiringCard, error) {
+	rows, err := d.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var cards []internal.ExpiringCard
+	for rows.Next() {
+		var card internal.ExpiringCard
+		var expiresOn time.Time
+		if err = rows.Scan(&card.ID, &card.UserName, &card.BankName, &card.LastDigits, &expiresOn, &card.ExpiresAt); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after expiring cards query: %w", err)
+		}
+		card.ExpiresOn = expiresOn.Format(monthLayout)
+		cards = append(cards, card)
+	}
+	return cards, nil
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDb_GetExpiringCards(t *testing.T) {
+	userLogin := "olenna"
+	before := time.Date(2026, 11, 17, 0, 0, 0, 0, time.UTC)
+	expiresAt := time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_name", "bank_name", "right", "expires_on", "timestamptz"}
+	ctx := context.Background()
+
+	t.Run("positive: expiring cards", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("where expires_on is not null and expires_on + interval '1 month' <= $1 and user_name = $2 order by expires_on, bank_name, id")).
+			WithArgs(before, userLogin).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(4, userLogin, "iron bank", "4444", time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC), expiresAt))
+
+		pg := db{conn: mockDB}
+		cards, err := pg.GetExpiringCards(ctx, userLogin, before)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.ExpiringCard{{ID: 4, UserName: userLogin, BankName: "iron bank", LastDigits: "4444",
+			ExpiresOn: "2026-10", ExpiresAt: expiresAt}}, cards)
+	})
+	t.Run("negative: query error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select id").WillReturnError(errors.New("query error"))
+
+		pg := db{conn: mockDB}
+		_, err = pg.GetExpiringCards(ctx, userLogin, before)
+		assert.EqualError(t, err, "error while getting expiring cards for user \"olenna\": query error")
+	})
+	t.Run("positive: pending expiries", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("and expiry_notified_on is distinct from expires_on order by user_name, expires_on, id")).
+			WithArgs(before).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		pg := db{conn: mockDB}
+		cards, err := pg.GetPendingCardExpiries(ctx, before)
+		assert.NoError(t, err)
+		assert.Empty(t, cards)
+	})
+}
+
+func TestDb_SetCardExpiryNotified(t *testing.T) {
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+
+	mock.ExpectExec(regexp.QuoteMeta("update cards set expiry_notified_on = expires_on where id = any($1)")).
+		WithArgs("{4,5}").
+		WillReturnResult(sqlmock.NewResult(0, 2))
+
+	pg := db{conn: mockDB}
+	assert.NoError(t, pg.SetCardExpiryNotified(context.Background(), []internal.ExpiringCard{{ID: 4}, {ID: 5}}))
+}
+
+func TestDb_SaveCardWithExpiry(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	days := 180
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery("insert into cards").
+		WithArgs("olenna", "iron bank", "1111222233334444", sqlmock.AnyArg(), sqlmock.AnyArg(), nil, 180, "2027-03-01").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+	mock.ExpectCommit()
+
+	pg := db{
+		conn:          mockDB,
+		encriptionKey: key,
+		dataCipher:    c,
+	}
+	id, err := pg.SaveCard(context.Background(), internal.Card{
+		UserName:  "olenna",
+		BankName:  Ptr("iron bank"),
+		Number:    Ptr("1111222233334444"),
+		CV:        Ptr("123"),
+		Password:  Ptr("1234"),
+		ExpiresOn: Ptr("2027-03"),
+		Rotation:  internal.Rotation{RotationDays: &days},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, int64(1), id)
+}
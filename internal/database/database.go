@@ -11,6 +11,8 @@ import (
 	"github.com/kontik-pk/goph-keeper/internal"
 	_ "github.com/lib/pq"
 	"golang.org/x/crypto/bcrypt"
+	"sync"
+	"time"
 )
 
 type db struct {
@@ -42,25 +44,49 @@ func New(params internal.Params) (*db, error) {
 	return &pg, nil
 }
 
-// SaveNote is a method for saving provided notes (note title, content and probably metadata)
-// for authorized user in goph-keeper storage.
-func (d *db) SaveNote(ctx context.Context, noteRequest internal.Note) error {
+// SaveNote is a method for saving provided notes (note title, content, probably metadata and custom fields)
+// for authorized user in goph-keeper storage. The note and its fields are saved in one transaction,
+// the id of the saved note is returned.
+func (d *db) SaveNote(ctx context.Context, noteRequest internal.Note) (int64, error) {
 	encryptedContent, err := d.encryptAES(*noteRequest.Content)
 	if err != nil {
-		return fmt.Errorf("error encrypting your classified text: %w", err)
+		return 0, fmt.Errorf("error encrypting your classified text: %w", err)
 	}
-	saveNotesQuery := "insert into notes (user_name, title, content, metadata) values ($1, $2, $3, $4)"
-	if _, err = d.conn.ExecContext(ctx, saveNotesQuery, noteRequest.UserName, noteRequest.Title, encryptedContent, noteRequest.Metadata); err != nil {
-		return fmt.Errorf("error while saving note for user %q: %w", noteRequest.UserName, err)
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error while starting transaction: %w", err)
 	}
-	return nil
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id int64
+	saveNotesQuery := "insert into notes (user_name, title, content, metadata) values ($1, $2, $3, $4) returning id"
+	if err = tx.QueryRowContext(ctx, saveNotesQuery, noteRequest.UserName, noteRequest.Title, encryptedContent, noteRequest.Metadata).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "notes_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving note for user %q: %w", noteRequest.UserName, err)
+	}
+	if err = d.insertFields(ctx, tx, noteRequest.UserName, internal.ItemTypeNote, id, noteRequest.Fields); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return id, nil
 }
 
 // GetNotes is a method for getting notes (note title, content and probably metadata) for
 // provided authorized user from goph-keeper storage.
 func (d *db) GetNotes(ctx context.Context, noteRequest internal.Note) ([]internal.Note, error) {
 	args := []any{noteRequest.UserName}
-	getNoteQuery := "select user_name, title, content, metadata from notes where user_name = $1"
+	getNoteQuery := "select user_name, title, content, metadata, id from notes where user_name = $1"
+	if noteRequest.ID != 0 {
+		args = append(args, noteRequest.ID)
+		getNoteQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
 	if noteRequest.Title != nil {
 		args = append(args, *noteRequest.Title)
 		getNoteQuery += fmt.Sprintf(" and title = $%d", len(args))
@@ -76,9 +102,10 @@ func (d *db) GetNotes(ctx context.Context, noteRequest internal.Note) ([]interna
 
 	var notes []internal.Note
 	for rows.Next() {
+		var id int64
 		var userName, title, content string
 		var metadata sql.NullString
-		if err = rows.Scan(&userName, &title, &content, &metadata); err != nil {
+		if err = rows.Scan(&userName, &title, &content, &metadata, &id); err != nil {
 			return nil, fmt.Errorf("error while scanning rows after get user notes query: %w", err)
 		}
 		decryptedContent, err := d.decryptAES(content)
@@ -86,6 +113,7 @@ func (d *db) GetNotes(ctx context.Context, noteRequest internal.Note) ([]interna
 			return nil, fmt.Errorf("error while decrypting password: %w", err)
 		}
 		res := internal.Note{
+			ID:       id,
 			UserName: userName,
 			Title:    &title,
 			Content:  &decryptedContent,
@@ -101,53 +129,96 @@ func (d *db) GetNotes(ctx context.Context, noteRequest internal.Note) ([]interna
 	return notes, nil
 }
 
-// DeleteNotes is a method for deleting notes for provided user. Title is optional parameter.
+// DeleteNotes is a method for deleting notes for provided user. ID and title are optional parameters.
 func (d *db) DeleteNotes(ctx context.Context, noteRequest internal.Note) error {
 	args := []any{noteRequest.UserName}
 	deleteNotesQuery := "delete from notes where user_name= $1"
+	if noteRequest.ID != 0 {
+		args = append(args, noteRequest.ID)
+		deleteNotesQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
 	if noteRequest.Title != nil {
 		args = append(args, *noteRequest.Title)
-		deleteNotesQuery += " and title = $2"
+		deleteNotesQuery += fmt.Sprintf(" and title = $%d", len(args))
 	}
-	deleteCredsQuery := "delete from notes where user_name= $1 and title=$2"
-	if _, err := d.conn.ExecContext(ctx, deleteCredsQuery, args...); err != nil {
+	if _, err := d.conn.ExecContext(ctx, deleteNotesQuery, args...); err != nil {
 		return fmt.Errorf("error while deleting note for user %q: %w", noteRequest.UserName, err)
 	}
 	return nil
 }
 
 // UpdateNote is a method for updating note content for authorized user in goph-keeper storage.
+// The custom fields of the note are replaced in the same transaction if they are provided, empty fields remove them.
 func (d *db) UpdateNote(ctx context.Context, noteRequest internal.Note) error {
 	encryptedContent, err := d.encryptAES(*noteRequest.Content)
 	if err != nil {
 		return fmt.Errorf("error encrypting note content: %w", err)
 	}
-	updateNoteQuery := "update notes set content = $1, metadata = $2 where user_name = $3 and title = $4"
-	if _, err = d.conn.ExecContext(ctx, updateNoteQuery, encryptedContent, noteRequest.Metadata, noteRequest.UserName, *noteRequest.Title); err != nil {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	updateNoteQuery := "update notes set content = $1, metadata = $2, updated_at = now() where user_name = $3 and title = $4"
+	if _, err = tx.ExecContext(ctx, updateNoteQuery, encryptedContent, noteRequest.Metadata, noteRequest.UserName, *noteRequest.Title); err != nil {
 		return fmt.Errorf("error while updating note %q for user %q: %w", *noteRequest.Title, noteRequest.UserName, err)
 	}
+	if err = d.replaceFields(ctx, tx, noteRequest.UserName, internal.ItemTypeNote, noteRequest.ID, noteRequest.Fields); err != nil {
+		return err
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error while committing transaction: %w", err)
+	}
 	return nil
 }
 
-// SaveCredentials is a method for saving provided credentials (pair of login/password and probably metadata)
-// for authorized user in goph-keeper storage.
-func (d *db) SaveCredentials(ctx context.Context, credentialsRequest internal.Credentials) error {
+// SaveCredentials is a method for saving provided credentials (pair of login/password, probably metadata and custom fields)
+// for authorized user in goph-keeper storage. The credentials and their fields are saved in one transaction,
+// the id of the saved credentials is returned.
+func (d *db) SaveCredentials(ctx context.Context, credentialsRequest internal.Credentials) (int64, error) {
 	encryptedPassword, err := d.encryptAES(*credentialsRequest.Password)
 	if err != nil {
-		return fmt.Errorf("error encrypting your classified text: %w", err)
+		return 0, fmt.Errorf("error encrypting your classified text: %w", err)
 	}
-	saveCredsQuery := "insert into credentials (user_name, login, password, metadata) values ($1, $2, $3, $4)"
-	if _, err = d.conn.ExecContext(ctx, saveCredsQuery, credentialsRequest.UserName, *credentialsRequest.Login, encryptedPassword, credentialsRequest.Metadata); err != nil {
-		return fmt.Errorf("error while saving credentials for user %q: %w", credentialsRequest.UserName, err)
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error while starting transaction: %w", err)
 	}
-	return nil
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id int64
+	saveCredsQuery := "insert into credentials (user_name, login, password, metadata, rotation_days) values ($1, $2, $3, $4, $5) returning id"
+	if err = tx.QueryRowContext(ctx, saveCredsQuery, credentialsRequest.UserName, *credentialsRequest.Login, encryptedPassword, credentialsRequest.Metadata,
+		rotationDays(credentialsRequest.RotationDays)).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "credentials_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving credentials for user %q: %w", credentialsRequest.UserName, err)
+	}
+	if err = d.insertFields(ctx, tx, credentialsRequest.UserName, internal.ItemTypeCredentials, id, credentialsRequest.Fields); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return id, nil
 }
 
 // GetCredentials is a method for getting credentials (pair of login/password and probably metadata) for
 // provided authorized user from goph-keeper storage.
 func (d *db) GetCredentials(ctx context.Context, credentialsRequest internal.Credentials) ([]internal.Credentials, error) {
 	args := []any{credentialsRequest.UserName}
-	getCredsQuery := "select user_name, login, password, metadata from credentials where user_name = $1"
+	getCredsQuery := "select user_name, login, password, metadata, id from credentials where user_name = $1"
+	if credentialsRequest.ID != 0 {
+		args = append(args, credentialsRequest.ID)
+		getCredsQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
 	if credentialsRequest.Login != nil {
 		args = append(args, *credentialsRequest.Login)
 		getCredsQuery += fmt.Sprintf(" and login = $%d", len(args))
@@ -163,9 +234,10 @@ func (d *db) GetCredentials(ctx context.Context, credentialsRequest internal.Cre
 
 	var creds []internal.Credentials
 	for rows.Next() {
+		var id int64
 		var userName, login, password string
 		var metadata sql.NullString
-		if err = rows.Scan(&userName, &login, &password, &metadata); err != nil {
+		if err = rows.Scan(&userName, &login, &password, &metadata, &id); err != nil {
 			return nil, fmt.Errorf("error while scanning rows after get user credentials query: %w", err)
 		}
 		decryptedPassword, err := d.decryptAES(password)
@@ -173,6 +245,7 @@ func (d *db) GetCredentials(ctx context.Context, credentialsRequest internal.Cre
 			return nil, fmt.Errorf("error while decrypting password: %w", err)
 		}
 		res := internal.Credentials{
+			ID:       id,
 			UserName: userName,
 			Login:    &login,
 			Password: &decryptedPassword,
@@ -188,13 +261,17 @@ func (d *db) GetCredentials(ctx context.Context, credentialsRequest internal.Cre
 	return creds, nil
 }
 
-// DeleteCredentials is a method for deleting all credentials for provided user. Login is optional parameter.
+// DeleteCredentials is a method for deleting all credentials for provided user. ID and login are optional parameters.
 func (d *db) DeleteCredentials(ctx context.Context, credentialsRequest internal.Credentials) error {
 	args := []any{credentialsRequest.UserName}
 	deleteCredsQuery := "delete from credentials where user_name= $1"
+	if credentialsRequest.ID != 0 {
+		args = append(args, credentialsRequest.ID)
+		deleteCredsQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
 	if credentialsRequest.Login != nil {
 		args = append(args, *credentialsRequest.Login)
-		deleteCredsQuery += " and login=$2"
+		deleteCredsQuery += fmt.Sprintf(" and login = $%d", len(args))
 	}
 	if _, err := d.conn.ExecContext(ctx, deleteCredsQuery, args...); err != nil {
 		return fmt.Errorf("error while deleting credentials for user %q: %w", credentialsRequest.UserName, err)
@@ -203,42 +280,100 @@ func (d *db) DeleteCredentials(ctx context.Context, credentialsRequest internal.
 }
 
 // UpdateCredentials is a method for updating credentials (pair of login/password and probably metadata)
-// for authorized user in goph-keeper storage.
-func (d *db) UpdateCredentials(ctx context.Context, credentialsRequest internal.Credentials) error {
+// for authorized user in goph-keeper storage. The rotation policy is kept if it's not provided, zero days disable it.
+// The time of the password change is only updated if the password differs from the stored one,
+// the stored rotation policy is returned. The custom fields are replaced in the same transaction if they are provided.
+func (d *db) UpdateCredentials(ctx context.Context, credentialsRequest internal.Credentials) (internal.Rotation, error) {
 	encryptedPassword, err := d.encryptAES(*credentialsRequest.Password)
 	if err != nil {
-		return fmt.Errorf("error encrypting your classified text: %w", err)
+		return internal.Rotation{}, fmt.Errorf("error encrypting your classified text: %w", err)
 	}
-	updateCredsQuery := "update credentials set password = $1, metadata = $2 where user_name = $3 and login = $4"
-	if _, err = d.conn.ExecContext(ctx, updateCredsQuery, encryptedPassword, credentialsRequest.Metadata, credentialsRequest.UserName, credentialsRequest.Login); err != nil {
-		return fmt.Errorf("error while updating credentials for user %q: %w", credentialsRequest.UserName, err)
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while starting transaction: %w", err)
 	}
-	return nil
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var storedPassword string
+	getPasswordQuery := "select password from credentials where user_name = $1 and login = $2 for update"
+	err = tx.QueryRowContext(ctx, getPasswordQuery, credentialsRequest.UserName, credentialsRequest.Login).Scan(&storedPassword)
+	if errors.Is(err, sql.ErrNoRows) {
+		return internal.Rotation{}, nil
+	}
+	if err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while getting credentials for user %q: %w", credentialsRequest.UserName, err)
+	}
+	changed, err := d.passwordChanged(storedPassword, *credentialsRequest.Password)
+	if err != nil {
+		return internal.Rotation{}, err
+	}
+	var days sql.NullInt32
+	var passwordChangedAt time.Time
+	updateCredsQuery := "update credentials set password = $1, metadata = $2, updated_at = now(), " + rotationUpdate(6, 5) +
+		" where user_name = $3 and login = $4 returning rotation_days, password_changed_at"
+	if err = tx.QueryRowContext(ctx, updateCredsQuery, encryptedPassword, credentialsRequest.Metadata, credentialsRequest.UserName, credentialsRequest.Login,
+		rotationDays(credentialsRequest.RotationDays), changed).Scan(&days, &passwordChangedAt); err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while updating credentials for user %q: %w", credentialsRequest.UserName, err)
+	}
+	if err = d.replaceFields(ctx, tx, credentialsRequest.UserName, internal.ItemTypeCredentials, credentialsRequest.ID, credentialsRequest.Fields); err != nil {
+		return internal.Rotation{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return rotation(days, passwordChangedAt), nil
 }
 
-// SaveCard is a method for saving provided bank card (bank name, card number, cv, password probably metadata)
-// for authorized user in goph-keeper storage.
-func (d *db) SaveCard(ctx context.Context, cardRequest internal.Card) error {
+// SaveCard is a method for saving provided bank card (bank name, card number, cv, password, probably metadata and custom fields)
+// for authorized user in goph-keeper storage. The card and its fields are saved in one transaction,
+// the id of the saved card is returned.
+func (d *db) SaveCard(ctx context.Context, cardRequest internal.Card) (int64, error) {
 	encryptedPassword, err := d.encryptAES(*cardRequest.Password)
 	if err != nil {
-		return fmt.Errorf("error encrypting card password: %w", err)
+		return 0, fmt.Errorf("error encrypting card password: %w", err)
 	}
 	encryptedCV, err := d.encryptAES(*cardRequest.CV)
 	if err != nil {
-		return fmt.Errorf("error encrypting card password: %w", err)
+		return 0, fmt.Errorf("error encrypting card password: %w", err)
 	}
-	saveCardQuery := "insert into cards (user_name, bank_name, number, cv, password, metadata) values ($1, $2, $3, $4, $5, $6)"
-	if _, err = d.conn.ExecContext(ctx, saveCardQuery, cardRequest.UserName, *cardRequest.BankName, *cardRequest.Number, encryptedCV, encryptedPassword, cardRequest.Metadata); err != nil {
-		return fmt.Errorf("error while saving card data for user %q: %w", cardRequest.UserName, err)
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("error while starting transaction: %w", err)
 	}
-	return nil
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var id int64
+	saveCardQuery := "insert into cards (user_name, bank_name, number, cv, password, metadata, rotation_days, expires_on) values ($1, $2, $3, $4, $5, $6, $7, $8) returning id"
+	if err = tx.QueryRowContext(ctx, saveCardQuery, cardRequest.UserName, *cardRequest.BankName, *cardRequest.Number, encryptedCV, encryptedPassword, cardRequest.Metadata,
+		rotationDays(cardRequest.RotationDays), expiryMonth(cardRequest.ExpiresOn)).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "cards_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving card data for user %q: %w", cardRequest.UserName, err)
+	}
+	if err = d.insertFields(ctx, tx, cardRequest.UserName, internal.ItemTypeCard, id, cardRequest.Fields); err != nil {
+		return 0, err
+	}
+	if err = tx.Commit(); err != nil {
+		return 0, fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return id, nil
 }
 
 // GetCard is a method for getting user's bank cards (bank name, number, cv, password and probably metadata) for
 // provided authorized user from goph-keeper storage.
 func (d *db) GetCard(ctx context.Context, cardRequest internal.Card) ([]internal.Card, error) {
 	args := []any{cardRequest.UserName}
-	getCardsQuery := "select user_name, bank_name, number, cv, password, metadata from cards where user_name = $1"
+	getCardsQuery := "select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name = $1"
+	if cardRequest.ID != 0 {
+		args = append(args, cardRequest.ID)
+		getCardsQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
 	if cardRequest.BankName != nil {
 		args = append(args, *cardRequest.BankName)
 		getCardsQuery += fmt.Sprintf(" and bank_name = $%d", len(args))
@@ -258,9 +393,11 @@ func (d *db) GetCard(ctx context.Context, cardRequest internal.Card) ([]internal
 
 	var cards []internal.Card
 	for rows.Next() {
+		var id int64
 		var userName, bankName, number, cv, password string
 		var metadata sql.NullString
-		if err = rows.Scan(&userName, &bankName, &number, &cv, &password, &metadata); err != nil {
+		var expiresOn sql.NullTime
+		if err = rows.Scan(&userName, &bankName, &number, &cv, &password, &metadata, &id, &expiresOn); err != nil {
 			return nil, fmt.Errorf("error while scanning rows after get user notes query: %w", err)
 		}
 		decryptedPassword, err := d.decryptAES(password)
@@ -272,11 +409,13 @@ func (d *db) GetCard(ctx context.Context, cardRequest internal.Card) ([]internal
 			return nil, fmt.Errorf("error while decrypting password: %w", err)
 		}
 		res := internal.Card{
-			UserName: userName,
-			BankName: &bankName,
-			Number:   &number,
-			CV:       &decryptedCV,
-			Password: &decryptedPassword,
+			ID:        id,
+			UserName:  userName,
+			BankName:  &bankName,
+			Number:    &number,
+			CV:        &decryptedCV,
+			Password:  &decryptedPassword,
+			ExpiresOn: nullMonth(expiresOn),
 		}
 		if metadata.Valid {
 			res.Metadata = &metadata.String
@@ -289,10 +428,14 @@ func (d *db) GetCard(ctx context.Context, cardRequest internal.Card) ([]internal
 	return cards, nil
 }
 
-// DeleteCards is a method for deleting bank cards for provided user. Bank name and number are optional parameters.
+// DeleteCards is a method for deleting bank cards for provided user. ID, bank name and number are optional parameters.
 func (d *db) DeleteCards(ctx context.Context, cardRequest internal.Card) error {
 	args := []any{cardRequest.UserName}
 	deleteNotesQuery := "delete from cards where user_name= $1"
+	if cardRequest.ID != 0 {
+		args = append(args, cardRequest.ID)
+		deleteNotesQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
 	if cardRequest.Number != nil {
 		args = append(args, *cardRequest.Number)
 		deleteNotesQuery += fmt.Sprintf(" and number = $%d", len(args))
@@ -307,15 +450,69 @@ func (d *db) DeleteCards(ctx context.Context, cardRequest internal.Card) error {
 	return nil
 }
 
+// UpdateCard is a method for updating bank card cv, password and metadata for authorized user in goph-keeper storage.
+// The rotation policy and the expiry month are kept if they are not provided, zero days disable the rotation.
+// The time of the password change is only updated if the password differs from the stored one,
+// the stored rotation policy is returned. The custom fields are replaced in the same transaction if they are provided.
+func (d *db) UpdateCard(ctx context.Context, cardRequest internal.Card) (internal.Rotation, error) {
+	encryptedPassword, err := d.encryptAES(*cardRequest.Password)
+	if err != nil {
+		return internal.Rotation{}, fmt.Errorf("error encrypting card password: %w", err)
+	}
+	encryptedCV, err := d.encryptAES(*cardRequest.CV)
+	if err != nil {
+		return internal.Rotation{}, fmt.Errorf("error encrypting card cv: %w", err)
+	}
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	var storedPassword string
+	getPasswordQuery := "select password from cards where user_name = $1 and bank_name = $2 and number = $3 for update"
+	err = tx.QueryRowContext(ctx, getPasswordQuery, cardRequest.UserName, *cardRequest.BankName, *cardRequest.Number).Scan(&storedPassword)
+	if errors.Is(err, sql.ErrNoRows) {
+		return internal.Rotation{}, nil
+	}
+	if err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while getting card for user %q: %w", cardRequest.UserName, err)
+	}
+	changed, err := d.passwordChanged(storedPassword, *cardRequest.Password)
+	if err != nil {
+		return internal.Rotation{}, err
+	}
+	var days sql.NullInt32
+	var passwordChangedAt time.Time
+	updateCardQuery := "update cards set cv = $1, password = $2, metadata = $3, updated_at = now(), " + rotationUpdate(9, 7) +
+		", expires_on = coalesce($8, expires_on) where user_name = $4 and bank_name = $5 and number = $6 returning rotation_days, password_changed_at"
+	if err = tx.QueryRowContext(ctx, updateCardQuery, encryptedCV, encryptedPassword, cardRequest.Metadata, cardRequest.UserName, *cardRequest.BankName, *cardRequest.Number,
+		rotationDays(cardRequest.RotationDays), expiryMonth(cardRequest.ExpiresOn), changed).Scan(&days, &passwordChangedAt); err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while updating card for user %q: %w", cardRequest.UserName, err)
+	}
+	if err = d.replaceFields(ctx, tx, cardRequest.UserName, internal.ItemTypeCard, cardRequest.ID, cardRequest.Fields); err != nil {
+		return internal.Rotation{}, err
+	}
+	if err = tx.Commit(); err != nil {
+		return internal.Rotation{}, fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return rotation(days, passwordChangedAt), nil
+}
+
 // Login is a method for login user in goph-keeper system with provided login and password.
 // User logins are stored in the goph-keeper database as bcrypt hashes.
 // Provided password is hashed and the result is compared with the content from database.
+// Unknown users take the same time to check as the registered ones.
 func (d *db) Login(ctx context.Context, login string, password string) error {
 	getRegisteredUser := `select login, password from registered_users where login = $1`
 
 	var loginFromDB, passwordFromDB string
 	if err := d.conn.QueryRowContext(ctx, getRegisteredUser, login).Scan(&loginFromDB, &passwordFromDB); err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
+			// compare with a dummy hash anyway, so unknown users can't be told apart by response time
+			_ = bcrypt.CompareHashAndPassword(dummyHash(), []byte(password))
 			return ErrNoSuchUser
 		}
 		return fmt.Errorf("error while executing search query: %w", err)
@@ -326,6 +523,19 @@ func (d *db) Login(ctx context.Context, login string, password string) error {
 	return nil
 }
 
+var (
+	dummyHashOnce  sync.Once
+	dummyHashValue []byte
+)
+
+// dummyHash returns a bcrypt hash with the same cost as the hashes of registered users.
+func dummyHash() []byte {
+	dummyHashOnce.Do(func() {
+		dummyHashValue, _ = bcrypt.GenerateFromPassword([]byte("goph-keeper"), bcrypt.DefaultCost)
+	})
+	return dummyHashValue
+}
+
 // Register is a method for register new user in goph-keeper storage with provided credentials.
 func (d *db) Register(ctx context.Context, login string, password string) error {
 	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
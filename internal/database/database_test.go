@@ -3,12 +3,14 @@ package database
 import (
 	"context"
 	"crypto/aes"
+	"database/sql"
 	"errors"
 	"github.com/DATA-DOG/go-sqlmock"
 	"github.com/kontik-pk/goph-keeper/internal"
 	"github.com/stretchr/testify/assert"
 	"golang.org/x/crypto/bcrypt"
 	"testing"
+	"time"
 )
 
 func TestDb_Register(t *testing.T) {
@@ -153,18 +155,21 @@ func TestDb_GetCredentials(t *testing.T) {
 	t.Run("positive: success", func(t *testing.T) {
 		expected := []internal.Credentials{
 			{
+				ID:       1,
 				UserName: userLogin,
 				Login:    Ptr("killer"),
 				Password: Ptr("sansaisfreak"),
 				Metadata: Ptr("bla bla password"),
 			},
 			{
+				ID:       2,
 				UserName: userLogin,
 				Login:    Ptr("warrior"),
 				Password: Ptr("valarmorgulis"),
 				Metadata: Ptr("valar dohaeris"),
 			},
 			{
+				ID:       3,
 				UserName: userLogin,
 				Login:    Ptr("avenger"),
 				Password: Ptr("qwerty12"),
@@ -177,12 +182,12 @@ func TestDb_GetCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, login, password, metadata from credentials where user_name").
+		mock.ExpectQuery("select user_name, login, password, metadata, id from credentials where user_name").
 			WithArgs(userLogin).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "login", "password", "metadata"}).
-				AddRow(userLogin, "killer", "zwkcxfLKNXGHrfgP", "bla bla password").
-				AddRow(userLogin, "warrior", "ygke1+HOKWWSvfUNiQ==", "valar dohaeris").
-				AddRow(userLogin, "avenger", "zR8XxOfadyU=", nil))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "login", "password", "metadata", "id"}).
+				AddRow(userLogin, "killer", "zwkcxfLKNXGHrfgP", "bla bla password", 1).
+				AddRow(userLogin, "warrior", "ygke1+HOKWWSvfUNiQ==", "valar dohaeris", 2).
+				AddRow(userLogin, "avenger", "zR8XxOfadyU=", nil, 3))
 
 		pg := db{
 			conn:          mockDB,
@@ -203,9 +208,9 @@ func TestDb_GetCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, login, password, metadata from credentials where user_name").
+		mock.ExpectQuery("select user_name, login, password, metadata, id from credentials where user_name").
 			WithArgs(userLogin).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "login", "password", "metadata"}))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "login", "password", "metadata", "id"}))
 
 		pg := db{
 			conn:          mockDB,
@@ -224,7 +229,7 @@ func TestDb_GetCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, login, password, metadata from credentials where user_name").
+		mock.ExpectQuery("select user_name, login, password, metadata, id from credentials where user_name").
 			WithArgs(userLogin).
 			WillReturnError(errors.New("query error"))
 
@@ -258,17 +263,20 @@ func TestDb_SaveCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into credentials").
-			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", credentials.Metadata).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into credentials").
+			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", credentials.Metadata, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
 			encriptionKey: key,
 			dataCipher:    c,
 		}
-		err = pg.SaveCredentials(ctx, credentials)
+		id, err := pg.SaveCredentials(ctx, credentials)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
 	})
 
 	t.Run("positive: without metadata", func(t *testing.T) {
@@ -278,9 +286,11 @@ func TestDb_SaveCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into credentials").
-			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", nil).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into credentials").
+			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
@@ -288,8 +298,80 @@ func TestDb_SaveCredentials(t *testing.T) {
 			dataCipher:    c,
 		}
 		credentials.Metadata = nil
-		err = pg.SaveCredentials(ctx, credentials)
+		id, err := pg.SaveCredentials(ctx, credentials)
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("positive: with fields in one transaction", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into credentials").
+			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec("insert into custom_fields").WithArgs(credentials.UserName, internal.ItemTypeCredentials, int64(3), 0, "house", internal.FieldTypeText, "lannister").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		withFields := credentials
+		withFields.Metadata = nil
+		withFields.Fields = []internal.Field{{Name: "house", Type: internal.FieldTypeText, Value: "lannister"}}
+		id, err := pg.SaveCredentials(ctx, withFields)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(3), id)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: fields error rolls back the credentials", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into credentials").
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3))
+		mock.ExpectExec("insert into custom_fields").WillReturnError(errors.New("insert error"))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		withFields := credentials
+		withFields.Fields = []internal.Field{{Name: "house", Type: internal.FieldTypeText, Value: "lannister"}}
+		_, err = pg.SaveCredentials(ctx, withFields)
+		assert.EqualError(t, err, "error while saving field of credentials for user \"tirion\": insert error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: duplicate login", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into credentials").
+			WillReturnError(ErrDublicateKey{Key: "credentials_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveCredentials(ctx, credentials)
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
 	})
 	t.Run("negative: exec error", func(t *testing.T) {
 		mockDB, mock, err := sqlmock.New()
@@ -298,8 +380,9 @@ func TestDb_SaveCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into credentials").
-			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", nil).
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into credentials").
+			WithArgs(credentials.UserName, credentials.Login, "1QQdwPbUL3mQ", nil, nil).
 			WillReturnError(errors.New("exec error"))
 
 		pg := db{
@@ -308,7 +391,7 @@ func TestDb_SaveCredentials(t *testing.T) {
 			dataCipher:    c,
 		}
 		credentials.Metadata = nil
-		err = pg.SaveCredentials(ctx, credentials)
+		_, err = pg.SaveCredentials(ctx, credentials)
 		assert.EqualError(t, err, "error while saving credentials for user \"tirion\": exec error")
 	})
 }
@@ -408,6 +491,7 @@ func TestDb_UpdateCredentials(t *testing.T) {
 		Metadata: Ptr("password for brothels"),
 	}
 	ctx := context.Background()
+	changedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
 
 	t.Run("positive: with metadata", func(t *testing.T) {
 		mockDB, mock, err := sqlmock.New()
@@ -416,16 +500,20 @@ func TestDb_UpdateCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("update credentials set password").
-			WithArgs("1QQdwPbUL3mQ", credentials.Metadata, credentials.UserName, credentials.Login).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from credentials").WithArgs(credentials.UserName, credentials.Login).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("1QQdwPbUL3mQ"))
+		mock.ExpectQuery("update credentials set password").
+			WithArgs("1QQdwPbUL3mQ", credentials.Metadata, credentials.UserName, credentials.Login, nil, false).
+			WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(nil, changedAt))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
 			encriptionKey: key,
 			dataCipher:    c,
 		}
-		err = pg.UpdateCredentials(ctx, credentials)
+		_, err = pg.UpdateCredentials(ctx, credentials)
 		assert.NoError(t, err)
 	})
 
@@ -436,9 +524,13 @@ func TestDb_UpdateCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("update credentials set password").
-			WithArgs("1QQdwPbUL3mQ", nil, credentials.UserName, credentials.Login).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from credentials").WithArgs(credentials.UserName, credentials.Login).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("1QQdwPbUL3mQ"))
+		mock.ExpectQuery("update credentials set password").
+			WithArgs("1QQdwPbUL3mQ", nil, credentials.UserName, credentials.Login, nil, false).
+			WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(nil, changedAt))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
@@ -446,7 +538,7 @@ func TestDb_UpdateCredentials(t *testing.T) {
 			dataCipher:    c,
 		}
 		credentials.Metadata = nil
-		err = pg.UpdateCredentials(ctx, credentials)
+		_, err = pg.UpdateCredentials(ctx, credentials)
 		assert.NoError(t, err)
 	})
 	t.Run("negative: exec error", func(t *testing.T) {
@@ -456,9 +548,13 @@ func TestDb_UpdateCredentials(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("update credentials set password").
-			WithArgs("1QQdwPbUL3mQ", nil, credentials.UserName, credentials.Login).
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from credentials").WithArgs(credentials.UserName, credentials.Login).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("1QQdwPbUL3mQ"))
+		mock.ExpectQuery("update credentials set password").
+			WithArgs("1QQdwPbUL3mQ", nil, credentials.UserName, credentials.Login, nil, false).
 			WillReturnError(errors.New("exec error"))
+		mock.ExpectRollback()
 
 		pg := db{
 			conn:          mockDB,
@@ -466,9 +562,51 @@ func TestDb_UpdateCredentials(t *testing.T) {
 			dataCipher:    c,
 		}
 		credentials.Metadata = nil
-		err = pg.UpdateCredentials(ctx, credentials)
+		_, err = pg.UpdateCredentials(ctx, credentials)
 		assert.EqualError(t, err, "error while updating credentials for user \"tirion\": exec error")
 	})
+	t.Run("positive: no such credentials", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from credentials").WithArgs(credentials.UserName, credentials.Login).
+			WillReturnError(sql.ErrNoRows)
+		mock.ExpectRollback()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.UpdateCredentials(ctx, credentials)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: stored password can't be decrypted", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from credentials").WithArgs(credentials.UserName, credentials.Login).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("not base64!"))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.UpdateCredentials(ctx, credentials)
+		assert.ErrorContains(t, err, "error while decrypting stored password")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
 }
 
 func TestDb_SaveNote(t *testing.T) {
@@ -489,17 +627,20 @@ func TestDb_SaveNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into notes").
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into notes").
 			WithArgs(note.UserName, *note.Title, "zwcf07PNKWOQ6PoLlO+3uU4=", note.Metadata).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
 			encriptionKey: key,
 			dataCipher:    c,
 		}
-		err = pg.SaveNote(ctx, note)
+		id, err := pg.SaveNote(ctx, note)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
 	})
 
 	t.Run("positive: without metadata", func(t *testing.T) {
@@ -509,9 +650,11 @@ func TestDb_SaveNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into notes").
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into notes").
 			WithArgs(note.UserName, *note.Title, "zwcf07PNKWOQ6PoLlO+3uU4=", nil).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
@@ -519,8 +662,28 @@ func TestDb_SaveNote(t *testing.T) {
 			dataCipher:    c,
 		}
 		note.Metadata = nil
-		err = pg.SaveNote(ctx, note)
+		id, err := pg.SaveNote(ctx, note)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("negative: duplicate title", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into notes").
+			WillReturnError(ErrDublicateKey{Key: "notes_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveNote(ctx, note)
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
 	})
 	t.Run("negative: exec error", func(t *testing.T) {
 		mockDB, mock, err := sqlmock.New()
@@ -529,7 +692,8 @@ func TestDb_SaveNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into notes").
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into notes").
 			WithArgs(note.UserName, note.Title, "zwcf07PNKWOQ6PoLlO+3uU4=", nil).
 			WillReturnError(errors.New("exec error"))
 
@@ -539,7 +703,7 @@ func TestDb_SaveNote(t *testing.T) {
 			dataCipher:    c,
 		}
 		note.Metadata = nil
-		err = pg.SaveNote(ctx, note)
+		_, err = pg.SaveNote(ctx, note)
 		assert.EqualError(t, err, "error while saving note for user \"podric\": exec error")
 	})
 }
@@ -553,18 +717,21 @@ func TestDb_GetNote(t *testing.T) {
 	t.Run("positive: without title", func(t *testing.T) {
 		expected := []internal.Note{
 			{
+				ID:       1,
 				UserName: userLogin,
 				Title:    Ptr("notes from dorne"),
 				Content:  Ptr("some lovely notes"),
 				Metadata: Ptr("love"),
 			},
 			{
+				ID:       2,
 				UserName: userLogin,
 				Title:    Ptr("notes from king's landing"),
 				Content:  Ptr("some not lovely notes"),
 				Metadata: Ptr("my worst days"),
 			},
 			{
+				ID:       3,
 				UserName: userLogin,
 				Title:    Ptr("my dear diary"),
 				Content:  Ptr("personal notes"),
@@ -577,12 +744,12 @@ func TestDb_GetNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, title, content, metadata from notes where user_name").
+		mock.ExpectQuery("select user_name, title, content, metadata, id from notes where user_name").
 			WithArgs(userLogin).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "title", "content", "metadata"}).
-				AddRow(userLogin, "notes from dorne", "zwcf07PPKWGQpOBElPSmsjQ=", "love").
-				AddRow(userLogin, "notes from king's landing", "zwcf07PNKWPVpPYSn/er95LFBKDJ", "my worst days").
-				AddRow(userLogin, "my dear diary", "zA0AxfzNJ3vVpvYQn+g=", nil))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "title", "content", "metadata", "id"}).
+				AddRow(userLogin, "notes from dorne", "zwcf07PPKWGQpOBElPSmsjQ=", "love", 1).
+				AddRow(userLogin, "notes from king's landing", "zwcf07PNKWPVpPYSn/er95LFBKDJ", "my worst days", 2).
+				AddRow(userLogin, "my dear diary", "zA0AxfzNJ3vVpvYQn+g=", nil, 3))
 
 		pg := db{
 			conn:          mockDB,
@@ -598,6 +765,7 @@ func TestDb_GetNote(t *testing.T) {
 	t.Run("positive: with title", func(t *testing.T) {
 		expected := []internal.Note{
 			{
+				ID:       1,
 				UserName: userLogin,
 				Title:    Ptr("notes from dorne"),
 				Content:  Ptr("some lovely notes"),
@@ -611,10 +779,10 @@ func TestDb_GetNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, title, content, metadata from notes where user_name").
+		mock.ExpectQuery("select user_name, title, content, metadata, id from notes where user_name").
 			WithArgs(userLogin, Ptr("notes from dorne")).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "title", "content", "metadata"}).
-				AddRow(userLogin, "notes from dorne", "zwcf07PPKWGQpOBElPSmsjQ=", "love"))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "title", "content", "metadata", "id"}).
+				AddRow(userLogin, "notes from dorne", "zwcf07PPKWGQpOBElPSmsjQ=", "love", 1))
 
 		pg := db{
 			conn:          mockDB,
@@ -635,9 +803,9 @@ func TestDb_GetNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, title, content, metadata from notes where user_name").
+		mock.ExpectQuery("select user_name, title, content, metadata, id from notes where user_name").
 			WithArgs(userLogin).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "title", "content", "metadata"}))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "title", "content", "metadata", "id"}))
 
 		pg := db{
 			conn:          mockDB,
@@ -656,7 +824,7 @@ func TestDb_GetNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, title, content, metadata from notes where user_name").
+		mock.ExpectQuery("select user_name, title, content, metadata, id from notes where user_name").
 			WithArgs(userLogin).
 			WillReturnError(errors.New("query error"))
 
@@ -755,9 +923,11 @@ func TestDb_UpdateNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
+		mock.ExpectBegin()
 		mock.ExpectExec("update notes set content").
 			WithArgs("zwcf07PAKnKDretEjvO7uXwo", note.Metadata, note.UserName, *note.Title).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
@@ -775,9 +945,11 @@ func TestDb_UpdateNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
+		mock.ExpectBegin()
 		mock.ExpectExec("update notes set content").
 			WithArgs("zwcf07PAKnKDretEjvO7uXwo", nil, note.UserName, note.Title).
 			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
@@ -795,9 +967,11 @@ func TestDb_UpdateNote(t *testing.T) {
 		}
 		defer mockDB.Close()
 
+		mock.ExpectBegin()
 		mock.ExpectExec("update notes set content").
 			WithArgs("zwcf07PAKnKDretEjvO7uXwo", nil, note.UserName, note.Title).
 			WillReturnError(errors.New("exec error"))
+		mock.ExpectRollback()
 
 		pg := db{
 			conn:          mockDB,
@@ -830,17 +1004,20 @@ func TestDb_SaveCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into cards").
-			WithArgs(card.UserName, *card.BankName, *card.Number, "jVpB", "0A0V1/Da", *card.Metadata).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into cards").
+			WithArgs(card.UserName, *card.BankName, *card.Number, "jVpB", "0A0V1/Da", *card.Metadata, nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
 			encriptionKey: key,
 			dataCipher:    c,
 		}
-		err = pg.SaveCard(ctx, card)
+		id, err := pg.SaveCard(ctx, card)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
 	})
 
 	t.Run("positive: without metadata", func(t *testing.T) {
@@ -850,9 +1027,11 @@ func TestDb_SaveCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into cards").
-			WithArgs(card.UserName, *card.BankName, *card.Number, "jVpB", "0A0V1/Da", nil).
-			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into cards").
+			WithArgs(card.UserName, *card.BankName, *card.Number, "jVpB", "0A0V1/Da", nil, nil, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectCommit()
 
 		pg := db{
 			conn:          mockDB,
@@ -860,8 +1039,28 @@ func TestDb_SaveCard(t *testing.T) {
 			dataCipher:    c,
 		}
 		card.Metadata = nil
-		err = pg.SaveCard(ctx, card)
+		id, err := pg.SaveCard(ctx, card)
 		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("negative: duplicate number", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into cards").
+			WillReturnError(ErrDublicateKey{Key: "cards_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveCard(ctx, card)
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
 	})
 	t.Run("negative: exec error", func(t *testing.T) {
 		mockDB, mock, err := sqlmock.New()
@@ -870,8 +1069,9 @@ func TestDb_SaveCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectExec("insert into cards").
-			WithArgs(card.UserName, *card.BankName, *card.Number, "jVpB", "0A0V1/Da", nil).
+		mock.ExpectBegin()
+		mock.ExpectQuery("insert into cards").
+			WithArgs(card.UserName, *card.BankName, *card.Number, "jVpB", "0A0V1/Da", nil, nil, nil).
 			WillReturnError(errors.New("exec error"))
 
 		pg := db{
@@ -880,7 +1080,7 @@ func TestDb_SaveCard(t *testing.T) {
 			dataCipher:    c,
 		}
 		card.Metadata = nil
-		err = pg.SaveCard(ctx, card)
+		_, err = pg.SaveCard(ctx, card)
 		assert.EqualError(t, err, "error while saving card data for user \"Tywin\": exec error")
 	})
 }
@@ -894,6 +1094,7 @@ func TestDb_GetCard(t *testing.T) {
 	t.Run("positive: without bank name and title", func(t *testing.T) {
 		expected := []internal.Card{
 			{
+				ID:       1,
 				UserName: userLogin,
 				BankName: Ptr("alpha"),
 				Number:   Ptr("9999333344446666"),
@@ -902,6 +1103,7 @@ func TestDb_GetCard(t *testing.T) {
 				Metadata: Ptr("red bank"),
 			},
 			{
+				ID:       2,
 				UserName: userLogin,
 				BankName: Ptr("tinkoff"),
 				Number:   Ptr("5555444433337777"),
@@ -910,6 +1112,7 @@ func TestDb_GetCard(t *testing.T) {
 				Metadata: Ptr("black bank"),
 			},
 			{
+				ID:       3,
 				UserName: userLogin,
 				BankName: Ptr("sber"),
 				Number:   Ptr("6666555544440000"),
@@ -924,12 +1127,12 @@ func TestDb_GetCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata from cards where user_name").
+		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name").
 			WithArgs(userLogin).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata"}).
-				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank").
-				AddRow(userLogin, "tinkoff", "5555444433337777", "hV1G", "zgkfxfba", "black bank").
-				AddRow(userLogin, "sber", "6666555544440000", "iFFA", "zR8XxOfa", nil))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata", "id", "expires_on"}).
+				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank", 1, nil).
+				AddRow(userLogin, "tinkoff", "5555444433337777", "hV1G", "zgkfxfba", "black bank", 2, nil).
+				AddRow(userLogin, "sber", "6666555544440000", "iFFA", "zR8XxOfa", nil, 3, nil))
 
 		pg := db{
 			conn:          mockDB,
@@ -945,6 +1148,7 @@ func TestDb_GetCard(t *testing.T) {
 	t.Run("positive: with bank name", func(t *testing.T) {
 		expected := []internal.Card{
 			{
+				ID:       1,
 				UserName: userLogin,
 				BankName: Ptr("alpha"),
 				Number:   Ptr("9999333344446666"),
@@ -960,10 +1164,10 @@ func TestDb_GetCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata from cards where user_name").
+		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name").
 			WithArgs(userLogin, "alpha").
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata"}).
-				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank"))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata", "id", "expires_on"}).
+				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank", 1, nil))
 
 		pg := db{
 			conn:          mockDB,
@@ -980,6 +1184,7 @@ func TestDb_GetCard(t *testing.T) {
 	t.Run("positive: with number", func(t *testing.T) {
 		expected := []internal.Card{
 			{
+				ID:       1,
 				UserName: userLogin,
 				BankName: Ptr("alpha"),
 				Number:   Ptr("9999333344446666"),
@@ -995,10 +1200,10 @@ func TestDb_GetCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata from cards where user_name").
+		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name").
 			WithArgs(userLogin, "9999333344446666").
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata"}).
-				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank"))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata", "id", "expires_on"}).
+				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank", 1, nil))
 
 		pg := db{
 			conn:          mockDB,
@@ -1015,6 +1220,7 @@ func TestDb_GetCard(t *testing.T) {
 	t.Run("positive: with bank name and number", func(t *testing.T) {
 		expected := []internal.Card{
 			{
+				ID:       1,
 				UserName: userLogin,
 				BankName: Ptr("alpha"),
 				Number:   Ptr("9999333344446666"),
@@ -1030,10 +1236,10 @@ func TestDb_GetCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata from cards where user_name").
+		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name").
 			WithArgs(userLogin, "alpha", "9999333344446666").
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata"}).
-				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank"))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata", "id", "expires_on"}).
+				AddRow(userLogin, "alpha", "9999333344446666", "j1pD", "1Rod2PHMNHmQ", "red bank", 1, nil))
 
 		pg := db{
 			conn:          mockDB,
@@ -1055,9 +1261,9 @@ func TestDb_GetCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata from cards where user_name").
+		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name").
 			WithArgs(userLogin).
-			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata"}))
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata", "id", "expires_on"}))
 
 		pg := db{
 			conn:          mockDB,
@@ -1076,7 +1282,7 @@ func TestDb_GetCard(t *testing.T) {
 		}
 		defer mockDB.Close()
 
-		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata from cards where user_name").
+		mock.ExpectQuery("select user_name, bank_name, number, cv, password, metadata, id, expires_on from cards where user_name").
 			WithArgs(userLogin).
 			WillReturnError(errors.New("query error"))
 
@@ -1181,3 +1387,90 @@ func TestDb_DeleteCards(t *testing.T) {
 func Ptr(s string) *string {
 	return &s
 }
+
+func TestDb_UpdateCard(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	card := internal.Card{
+		UserName: "theon",
+		BankName: Ptr("alpha"),
+		Number:   Ptr("9999333344446666"),
+		CV:       Ptr("123"),
+		Password: Ptr("reek"),
+		Metadata: Ptr("iron bank"),
+	}
+	ctx := context.Background()
+	changedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	t.Run("positive: card updated", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from cards").WithArgs(card.UserName, *card.BankName, *card.Number).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("yAAX2f0="))
+		mock.ExpectQuery("update cards set cv").
+			WithArgs(sqlmock.AnyArg(), sqlmock.AnyArg(), card.Metadata, card.UserName, *card.BankName, *card.Number, nil, nil, true).
+			WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(90, changedAt))
+		mock.ExpectCommit()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		rotation, err := pg.UpdateCard(ctx, card)
+		assert.NoError(t, err)
+		days := 90
+		assert.Equal(t, internal.Rotation{RotationDays: &days, PasswordChangedAt: &changedAt}, rotation)
+	})
+	t.Run("positive: same password keeps the time of the change", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from cards").WithArgs(card.UserName, *card.BankName, *card.Number).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("zg0X3Q=="))
+		mock.ExpectQuery("update cards set cv").
+			WithArgs(sqlmock.AnyArg(), "zg0X3Q==", card.Metadata, card.UserName, *card.BankName, *card.Number, nil, nil, false).
+			WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(nil, changedAt))
+		mock.ExpectCommit()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.UpdateCard(ctx, card)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: exec error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from cards").
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("yAAX2f0="))
+		mock.ExpectQuery("update cards set cv").
+			WillReturnError(errors.New("exec error"))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.UpdateCard(ctx, card)
+		assert.EqualError(t, err, "error while updating card for user \"theon\": exec error")
+	})
+}
@@ -14,8 +14,16 @@ func (m ErrDublicateKey) Error() string {
 }
 
 var (
-	ErrUserAlreadyExists  = errors.New("user already exists")
-	ErrNoSuchUser         = errors.New("no such user")
-	ErrInvalidCredentials = errors.New("incorrect password")
-	ErrNoData             = errors.New("no data for user")
+	ErrUserAlreadyExists   = errors.New("user already exists")
+	ErrNoSuchUser          = errors.New("no such user")
+	ErrInvalidCredentials  = errors.New("incorrect password")
+	ErrNoData              = errors.New("no data for user")
+	ErrTwoFactorEnabled    = errors.New("two-factor authentication is already enabled")
+	ErrInvalidRecoveryCode = errors.New("invalid recovery code")
+	ErrKeyAlreadyExists    = errors.New("key is already registered")
+	ErrItemAlreadyExists   = errors.New("item already exists")
+	ErrInvalidCursor       = errors.New("invalid cursor")
+	ErrInvalidSort         = errors.New("invalid sort field")
+	ErrInvalidItemType     = errors.New("invalid item type")
+	ErrFolderCycle         = errors.New("folder can't be moved into itself or its subfolders")
 )
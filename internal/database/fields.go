@@ -0,0 +1,71 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/lib/pq"
+)
+
+// replaceFields replaces the custom fields of the item in the transaction of the caller, so the item is updated
+// together with its fields. Nil fields are kept, empty fields remove them.
+func (d *db) replaceFields(ctx context.Context, tx *sql.Tx, userName string, itemType string, id int64, fields []internal.Field) error {
+	if fields == nil {
+		return nil
+	}
+	deleteFieldsQuery := "delete from custom_fields where user_name = $1 and item_type = $2 and item_id = $3"
+	if _, err := tx.ExecContext(ctx, deleteFieldsQuery, userName, itemType, id); err != nil {
+		return fmt.Errorf("error while deleting fields of %s for user %q: %w", itemType, userName, err)
+	}
+	return d.insertFields(ctx, tx, userName, itemType, id, fields)
+}
+
+// insertFields saves the custom fields of the item in the transaction of the caller,
+// so the item and its fields are written together.
+func (d *db) insertFields(ctx context.Context, tx *sql.Tx, userName string, itemType string, id int64, fields []internal.Field) error {
+	for position, field := range fields {
+		value := field.Value
+		if field.Type == internal.FieldTypeHidden {
+			var err error
+			if value, err = d.encryptAES(value); err != nil {
+				return fmt.Errorf("error while encrypting hidden field: %w", err)
+			}
+		}
+		saveFieldQuery := "insert into custom_fields (user_name, item_type, item_id, position, name, type, value) values ($1, $2, $3, $4, $5, $6, $7)"
+		if _, err := tx.ExecContext(ctx, saveFieldQuery, userName, itemType, id, position, field.Name, field.Type, value); err != nil {
+			return fmt.Errorf("error while saving field of %s for user %q: %w", itemType, userName, err)
+		}
+	}
+	return nil
+}
+
+// GetFields is a method for getting the custom fields of the user's items by their ids.
+// Items without fields are missing in the result.
+func (d *db) GetFields(ctx context.Context, userName string, itemType string, ids []int64) (map[int64][]internal.Field, error) {
+	getFieldsQuery := "select item_id, name, type, value from custom_fields where user_name = $1 and item_type = $2 and item_id = any($3) order by item_id, position"
+	rows, err := d.conn.QueryContext(ctx, getFieldsQuery, userName, itemType, pq.Array(ids))
+	if err != nil {
+		return nil, fmt.Errorf("error while getting fields of %s for user %q: %w", itemType, userName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	fields := make(map[int64][]internal.Field)
+	for rows.Next() {
+		var id int64
+		var field internal.Field
+		if err = rows.Scan(&id, &field.Name, &field.Type, &field.Value); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get fields query: %w", err)
+		}
+		if field.Type == internal.FieldTypeHidden {
+			if field.Value, err = d.decryptAES(field.Value); err != nil {
+				return nil, fmt.Errorf("error while decrypting hidden field: %w", err)
+			}
+		}
+		fields[id] = append(fields[id], field)
+	}
+	return fields, nil
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDb_Fields(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "tyrion"
+	ctx := context.Background()
+
+	t.Run("positive: fields replaced with the note, hidden value encrypted", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("update notes set content").WithArgs("zR8XxOfa", nil, userLogin, "gold").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from custom_fields").WithArgs(userLogin, internal.ItemTypeNote, int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectExec("insert into custom_fields").WithArgs(userLogin, internal.ItemTypeNote, int64(7), 0, "house", internal.FieldTypeText, "lannister").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into custom_fields").WithArgs(userLogin, internal.ItemTypeNote, int64(7), 1, "PIN", internal.FieldTypeHidden, "zR8XxOfadyU=").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		err = pg.UpdateNote(ctx, internal.Note{ID: 7, UserName: userLogin, Title: Ptr("gold"), Content: Ptr("qwerty"), Fields: []internal.Field{
+			{Name: "house", Type: internal.FieldTypeText, Value: "lannister"},
+			{Name: "PIN", Type: internal.FieldTypeHidden, Value: "qwerty12"},
+		}})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("positive: empty fields removed with the card", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from cards").WithArgs(userLogin, "iron bank", "1111222233334444").
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("zR8XxOfa"))
+		mock.ExpectQuery("update cards set cv").
+			WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(nil, time.Now()))
+		mock.ExpectExec("delete from custom_fields").WithArgs(userLogin, internal.ItemTypeCard, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 2))
+		mock.ExpectCommit()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.UpdateCard(ctx, internal.Card{ID: 5, UserName: userLogin, BankName: Ptr("iron bank"), Number: Ptr("1111222233334444"),
+			CV: Ptr("123"), Password: Ptr("qwerty"), Fields: []internal.Field{}})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: credentials not updated without their fields", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select password from credentials").WithArgs(userLogin, Ptr("gold")).
+			WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("zR8XxOfa"))
+		mock.ExpectQuery("update credentials set password").
+			WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(nil, time.Now()))
+		mock.ExpectExec("delete from custom_fields").WithArgs(userLogin, internal.ItemTypeCredentials, int64(3)).
+			WillReturnError(errors.New("delete error"))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.UpdateCredentials(ctx, internal.Credentials{ID: 3, UserName: userLogin, Login: Ptr("gold"), Password: Ptr("qwerty"), Fields: []internal.Field{}})
+		assert.EqualError(t, err, "error while deleting fields of credentials for user \"tyrion\": delete error")
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("positive: fields of the items, hidden value decrypted", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select item_id, name, type, value from custom_fields").WithArgs(userLogin, internal.ItemTypeNote, "{1,2}").
+			WillReturnRows(sqlmock.NewRows([]string{"item_id", "name", "type", "value"}).
+				AddRow(1, "house", internal.FieldTypeText, "lannister").
+				AddRow(1, "PIN", internal.FieldTypeHidden, "zR8XxOfadyU=").
+				AddRow(2, "paid", internal.FieldTypeBoolean, "true"))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		fields, err := pg.GetFields(ctx, userLogin, internal.ItemTypeNote, []int64{1, 2})
+		assert.NoError(t, err)
+		assert.Equal(t, map[int64][]internal.Field{
+			1: {{Name: "house", Type: internal.FieldTypeText, Value: "lannister"}, {Name: "PIN", Type: internal.FieldTypeHidden, Value: "qwerty12"}},
+			2: {{Name: "paid", Type: internal.FieldTypeBoolean, Value: "true"}},
+		}, fields)
+	})
+}
@@ -0,0 +1,107 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+)
+
+// SaveFile is a method for saving provided file of authorized user in goph-keeper storage.
+// The content is encrypted the same way as the other user's data.
+// The id of the saved file is returned.
+func (d *db) SaveFile(ctx context.Context, file internal.File, content []byte) (int64, error) {
+	encryptedContent, err := d.encryptAES(string(content))
+	if err != nil {
+		return 0, fmt.Errorf("error encrypting file content: %w", err)
+	}
+	var id int64
+	saveFileQuery := "insert into files (user_name, name, content, size, metadata) values ($1, $2, $3, $4, $5) returning id"
+	if err = d.conn.QueryRowContext(ctx, saveFileQuery, file.UserName, file.Name, encryptedContent, len(content), file.Metadata).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "files_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving file for user %q: %w", file.UserName, err)
+	}
+	return id, nil
+}
+
+// GetFiles is a method for getting files (name, size and probably metadata) of provided user without their content.
+// ID and name are optional parameters.
+func (d *db) GetFiles(ctx context.Context, fileRequest internal.File) ([]internal.File, error) {
+	args := []any{fileRequest.UserName}
+	getFilesQuery := "select id, user_name, name, size, metadata from files where user_name = $1"
+	if fileRequest.ID != 0 {
+		args = append(args, fileRequest.ID)
+		getFilesQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
+	if fileRequest.Name != nil {
+		args = append(args, *fileRequest.Name)
+		getFilesQuery += fmt.Sprintf(" and name = $%d", len(args))
+	}
+	rows, err := d.conn.QueryContext(ctx, getFilesQuery, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting files for user %q: %w", fileRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var files []internal.File
+	for rows.Next() {
+		var file internal.File
+		var name string
+		var metadata sql.NullString
+		if err = rows.Scan(&file.ID, &file.UserName, &name, &file.Size, &metadata); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user files query: %w", err)
+		}
+		file.Name = &name
+		if metadata.Valid {
+			file.Metadata = &metadata.String
+		}
+		files = append(files, file)
+	}
+	if len(files) == 0 {
+		return nil, ErrNoData
+	}
+	return files, nil
+}
+
+// GetFileContent is a method for getting decrypted content of the user's file by id.
+func (d *db) GetFileContent(ctx context.Context, fileRequest internal.File) ([]byte, error) {
+	getContentQuery := "select content from files where user_name = $1 and id = $2"
+
+	var content string
+	if err := d.conn.QueryRowContext(ctx, getContentQuery, fileRequest.UserName, fileRequest.ID).Scan(&content); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, ErrNoData
+		}
+		return nil, fmt.Errorf("error while getting file %d for user %q: %w", fileRequest.ID, fileRequest.UserName, err)
+	}
+	decryptedContent, err := d.decryptAES(content)
+	if err != nil {
+		return nil, fmt.Errorf("error while decrypting file content: %w", err)
+	}
+	return []byte(decryptedContent), nil
+}
+
+// DeleteFiles is a method for deleting files of provided user. ID and name are optional parameters.
+func (d *db) DeleteFiles(ctx context.Context, fileRequest internal.File) error {
+	args := []any{fileRequest.UserName}
+	deleteFilesQuery := "delete from files where user_name = $1"
+	if fileRequest.ID != 0 {
+		args = append(args, fileRequest.ID)
+		deleteFilesQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
+	if fileRequest.Name != nil {
+		args = append(args, *fileRequest.Name)
+		deleteFilesQuery += fmt.Sprintf(" and name = $%d", len(args))
+	}
+	if _, err := d.conn.ExecContext(ctx, deleteFilesQuery, args...); err != nil {
+		return fmt.Errorf("error while deleting files for user %q: %w", fileRequest.UserName, err)
+	}
+	return nil
+}
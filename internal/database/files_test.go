@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDb_SaveFile(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	name := "passport.pdf"
+	content := []byte("%PDF-1.7 some content")
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		storageError  error
+		expectedError string
+	}{
+		{
+			name: "positive: file saved",
+		},
+		{
+			name:          "negative: file exists",
+			storageError:  ErrDublicateKey{Key: "files_pkey"},
+			expectedError: ErrItemAlreadyExists.Error(),
+		},
+		{
+			name:          "negative: insert error",
+			storageError:  errors.New("insert error"),
+			expectedError: `error while saving file for user "sansa": insert error`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer mockDB.Close()
+
+			pg := db{conn: mockDB, encriptionKey: key, dataCipher: c}
+			encrypted, err := pg.encryptAES(string(content))
+			assert.NoError(t, err)
+			expectation := mock.ExpectQuery("insert into files").
+				WithArgs("sansa", &name, encrypted, len(content), nil)
+			if tt.storageError != nil {
+				expectation.WillReturnError(tt.storageError)
+			} else {
+				expectation.WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+			}
+
+			id, err := pg.SaveFile(ctx, internal.File{UserName: "sansa", Name: &name}, content)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+				assert.Equal(t, int64(1), id)
+			}
+			assert.NoError(t, mock.ExpectationsWereMet())
+		})
+	}
+}
+
+func TestDb_GetFiles(t *testing.T) {
+	name := "passport.pdf"
+	metadata := "scan"
+	ctx := context.Background()
+
+	t.Run("positive: files by name", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		rows := sqlmock.NewRows([]string{"id", "user_name", "name", "size", "metadata"}).
+			AddRow(3, "sansa", name, 21, metadata)
+		mock.ExpectQuery("select id, user_name, name, size, metadata from files where user_name = \\$1 and name = \\$2").
+			WithArgs("sansa", name).
+			WillReturnRows(rows)
+
+		pg := db{conn: mockDB}
+		files, err := pg.GetFiles(ctx, internal.File{UserName: "sansa", Name: &name})
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.File{{ID: 3, UserName: "sansa", Name: &name, Size: 21, Metadata: &metadata}}, files)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: no files", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select id, user_name, name, size, metadata from files where user_name = \\$1 and id = \\$2").
+			WithArgs("sansa", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "name", "size", "metadata"}))
+
+		pg := db{conn: mockDB}
+		_, err = pg.GetFiles(ctx, internal.File{UserName: "sansa", ID: 3})
+		assert.ErrorIs(t, err, ErrNoData)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDb_GetFileContent(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	content := []byte("%PDF-1.7 some content")
+	ctx := context.Background()
+
+	t.Run("positive: content decrypted", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		pg := db{conn: mockDB, encriptionKey: key, dataCipher: c}
+		encrypted, err := pg.encryptAES(string(content))
+		assert.NoError(t, err)
+		mock.ExpectQuery("select content from files").
+			WithArgs("sansa", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"content"}).AddRow(encrypted))
+
+		got, err := pg.GetFileContent(ctx, internal.File{UserName: "sansa", ID: 3})
+		assert.NoError(t, err)
+		assert.Equal(t, content, got)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: no such file", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select content from files").
+			WithArgs("sansa", int64(3)).
+			WillReturnRows(sqlmock.NewRows([]string{"content"}))
+
+		pg := db{conn: mockDB, encriptionKey: key, dataCipher: c}
+		_, err = pg.GetFileContent(ctx, internal.File{UserName: "sansa", ID: 3})
+		assert.ErrorIs(t, err, ErrNoData)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDb_DeleteFiles(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("positive: file deleted by id", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from files where user_name = \\$1 and id = \\$2").
+			WithArgs("sansa", int64(3)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		pg := db{conn: mockDB}
+		assert.NoError(t, pg.DeleteFiles(ctx, internal.File{UserName: "sansa", ID: 3}))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: delete error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from files").
+			WithArgs("sansa").
+			WillReturnError(errors.New("delete error"))
+
+		pg := db{conn: mockDB}
+		err = pg.DeleteFiles(ctx, internal.File{UserName: "sansa"})
+		assert.EqualError(t, err, `error while deleting files for user "sansa": delete error`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
@@ -0,0 +1,132 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/lib/pq"
+	"time"
+)
+
+// SaveIdentity is a method for saving the identity document of authorized user, the number is encrypted.
+// Names of the documents are unique for the user, the id of the saved document is returned.
+func (d *db) SaveIdentity(ctx context.Context, identity internal.Identity) (int64, error) {
+	encryptedNumber, err := d.encryptAES(*identity.Number)
+	if err != nil {
+		return 0, fmt.Errorf("error while encrypting document number: %w", err)
+	}
+	scans := identity.Scans
+	if scans == nil {
+		scans = []string{}
+	}
+	var id int64
+	saveIdentityQuery := "insert into identities (user_name, name, document_type, full_name, number, country, issued_on, expires_on, scans, metadata) " +
+		"values ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10) returning id"
+	if err = d.conn.QueryRowContext(ctx, saveIdentityQuery, identity.UserName, *identity.Name, identity.DocumentType, identity.FullName,
+		encryptedNumber, identity.Country, identity.IssuedOn, identity.ExpiresOn, pq.Array(scans), identity.Metadata).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "identities_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving identity for user %q: %w", identity.UserName, err)
+	}
+	return id, nil
+}
+
+// GetIdentities is a method for getting the identity documents of provided user with decrypted numbers.
+// ID, name and document type are optional parameters. With expiresBefore (YYYY-MM-DD) only the documents
+// expiring before the date are returned ordered by the expiry date, otherwise the documents are ordered by name.
+func (d *db) GetIdentities(ctx context.Context, identityRequest internal.Identity, expiresBefore string) ([]internal.Identity, error) {
+	args := []any{identityRequest.UserName}
+	getIdentitiesQuery := "select id, user_name, name, document_type, full_name, number, country, issued_on, expires_on, scans, metadata, " +
+		"created_at, updated_at from identities where user_name = $1"
+	if identityRequest.ID != 0 {
+		args = append(args, identityRequest.ID)
+		getIdentitiesQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
+	if identityRequest.Name != nil {
+		args = append(args, *identityRequest.Name)
+		getIdentitiesQuery += fmt.Sprintf(" and name = $%d", len(args))
+	}
+	if identityRequest.DocumentType != "" {
+		args = append(args, identityRequest.DocumentType)
+		getIdentitiesQuery += fmt.Sprintf(" and document_type = $%d", len(args))
+	}
+	order := " order by name"
+	if expiresBefore != "" {
+		args = append(args, expiresBefore)
+		getIdentitiesQuery += fmt.Sprintf(" and expires_on < $%d", len(args))
+		order = " order by expires_on, name"
+	}
+	rows, err := d.conn.QueryContext(ctx, getIdentitiesQuery+order, args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting identities for user %q: %w", identityRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var identities []internal.Identity
+	for rows.Next() {
+		var identity internal.Identity
+		var name, number string
+		var fullName, country, metadata sql.NullString
+		var issuedOn, expiresOn sql.NullTime
+		var scans []string
+		var createdAt, updatedAt time.Time
+		if err = rows.Scan(&identity.ID, &identity.UserName, &name, &identity.DocumentType, &fullName, &number, &country,
+			&issuedOn, &expiresOn, pq.Array(&scans), &metadata, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user identities query: %w", err)
+		}
+		decryptedNumber, err := d.decryptAES(number)
+		if err != nil {
+			return nil, fmt.Errorf("error while decrypting document number: %w", err)
+		}
+		identity.Name = &name
+		identity.Number = &decryptedNumber
+		identity.FullName = fullName.String
+		identity.Country = country.String
+		identity.IssuedOn = nullDate(issuedOn)
+		identity.ExpiresOn = nullDate(expiresOn)
+		if len(scans) > 0 {
+			identity.Scans = scans
+		}
+		identity.CreatedAt = &createdAt
+		identity.UpdatedAt = &updatedAt
+		if metadata.Valid {
+			identity.Metadata = &metadata.String
+		}
+		identities = append(identities, identity)
+	}
+	if len(identities) == 0 {
+		return nil, ErrNoData
+	}
+	return identities, nil
+}
+
+// DeleteIdentity is a method for deleting the identity document of provided user by id, ErrNoData is returned if there is no such one.
+func (d *db) DeleteIdentity(ctx context.Context, identityRequest internal.Identity) error {
+	res, err := d.conn.ExecContext(ctx, "delete from identities where user_name = $1 and id = $2", identityRequest.UserName, identityRequest.ID)
+	if err != nil {
+		return fmt.Errorf("error while deleting identity for user %q: %w", identityRequest.UserName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while deleting identity for user %q: %w", identityRequest.UserName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	return nil
+}
+
+// nullDate formats the date column in YYYY-MM-DD format, nil for null.
+func nullDate(date sql.NullTime) *string {
+	if !date.Valid {
+		return nil
+	}
+	formatted := date.Time.Format(time.DateOnly)
+	return &formatted
+}
@@ -0,0 +1,125 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDb_Identities(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "samwell"
+	name := "passport"
+	number := "qwerty12"
+	issued := "2020-05-01"
+	expires := "2030-04-30"
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	columns := []string{"id", "user_name", "name", "document_type", "full_name", "number", "country", "issued_on", "expires_on", "scans",
+		"metadata", "created_at", "updated_at"}
+	ctx := context.Background()
+
+	t.Run("positive: identity saved with encrypted number", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into identities").
+			WithArgs(userLogin, name, "passport", "Samwell Tarly", "zR8XxOfadyU=", "WS", &issued, &expires, `{"passport.jpg"}`, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		id, err := pg.SaveIdentity(ctx, internal.Identity{UserName: userLogin, Name: &name, DocumentType: "passport", FullName: "Samwell Tarly",
+			Number: &number, Country: "WS", IssuedOn: &issued, ExpiresOn: &expires, Scans: []string{"passport.jpg"}})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("negative: duplicate name", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into identities").WillReturnError(ErrDublicateKey{Key: "identities_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveIdentity(ctx, internal.Identity{UserName: userLogin, Name: &name, Number: &number})
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+	})
+	t.Run("positive: identities expiring before the date", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from identities where user_name = \\$1 and document_type = \\$2 and expires_on < \\$3 order by expires_on, name").
+			WithArgs(userLogin, "passport", "2031-01-01").
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(3, userLogin, name, "passport", "Samwell Tarly", "zR8XxOfadyU=", "WS", time.Date(2020, 5, 1, 0, 0, 0, 0, time.UTC),
+					time.Date(2030, 4, 30, 0, 0, 0, 0, time.UTC), "{passport.jpg}", nil, created, created))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		identities, err := pg.GetIdentities(ctx, internal.Identity{UserName: userLogin, DocumentType: "passport"}, "2031-01-01")
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.Identity{{
+			ID:           3,
+			UserName:     userLogin,
+			Name:         &name,
+			DocumentType: "passport",
+			FullName:     "Samwell Tarly",
+			Number:       &number,
+			Country:      "WS",
+			IssuedOn:     &issued,
+			ExpiresOn:    &expires,
+			Scans:        []string{"passport.jpg"},
+			CreatedAt:    &created,
+			UpdatedAt:    &created,
+		}}, identities)
+	})
+	t.Run("negative: no identities", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from identities where user_name = \\$1 order by name").WithArgs(userLogin).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		pg := db{conn: mockDB}
+		_, err = pg.GetIdentities(ctx, internal.Identity{UserName: userLogin}, "")
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+	t.Run("negative: no identity to delete", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from identities").WithArgs(userLogin, int64(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		pg := db{conn: mockDB}
+		assert.ErrorIs(t, pg.DeleteIdentity(ctx, internal.Identity{UserName: userLogin, ID: 3}), ErrNoData)
+	})
+}
@@ -0,0 +1,280 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/lib/pq"
+	"sort"
+	"strings"
+)
+
+// labelledTypes are the types of the items with labels and custom fields, the tables are named after the types.
+// Files, TOTP, SSH keys, identities and secrets have neither: their tables have no label columns
+// and the triggers deleting tags and fields with the items are created only for these tables.
+var labelledTypes = map[string]bool{
+	internal.ItemTypeCredentials: true,
+	internal.ItemTypeNote:        true,
+	internal.ItemTypeCard:        true,
+}
+
+// checkLabelled returns ErrInvalidItemType for the items which can't have labels and custom fields.
+func checkLabelled(itemType string) error {
+	if !labelledTypes[itemType] {
+		return fmt.Errorf("%w %q: only credentials, notes and cards have labels and custom fields", ErrInvalidItemType, itemType)
+	}
+	return nil
+}
+
+// labelColumns selects the labels of the items of the table, tags are aggregated into an array.
+func labelColumns(table string) string {
+	return fmt.Sprintf(", favorite, folder_id, array(select tag from item_tags where item_tags.user_name = %[1]s.user_name "+
+		"and item_type = '%[1]s' and item_id = %[1]s.id order by tag) as tags", table)
+}
+
+// labels converts the scanned label columns.
+func labels(favorite bool, folderID sql.NullInt64, tags pq.StringArray) internal.Labels {
+	res := internal.Labels{Favorite: favorite}
+	if folderID.Valid {
+		res.FolderID = &folderID.Int64
+	}
+	if len(tags) > 0 {
+		res.Tags = tags
+	}
+	return res
+}
+
+// normalizeTags trims the tags and removes empty and repeated ones.
+func normalizeTags(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	var res []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		res = append(res, tag)
+	}
+	sort.Strings(res)
+	return res
+}
+
+// labelsQuery adds the tags, folder and favourite filters to the query selecting the items of the user ($1) from the table.
+func labelsQuery(query string, args []any, table string, opts internal.ListOptions) (string, []any) {
+	if tags := normalizeTags(opts.Tags); len(tags) > 0 {
+		args = append(args, pq.Array(tags), len(tags))
+		query += fmt.Sprintf(" and id in (select item_id from item_tags where user_name = $1 and item_type = '%s' "+
+			"and tag = any($%d) group by item_id having count(*) = $%d)", table, len(args)-1, len(args))
+	}
+	if opts.FolderID != nil {
+		args = append(args, *opts.FolderID)
+		query += fmt.Sprintf(" and folder_id in (with recursive subfolders as (select id from folders where user_name = $1 and id = $%d "+
+			"union all select folders.id from folders join subfolders on folders.parent_id = subfolders.id) select id from subfolders)", len(args))
+	}
+	if opts.Favorite != nil {
+		args = append(args, *opts.Favorite)
+		query += fmt.Sprintf(" and favorite = $%d", len(args))
+	}
+	return query, args
+}
+
+// SetLabels is a method for replacing the tags, the folder and the favourite flag of the user's item.
+// The folder must belong to the user, items of other types than credentials, notes and cards are rejected with ErrInvalidItemType.
+func (d *db) SetLabels(ctx context.Context, userName string, itemType string, id int64, itemLabels internal.Labels) error {
+	if err := checkLabelled(itemType); err != nil {
+		return err
+	}
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	if itemLabels.FolderID != nil {
+		var folderID int64
+		err = tx.QueryRowContext(ctx, "select id from folders where user_name = $1 and id = $2", userName, *itemLabels.FolderID).Scan(&folderID)
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrNoData
+		}
+		if err != nil {
+			return fmt.Errorf("error while getting folder for user %q: %w", userName, err)
+		}
+	}
+	setLabelsQuery := fmt.Sprintf("update %s set favorite = $1, folder_id = $2 where user_name = $3 and id = $4", itemType)
+	res, err := tx.ExecContext(ctx, setLabelsQuery, itemLabels.Favorite, itemLabels.FolderID, userName, id)
+	if err != nil {
+		return fmt.Errorf("error while setting labels of %s for user %q: %w", itemType, userName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while setting labels of %s for user %q: %w", itemType, userName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	deleteTagsQuery := "delete from item_tags where user_name = $1 and item_type = $2 and item_id = $3"
+	if _, err = tx.ExecContext(ctx, deleteTagsQuery, userName, itemType, id); err != nil {
+		return fmt.Errorf("error while deleting tags of %s for user %q: %w", itemType, userName, err)
+	}
+	for _, tag := range normalizeTags(itemLabels.Tags) {
+		saveTagQuery := "insert into item_tags (user_name, item_type, item_id, tag) values ($1, $2, $3, $4)"
+		if _, err = tx.ExecContext(ctx, saveTagQuery, userName, itemType, id, tag); err != nil {
+			return fmt.Errorf("error while saving tag of %s for user %q: %w", itemType, userName, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return nil
+}
+
+// GetTags is a method for getting all the tags of the user's items in alphabetical order.
+func (d *db) GetTags(ctx context.Context, userName string) ([]string, error) {
+	rows, err := d.conn.QueryContext(ctx, "select distinct tag from item_tags where user_name = $1 order by tag", userName)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting tags for user %q: %w", userName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var tags []string
+	for rows.Next() {
+		var tag string
+		if err = rows.Scan(&tag); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user tags query: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+	return tags, nil
+}
+
+// SaveFolder is a method for creating the folder of the user, the id of the new folder is returned.
+// Names are unique among the subfolders of the same parent.
+func (d *db) SaveFolder(ctx context.Context, folder internal.Folder) (int64, error) {
+	if folder.ParentID != nil {
+		if _, err := d.folderAncestors(ctx, folder.UserName, *folder.ParentID); err != nil {
+			return 0, err
+		}
+	}
+	var id int64
+	saveFolderQuery := "insert into folders (user_name, name, parent_id) values ($1, $2, $3) returning id"
+	if err := d.conn.QueryRowContext(ctx, saveFolderQuery, folder.UserName, folder.Name, folder.ParentID).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "folders_name_idx"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving folder for user %q: %w", folder.UserName, err)
+	}
+	return id, nil
+}
+
+// GetFolders is a method for getting all the folders of the user, parents go before their subfolders.
+func (d *db) GetFolders(ctx context.Context, userName string) ([]internal.Folder, error) {
+	getFoldersQuery := "select id, user_name, name, parent_id from folders where user_name = $1 order by id"
+	rows, err := d.conn.QueryContext(ctx, getFoldersQuery, userName)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting folders for user %q: %w", userName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var folders []internal.Folder
+	for rows.Next() {
+		var folder internal.Folder
+		var parentID sql.NullInt64
+		if err = rows.Scan(&folder.ID, &folder.UserName, &folder.Name, &parentID); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user folders query: %w", err)
+		}
+		if parentID.Valid {
+			folder.ParentID = &parentID.Int64
+		}
+		folders = append(folders, folder)
+	}
+	return folders, nil
+}
+
+// UpdateFolder is a method for renaming the folder of the user and moving it to another parent.
+// The folder can't be moved into itself or its subfolders.
+func (d *db) UpdateFolder(ctx context.Context, folder internal.Folder) error {
+	if folder.ParentID != nil {
+		ancestors, err := d.folderAncestors(ctx, folder.UserName, *folder.ParentID)
+		if err != nil {
+			return err
+		}
+		for _, id := range ancestors {
+			if id == folder.ID {
+				return ErrFolderCycle
+			}
+		}
+	}
+	updateFolderQuery := "update folders set name = $1, parent_id = $2 where user_name = $3 and id = $4"
+	res, err := d.conn.ExecContext(ctx, updateFolderQuery, folder.Name, folder.ParentID, folder.UserName, folder.ID)
+	if err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "folders_name_idx"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return ErrItemAlreadyExists
+		}
+		return fmt.Errorf("error while updating folder for user %q: %w", folder.UserName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while updating folder for user %q: %w", folder.UserName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	return nil
+}
+
+// DeleteFolder is a method for deleting the folder of the user with its subfolders.
+// The items of deleted folders are kept without a folder.
+func (d *db) DeleteFolder(ctx context.Context, userName string, id int64) error {
+	res, err := d.conn.ExecContext(ctx, "delete from folders where user_name = $1 and id = $2", userName, id)
+	if err != nil {
+		return fmt.Errorf("error while deleting folder for user %q: %w", userName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while deleting folder for user %q: %w", userName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	return nil
+}
+
+// folderAncestors returns the ids of the folder and all its parents, ErrNoData if the user has no such folder.
+func (d *db) folderAncestors(ctx context.Context, userName string, id int64) ([]int64, error) {
+	ancestorsQuery := "with recursive ancestors as (select id, parent_id from folders where user_name = $1 and id = $2 " +
+		"union all select folders.id, folders.parent_id from folders join ancestors on folders.id = ancestors.parent_id) select id from ancestors"
+	rows, err := d.conn.QueryContext(ctx, ancestorsQuery, userName, id)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting folder for user %q: %w", userName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var ancestors []int64
+	for rows.Next() {
+		var ancestor int64
+		if err = rows.Scan(&ancestor); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get folder query: %w", err)
+		}
+		ancestors = append(ancestors, ancestor)
+	}
+	if len(ancestors) == 0 {
+		return nil, ErrNoData
+	}
+	return ancestors, nil
+}
@@ -0,0 +1,189 @@
+package database
+
+import (
+	"context"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDb_SetLabels(t *testing.T) {
+	userLogin := "tyrion"
+	folderID := int64(3)
+	ctx := context.Background()
+
+	t.Run("positive: labels replaced", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select id from folders").WithArgs(userLogin, folderID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(folderID))
+		mock.ExpectExec("update notes set favorite = \\$1, folder_id = \\$2").WithArgs(true, folderID, userLogin, int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from item_tags").WithArgs(userLogin, internal.ItemTypeNote, int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into item_tags").WithArgs(userLogin, internal.ItemTypeNote, int64(7), "hand").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("insert into item_tags").WithArgs(userLogin, internal.ItemTypeNote, int64(7), "wine").
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectCommit()
+
+		pg := db{conn: mockDB}
+		err = pg.SetLabels(ctx, userLogin, internal.ItemTypeNote, 7, internal.Labels{
+			Tags:     []string{"wine", " hand ", "", "wine"},
+			FolderID: &folderID,
+			Favorite: true,
+		})
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: folder of another user", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectQuery("select id from folders").WithArgs(userLogin, folderID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}))
+		mock.ExpectRollback()
+
+		pg := db{conn: mockDB}
+		err = pg.SetLabels(ctx, userLogin, internal.ItemTypeCard, 7, internal.Labels{FolderID: &folderID})
+		assert.ErrorIs(t, err, ErrNoData)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: no such item", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("update credentials set favorite").WithArgs(false, nil, userLogin, int64(7)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		pg := db{conn: mockDB}
+		err = pg.SetLabels(ctx, userLogin, internal.ItemTypeCredentials, 7, internal.Labels{})
+		assert.ErrorIs(t, err, ErrNoData)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: item type without labels", func(t *testing.T) {
+		pg := db{}
+		err := pg.SetLabels(ctx, userLogin, "registered_users", 7, internal.Labels{})
+		assert.ErrorIs(t, err, ErrInvalidItemType)
+	})
+	t.Run("negative: secrets have no labels", func(t *testing.T) {
+		pg := db{}
+		err := pg.SetLabels(ctx, userLogin, internal.ItemTypeSecret, 7, internal.Labels{Favorite: true})
+		assert.ErrorIs(t, err, ErrInvalidItemType)
+		assert.EqualError(t, err, `invalid item type "secrets": only credentials, notes and cards have labels and custom fields`)
+	})
+}
+
+func TestDb_Folders(t *testing.T) {
+	userLogin := "tyrion"
+	parentID := int64(1)
+	ctx := context.Background()
+
+	t.Run("positive: subfolder saved", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("with recursive ancestors").WithArgs(userLogin, parentID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+		mock.ExpectQuery("insert into folders").WithArgs(userLogin, "casterly rock", &parentID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(2))
+
+		pg := db{conn: mockDB}
+		id, err := pg.SaveFolder(ctx, internal.Folder{UserName: userLogin, Name: "casterly rock", ParentID: &parentID})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), id)
+	})
+	t.Run("negative: duplicate folder", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into folders").WillReturnError(ErrDublicateKey{Key: "folders_name_idx"})
+
+		pg := db{conn: mockDB}
+		_, err = pg.SaveFolder(ctx, internal.Folder{UserName: userLogin, Name: "casterly rock"})
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+	})
+	t.Run("negative: folder moved into its subfolder", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		subfolderID := int64(3)
+		mock.ExpectQuery("with recursive ancestors").WithArgs(userLogin, subfolderID).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(3).AddRow(2).AddRow(1))
+
+		pg := db{conn: mockDB}
+		err = pg.UpdateFolder(ctx, internal.Folder{ID: 2, UserName: userLogin, Name: "casterly rock", ParentID: &subfolderID})
+		assert.ErrorIs(t, err, ErrFolderCycle)
+	})
+	t.Run("positive: folder moved to the root", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("update folders set name = \\$1, parent_id = \\$2").WithArgs("casterly rock", nil, userLogin, int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		pg := db{conn: mockDB}
+		err = pg.UpdateFolder(ctx, internal.Folder{ID: 2, UserName: userLogin, Name: "casterly rock"})
+		assert.NoError(t, err)
+	})
+	t.Run("positive: folders of the user", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select id, user_name, name, parent_id from folders").WithArgs(userLogin).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "name", "parent_id"}).
+				AddRow(1, userLogin, "lannister", nil).
+				AddRow(2, userLogin, "casterly rock", 1))
+
+		pg := db{conn: mockDB}
+		folders, err := pg.GetFolders(ctx, userLogin)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.Folder{
+			{ID: 1, UserName: userLogin, Name: "lannister"},
+			{ID: 2, UserName: userLogin, Name: "casterly rock", ParentID: &parentID},
+		}, folders)
+	})
+	t.Run("negative: no folder to delete", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from folders").WithArgs(userLogin, int64(5)).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+
+		pg := db{conn: mockDB}
+		assert.ErrorIs(t, pg.DeleteFolder(ctx, userLogin, 5), ErrNoData)
+	})
+}
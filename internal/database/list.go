@@ -0,0 +1,325 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/lib/pq"
+	"strings"
+	"time"
+)
+
+// cursor is the position after the last item of the page: the values of the sort columns and the id.
+// It's passed to the clients as an opaque string.
+type cursor struct {
+	Sort       string   `json:"s"`
+	Descending bool     `json:"d,omitempty"`
+	Values     []string `json:"v"`
+	ID         int64    `json:"id"`
+}
+
+// sortColumns returns the columns the items are ordered by, the id is added to them to make the order stable.
+// Every combination has an index, see the migrations.
+func sortColumns(nameColumns []string, sort string) ([]string, error) {
+	switch sort {
+	case "", internal.SortByName:
+		return nameColumns, nil
+	case internal.SortByCreated:
+		return []string{"created_at"}, nil
+	case internal.SortByUpdated:
+		return []string{"updated_at"}, nil
+	}
+	return nil, fmt.Errorf("%w %q", ErrInvalidSort, sort)
+}
+
+// pageQuery completes the query selecting the items of the user from the table with the metadata and labels filters,
+// the cursor condition, the order and the limit. One item more than the limit is selected to know whether there is the next page.
+func pageQuery(query string, args []any, table string, nameColumns []string, opts internal.ListOptions) (string, []any, error) {
+	columns, err := sortColumns(nameColumns, opts.Sort)
+	if err != nil {
+		return "", nil, err
+	}
+	query, args = labelsQuery(query, args, table, opts)
+	if opts.Metadata != "" {
+		args = append(args, "%"+escapeLike(opts.Metadata)+"%")
+		query += fmt.Sprintf(" and metadata ilike $%d", len(args))
+	}
+	if opts.Cursor != "" {
+		c, err := decodeCursor(opts.Cursor)
+		if err != nil || c.Sort != sortName(opts.Sort) || c.Descending != opts.Descending || len(c.Values) != len(columns) {
+			return "", nil, ErrInvalidCursor
+		}
+		placeholders := make([]string, 0, len(columns)+1)
+		for i, value := range c.Values {
+			args = append(args, value)
+			placeholder := fmt.Sprintf("$%d", len(args))
+			if strings.HasSuffix(columns[i], "_at") {
+				placeholder += "::timestamptz"
+			}
+			placeholders = append(placeholders, placeholder)
+		}
+		args = append(args, c.ID)
+		placeholders = append(placeholders, fmt.Sprintf("$%d", len(args)))
+		operator := ">"
+		if opts.Descending {
+			operator = "<"
+		}
+		query += fmt.Sprintf(" and (%s, id) %s (%s)", strings.Join(columns, ", "), operator, strings.Join(placeholders, ", "))
+	}
+
+	direction := "asc"
+	if opts.Descending {
+		direction = "desc"
+	}
+	order := make([]string, 0, len(columns)+1)
+	for _, column := range append(columns, "id") {
+		order = append(order, fmt.Sprintf("%s %s", column, direction))
+	}
+	query += " order by " + strings.Join(order, ", ")
+	if opts.Limit > 0 {
+		args = append(args, opts.Limit+1)
+		query += fmt.Sprintf(" limit $%d", len(args))
+	}
+	return query, args, nil
+}
+
+// nextCursor returns the cursor of the page following the item, name contains the values of its name columns.
+func nextCursor(opts internal.ListOptions, name []string, id int64, created time.Time, updated time.Time) string {
+	c := cursor{Sort: sortName(opts.Sort), Descending: opts.Descending, Values: name, ID: id}
+	switch c.Sort {
+	case internal.SortByCreated:
+		c.Values = []string{created.Format(time.RFC3339Nano)}
+	case internal.SortByUpdated:
+		c.Values = []string{updated.Format(time.RFC3339Nano)}
+	}
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeCursor(value string) (cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return cursor{}, err
+	}
+	var c cursor
+	if err = json.Unmarshal(data, &c); err != nil {
+		return cursor{}, err
+	}
+	return c, nil
+}
+
+func sortName(sort string) string {
+	if sort == "" {
+		return internal.SortByName
+	}
+	return sort
+}
+
+// escapeLike escapes the wildcards of LIKE patterns.
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(value)
+}
+
+// ListCredentials is a method for getting the page of credentials for provided authorized user.
+// Login is an optional filter. The cursor of the next page is empty for the last page.
+func (d *db) ListCredentials(ctx context.Context, credentialsRequest internal.Credentials, opts internal.ListOptions) ([]internal.Credentials, string, error) {
+	args := []any{credentialsRequest.UserName}
+	listCredsQuery := "select user_name, login, password, metadata, id, created_at, updated_at, rotation_days, password_changed_at" + labelColumns("credentials") + " from credentials where user_name = $1"
+	if credentialsRequest.Login != nil {
+		args = append(args, *credentialsRequest.Login)
+		listCredsQuery += fmt.Sprintf(" and login = $%d", len(args))
+	}
+	listCredsQuery, args, err := pageQuery(listCredsQuery, args, "credentials", []string{"login"}, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := d.conn.QueryContext(ctx, listCredsQuery, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error while listing credentials for user %q: %w", credentialsRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var creds []internal.Credentials
+	for rows.Next() {
+		var id int64
+		var userName, login, password string
+		var metadata sql.NullString
+		var createdAt, updatedAt, passwordChangedAt time.Time
+		var days sql.NullInt32
+		var favorite bool
+		var folderID sql.NullInt64
+		var tags pq.StringArray
+		if err = rows.Scan(&userName, &login, &password, &metadata, &id, &createdAt, &updatedAt, &days, &passwordChangedAt, &favorite, &folderID, &tags); err != nil {
+			return nil, "", fmt.Errorf("error while scanning rows after list user credentials query: %w", err)
+		}
+		decryptedPassword, err := d.decryptAES(password)
+		if err != nil {
+			return nil, "", fmt.Errorf("error while decrypting password: %w", err)
+		}
+		res := internal.Credentials{
+			ID:        id,
+			UserName:  userName,
+			Login:     &login,
+			Password:  &decryptedPassword,
+			CreatedAt: &createdAt,
+			UpdatedAt: &updatedAt,
+			Rotation:  rotation(days, passwordChangedAt),
+			Labels:    labels(favorite, folderID, tags),
+		}
+		if metadata.Valid {
+			res.Metadata = &metadata.String
+		}
+		creds = append(creds, res)
+	}
+
+	if opts.Limit == 0 || len(creds) <= opts.Limit {
+		return creds, "", nil
+	}
+	creds = creds[:opts.Limit]
+	last := creds[len(creds)-1]
+	return creds, nextCursor(opts, []string{*last.Login}, last.ID, *last.CreatedAt, *last.UpdatedAt), nil
+}
+
+// ListNotes is a method for getting the page of notes for provided authorized user.
+// Title is an optional filter. The cursor of the next page is empty for the last page.
+func (d *db) ListNotes(ctx context.Context, noteRequest internal.Note, opts internal.ListOptions) ([]internal.Note, string, error) {
+	args := []any{noteRequest.UserName}
+	listNotesQuery := "select user_name, title, content, metadata, id, created_at, updated_at" + labelColumns("notes") + " from notes where user_name = $1"
+	if noteRequest.Title != nil {
+		args = append(args, *noteRequest.Title)
+		listNotesQuery += fmt.Sprintf(" and title = $%d", len(args))
+	}
+	listNotesQuery, args, err := pageQuery(listNotesQuery, args, "notes", []string{"title"}, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := d.conn.QueryContext(ctx, listNotesQuery, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error while listing notes for user %q: %w", noteRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var notes []internal.Note
+	for rows.Next() {
+		var id int64
+		var userName, title, content string
+		var metadata sql.NullString
+		var createdAt, updatedAt time.Time
+		var favorite bool
+		var folderID sql.NullInt64
+		var tags pq.StringArray
+		if err = rows.Scan(&userName, &title, &content, &metadata, &id, &createdAt, &updatedAt, &favorite, &folderID, &tags); err != nil {
+			return nil, "", fmt.Errorf("error while scanning rows after list user notes query: %w", err)
+		}
+		decryptedContent, err := d.decryptAES(content)
+		if err != nil {
+			return nil, "", fmt.Errorf("error while decrypting note content: %w", err)
+		}
+		res := internal.Note{
+			ID:        id,
+			UserName:  userName,
+			Title:     &title,
+			Content:   &decryptedContent,
+			CreatedAt: &createdAt,
+			UpdatedAt: &updatedAt,
+			Labels:    labels(favorite, folderID, tags),
+		}
+		if metadata.Valid {
+			res.Metadata = &metadata.String
+		}
+		notes = append(notes, res)
+	}
+
+	if opts.Limit == 0 || len(notes) <= opts.Limit {
+		return notes, "", nil
+	}
+	notes = notes[:opts.Limit]
+	last := notes[len(notes)-1]
+	return notes, nextCursor(opts, []string{*last.Title}, last.ID, *last.CreatedAt, *last.UpdatedAt), nil
+}
+
+// ListCards is a method for getting the page of bank cards for provided authorized user.
+// Bank name and number are optional filters. The cursor of the next page is empty for the last page.
+func (d *db) ListCards(ctx context.Context, cardRequest internal.Card, opts internal.ListOptions) ([]internal.Card, string, error) {
+	args := []any{cardRequest.UserName}
+	listCardsQuery := "select user_name, bank_name, number, cv, password, metadata, id, created_at, updated_at, rotation_days, password_changed_at, expires_on" +
+		labelColumns("cards") + " from cards where user_name = $1"
+	if cardRequest.BankName != nil {
+		args = append(args, *cardRequest.BankName)
+		listCardsQuery += fmt.Sprintf(" and bank_name = $%d", len(args))
+	}
+	if cardRequest.Number != nil {
+		args = append(args, *cardRequest.Number)
+		listCardsQuery += fmt.Sprintf(" and number = $%d", len(args))
+	}
+	listCardsQuery, args, err := pageQuery(listCardsQuery, args, "cards", []string{"bank_name", "number"}, opts)
+	if err != nil {
+		return nil, "", err
+	}
+	rows, err := d.conn.QueryContext(ctx, listCardsQuery, args...)
+	if err != nil {
+		return nil, "", fmt.Errorf("error while listing cards for user %q: %w", cardRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var cards []internal.Card
+	for rows.Next() {
+		var id int64
+		var userName, bankName, number, cv, password string
+		var metadata sql.NullString
+		var createdAt, updatedAt, passwordChangedAt time.Time
+		var days sql.NullInt32
+		var expiresOn sql.NullTime
+		var favorite bool
+		var folderID sql.NullInt64
+		var tags pq.StringArray
+		if err = rows.Scan(&userName, &bankName, &number, &cv, &password, &metadata, &id, &createdAt, &updatedAt, &days, &passwordChangedAt, &expiresOn,
+			&favorite, &folderID, &tags); err != nil {
+			return nil, "", fmt.Errorf("error while scanning rows after list user cards query: %w", err)
+		}
+		decryptedPassword, err := d.decryptAES(password)
+		if err != nil {
+			return nil, "", fmt.Errorf("error while decrypting password: %w", err)
+		}
+		decryptedCV, err := d.decryptAES(cv)
+		if err != nil {
+			return nil, "", fmt.Errorf("error while decrypting cv: %w", err)
+		}
+		res := internal.Card{
+			ID:        id,
+			UserName:  userName,
+			BankName:  &bankName,
+			Number:    &number,
+			CV:        &decryptedCV,
+			Password:  &decryptedPassword,
+			ExpiresOn: nullMonth(expiresOn),
+			CreatedAt: &createdAt,
+			UpdatedAt: &updatedAt,
+			Rotation:  rotation(days, passwordChangedAt),
+			Labels:    labels(favorite, folderID, tags),
+		}
+		if metadata.Valid {
+			res.Metadata = &metadata.String
+		}
+		cards = append(cards, res)
+	}
+
+	if opts.Limit == 0 || len(cards) <= opts.Limit {
+		return cards, "", nil
+	}
+	cards = cards[:opts.Limit]
+	last := cards[len(cards)-1]
+	return cards, nextCursor(opts, []string{*last.BankName, *last.Number}, last.ID, *last.CreatedAt, *last.UpdatedAt), nil
+}
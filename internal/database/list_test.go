@@ -0,0 +1,188 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDb_ListCredentials(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "arya"
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	updated := time.Date(2026, 10, 2, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	folderID := int64(4)
+	favorite := true
+	rotationDays := 90
+	columns := []string{"user_name", "login", "password", "metadata", "id", "created_at", "updated_at", "rotation_days", "password_changed_at", "favorite", "folder_id", "tags"}
+
+	t.Run("positive: first page by name", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("from credentials where user_name = $1 order by login asc, id asc limit $2")).
+			WithArgs(userLogin, 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(userLogin, "killer", "zwkcxfLKNXGHrfgP", "bla bla password", 1, created, updated, 90, created, true, 4, "{faceless,north}").
+				AddRow(userLogin, "warrior", "ygke1+HOKWWSvfUNiQ==", "valar dohaeris", 2, created, updated, nil, created, false, nil, "{}"))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		opts := internal.ListOptions{Limit: 1}
+		creds, next, err := pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin}, opts)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.Credentials{{
+			ID:        1,
+			UserName:  userLogin,
+			Login:     Ptr("killer"),
+			Password:  Ptr("sansaisfreak"),
+			Metadata:  Ptr("bla bla password"),
+			CreatedAt: &created,
+			UpdatedAt: &updated,
+			Rotation:  internal.Rotation{RotationDays: &rotationDays, PasswordChangedAt: &created},
+			Labels:    internal.Labels{Tags: []string{"faceless", "north"}, FolderID: &folderID, Favorite: true},
+		}}, creds)
+		assert.Equal(t, nextCursor(opts, []string{"killer"}, 1, created, updated), next)
+	})
+	t.Run("positive: next page by update time with metadata filter", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		opts := internal.ListOptions{Limit: 1, Sort: internal.SortByUpdated, Descending: true, Metadata: "100%"}
+		opts.Cursor = nextCursor(opts, []string{"killer"}, 1, created, updated)
+		mock.ExpectQuery(regexp.QuoteMeta("from credentials where user_name = $1 and metadata ilike $2 and (updated_at, id) < ($3::timestamptz, $4) order by updated_at desc, id desc limit $5")).
+			WithArgs(userLogin, `%100\%%`, updated.Format(time.RFC3339Nano), int64(1), 2).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow(userLogin, "avenger", "zR8XxOfadyU=", nil, 3, created, created, nil, created, false, nil, "{}"))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		creds, next, err := pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin}, opts)
+		assert.NoError(t, err)
+		assert.Len(t, creds, 1)
+		assert.Equal(t, "qwerty12", *creds[0].Password)
+		assert.Empty(t, next)
+	})
+	t.Run("positive: tags, folder and favourite filters", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("from credentials where user_name = $1 "+
+			"and id in (select item_id from item_tags where user_name = $1 and item_type = 'credentials' and tag = any($2) group by item_id having count(*) = $3) "+
+			"and folder_id in (with recursive subfolders as (select id from folders where user_name = $1 and id = $4 "+
+			"union all select folders.id from folders join subfolders on folders.parent_id = subfolders.id) select id from subfolders) "+
+			"and favorite = $5 order by login asc, id asc")).
+			WithArgs(userLogin, `{"faceless","north"}`, 2, int64(4), true).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		opts := internal.ListOptions{Tags: []string{"north", " faceless", "north"}, FolderID: &folderID, Favorite: &favorite}
+		creds, _, err := pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin}, opts)
+		assert.NoError(t, err)
+		assert.Empty(t, creds)
+	})
+	t.Run("positive: no data for user", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("from credentials where user_name = $1 and login = $2 order by login asc, id asc")).
+			WithArgs(userLogin, "killer").
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		creds, next, err := pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin, Login: Ptr("killer")}, internal.ListOptions{})
+		assert.NoError(t, err)
+		assert.Empty(t, creds)
+		assert.Empty(t, next)
+	})
+	t.Run("negative: cursor of another order", func(t *testing.T) {
+		mockDB, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		pg := db{conn: mockDB}
+		cursor := nextCursor(internal.ListOptions{}, []string{"killer"}, 1, created, updated)
+		_, _, err = pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin}, internal.ListOptions{Cursor: cursor, Sort: internal.SortByCreated})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+		_, _, err = pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin}, internal.ListOptions{Cursor: "not a cursor"})
+		assert.ErrorIs(t, err, ErrInvalidCursor)
+	})
+	t.Run("negative: invalid sort", func(t *testing.T) {
+		mockDB, _, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		pg := db{conn: mockDB}
+		_, _, err = pg.ListCredentials(ctx, internal.Credentials{UserName: userLogin}, internal.ListOptions{Sort: "password"})
+		assert.ErrorIs(t, err, ErrInvalidSort)
+	})
+}
+
+func TestDb_ListCards(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "arya"
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("positive: next page by bank name and number", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		opts := internal.ListOptions{Limit: 10}
+		opts.Cursor = nextCursor(opts, []string{"iron bank", "1111"}, 4, created, created)
+		mock.ExpectQuery(regexp.QuoteMeta("from cards where user_name = $1 and (bank_name, number, id) > ($2, $3, $4) order by bank_name asc, number asc, id asc limit $5")).
+			WithArgs(userLogin, "iron bank", "1111", int64(4), 11).
+			WillReturnRows(sqlmock.NewRows([]string{"user_name", "bank_name", "number", "cv", "password", "metadata", "id", "created_at", "updated_at"}))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		cards, next, err := pg.ListCards(ctx, internal.Card{UserName: userLogin}, opts)
+		assert.NoError(t, err)
+		assert.Empty(t, cards)
+		assert.Empty(t, next)
+	})
+}
@@ -0,0 +1,48 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+)
+
+// SavePublicKey is a method for registering a new public key which can be used for login instead of password.
+func (d *db) SavePublicKey(ctx context.Context, key internal.PublicKey) error {
+	saveKeyQuery := "insert into public_keys (login, key_id, public_key, algorithm) values ($1, $2, $3, $4)"
+	if _, err := d.conn.ExecContext(ctx, saveKeyQuery, key.UserName, key.KeyID, key.PublicKey, key.Algorithm); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "public_keys_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return ErrKeyAlreadyExists
+		}
+		return fmt.Errorf("error while saving public key for user %q: %w", key.UserName, err)
+	}
+	return nil
+}
+
+// GetPublicKey is a method for getting registered public key of provided user by key id.
+func (d *db) GetPublicKey(ctx context.Context, login string, keyID string) (internal.PublicKey, error) {
+	getKeyQuery := "select public_key, algorithm, sign_count from public_keys where login = $1 and key_id = $2"
+
+	key := internal.PublicKey{
+		UserName: login,
+		KeyID:    keyID,
+	}
+	if err := d.conn.QueryRowContext(ctx, getKeyQuery, login, keyID).Scan(&key.PublicKey, &key.Algorithm, &key.SignCount); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return internal.PublicKey{}, ErrNoData
+		}
+		return internal.PublicKey{}, fmt.Errorf("error while getting public key for user %q: %w", login, err)
+	}
+	return key, nil
+}
+
+// UpdateSignCount is a method for saving the last sign counter reported by the authenticator.
+func (d *db) UpdateSignCount(ctx context.Context, login string, keyID string, signCount uint32) error {
+	updateCountQuery := "update public_keys set sign_count = $1 where login = $2 and key_id = $3"
+	if _, err := d.conn.ExecContext(ctx, updateCountQuery, signCount, login, keyID); err != nil {
+		return fmt.Errorf("error while updating sign counter for user %q: %w", login, err)
+	}
+	return nil
+}
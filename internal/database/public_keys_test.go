@@ -0,0 +1,154 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDb_SavePublicKey(t *testing.T) {
+	key := internal.PublicKey{
+		UserName:  "robb",
+		KeyID:     "key-id",
+		PublicKey: "cHVibGljIGtleQ==",
+		Algorithm: "EdDSA",
+	}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		storageError  error
+		expectedError string
+	}{
+		{
+			name: "positive: key saved",
+		},
+		{
+			name:          "negative: key exists",
+			storageError:  ErrDublicateKey{Key: "public_keys_pkey"},
+			expectedError: ErrKeyAlreadyExists.Error(),
+		},
+		{
+			name:          "negative: insert error",
+			storageError:  errors.New("insert error"),
+			expectedError: `error while saving public key for user "robb": insert error`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer mockDB.Close()
+
+			expectation := mock.ExpectExec("insert into public_keys").
+				WithArgs(key.UserName, key.KeyID, key.PublicKey, key.Algorithm)
+			if tt.storageError != nil {
+				expectation.WillReturnError(tt.storageError)
+			} else {
+				expectation.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			pg := db{
+				conn: mockDB,
+			}
+			err = pg.SavePublicKey(ctx, key)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDb_GetPublicKey(t *testing.T) {
+	login := "robb"
+	keyID := "key-id"
+	ctx := context.Background()
+
+	t.Run("positive: key found", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select public_key, algorithm, sign_count from public_keys").
+			WithArgs(login, keyID).
+			WillReturnRows(sqlmock.NewRows([]string{"public_key", "algorithm", "sign_count"}).AddRow("cHVibGljIGtleQ==", "EdDSA", 7))
+
+		pg := db{
+			conn: mockDB,
+		}
+		key, err := pg.GetPublicKey(ctx, login, keyID)
+		assert.NoError(t, err)
+		assert.Equal(t, internal.PublicKey{
+			UserName:  login,
+			KeyID:     keyID,
+			PublicKey: "cHVibGljIGtleQ==",
+			Algorithm: "EdDSA",
+			SignCount: 7,
+		}, key)
+	})
+	t.Run("negative: no key", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select public_key, algorithm, sign_count from public_keys").
+			WithArgs(login, keyID).
+			WillReturnRows(sqlmock.NewRows([]string{"public_key", "algorithm", "sign_count"}))
+
+		pg := db{
+			conn: mockDB,
+		}
+		_, err = pg.GetPublicKey(ctx, login, keyID)
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+}
+
+func TestDb_UpdateSignCount(t *testing.T) {
+	login := "robb"
+	keyID := "key-id"
+	ctx := context.Background()
+
+	t.Run("positive: counter updated", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("update public_keys set sign_count").
+			WithArgs(uint32(8), login, keyID).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		pg := db{
+			conn: mockDB,
+		}
+		assert.NoError(t, pg.UpdateSignCount(ctx, login, keyID, 8))
+	})
+	t.Run("negative: update error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("update public_keys set sign_count").
+			WillReturnError(errors.New("update error"))
+
+		pg := db{
+			conn: mockDB,
+		}
+		err = pg.UpdateSignCount(ctx, login, keyID, 8)
+		assert.EqualError(t, err, `error while updating sign counter for user "robb": update error`)
+	})
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/lib/pq"
+	"time"
+)
+
+// dueItemsQuery selects the credentials and the bank cards with the rotation policy and the time of the next password change.
+// Cards are named by the bank and the last digits of the number.
+const dueItemsQuery = "select type, id, user_name, name, rotation_days, password_changed_at, due_at from (" +
+	"select 'credentials' as type, id, user_name, login as name, rotation_days, password_changed_at, rotation_notified_at, " +
+	"password_changed_at + rotation_days * interval '1 day' as due_at from credentials where rotation_days is not null " +
+	"union all select 'cards', id, user_name, bank_name || ' *' || right(number, 4), rotation_days, password_changed_at, rotation_notified_at, " +
+	"password_changed_at + rotation_days * interval '1 day' from cards where rotation_days is not null) as items where due_at <= $1"
+
+// rotationUpdate sets the rotation days to the value of the argument, keeps them if the argument is null and removes them if it's zero.
+// The time of the password change is updated if the boolean argument is true, see passwordChanged.
+func rotationUpdate(changedArg int, daysArg int) string {
+	return fmt.Sprintf("rotation_days = case when $%[2]d::integer is null then rotation_days else nullif($%[2]d::integer, 0) end, "+
+		"password_changed_at = case when $%[1]d::boolean then now() else password_changed_at end", changedArg, daysArg)
+}
+
+// passwordChanged reports whether the new password differs from the stored encrypted one.
+// The passwords are compared decrypted, so the result doesn't depend on how they are encrypted.
+func (d *db) passwordChanged(storedPassword string, password string) (bool, error) {
+	decrypted, err := d.decryptAES(storedPassword)
+	if err != nil {
+		return false, fmt.Errorf("error while decrypting stored password: %w", err)
+	}
+	return decrypted != password, nil
+}
+
+// rotationDays converts the rotation days for the queries, nil keeps the stored days on update.
+func rotationDays(days *int) any {
+	if days == nil {
+		return nil
+	}
+	return *days
+}
+
+// rotation converts the scanned rotation columns.
+func rotation(days sql.NullInt32, passwordChangedAt time.Time) internal.Rotation {
+	res := internal.Rotation{PasswordChangedAt: &passwordChangedAt}
+	if days.Valid {
+		rotationDays := int(days.Int32)
+		res.RotationDays = &rotationDays
+	}
+	return res
+}
+
+// GetDueItems is a method for getting the credentials and the bank cards of provided user with the passwords
+// due for a change before the time, ordered by the due time.
+func (d *db) GetDueItems(ctx context.Context, userName string, before time.Time) ([]internal.DueItem, error) {
+	items, err := d.dueItems(ctx, dueItemsQuery+" and user_name = $2 order by due_at, type, id", before, userName)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting due items for user %q: %w", userName, err)
+	}
+	return items, nil
+}
+
+// GetPendingRotations is a method for getting the items of all users with the passwords due for a change at the time,
+// whose owners were not notified since the last change of the password. Items are ordered by user and due time.
+func (d *db) GetPendingRotations(ctx context.Context, at time.Time) ([]internal.DueItem, error) {
+	items, err := d.dueItems(ctx, dueItemsQuery+" and (rotation_notified_at is null or rotation_notified_at < password_changed_at) "+
+		"order by user_name, due_at, type, id", at)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting pending rotations: %w", err)
+	}
+	return items, nil
+}
+
+// SetRotationNotified is a method for marking the owners of the items as notified about the rotation at the time.
+// The items are not returned as pending until their passwords change.
+func (d *db) SetRotationNotified(ctx context.Context, items []internal.DueItem, at time.Time) error {
+	ids := map[string][]int64{}
+	for _, item := range items {
+		ids[item.Type] = append(ids[item.Type], item.ID)
+	}
+	for _, table := range []string{internal.ItemTypeCredentials, internal.ItemTypeCard} {
+		if len(ids[table]) == 0 {
+			continue
+		}
+		setNotifiedQuery := fmt.Sprintf("update %s set rotation_notified_at = $1 where id = any($2)", table)
+		if _, err := d.conn.ExecContext(ctx, setNotifiedQuery, at, pq.Array(ids[table])); err != nil {
+			return fmt.Errorf("error while marking %s as notified about rotation: %w", table, err)
+		}
+	}
+	return nil
+}
+
+func (d *db) dueItems(ctx context.Context, query string, args ...any) ([]internal.DueItem, error) {
+	rows, err := d.conn.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var items []internal.DueItem
+	for rows.Next() {
+		var item internal.DueItem
+		if err = rows.Scan(&item.Type, &item.ID, &item.UserName, &item.Name, &item.RotationDays, &item.PasswordChangedAt, &item.DueAt); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after due items query: %w", err)
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
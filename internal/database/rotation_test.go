@@ -0,0 +1,156 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+	"time"
+)
+
+func TestDb_GetDueItems(t *testing.T) {
+	userLogin := "bran"
+	changed := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	before := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	columns := []string{"type", "id", "user_name", "name", "rotation_days", "password_changed_at", "due_at"}
+	ctx := context.Background()
+
+	t.Run("positive: due items", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("where due_at <= $1 and user_name = $2 order by due_at, type, id")).
+			WithArgs(before, userLogin).
+			WillReturnRows(sqlmock.NewRows(columns).
+				AddRow("credentials", 3, userLogin, "raven", 30, changed, changed.AddDate(0, 0, 30)).
+				AddRow("cards", 5, userLogin, "iron bank *4444", 90, changed, changed.AddDate(0, 0, 90)))
+
+		pg := db{conn: mockDB}
+		items, err := pg.GetDueItems(ctx, userLogin, before)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.DueItem{
+			{Type: "credentials", ID: 3, UserName: userLogin, Name: "raven", RotationDays: 30, PasswordChangedAt: changed, DueAt: changed.AddDate(0, 0, 30)},
+			{Type: "cards", ID: 5, UserName: userLogin, Name: "iron bank *4444", RotationDays: 90, PasswordChangedAt: changed, DueAt: changed.AddDate(0, 0, 90)},
+		}, items)
+	})
+	t.Run("negative: query error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select type, id").WillReturnError(errors.New("query error"))
+
+		pg := db{conn: mockDB}
+		_, err = pg.GetDueItems(ctx, userLogin, before)
+		assert.EqualError(t, err, "error while getting due items for user \"bran\": query error")
+	})
+}
+
+func TestDb_GetPendingRotations(t *testing.T) {
+	changed := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	at := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+
+	mock.ExpectQuery(regexp.QuoteMeta("where due_at <= $1 and (rotation_notified_at is null or rotation_notified_at < password_changed_at) order by user_name, due_at, type, id")).
+		WithArgs(at).
+		WillReturnRows(sqlmock.NewRows([]string{"type", "id", "user_name", "name", "rotation_days", "password_changed_at", "due_at"}).
+			AddRow("credentials", 3, "bran", "raven", 30, changed, changed.AddDate(0, 0, 30)))
+
+	pg := db{conn: mockDB}
+	items, err := pg.GetPendingRotations(ctx, at)
+	assert.NoError(t, err)
+	assert.Len(t, items, 1)
+	assert.Equal(t, "bran", items[0].UserName)
+}
+
+func TestDb_SetRotationNotified(t *testing.T) {
+	at := time.Date(2026, 10, 18, 0, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+	items := []internal.DueItem{
+		{Type: internal.ItemTypeCredentials, ID: 3},
+		{Type: internal.ItemTypeCredentials, ID: 4},
+	}
+
+	t.Run("positive: only tables of the items are updated", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec(regexp.QuoteMeta("update credentials set rotation_notified_at = $1 where id = any($2)")).
+			WithArgs(at, "{3,4}").
+			WillReturnResult(sqlmock.NewResult(0, 2))
+
+		pg := db{conn: mockDB}
+		assert.NoError(t, pg.SetRotationNotified(ctx, items, at))
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: exec error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("update credentials").WillReturnError(errors.New("exec error"))
+
+		pg := db{conn: mockDB}
+		err = pg.SetRotationNotified(ctx, items, at)
+		assert.EqualError(t, err, "error while marking credentials as notified about rotation: exec error")
+	})
+}
+
+func TestDb_UpdateCredentialsRotation(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	days := 0
+
+	mockDB, mock, err := sqlmock.New()
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+	}
+	defer mockDB.Close()
+
+	mock.ExpectBegin()
+	mock.ExpectQuery(regexp.QuoteMeta("select password from credentials where user_name = $1 and login = $2 for update")).
+		WithArgs("bran", "raven").
+		WillReturnRows(sqlmock.NewRows([]string{"password"}).AddRow("zR8XxOfa"))
+	changedAt := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(regexp.QuoteMeta("rotation_days = case when $5::integer is null then rotation_days else nullif($5::integer, 0) end, "+
+		"password_changed_at = case when $6::boolean then now() else password_changed_at end where user_name = $3 and login = $4 "+
+		"returning rotation_days, password_changed_at")).
+		WithArgs("zR8XxOfadyU=", nil, "bran", "raven", 0, true).
+		WillReturnRows(sqlmock.NewRows([]string{"rotation_days", "password_changed_at"}).AddRow(nil, changedAt))
+	mock.ExpectCommit()
+
+	pg := db{
+		conn:          mockDB,
+		encriptionKey: key,
+		dataCipher:    c,
+	}
+	rotation, err := pg.UpdateCredentials(context.Background(), internal.Credentials{
+		UserName: "bran",
+		Login:    Ptr("raven"),
+		Password: Ptr("qwerty12"),
+		Rotation: internal.Rotation{RotationDays: &days},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, internal.Rotation{PasswordChangedAt: &changedAt}, rotation)
+	assert.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,81 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"strings"
+)
+
+// searchTables are the tables of the searched items with their name columns. Secret fields are encrypted,
+// so only the names, the public columns and the metadata are searched. Each document has full-text and trigram indexes,
+// see the migrations.
+var searchTables = []struct {
+	itemType string
+	table    string
+	name     string
+	// public are the not null columns searched besides the name
+	public []string
+	// urls reports whether the custom URL fields of the items are searched
+	urls bool
+}{
+	{itemType: internal.ItemTypeCredentials, table: "credentials", name: "login", urls: true},
+	{itemType: internal.ItemTypeNote, table: "notes", name: "title"},
+	{itemType: internal.ItemTypeCard, table: "cards", name: "bank_name"},
+	{itemType: internal.ItemTypeFile, table: "files", name: "name"},
+	{itemType: internal.ItemTypeSSHKey, table: "ssh_keys", name: "name", public: []string{"fingerprint", "public_key"}},
+}
+
+// searchQuery selects the items of the user ($1) matching the words of the query ($2) or containing it ($3)
+// in the name, the metadata, the tags or the URL fields, ranked by the full-text rank and the trigram similarity.
+func searchQuery() string {
+	selects := make([]string, 0, len(searchTables))
+	for _, t := range searchTables {
+		columns := append([]string{t.name}, t.public...)
+		document := fmt.Sprintf("(%s || ' ' || coalesce(metadata, ''))", strings.Join(columns, " || ' ' || "))
+		condition := fmt.Sprintf("to_tsvector('simple', %s) @@ plainto_tsquery('simple', $2) or %s ilike $3", document, document)
+		if labelledTypes[t.itemType] {
+			condition += fmt.Sprintf(" or id in (select item_id from item_tags where user_name = $1 and item_type = '%s' and tag ilike $3)", t.itemType)
+		}
+		if t.urls {
+			condition += fmt.Sprintf(" or id in (select item_id from custom_fields where user_name = $1 and item_type = '%s' and type = '%s' "+
+				"and (to_tsvector('simple', value) @@ plainto_tsquery('simple', $2) or value ilike $3))", t.itemType, internal.FieldTypeURL)
+		}
+		selects = append(selects, fmt.Sprintf(
+			"select '%s' as type, id, %s as name, metadata, "+
+				"ts_rank(to_tsvector('simple', %s), plainto_tsquery('simple', $2)) + similarity(%s, $2) as rank "+
+				"from %s where user_name = $1 and (%s)",
+			t.itemType, t.name, document, document, t.table, condition,
+		))
+	}
+	return strings.Join(selects, " union all ") + " order by rank desc, type, id limit $4"
+}
+
+// Search is a method for finding the items of the user by the words or the substring of their names, metadata, tags
+// and URL fields of the credentials.
+// The results are ordered by relevance.
+func (d *db) Search(ctx context.Context, userName string, query string, limit int) ([]internal.SearchResult, error) {
+	rows, err := d.conn.QueryContext(ctx, searchQuery(), userName, query, "%"+escapeLike(query)+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("error while searching items of user %q: %w", userName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var results []internal.SearchResult
+	for rows.Next() {
+		var res internal.SearchResult
+		var metadata sql.NullString
+		if err = rows.Scan(&res.Type, &res.ID, &res.Name, &metadata, &res.Rank); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after search query: %w", err)
+		}
+		if metadata.Valid {
+			res.Metadata = &metadata.String
+		}
+		results = append(results, res)
+	}
+	return results, nil
+}
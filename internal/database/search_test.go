@@ -0,0 +1,88 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"regexp"
+	"testing"
+)
+
+func TestDb_Search(t *testing.T) {
+	userLogin := "bran"
+	ctx := context.Background()
+
+	t.Run("positive: items of all types", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("from notes where user_name = $1 and (to_tsvector('simple', (title || ' ' || coalesce(metadata, ''))) @@ plainto_tsquery('simple', $2) or (title || ' ' || coalesce(metadata, '')) ilike $3 "+
+			"or id in (select item_id from item_tags where user_name = $1 and item_type = 'notes' and tag ilike $3))")).
+			WithArgs(userLogin, "vpn_1", `%vpn\_1%`, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"type", "id", "name", "metadata", "rank"}).
+				AddRow("notes", 3, "vpn_1", "staging", 0.9).
+				AddRow("files", 5, "vpn_1.ovpn", nil, 0.4))
+
+		pg := db{conn: mockDB}
+		results, err := pg.Search(ctx, userLogin, "vpn_1", 20)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.SearchResult{
+			{Type: internal.ItemTypeNote, ID: 3, Name: "vpn_1", Metadata: Ptr("staging"), Rank: 0.9},
+			{Type: internal.ItemTypeFile, ID: 5, Name: "vpn_1.ovpn", Rank: 0.4},
+		}, results)
+	})
+	t.Run("positive: ssh keys by fingerprint", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("from ssh_keys where user_name = $1 and (to_tsvector('simple', (name || ' ' || fingerprint || ' ' || public_key || ' ' || coalesce(metadata, ''))) @@ plainto_tsquery('simple', $2)")).
+			WithArgs(userLogin, "SHA256:3pG8", `%SHA256:3pG8%`, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"type", "id", "name", "metadata", "rank"}).
+				AddRow("ssh_keys", 2, "deploy", nil, 0.3))
+
+		pg := db{conn: mockDB}
+		results, err := pg.Search(ctx, userLogin, "SHA256:3pG8", 20)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.SearchResult{{Type: internal.ItemTypeSSHKey, ID: 2, Name: "deploy", Rank: 0.3}}, results)
+	})
+	t.Run("positive: credentials by url field", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery(regexp.QuoteMeta("or id in (select item_id from custom_fields where user_name = $1 and item_type = 'credentials' and type = 'url' "+
+			"and (to_tsvector('simple', value) @@ plainto_tsquery('simple', $2) or value ilike $3))")).
+			WithArgs(userLogin, "winterfell.org", `%winterfell.org%`, 20).
+			WillReturnRows(sqlmock.NewRows([]string{"type", "id", "name", "metadata", "rank"}).
+				AddRow("credentials", 4, "bran", nil, 0.1))
+
+		pg := db{conn: mockDB}
+		results, err := pg.Search(ctx, userLogin, "winterfell.org", 20)
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.SearchResult{{Type: internal.ItemTypeCredentials, ID: 4, Name: "bran", Rank: 0.1}}, results)
+	})
+	t.Run("negative: query error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from credentials").
+			WillReturnError(errors.New("query error"))
+
+		pg := db{conn: mockDB}
+		_, err = pg.Search(ctx, userLogin, "vpn", 20)
+		assert.EqualError(t, err, "error while searching items of user \"bran\": query error")
+	})
+}
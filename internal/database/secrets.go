@@ -0,0 +1,123 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"time"
+)
+
+// SaveSecret is a method for saving the secret of authorized user, the key/value pairs are encrypted together.
+// Names of the secrets are unique for the user, the id of the saved secret is returned.
+func (d *db) SaveSecret(ctx context.Context, secret internal.Secret) (int64, error) {
+	encryptedValues, err := d.encryptValues(secret.Values)
+	if err != nil {
+		return 0, err
+	}
+	var id int64
+	saveSecretQuery := "insert into secrets (user_name, name, data, metadata) values ($1, $2, $3, $4) returning id"
+	if err = d.conn.QueryRowContext(ctx, saveSecretQuery, secret.UserName, *secret.Name, encryptedValues, secret.Metadata).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "secrets_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving secret for user %q: %w", secret.UserName, err)
+	}
+	return id, nil
+}
+
+// GetSecrets is a method for getting the secrets of provided user ordered by name with decrypted values.
+// ID and name are optional parameters.
+func (d *db) GetSecrets(ctx context.Context, secretRequest internal.Secret) ([]internal.Secret, error) {
+	args := []any{secretRequest.UserName}
+	getSecretsQuery := "select id, user_name, name, data, metadata, created_at, updated_at from secrets where user_name = $1"
+	if secretRequest.ID != 0 {
+		args = append(args, secretRequest.ID)
+		getSecretsQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
+	if secretRequest.Name != nil {
+		args = append(args, *secretRequest.Name)
+		getSecretsQuery += fmt.Sprintf(" and name = $%d", len(args))
+	}
+	rows, err := d.conn.QueryContext(ctx, getSecretsQuery+" order by name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting secrets for user %q: %w", secretRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var secrets []internal.Secret
+	for rows.Next() {
+		var secret internal.Secret
+		var name, data string
+		var metadata sql.NullString
+		var createdAt, updatedAt time.Time
+		if err = rows.Scan(&secret.ID, &secret.UserName, &name, &data, &metadata, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user secrets query: %w", err)
+		}
+		decryptedData, err := d.decryptAES(data)
+		if err != nil {
+			return nil, fmt.Errorf("error while decrypting secret values: %w", err)
+		}
+		if err = json.Unmarshal([]byte(decryptedData), &secret.Values); err != nil {
+			return nil, fmt.Errorf("error while decoding secret values: %w", err)
+		}
+		secret.Name = &name
+		secret.CreatedAt = &createdAt
+		secret.UpdatedAt = &updatedAt
+		if metadata.Valid {
+			secret.Metadata = &metadata.String
+		}
+		secrets = append(secrets, secret)
+	}
+	if len(secrets) == 0 {
+		return nil, ErrNoData
+	}
+	return secrets, nil
+}
+
+// UpdateSecret is a method for replacing the key/value pairs and the metadata of the secret by id.
+func (d *db) UpdateSecret(ctx context.Context, secret internal.Secret) error {
+	encryptedValues, err := d.encryptValues(secret.Values)
+	if err != nil {
+		return err
+	}
+	updateSecretQuery := "update secrets set data = $1, metadata = $2, updated_at = now() where user_name = $3 and id = $4"
+	if _, err = d.conn.ExecContext(ctx, updateSecretQuery, encryptedValues, secret.Metadata, secret.UserName, secret.ID); err != nil {
+		return fmt.Errorf("error while updating secret for user %q: %w", secret.UserName, err)
+	}
+	return nil
+}
+
+// DeleteSecret is a method for deleting the secret of provided user by id, ErrNoData is returned if there is no such one.
+func (d *db) DeleteSecret(ctx context.Context, secretRequest internal.Secret) error {
+	res, err := d.conn.ExecContext(ctx, "delete from secrets where user_name = $1 and id = $2", secretRequest.UserName, secretRequest.ID)
+	if err != nil {
+		return fmt.Errorf("error while deleting secret for user %q: %w", secretRequest.UserName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while deleting secret for user %q: %w", secretRequest.UserName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	return nil
+}
+
+// encryptValues encrypts the key/value pairs encoded as a JSON object.
+func (d *db) encryptValues(values map[string]string) (string, error) {
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("error while encoding secret values: %w", err)
+	}
+	encrypted, err := d.encryptAES(string(data))
+	if err != nil {
+		return "", fmt.Errorf("error while encrypting secret values: %w", err)
+	}
+	return encrypted, nil
+}
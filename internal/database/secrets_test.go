@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDb_Secrets(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "samwell"
+	name := "deploy"
+	values := map[string]string{"TOKEN": "qwerty12"}
+	// {"TOKEN":"qwerty12"} encrypted
+	encrypted := "x0om+djmCDXP6ugTn+mmrinVfmY="
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("positive: secret saved with encrypted values", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into secrets").
+			WithArgs(userLogin, name, encrypted, nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		id, err := pg.SaveSecret(ctx, internal.Secret{UserName: userLogin, Name: &name, Values: values})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("negative: duplicate name", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into secrets").WillReturnError(ErrDublicateKey{Key: "secrets_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveSecret(ctx, internal.Secret{UserName: userLogin, Name: &name, Values: values})
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+	})
+	t.Run("positive: secret by name with decrypted values", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from secrets where user_name = \\$1 and name = \\$2 order by name").WithArgs(userLogin, name).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "name", "data", "metadata", "created_at", "updated_at"}).
+				AddRow(2, userLogin, name, encrypted, "ci", created, created))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		secrets, err := pg.GetSecrets(ctx, internal.Secret{UserName: userLogin, Name: &name})
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.Secret{{
+			ID:        2,
+			UserName:  userLogin,
+			Name:      &name,
+			Values:    values,
+			Metadata:  Ptr("ci"),
+			CreatedAt: &created,
+			UpdatedAt: &created,
+		}}, secrets)
+	})
+	t.Run("positive: secret updated", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("update secrets set data = \\$1, metadata = \\$2, updated_at = now\\(\\) where user_name = \\$3 and id = \\$4").
+			WithArgs(encrypted, "ci", userLogin, int64(2)).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		assert.NoError(t, pg.UpdateSecret(ctx, internal.Secret{ID: 2, UserName: userLogin, Values: values, Metadata: Ptr("ci")}))
+	})
+	t.Run("negative: no secret to delete", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from secrets").WithArgs(userLogin, int64(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		pg := db{conn: mockDB}
+		assert.ErrorIs(t, pg.DeleteSecret(ctx, internal.Secret{UserName: userLogin, ID: 3}), ErrNoData)
+	})
+}
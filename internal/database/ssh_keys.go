@@ -0,0 +1,97 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"time"
+)
+
+// SaveSSHKey is a method for saving the SSH key of authorized user, the private key is encrypted.
+// Names of the keys are unique for the user, the id of the saved key is returned.
+func (d *db) SaveSSHKey(ctx context.Context, key internal.SSHKey) (int64, error) {
+	encryptedKey, err := d.encryptAES(*key.PrivateKey)
+	if err != nil {
+		return 0, fmt.Errorf("error while encrypting ssh private key: %w", err)
+	}
+	var id int64
+	saveSSHKeyQuery := "insert into ssh_keys (user_name, name, type, public_key, fingerprint, private_key, comment, metadata) values ($1, $2, $3, $4, $5, $6, $7, $8) returning id"
+	if err = d.conn.QueryRowContext(ctx, saveSSHKeyQuery, key.UserName, *key.Name, key.Type, key.PublicKey, key.Fingerprint, encryptedKey,
+		key.Comment, key.Metadata).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "ssh_keys_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving ssh key for user %q: %w", key.UserName, err)
+	}
+	return id, nil
+}
+
+// GetSSHKeys is a method for getting the SSH keys of provided user ordered by name with decrypted private keys.
+// ID and name are optional parameters.
+func (d *db) GetSSHKeys(ctx context.Context, keyRequest internal.SSHKey) ([]internal.SSHKey, error) {
+	args := []any{keyRequest.UserName}
+	getSSHKeysQuery := "select id, user_name, name, type, public_key, fingerprint, private_key, comment, metadata, created_at, updated_at from ssh_keys where user_name = $1"
+	if keyRequest.ID != 0 {
+		args = append(args, keyRequest.ID)
+		getSSHKeysQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
+	if keyRequest.Name != nil {
+		args = append(args, *keyRequest.Name)
+		getSSHKeysQuery += fmt.Sprintf(" and name = $%d", len(args))
+	}
+	rows, err := d.conn.QueryContext(ctx, getSSHKeysQuery+" order by name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting ssh keys for user %q: %w", keyRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var keys []internal.SSHKey
+	for rows.Next() {
+		var key internal.SSHKey
+		var name, privateKey string
+		var comment, metadata sql.NullString
+		var createdAt, updatedAt time.Time
+		if err = rows.Scan(&key.ID, &key.UserName, &name, &key.Type, &key.PublicKey, &key.Fingerprint, &privateKey,
+			&comment, &metadata, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user ssh keys query: %w", err)
+		}
+		decryptedKey, err := d.decryptAES(privateKey)
+		if err != nil {
+			return nil, fmt.Errorf("error while decrypting ssh private key: %w", err)
+		}
+		key.Name = &name
+		key.PrivateKey = &decryptedKey
+		key.Comment = comment.String
+		key.CreatedAt = &createdAt
+		key.UpdatedAt = &updatedAt
+		if metadata.Valid {
+			key.Metadata = &metadata.String
+		}
+		keys = append(keys, key)
+	}
+	if len(keys) == 0 {
+		return nil, ErrNoData
+	}
+	return keys, nil
+}
+
+// DeleteSSHKey is a method for deleting the SSH key of provided user by id, ErrNoData is returned if there is no such one.
+func (d *db) DeleteSSHKey(ctx context.Context, keyRequest internal.SSHKey) error {
+	res, err := d.conn.ExecContext(ctx, "delete from ssh_keys where user_name = $1 and id = $2", keyRequest.UserName, keyRequest.ID)
+	if err != nil {
+		return fmt.Errorf("error while deleting ssh key for user %q: %w", keyRequest.UserName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while deleting ssh key for user %q: %w", keyRequest.UserName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	return nil
+}
@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDb_SSHKeys(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "samwell"
+	name := "citadel"
+	privateKey := "qwerty12"
+	publicKey := "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIGJvZ3VzLWtleS1mb3ItdGVzdHMtb25seS0wMDAw sam@citadel"
+	fingerprint := "SHA256:3pG8vKkPnvl2b5Q0M9Y0x1lU3vQJ0g0E0lQeZkq0t1Y"
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("positive: ssh key saved with encrypted private key", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into ssh_keys").
+			WithArgs(userLogin, name, "ssh-ed25519", publicKey, fingerprint, "zR8XxOfadyU=", "sam@citadel", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		id, err := pg.SaveSSHKey(ctx, internal.SSHKey{UserName: userLogin, Name: &name, Type: "ssh-ed25519", PublicKey: publicKey,
+			Fingerprint: fingerprint, PrivateKey: &privateKey, Comment: "sam@citadel"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("negative: duplicate name", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into ssh_keys").WillReturnError(ErrDublicateKey{Key: "ssh_keys_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveSSHKey(ctx, internal.SSHKey{UserName: userLogin, Name: &name, PrivateKey: &privateKey})
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+	})
+	t.Run("positive: ssh key by name with decrypted private key", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from ssh_keys where user_name = \\$1 and name = \\$2 order by name").WithArgs(userLogin, name).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "name", "type", "public_key", "fingerprint", "private_key", "comment", "metadata", "created_at", "updated_at"}).
+				AddRow(3, userLogin, name, "ssh-ed25519", publicKey, fingerprint, "zR8XxOfadyU=", nil, "maesters", created, created))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		keys, err := pg.GetSSHKeys(ctx, internal.SSHKey{UserName: userLogin, Name: &name})
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.SSHKey{{
+			ID:          3,
+			UserName:    userLogin,
+			Name:        &name,
+			Type:        "ssh-ed25519",
+			PublicKey:   publicKey,
+			Fingerprint: fingerprint,
+			PrivateKey:  &privateKey,
+			Metadata:    Ptr("maesters"),
+			CreatedAt:   &created,
+			UpdatedAt:   &created,
+		}}, keys)
+	})
+	t.Run("negative: no ssh keys", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from ssh_keys where user_name = \\$1 order by name").WithArgs(userLogin).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "name", "type", "public_key", "fingerprint", "private_key", "comment", "metadata", "created_at", "updated_at"}))
+
+		pg := db{conn: mockDB}
+		_, err = pg.GetSSHKeys(ctx, internal.SSHKey{UserName: userLogin})
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+	t.Run("negative: no ssh key to delete", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from ssh_keys").WithArgs(userLogin, int64(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		pg := db{conn: mockDB}
+		assert.ErrorIs(t, pg.DeleteSSHKey(ctx, internal.SSHKey{UserName: userLogin, ID: 3}), ErrNoData)
+	})
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"time"
+)
+
+// SaveTOTP is a method for saving the authenticator of authorized user, the secret is encrypted.
+// Names of the authenticators are unique for the user, the id of the saved authenticator is returned.
+func (d *db) SaveTOTP(ctx context.Context, totp internal.TOTP) (int64, error) {
+	encryptedSecret, err := d.encryptAES(*totp.Secret)
+	if err != nil {
+		return 0, fmt.Errorf("error while encrypting totp secret: %w", err)
+	}
+	var id int64
+	saveTOTPQuery := "insert into totp (user_name, name, issuer, account, secret, digits, period, algorithm, metadata) values ($1, $2, $3, $4, $5, $6, $7, $8, $9) returning id"
+	if err = d.conn.QueryRowContext(ctx, saveTOTPQuery, totp.UserName, *totp.Name, totp.Issuer, totp.Account, encryptedSecret,
+		totp.Digits, totp.Period, totp.Algorithm, totp.Metadata).Scan(&id); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "totp_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return 0, ErrItemAlreadyExists
+		}
+		return 0, fmt.Errorf("error while saving totp for user %q: %w", totp.UserName, err)
+	}
+	return id, nil
+}
+
+// GetTOTP is a method for getting the authenticators of provided user ordered by name with decrypted secrets.
+// ID and name are optional parameters.
+func (d *db) GetTOTP(ctx context.Context, totpRequest internal.TOTP) ([]internal.TOTP, error) {
+	args := []any{totpRequest.UserName}
+	getTOTPQuery := "select id, user_name, name, issuer, account, secret, digits, period, algorithm, metadata, created_at, updated_at from totp where user_name = $1"
+	if totpRequest.ID != 0 {
+		args = append(args, totpRequest.ID)
+		getTOTPQuery += fmt.Sprintf(" and id = $%d", len(args))
+	}
+	if totpRequest.Name != nil {
+		args = append(args, *totpRequest.Name)
+		getTOTPQuery += fmt.Sprintf(" and name = $%d", len(args))
+	}
+	rows, err := d.conn.QueryContext(ctx, getTOTPQuery+" order by name", args...)
+	if err != nil {
+		return nil, fmt.Errorf("error while getting totp for user %q: %w", totpRequest.UserName, err)
+	}
+	defer func() {
+		_ = rows.Close()
+		_ = rows.Err()
+	}()
+
+	var authenticators []internal.TOTP
+	for rows.Next() {
+		var totp internal.TOTP
+		var name, secret string
+		var issuer, account, metadata sql.NullString
+		var createdAt, updatedAt time.Time
+		if err = rows.Scan(&totp.ID, &totp.UserName, &name, &issuer, &account, &secret, &totp.Digits, &totp.Period,
+			&totp.Algorithm, &metadata, &createdAt, &updatedAt); err != nil {
+			return nil, fmt.Errorf("error while scanning rows after get user totp query: %w", err)
+		}
+		decryptedSecret, err := d.decryptAES(secret)
+		if err != nil {
+			return nil, fmt.Errorf("error while decrypting totp secret: %w", err)
+		}
+		totp.Name = &name
+		totp.Secret = &decryptedSecret
+		totp.Issuer = issuer.String
+		totp.Account = account.String
+		totp.CreatedAt = &createdAt
+		totp.UpdatedAt = &updatedAt
+		if metadata.Valid {
+			totp.Metadata = &metadata.String
+		}
+		authenticators = append(authenticators, totp)
+	}
+	if len(authenticators) == 0 {
+		return nil, ErrNoData
+	}
+	return authenticators, nil
+}
+
+// DeleteTOTP is a method for deleting the authenticator of provided user by id, ErrNoData is returned if there is no such one.
+func (d *db) DeleteTOTP(ctx context.Context, totpRequest internal.TOTP) error {
+	res, err := d.conn.ExecContext(ctx, "delete from totp where user_name = $1 and id = $2", totpRequest.UserName, totpRequest.ID)
+	if err != nil {
+		return fmt.Errorf("error while deleting totp for user %q: %w", totpRequest.UserName, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while deleting totp for user %q: %w", totpRequest.UserName, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestDb_TOTP(t *testing.T) {
+	key := "thisis32bitlongpassphraseimusing"
+	c, _ := aes.NewCipher([]byte(key))
+	userLogin := "samwell"
+	name := "citadel"
+	secret := "qwerty12"
+	created := time.Date(2026, 10, 1, 12, 0, 0, 0, time.UTC)
+	ctx := context.Background()
+
+	t.Run("positive: totp saved with encrypted secret", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into totp").
+			WithArgs(userLogin, name, "citadel", "sam", "zR8XxOfadyU=", 6, 30, "SHA1", nil).
+			WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow(1))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		id, err := pg.SaveTOTP(ctx, internal.TOTP{UserName: userLogin, Name: &name, Issuer: "citadel", Account: "sam", Secret: &secret,
+			Digits: 6, Period: 30, Algorithm: "SHA1"})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(1), id)
+	})
+	t.Run("negative: duplicate name", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("insert into totp").WillReturnError(ErrDublicateKey{Key: "totp_pkey"})
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		_, err = pg.SaveTOTP(ctx, internal.TOTP{UserName: userLogin, Name: &name, Secret: &secret})
+		assert.ErrorIs(t, err, ErrItemAlreadyExists)
+	})
+	t.Run("positive: totp by name with decrypted secret", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("from totp where user_name = \\$1 and name = \\$2 order by name").WithArgs(userLogin, name).
+			WillReturnRows(sqlmock.NewRows([]string{"id", "user_name", "name", "issuer", "account", "secret", "digits", "period", "algorithm", "metadata", "created_at", "updated_at"}).
+				AddRow(3, userLogin, name, "citadel", nil, "zR8XxOfadyU=", 8, 60, "SHA256", "maesters", created, created))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: key,
+			dataCipher:    c,
+		}
+		authenticators, err := pg.GetTOTP(ctx, internal.TOTP{UserName: userLogin, Name: &name})
+		assert.NoError(t, err)
+		assert.Equal(t, []internal.TOTP{{
+			ID:        3,
+			UserName:  userLogin,
+			Name:      &name,
+			Issuer:    "citadel",
+			Secret:    &secret,
+			Digits:    8,
+			Period:    60,
+			Algorithm: "SHA256",
+			Metadata:  Ptr("maesters"),
+			CreatedAt: &created,
+			UpdatedAt: &created,
+		}}, authenticators)
+	})
+	t.Run("negative: no totp to delete", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectExec("delete from totp").WithArgs(userLogin, int64(3)).WillReturnResult(sqlmock.NewResult(0, 0))
+
+		pg := db{conn: mockDB}
+		assert.ErrorIs(t, pg.DeleteTOTP(ctx, internal.TOTP{UserName: userLogin, ID: 3}), ErrNoData)
+	})
+}
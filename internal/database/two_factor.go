@@ -0,0 +1,118 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+)
+
+// SaveTwoFactorSecret is a method for saving not yet confirmed TOTP secret for provided user.
+// The secret is stored in the encrypted form. Secret can't be replaced once two-factor authentication is enabled.
+func (d *db) SaveTwoFactorSecret(ctx context.Context, login string, secret string) error {
+	encryptedSecret, err := d.encryptAES(secret)
+	if err != nil {
+		return fmt.Errorf("error encrypting two-factor secret: %w", err)
+	}
+	saveSecretQuery := `insert into two_factor (login, secret, enabled) values ($1, $2, false)
+on conflict (login) do update set secret = excluded.secret where two_factor.enabled = false`
+	res, err := d.conn.ExecContext(ctx, saveSecretQuery, login, encryptedSecret)
+	if err != nil {
+		return fmt.Errorf("error while saving two-factor secret for user %q: %w", login, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while saving two-factor secret for user %q: %w", login, err)
+	}
+	if affected == 0 {
+		return ErrTwoFactorEnabled
+	}
+	return nil
+}
+
+// GetTwoFactor is a method for getting two-factor authentication settings of provided user.
+// ErrNoData is returned if user has never started two-factor authentication setup.
+func (d *db) GetTwoFactor(ctx context.Context, login string) (internal.TwoFactor, error) {
+	getTwoFactorQuery := "select secret, enabled from two_factor where login = $1"
+
+	var secret string
+	var enabled bool
+	if err := d.conn.QueryRowContext(ctx, getTwoFactorQuery, login).Scan(&secret, &enabled); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return internal.TwoFactor{}, ErrNoData
+		}
+		return internal.TwoFactor{}, fmt.Errorf("error while getting two-factor settings for user %q: %w", login, err)
+	}
+	decryptedSecret, err := d.decryptAES(secret)
+	if err != nil {
+		return internal.TwoFactor{}, fmt.Errorf("error while decrypting two-factor secret: %w", err)
+	}
+	return internal.TwoFactor{
+		Login:   login,
+		Secret:  decryptedSecret,
+		Enabled: enabled,
+	}, nil
+}
+
+// EnableTwoFactor is a method for enabling two-factor authentication for provided user.
+// Previously generated recovery codes are replaced with the provided ones, only their hashes are stored.
+func (d *db) EnableTwoFactor(ctx context.Context, login string, recoveryCodes []string) error {
+	tx, err := d.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error while starting transaction: %w", err)
+	}
+	defer func() {
+		_ = tx.Rollback()
+	}()
+
+	res, err := tx.ExecContext(ctx, "update two_factor set enabled = true where login = $1", login)
+	if err != nil {
+		return fmt.Errorf("error while enabling two-factor authentication for user %q: %w", login, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while enabling two-factor authentication for user %q: %w", login, err)
+	}
+	if affected == 0 {
+		return ErrNoData
+	}
+	if _, err = tx.ExecContext(ctx, "delete from recovery_codes where login = $1", login); err != nil {
+		return fmt.Errorf("error while deleting recovery codes for user %q: %w", login, err)
+	}
+	for _, code := range recoveryCodes {
+		if _, err = tx.ExecContext(ctx, "insert into recovery_codes (login, code) values ($1, $2)", login, hashRecoveryCode(code)); err != nil {
+			return fmt.Errorf("error while saving recovery codes for user %q: %w", login, err)
+		}
+	}
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("error while committing transaction: %w", err)
+	}
+	return nil
+}
+
+// UseRecoveryCode is a method for spending one of the user's recovery codes.
+// Each recovery code can be used only once.
+func (d *db) UseRecoveryCode(ctx context.Context, login string, code string) error {
+	useCodeQuery := "delete from recovery_codes where login = $1 and code = $2"
+	res, err := d.conn.ExecContext(ctx, useCodeQuery, login, hashRecoveryCode(code))
+	if err != nil {
+		return fmt.Errorf("error while using recovery code for user %q: %w", login, err)
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("error while using recovery code for user %q: %w", login, err)
+	}
+	if affected == 0 {
+		return ErrInvalidRecoveryCode
+	}
+	return nil
+}
+
+// recovery codes are random and long enough, so a plain sha256 is sufficient and allows lookups by hash.
+func hashRecoveryCode(code string) string {
+	sum := sha256.Sum256([]byte(code))
+	return hex.EncodeToString(sum[:])
+}
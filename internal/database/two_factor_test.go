@@ -0,0 +1,274 @@
+package database
+
+import (
+	"context"
+	"crypto/aes"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDb_SaveTwoFactorSecret(t *testing.T) {
+	userLogin := "tyrion"
+	secret := "JBSWY3DPEHPK3PXP"
+	encryptionKey := "0123456789abcdef"
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		affected      int64
+		expectedError error
+	}{
+		{
+			name:     "positive: secret saved",
+			affected: 1,
+		},
+		{
+			name:          "negative: already enabled",
+			affected:      0,
+			expectedError: ErrTwoFactorEnabled,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer mockDB.Close()
+			c, err := aes.NewCipher([]byte(encryptionKey))
+			assert.NoError(t, err)
+
+			pg := db{
+				conn:          mockDB,
+				encriptionKey: encryptionKey,
+				dataCipher:    c,
+			}
+			encryptedSecret, err := pg.encryptAES(secret)
+			assert.NoError(t, err)
+			mock.ExpectExec("insert into two_factor").
+				WithArgs(userLogin, encryptedSecret).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			err = pg.SaveTwoFactorSecret(ctx, userLogin, secret)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+	t.Run("negative: query error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		c, err := aes.NewCipher([]byte(encryptionKey))
+		assert.NoError(t, err)
+
+		mock.ExpectExec("insert into two_factor").
+			WillReturnError(errors.New("insert error"))
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: encryptionKey,
+			dataCipher:    c,
+		}
+		err = pg.SaveTwoFactorSecret(ctx, userLogin, secret)
+		assert.EqualError(t, err, `error while saving two-factor secret for user "tyrion": insert error`)
+	})
+}
+
+func TestDb_GetTwoFactor(t *testing.T) {
+	userLogin := "tyrion"
+	secret := "JBSWY3DPEHPK3PXP"
+	encryptionKey := "0123456789abcdef"
+	ctx := context.Background()
+
+	t.Run("positive: enabled", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+		c, err := aes.NewCipher([]byte(encryptionKey))
+		assert.NoError(t, err)
+
+		pg := db{
+			conn:          mockDB,
+			encriptionKey: encryptionKey,
+			dataCipher:    c,
+		}
+		encryptedSecret, err := pg.encryptAES(secret)
+		assert.NoError(t, err)
+		mock.ExpectQuery("select secret, enabled from two_factor where login").
+			WithArgs(userLogin).
+			WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}).AddRow(encryptedSecret, true))
+
+		twoFactor, err := pg.GetTwoFactor(ctx, userLogin)
+		assert.NoError(t, err)
+		assert.Equal(t, internal.TwoFactor{Login: userLogin, Secret: secret, Enabled: true}, twoFactor)
+	})
+	t.Run("negative: no data", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select secret, enabled from two_factor where login").
+			WithArgs(userLogin).
+			WillReturnRows(sqlmock.NewRows([]string{"secret", "enabled"}))
+
+		pg := db{
+			conn: mockDB,
+		}
+		_, err = pg.GetTwoFactor(ctx, userLogin)
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+	t.Run("negative: query error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select secret, enabled from two_factor where login").
+			WithArgs(userLogin).
+			WillReturnError(errors.New("select error"))
+
+		pg := db{
+			conn: mockDB,
+		}
+		_, err = pg.GetTwoFactor(ctx, userLogin)
+		assert.EqualError(t, err, `error while getting two-factor settings for user "tyrion": select error`)
+	})
+}
+
+func TestDb_EnableTwoFactor(t *testing.T) {
+	userLogin := "tyrion"
+	codes := []string{"abcde-fghjk", "mnpqr-stuvw"}
+	ctx := context.Background()
+
+	t.Run("positive: enabled", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("update two_factor set enabled = true").
+			WithArgs(userLogin).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from recovery_codes").
+			WithArgs(userLogin).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		for _, code := range codes {
+			mock.ExpectExec("insert into recovery_codes").
+				WithArgs(userLogin, hashRecoveryCode(code)).
+				WillReturnResult(sqlmock.NewResult(0, 1))
+		}
+		mock.ExpectCommit()
+
+		pg := db{
+			conn: mockDB,
+		}
+		err = pg.EnableTwoFactor(ctx, userLogin, codes)
+		assert.NoError(t, err)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: setup was not started", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("update two_factor set enabled = true").
+			WithArgs(userLogin).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn: mockDB,
+		}
+		err = pg.EnableTwoFactor(ctx, userLogin, codes)
+		assert.ErrorIs(t, err, ErrNoData)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+	t.Run("negative: insert error", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectBegin()
+		mock.ExpectExec("update two_factor set enabled = true").
+			WithArgs(userLogin).
+			WillReturnResult(sqlmock.NewResult(0, 1))
+		mock.ExpectExec("delete from recovery_codes").
+			WithArgs(userLogin).
+			WillReturnResult(sqlmock.NewResult(0, 0))
+		mock.ExpectExec("insert into recovery_codes").
+			WillReturnError(errors.New("insert error"))
+		mock.ExpectRollback()
+
+		pg := db{
+			conn: mockDB,
+		}
+		err = pg.EnableTwoFactor(ctx, userLogin, codes)
+		assert.EqualError(t, err, `error while saving recovery codes for user "tyrion": insert error`)
+		assert.NoError(t, mock.ExpectationsWereMet())
+	})
+}
+
+func TestDb_UseRecoveryCode(t *testing.T) {
+	userLogin := "tyrion"
+	code := "abcde-fghjk"
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		affected      int64
+		expectedError error
+	}{
+		{
+			name:     "positive: code used",
+			affected: 1,
+		},
+		{
+			name:          "negative: unknown or already used code",
+			affected:      0,
+			expectedError: ErrInvalidRecoveryCode,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer mockDB.Close()
+
+			mock.ExpectExec("delete from recovery_codes where login").
+				WithArgs(userLogin, hashRecoveryCode(code)).
+				WillReturnResult(sqlmock.NewResult(0, tt.affected))
+
+			pg := db{
+				conn: mockDB,
+			}
+			err = pg.UseRecoveryCode(ctx, userLogin, code)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
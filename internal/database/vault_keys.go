@@ -0,0 +1,39 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+)
+
+// SaveVaultKey is a method for saving the wrapped vault key of provided user.
+// The key is wrapped on the client side, so it's stored as is.
+func (d *db) SaveVaultKey(ctx context.Context, login string, key internal.VaultKey) error {
+	saveKeyQuery := `insert into vault_keys (login, password_salt, password_wrapped_key, recovery_wrapped_key, recovery_verifier)
+values ($1, $2, $3, $4, $5)`
+	if _, err := d.conn.ExecContext(ctx, saveKeyQuery, login, key.PasswordSalt, key.PasswordWrappedKey, key.RecoveryWrappedKey, key.RecoveryVerifier); err != nil {
+		dublicateKeyErr := ErrDublicateKey{Key: "vault_keys_pkey"}
+		if err.Error() == dublicateKeyErr.Error() {
+			return ErrKeyAlreadyExists
+		}
+		return fmt.Errorf("error while saving vault key for user %q: %w", login, err)
+	}
+	return nil
+}
+
+// GetVaultKey is a method for getting the wrapped vault key of provided user.
+// ErrNoData is returned for users registered without vault key.
+func (d *db) GetVaultKey(ctx context.Context, login string) (internal.VaultKey, error) {
+	getKeyQuery := "select password_salt, password_wrapped_key, recovery_wrapped_key, recovery_verifier from vault_keys where login = $1"
+
+	var key internal.VaultKey
+	if err := d.conn.QueryRowContext(ctx, getKeyQuery, login).Scan(&key.PasswordSalt, &key.PasswordWrappedKey, &key.RecoveryWrappedKey, &key.RecoveryVerifier); err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return internal.VaultKey{}, ErrNoData
+		}
+		return internal.VaultKey{}, fmt.Errorf("error while getting vault key for user %q: %w", login, err)
+	}
+	return key, nil
+}
@@ -0,0 +1,115 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestDb_SaveVaultKey(t *testing.T) {
+	login := "arya"
+	key := internal.VaultKey{
+		PasswordSalt:       "c2FsdA==",
+		PasswordWrappedKey: "cGFzc3dvcmQ=",
+		RecoveryWrappedKey: "cmVjb3Zlcnk=",
+		RecoveryVerifier:   "verifier",
+	}
+	ctx := context.Background()
+
+	testCases := []struct {
+		name          string
+		storageError  error
+		expectedError string
+	}{
+		{
+			name: "positive: key saved",
+		},
+		{
+			name:          "negative: key exists",
+			storageError:  ErrDublicateKey{Key: "vault_keys_pkey"},
+			expectedError: ErrKeyAlreadyExists.Error(),
+		},
+		{
+			name:          "negative: insert error",
+			storageError:  errors.New("insert error"),
+			expectedError: `error while saving vault key for user "arya": insert error`,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockDB, mock, err := sqlmock.New()
+			if err != nil {
+				t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+			}
+			defer mockDB.Close()
+
+			expectation := mock.ExpectExec("insert into vault_keys").
+				WithArgs(login, key.PasswordSalt, key.PasswordWrappedKey, key.RecoveryWrappedKey, key.RecoveryVerifier)
+			if tt.storageError != nil {
+				expectation.WillReturnError(tt.storageError)
+			} else {
+				expectation.WillReturnResult(sqlmock.NewResult(0, 1))
+			}
+
+			pg := db{
+				conn: mockDB,
+			}
+			err = pg.SaveVaultKey(ctx, login, key)
+			if tt.expectedError != "" {
+				assert.EqualError(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestDb_GetVaultKey(t *testing.T) {
+	login := "arya"
+	ctx := context.Background()
+	columns := []string{"password_salt", "password_wrapped_key", "recovery_wrapped_key", "recovery_verifier"}
+
+	t.Run("positive: key found", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select password_salt, password_wrapped_key, recovery_wrapped_key, recovery_verifier from vault_keys").
+			WithArgs(login).
+			WillReturnRows(sqlmock.NewRows(columns).AddRow("c2FsdA==", "cGFzc3dvcmQ=", "cmVjb3Zlcnk=", "verifier"))
+
+		pg := db{
+			conn: mockDB,
+		}
+		key, err := pg.GetVaultKey(ctx, login)
+		assert.NoError(t, err)
+		assert.Equal(t, internal.VaultKey{
+			PasswordSalt:       "c2FsdA==",
+			PasswordWrappedKey: "cGFzc3dvcmQ=",
+			RecoveryWrappedKey: "cmVjb3Zlcnk=",
+			RecoveryVerifier:   "verifier",
+		}, key)
+	})
+	t.Run("negative: no key", func(t *testing.T) {
+		mockDB, mock, err := sqlmock.New()
+		if err != nil {
+			t.Fatalf("an error '%s' was not expected when opening a stub database connection", err)
+		}
+		defer mockDB.Close()
+
+		mock.ExpectQuery("select password_salt, password_wrapped_key, recovery_wrapped_key, recovery_verifier from vault_keys").
+			WithArgs(login).
+			WillReturnRows(sqlmock.NewRows(columns))
+
+		pg := db{
+			conn: mockDB,
+		}
+		_, err = pg.GetVaultKey(ctx, login)
+		assert.ErrorIs(t, err, ErrNoData)
+	})
+}
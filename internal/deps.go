@@ -1,21 +1,70 @@
 package internal
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 //go:generate mockery --disable-version-string --filename storage_mock.go --name Storage
 type Storage interface {
-	SaveCredentials(ctx context.Context, credentialsRequest Credentials) error
+	SaveCredentials(ctx context.Context, credentialsRequest Credentials) (int64, error)
 	GetCredentials(ctx context.Context, credentialsRequest Credentials) ([]Credentials, error)
+	ListCredentials(ctx context.Context, credentialsRequest Credentials, opts ListOptions) ([]Credentials, string, error)
 	DeleteCredentials(ctx context.Context, credentialsRequest Credentials) error
-	UpdateCredentials(ctx context.Context, credentials Credentials) error
-	SaveNote(ctx context.Context, note Note) error
+	UpdateCredentials(ctx context.Context, credentials Credentials) (Rotation, error)
+	SaveNote(ctx context.Context, note Note) (int64, error)
 	GetNotes(ctx context.Context, noteRequest Note) ([]Note, error)
+	ListNotes(ctx context.Context, noteRequest Note, opts ListOptions) ([]Note, string, error)
 	DeleteNotes(ctx context.Context, noteRequest Note) error
 	UpdateNote(ctx context.Context, note Note) error
-	SaveCard(ctx context.Context, card Card) error
+	SaveCard(ctx context.Context, card Card) (int64, error)
 	GetCard(ctx context.Context, cardRequest Card) ([]Card, error)
+	ListCards(ctx context.Context, cardRequest Card, opts ListOptions) ([]Card, string, error)
 	DeleteCards(ctx context.Context, cardRequest Card) error
+	UpdateCard(ctx context.Context, card Card) (Rotation, error)
+	SaveFile(ctx context.Context, file File, content []byte) (int64, error)
+	GetFiles(ctx context.Context, fileRequest File) ([]File, error)
+	GetFileContent(ctx context.Context, fileRequest File) ([]byte, error)
+	DeleteFiles(ctx context.Context, fileRequest File) error
+	SaveTOTP(ctx context.Context, totp TOTP) (int64, error)
+	GetTOTP(ctx context.Context, totpRequest TOTP) ([]TOTP, error)
+	DeleteTOTP(ctx context.Context, totpRequest TOTP) error
+	SaveSSHKey(ctx context.Context, key SSHKey) (int64, error)
+	GetSSHKeys(ctx context.Context, keyRequest SSHKey) ([]SSHKey, error)
+	DeleteSSHKey(ctx context.Context, keyRequest SSHKey) error
+	SaveIdentity(ctx context.Context, identity Identity) (int64, error)
+	GetIdentities(ctx context.Context, identityRequest Identity, expiresBefore string) ([]Identity, error)
+	DeleteIdentity(ctx context.Context, identityRequest Identity) error
+	SaveSecret(ctx context.Context, secret Secret) (int64, error)
+	GetSecrets(ctx context.Context, secretRequest Secret) ([]Secret, error)
+	UpdateSecret(ctx context.Context, secret Secret) error
+	DeleteSecret(ctx context.Context, secretRequest Secret) error
+	GetDueItems(ctx context.Context, userName string, before time.Time) ([]DueItem, error)
+	GetPendingRotations(ctx context.Context, at time.Time) ([]DueItem, error)
+	SetRotationNotified(ctx context.Context, items []DueItem, at time.Time) error
+	GetExpiringCards(ctx context.Context, userName string, before time.Time) ([]ExpiringCard, error)
+	GetPendingCardExpiries(ctx context.Context, before time.Time) ([]ExpiringCard, error)
+	SetCardExpiryNotified(ctx context.Context, cards []ExpiringCard) error
+	Search(ctx context.Context, userName string, query string, limit int) ([]SearchResult, error)
+	SetLabels(ctx context.Context, userName string, itemType string, id int64, labels Labels) error
+	GetTags(ctx context.Context, userName string) ([]string, error)
+	SaveFolder(ctx context.Context, folder Folder) (int64, error)
+	GetFolders(ctx context.Context, userName string) ([]Folder, error)
+	UpdateFolder(ctx context.Context, folder Folder) error
+	DeleteFolder(ctx context.Context, userName string, id int64) error
+	GetFields(ctx context.Context, userName string, itemType string, ids []int64) (map[int64][]Field, error)
 	Register(ctx context.Context, login string, password string) error
 	Login(ctx context.Context, login string, password string) error
+	ChangePassword(ctx context.Context, login string, newPassword string, key *VaultKey) error
+	DeleteAccount(ctx context.Context, login string) error
+	SaveTwoFactorSecret(ctx context.Context, login string, secret string) error
+	GetTwoFactor(ctx context.Context, login string) (TwoFactor, error)
+	EnableTwoFactor(ctx context.Context, login string, recoveryCodes []string) error
+	UseRecoveryCode(ctx context.Context, login string, code string) error
+	SavePublicKey(ctx context.Context, key PublicKey) error
+	GetPublicKey(ctx context.Context, login string, keyID string) (PublicKey, error)
+	UpdateSignCount(ctx context.Context, login string, keyID string, signCount uint32) error
+	SaveVaultKey(ctx context.Context, login string, key VaultKey) error
+	GetVaultKey(ctx context.Context, login string) (VaultKey, error)
 	Close() error
 }
@@ -0,0 +1,56 @@
+package internal
+
+import "fmt"
+
+// Error codes are stable identifiers of the errors returned by the server, clients should rely on them
+// instead of the messages. Every code is always returned with the same HTTP status.
+const (
+	// ErrorCodeInvalidRequest means that the request body or parameters are malformed or incomplete (400).
+	ErrorCodeInvalidRequest = "invalid_request"
+	// ErrorCodeWeakPassword means that the password doesn't satisfy the policy or appears in data breaches (400).
+	ErrorCodeWeakPassword = "weak_password"
+	// ErrorCodeInvalidCredentials means that the user doesn't exist or the password is wrong (401).
+	ErrorCodeInvalidCredentials = "invalid_credentials"
+	// ErrorCodeInvalidSecondFactor means that the one-time or recovery code is wrong (401).
+	ErrorCodeInvalidSecondFactor = "invalid_second_factor"
+	// ErrorCodeInvalidRecoveryKey means that the user doesn't exist or the recovery key is wrong (401).
+	ErrorCodeInvalidRecoveryKey = "invalid_recovery_key"
+	// ErrorCodePublicKeyLoginFailed means that the assertion of the public key login can't be verified (401).
+	ErrorCodePublicKeyLoginFailed = "public_key_login_failed"
+	// ErrorCodeUnauthorized means that the user has not logged in (401).
+	ErrorCodeUnauthorized = "unauthorized"
+	// ErrorCodeInvalidToken means that the token is missing, malformed or forged (401).
+	ErrorCodeInvalidToken = "invalid_token"
+	// ErrorCodeTokenExpired means that the token has expired and the user should login again (401).
+	ErrorCodeTokenExpired = "token_expired"
+	// ErrorCodeSessionRevoked means that the token was replaced by a newer login or password change (401).
+	ErrorCodeSessionRevoked = "session_revoked"
+	// ErrorCodeForbidden means that the endpoint is disabled on the server (403).
+	ErrorCodeForbidden = "forbidden"
+	// ErrorCodeItemNotFound means that there is no requested credentials, note, card or key (404).
+	ErrorCodeItemNotFound = "item_not_found"
+	// ErrorCodeUserAlreadyExists means that the login is already taken (409).
+	ErrorCodeUserAlreadyExists = "user_already_exists"
+	// ErrorCodeDuplicateItem means that the item with the same login, title or card number already exists (409).
+	ErrorCodeDuplicateItem = "duplicate_item"
+	// ErrorCodeTwoFactorEnabled means that two-factor authentication is already enabled (409).
+	ErrorCodeTwoFactorEnabled = "two_factor_already_enabled"
+	// ErrorCodeKeyAlreadyExists means that the key is already registered (409).
+	ErrorCodeKeyAlreadyExists = "key_already_registered"
+	// ErrorCodeTooManyRequests means that login attempts are throttled, details contain `retry_after` seconds (429).
+	ErrorCodeTooManyRequests = "too_many_requests"
+	// ErrorCodeInternal means an unexpected server error, the request id helps to find it in the server logs (500).
+	ErrorCodeInternal = "internal_error"
+)
+
+// Error is the body of every error response of the server.
+type Error struct {
+	Code      string         `json:"code"`
+	Message   string         `json:"message"`
+	Details   map[string]any `json:"details,omitempty"`
+	RequestID string         `json:"request_id,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
@@ -0,0 +1,187 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/otp"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ChangePassword is a method for changing password of registered user.
+// The body of the HTTP request must contain `login`, current `password` and `new_password`,
+// users with enabled two-factor authentication must also provide TOTP `code`.
+// The new password is checked like on registration. Users with the vault key must provide it in the `vault`
+// re-wrapped with the new password, see GetVaultKey. Sessions opened before are invalidated,
+// the response contains a new token for the user.
+// For example: curl -X POST http://127.0.0.1:8080/auth/change-password --data `{"login": "user_login", "password": "old", "new_password": "new"}`
+func (h *handler) ChangePassword(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	request, err := parseAccountRequest(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if request.NewPassword == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "new password should not be empty")
+		return
+	}
+	// the current password is required even for authorized user
+	if !h.reauthenticate(ctx, w, r, request) {
+		return
+	}
+	if err = h.checkPassword(ctx, &internal.User{Login: request.Login, Password: request.NewPassword}); err != nil {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+	// the vault key becomes inaccessible if it's not re-wrapped with the new password
+	if _, err = h.db.GetVaultKey(ctx, request.Login); err != nil && !errors.Is(err, database.ErrNoData) {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+	hasVault := err == nil
+	if hasVault && (request.Vault == nil || !validVaultKey(request.Vault, false)) {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "vault key should be re-wrapped with the new password")
+		return
+	}
+	if !hasVault {
+		request.Vault = nil
+	}
+
+	// replace password hash in goph-keeper storage
+	if err = h.db.ChangePassword(ctx, request.Login, request.NewPassword, request.Vault); err != nil {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+
+	// the new token replaces the remembered one, so other sessions are no longer valid
+	if err = h.authorize(w, request.Login); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	h.log.Infof("password of user %q was successfully changed", request.Login)
+}
+
+// DeleteAccount is a method for deleting registered user with all his data from goph-keeper system.
+// The body of the HTTP request must contain `login` and `password`,
+// users with enabled two-factor authentication must also provide TOTP `code`.
+// For example: curl -X POST http://127.0.0.1:8080/auth/delete-account --data `{"login": "user_login", "password": "user_password"}`
+func (h *handler) DeleteAccount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	request, err := parseAccountRequest(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if !h.reauthenticate(ctx, w, r, request) {
+		return
+	}
+
+	// delete user data from goph-keeper storage
+	if err = h.db.DeleteAccount(ctx, request.Login); err != nil {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+	h.cookies.delete(request.Login)
+
+	// response
+	if _, err = io.WriteString(w, fmt.Sprintf("account of user %q was deleted", request.Login)); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	h.log.Infof("account of user %q was deleted", request.Login)
+}
+
+// GetVaultKey is a method for getting the vault key of registered user wrapped with his password.
+// The body of the HTTP request must contain `login` and `password`,
+// users with enabled two-factor authentication must also provide TOTP `code`.
+// For example: curl -X POST http://127.0.0.1:8080/auth/vault --data `{"login": "user_login", "password": "user_password"}`
+func (h *handler) GetVaultKey(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	request, err := parseAccountRequest(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if !h.reauthenticate(ctx, w, r, request) {
+		return
+	}
+
+	// get the key from goph-keeper storage, only the password wrapping is returned
+	key, err := h.db.GetVaultKey(ctx, request.Login)
+	if err != nil {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+	keyResponse, err := json.Marshal(internal.VaultKey{
+		PasswordSalt:       key.PasswordSalt,
+		PasswordWrappedKey: key.PasswordWrappedKey,
+	})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if _, err = w.Write(keyResponse); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+}
+
+// reauthenticate checks password and, if it's enabled, the second factor of the user before sensitive account changes.
+// Failed attempts are counted like failed logins. The error response is written if the check fails.
+func (h *handler) reauthenticate(ctx context.Context, w http.ResponseWriter, r *http.Request, request internal.AccountRequest) bool {
+	address := clientAddress(r)
+	if h.throttled(w, r, request.Login, address) {
+		return false
+	}
+	if err := h.db.Login(ctx, request.Login, request.Password); err != nil {
+		if errors.Is(err, database.ErrNoSuchUser) || errors.Is(err, database.ErrInvalidCredentials) {
+			h.loginFailed(request.Login, address)
+		}
+		h.writeUserError(w, r, request.Login, err)
+		return false
+	}
+	twoFactor, err := h.db.GetTwoFactor(ctx, request.Login)
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		h.writeUserError(w, r, request.Login, err)
+		return false
+	}
+	key := otp.Key{Secret: twoFactor.Secret}
+	if twoFactor.Enabled && !h.usedCodes.accept(request.Login, key, request.Code, time.Now()) {
+		h.loginFailed(request.Login, address)
+		h.writeUserError(w, r, request.Login, ErrInvalidOTP)
+		return false
+	}
+	h.accountThrottle.reset(request.Login)
+	return true
+}
+
+func parseAccountRequest(r io.Reader) (internal.AccountRequest, error) {
+	var request internal.AccountRequest
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return request, fmt.Errorf("error while reading request body: %w", err)
+	}
+	if err = json.Unmarshal(body, &request); err != nil {
+		return request, fmt.Errorf("error while unmarshalling request body: %w", err)
+	}
+	if request.Login == "" || request.Password == "" {
+		return request, fmt.Errorf("login or password is empty")
+	}
+	return request, nil
+}
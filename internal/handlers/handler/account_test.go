@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/otp"
+	keeperPassword "github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newAccountServer(mockedStorage *mocks.Storage) (*handler, *httptest.Server) {
+	logger, _ := zap.NewProduction()
+	h := New(mockedStorage, logger.Sugar(), WithPasswordPolicy(keeperPassword.Policy{
+		MinLength: keeperPassword.DefaultMinLength,
+		MinScore:  keeperPassword.DefaultMinScore,
+	}))
+
+	r := chi.NewRouter()
+	r.Post("/auth/change-password", h.ChangePassword)
+	r.Post("/auth/delete-account", h.DeleteAccount)
+	return h, httptest.NewServer(r)
+}
+
+func TestHandler_ChangePassword(t *testing.T) {
+	userName := "brienne"
+	password := "oathkeeper"
+	newPassword := "sapphire isle of tarth"
+	rewrapped := &internal.VaultKey{PasswordSalt: "c2FsdA==", PasswordWrappedKey: "d3JhcHBlZA=="}
+
+	testCases := []struct {
+		name          string
+		body          string
+		loginError    error
+		vaultError    error
+		vault         *internal.VaultKey
+		callChange    bool
+		changeError   error
+		expectedCode  int
+		expectedToken bool
+	}{
+		{
+			name:          "positive: password changed",
+			body:          fmt.Sprintf(`{"login": %q, "password": %q, "new_password": %q}`, userName, password, newPassword),
+			vaultError:    database.ErrNoData,
+			callChange:    true,
+			expectedCode:  http.StatusOK,
+			expectedToken: true,
+		},
+		{
+			name: "positive: password changed with re-wrapped vault key",
+			body: fmt.Sprintf(`{"login": %q, "password": %q, "new_password": %q, "vault": {"password_salt": "c2FsdA==", "password_wrapped_key": "d3JhcHBlZA=="}}`,
+				userName, password, newPassword),
+			vault:         rewrapped,
+			callChange:    true,
+			expectedCode:  http.StatusOK,
+			expectedToken: true,
+		},
+		{
+			name:         "negative: vault key is not re-wrapped",
+			body:         fmt.Sprintf(`{"login": %q, "password": %q, "new_password": %q}`, userName, password, newPassword),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "negative: wrong current password",
+			body:         fmt.Sprintf(`{"login": %q, "password": "wrong", "new_password": %q}`, userName, newPassword),
+			loginError:   database.ErrInvalidCredentials,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "negative: weak new password",
+			body:         fmt.Sprintf(`{"login": %q, "password": %q, "new_password": "brienne1"}`, userName, password),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "negative: no new password",
+			body:         fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "negative: db error",
+			body:         fmt.Sprintf(`{"login": %q, "password": %q, "new_password": %q}`, userName, password, newPassword),
+			vaultError:   database.ErrNoData,
+			callChange:   true,
+			changeError:  fmt.Errorf("some db error"),
+			expectedCode: http.StatusInternalServerError,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockedStorage := mocks.NewStorage(t)
+			mockedStorage.On("Login", mock.Anything, userName, mock.AnythingOfType("string")).Return(tt.loginError).Maybe()
+			mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData).Maybe()
+			mockedStorage.On("GetVaultKey", mock.Anything, userName).Return(internal.VaultKey{}, tt.vaultError).Maybe()
+			if tt.callChange {
+				mockedStorage.On("ChangePassword", mock.Anything, userName, newPassword, tt.vault).Return(tt.changeError)
+			}
+			h, srv := newAccountServer(mockedStorage)
+			defer srv.Close()
+			h.cookies.set(userName, "Bearer old-session")
+
+			resp, err := resty.New().R().
+				SetHeader("content-type", "application/json").
+				SetBody(tt.body).
+				Post(fmt.Sprintf("%s/auth/change-password", srv.URL))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode())
+			if tt.expectedToken {
+				assert.NotEmpty(t, resp.Header().Get("Authorization"))
+				assert.Equal(t, resp.Header().Get("Authorization"), h.cookies.get(userName))
+			} else {
+				assert.Equal(t, "Bearer old-session", h.cookies.get(userName))
+			}
+		})
+	}
+}
+
+func TestHandler_DeleteAccount(t *testing.T) {
+	userName := "brienne"
+	password := "oathkeeper"
+	key, err := otp.NewKey(otpIssuer, userName)
+	assert.NoError(t, err)
+
+	deleteAccount := func(srv *httptest.Server, body string) *resty.Response {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(body).
+			Post(fmt.Sprintf("%s/auth/delete-account", srv.URL))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("positive: account deleted", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("DeleteAccount", mock.Anything, userName).Return(nil)
+		h, srv := newAccountServer(mockedStorage)
+		defer srv.Close()
+		h.cookies.set(userName, "Bearer session")
+
+		resp := deleteAccount(srv, fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password))
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("positive: account with two-factor authentication deleted", func(t *testing.T) {
+		code, err := key.Code(time.Now())
+		assert.NoError(t, err)
+
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Login: userName, Secret: key.Secret, Enabled: true}, nil)
+		mockedStorage.On("DeleteAccount", mock.Anything, userName).Return(nil)
+		_, srv := newAccountServer(mockedStorage)
+		defer srv.Close()
+
+		resp := deleteAccount(srv, fmt.Sprintf(`{"login": %q, "password": %q, "code": %q}`, userName, password, code))
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+	})
+	t.Run("negative: two-factor code is missing", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Login: userName, Secret: key.Secret, Enabled: true}, nil)
+		_, srv := newAccountServer(mockedStorage)
+		defer srv.Close()
+
+		resp := deleteAccount(srv, fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("negative: wrong password", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "wrong").Return(database.ErrInvalidCredentials)
+		h, srv := newAccountServer(mockedStorage)
+		defer srv.Close()
+		h.cookies.set(userName, "Bearer session")
+
+		resp := deleteAccount(srv, fmt.Sprintf(`{"login": %q, "password": "wrong"}`, userName))
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Equal(t, "Bearer session", h.cookies.get(userName))
+	})
+}
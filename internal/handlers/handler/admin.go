@@ -0,0 +1,70 @@
+package handler
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"io"
+	"net/http"
+)
+
+// AdminAuth is a method for checking if the request is authorized with the admin token.
+// Admin endpoints are disabled if no admin token is configured.
+func (h *handler) AdminAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if h.adminToken == "" {
+			writeError(w, r, http.StatusForbidden, internal.ErrorCodeForbidden, "admin endpoints are disabled")
+			return
+		}
+		expected := []byte(fmt.Sprintf("Bearer %s", h.adminToken))
+		if subtle.ConstantTimeCompare([]byte(r.Header.Get("Authorization")), expected) != 1 {
+			writeError(w, r, http.StatusUnauthorized, internal.ErrorCodeInvalidToken, "invalid admin token")
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// UnlockAccount is a method for resetting failed login attempts for the account and optionally for the client address.
+// The request must be authorized with the admin token.
+// For example:
+// curl -X POST http://127.0.0.1:8080/admin/unlock -H "Authorization: Bearer admin_token" --data `{"login": "user_login", "address": "127.0.0.1"}`
+func (h *handler) UnlockAccount(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	// parse body
+	var buf bytes.Buffer
+	if _, err := buf.ReadFrom(r.Body); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var unlockRequest internal.UnlockRequest
+	if err := json.Unmarshal(buf.Bytes(), &unlockRequest); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if unlockRequest.Login == "" && unlockRequest.Address == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login or address should not be empty")
+		return
+	}
+
+	// reset counters
+	response := "unlocked"
+	if unlockRequest.Login != "" {
+		h.accountThrottle.reset(unlockRequest.Login)
+		response += fmt.Sprintf(" account %q", unlockRequest.Login)
+	}
+	if unlockRequest.Address != "" {
+		h.addressThrottle.reset(unlockRequest.Address)
+		response += fmt.Sprintf(" address %q", unlockRequest.Address)
+	}
+	h.log.Infof("admin %s", response)
+
+	// response
+	if _, err := io.WriteString(w, response); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+}
@@ -0,0 +1,205 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 500
+	// maxWithinDays limits the periods of the reports to ten years.
+	maxWithinDays = 3650
+)
+
+// userNameKey is the request context key of the authorized user's name in API v2.
+type userNameKey struct{}
+
+// TokenAuth is a middleware for API v2 which authorizes requests with the token from the Authorization header
+// (`Bearer <token>`) returned on login. Only the latest token of the user is accepted, so the sessions are
+// invalidated on password change. The user's name is put into the request context.
+func (h *handler) TokenAuth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name, err := h.authenticate(r.Header.Get("Authorization"))
+		if err != nil {
+			writeErrorResponse(w, r, http.StatusUnauthorized, authError(err))
+			return
+		}
+		ctx := context.WithValue(r.Context(), userNameKey{}, name)
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// RefreshToken is a method for prolonging the session of the user authorized by TokenAuth.
+// The new token is returned like on login and replaces the current one.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/auth/refresh
+func (h *handler) RefreshToken(w http.ResponseWriter, r *http.Request) {
+	if err := h.authorize(w, userName(r)); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// authenticate checks the token of the authorization value (`Bearer <token>`) and returns the name of the user.
+// The token must be the latest one issued for the user, challenge tokens are not accepted.
+func (h *handler) authenticate(authorization string) (string, error) {
+	tkn, err := extractJwtToken(authorization)
+	if err != nil {
+		return "", err
+	}
+	claims, ok := tkn.Claims.(*internal.Claims)
+	// challenge tokens only allow to pass the second factor
+	if !ok || !tkn.Valid || claims.Subject != "" {
+		return "", ErrInvalidToken
+	}
+	if h.cookies.get(claims.Username) != authorization {
+		return "", ErrSessionRevoked
+	}
+	return claims.Username, nil
+}
+
+// authError maps the error of authenticate to the error from the catalogue.
+func authError(err error) internal.Error {
+	switch {
+	case errors.Is(err, jwt.ErrTokenExpired):
+		return internal.Error{Code: internal.ErrorCodeTokenExpired, Message: "token has expired, login again"}
+	case errors.Is(err, ErrSessionRevoked):
+		return internal.Error{Code: internal.ErrorCodeSessionRevoked, Message: "session is no longer valid, login again"}
+	case errors.Is(err, ErrInvalidToken):
+		return internal.Error{Code: internal.ErrorCodeInvalidToken, Message: err.Error()}
+	default:
+		return internal.Error{Code: internal.ErrorCodeInvalidToken, Message: fmt.Sprintf("invalid token: %s", err)}
+	}
+}
+
+// userName returns the name of the user authorized by TokenAuth.
+func userName(r *http.Request) string {
+	return contextUserName(r.Context())
+}
+
+// contextUserName returns the name of the user authorized by TokenAuth or the gRPC interceptors.
+func contextUserName(ctx context.Context) string {
+	name, _ := ctx.Value(userNameKey{}).(string)
+	return name
+}
+
+// itemID returns the id of the item from the URL path.
+func itemID(r *http.Request) (int64, error) {
+	id, err := strconv.ParseInt(chi.URLParam(r, "id"), 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid item id %q", chi.URLParam(r, "id"))
+	}
+	return id, nil
+}
+
+// queryParam returns the pointer to the query parameter value, nil if it's not set.
+func queryParam(r *http.Request, name string) *string {
+	if !r.URL.Query().Has(name) {
+		return nil
+	}
+	value := r.URL.Query().Get(name)
+	return &value
+}
+
+// withinDays returns the number of days from the `within` query parameter, the default if it's not set.
+func withinDays(r *http.Request, defaultDays int) (int, error) {
+	value := queryParam(r, "within")
+	if value == nil {
+		return defaultDays, nil
+	}
+	days, err := strconv.Atoi(*value)
+	if err != nil || days < 0 || days > maxWithinDays {
+		return 0, fmt.Errorf("within should be a number of days from 0 to %d", maxWithinDays)
+	}
+	return days, nil
+}
+
+// intParam returns the number from the query parameter within the bounds, the default if it's not set.
+func intParam(r *http.Request, name string, defaultValue, min, max int) (int, error) {
+	value := queryParam(r, name)
+	if value == nil {
+		return defaultValue, nil
+	}
+	number, err := strconv.Atoi(*value)
+	if err != nil || number < min || number > max {
+		return 0, fmt.Errorf("%s should be a number from %d to %d", name, min, max)
+	}
+	return number, nil
+}
+
+// listOptions parses the page parameters of list endpoints: `limit` (50 by default, at most 500), `cursor`
+// returned with the previous page, `sort` by name, created or updated (descending with the `-` prefix),
+// `metadata`, `tag` (repeated for several tags), `folder` and `favorite` filters.
+func listOptions(r *http.Request) (internal.ListOptions, error) {
+	query := r.URL.Query()
+	limit, err := pageLimit(r)
+	if err != nil {
+		return internal.ListOptions{}, err
+	}
+	opts := internal.ListOptions{
+		Limit:    limit,
+		Cursor:   query.Get("cursor"),
+		Metadata: query.Get("metadata"),
+		Tags:     query["tag"],
+	}
+	if query.Has("folder") {
+		folderID, err := strconv.ParseInt(query.Get("folder"), 10, 64)
+		if err != nil || folderID <= 0 {
+			return internal.ListOptions{}, fmt.Errorf("invalid folder id %q", query.Get("folder"))
+		}
+		opts.FolderID = &folderID
+	}
+	if query.Has("favorite") {
+		favorite, err := strconv.ParseBool(query.Get("favorite"))
+		if err != nil {
+			return internal.ListOptions{}, fmt.Errorf("favorite should be true or false")
+		}
+		opts.Favorite = &favorite
+	}
+	sort := query.Get("sort")
+	if strings.HasPrefix(sort, "-") {
+		opts.Descending = true
+		sort = strings.TrimPrefix(sort, "-")
+	}
+	switch sort {
+	case "", internal.SortByName, internal.SortByCreated, internal.SortByUpdated:
+		opts.Sort = sort
+	default:
+		return internal.ListOptions{}, fmt.Errorf("invalid sort field %q, use name, created or updated", sort)
+	}
+	return opts, nil
+}
+
+// pageLimit parses the `limit` query parameter, 50 by default, at most 500.
+func pageLimit(r *http.Request) (int, error) {
+	if !r.URL.Query().Has("limit") {
+		return defaultPageLimit, nil
+	}
+	limit, err := strconv.Atoi(r.URL.Query().Get("limit"))
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		return 0, fmt.Errorf("limit should be a number from 1 to %d", maxPageLimit)
+	}
+	return limit, nil
+}
+
+// decodeBody decodes JSON request body into v.
+func decodeBody(r io.Reader, v any) error {
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("error while reading request body: %w", err)
+	}
+	if err = json.Unmarshal(body, v); err != nil {
+		return fmt.Errorf("error while unmarshalling request body: %w", err)
+	}
+	return nil
+}
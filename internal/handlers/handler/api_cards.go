@@ -0,0 +1,257 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"net/http"
+	"time"
+)
+
+// defaultCardExpiryDays is the period of the expiring cards report if it's not provided.
+const defaultCardExpiryDays = 30
+
+// ListCards is a method for getting bank cards of authorized user, optionally filtered by `bank_name` and `number` query parameters.
+// The list is returned by pages, see listOptions for the page parameters.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards?bank_name=some_bank
+func (h *handler) ListCards(w http.ResponseWriter, r *http.Request) {
+	opts, err := listOptions(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	cards, next, err := h.db.ListCards(context.Background(), internal.Card{
+		UserName: userName(r),
+		BankName: queryParam(r, "bank_name"),
+		Number:   queryParam(r, "number"),
+	}, opts)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	ids := make([]int64, 0, len(cards))
+	for _, item := range cards {
+		ids = append(ids, item.ID)
+	}
+	fields, err := h.itemFields(context.Background(), userName(r), internal.ItemTypeCard, ids...)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	for i := range cards {
+		cards[i].Fields = fields[cards[i].ID]
+	}
+	if cards == nil {
+		cards = []internal.Card{}
+	}
+	writePage(w, cards, opts.Limit, next)
+}
+
+// ListExpiringCards is a method for getting the bank cards of authorized user which won't be valid in `within` days
+// (30 by default), including the expired ones. The cards are ordered by the expiry month, only the last digits of the numbers are returned.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards/expiring?within=60
+func (h *handler) ListExpiringCards(w http.ResponseWriter, r *http.Request) {
+	within, err := withinDays(r, defaultCardExpiryDays)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	cards, err := h.db.GetExpiringCards(context.Background(), userName(r), time.Now().AddDate(0, 0, within))
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	if cards == nil {
+		cards = []internal.ExpiringCard{}
+	}
+	writeData(w, http.StatusOK, cards)
+}
+
+// GetCardItem is a method for getting the bank card of authorized user by id.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards/1
+func (h *handler) GetCardItem(w http.ResponseWriter, r *http.Request) {
+	card, ok := h.cardItem(w, r)
+	if !ok {
+		return
+	}
+	fields, err := h.itemFields(context.Background(), card.UserName, internal.ItemTypeCard, card.ID)
+	if err != nil {
+		h.writeUserError(w, r, card.UserName, err)
+		return
+	}
+	card.Fields = fields[card.ID]
+	writeData(w, http.StatusOK, card)
+}
+
+// CreateCard is a method for saving new bank card of authorized user.
+// The body of the HTTP request must contain `bank_name`, `number`, `cv` and `password`,
+// `expires_on` (YYYY-MM), `metadata`, `fields` and `rotation_days` are optional.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards --data `{"bank_name": "some_bank", "number": "1111222233334444", "cv": "123", "password": "1234"}`
+func (h *handler) CreateCard(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	var card internal.Card
+	if err := decodeBody(r.Body, &card); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if card.BankName == nil || *card.BankName == "" || card.Number == nil || *card.Number == "" || card.CV == nil || card.Password == nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "bank name, number, cv and password should not be empty")
+		return
+	}
+	if err := validateFields(card.Fields); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateRotation(&card.Rotation); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateExpiry(card.ExpiresOn); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	card.ID = 0
+	// the response echoes the saved item, so the values managed by the server are dropped
+	card.CreatedAt, card.UpdatedAt = nil, nil
+	card.Labels = internal.Labels{}
+	card.UserName = userName(r)
+	// the card and its fields are saved together
+	id, err := h.db.SaveCard(ctx, card)
+	if err != nil {
+		h.writeUserError(w, r, card.UserName, err)
+		return
+	}
+	card.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/cards/%d", card.ID))
+	writeData(w, http.StatusCreated, card)
+}
+
+// ReplaceCard is a method for replacing cv, password and metadata of the bank card by id.
+// The body of the HTTP request must contain `cv` and `password`, missing `metadata`, `fields` and `rotation_days`
+// are removed, missing `expires_on` keeps the expiry month.
+// The bank name and the number can't be changed.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards/1 --data `{"cv": "321", "password": "4321"}`
+func (h *handler) ReplaceCard(w http.ResponseWriter, r *http.Request) {
+	h.updateCard(w, r, true)
+}
+
+// PatchCard is a method for changing cv, password and/or metadata of the bank card by id.
+// Only the fields present in the body are changed, 0 `rotation_days` disables the rotation policy.
+// The bank name and the number can't be changed.
+// For example: curl -X PATCH -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards/1 --data `{"metadata": "new metadata"}`
+func (h *handler) PatchCard(w http.ResponseWriter, r *http.Request) {
+	h.updateCard(w, r, false)
+}
+
+// RemoveCard is a method for deleting the bank card of authorized user by id.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards/1
+func (h *handler) RemoveCard(w http.ResponseWriter, r *http.Request) {
+	card, ok := h.cardItem(w, r)
+	if !ok {
+		return
+	}
+	if err := h.db.DeleteCards(context.Background(), internal.Card{UserName: card.UserName, ID: card.ID}); err != nil {
+		h.writeUserError(w, r, card.UserName, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) updateCard(w http.ResponseWriter, r *http.Request, replace bool) {
+	card, ok := h.cardItem(w, r)
+	if !ok {
+		return
+	}
+	var update internal.Card
+	if err := decodeBody(r.Body, &update); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateFields(update.Fields); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateRotation(&update.Rotation); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateExpiry(update.ExpiresOn); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if update.BankName != nil && *update.BankName != *card.BankName || update.Number != nil && *update.Number != *card.Number {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "bank name and number can't be changed")
+		return
+	}
+	if replace && (update.CV == nil || update.Password == nil) {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "cv and password should not be empty")
+		return
+	}
+	if update.CV != nil {
+		card.CV = update.CV
+	}
+	if update.Password != nil {
+		card.Password = update.Password
+	}
+	if replace || update.Metadata != nil {
+		card.Metadata = update.Metadata
+	}
+	card.RotationDays = rotationUpdateDays(update.RotationDays, replace)
+	if update.ExpiresOn != nil {
+		card.ExpiresOn = update.ExpiresOn
+	}
+	// the fields are replaced together with the item, PUT without them removes the stored ones
+	card.Fields = update.Fields
+	if replace && card.Fields == nil {
+		card.Fields = []internal.Field{}
+	}
+	ctx := context.Background()
+	rotation, err := h.db.UpdateCard(ctx, card)
+	if err != nil {
+		h.writeUserError(w, r, card.UserName, err)
+		return
+	}
+	card.Rotation = rotation
+	if card.Fields == nil {
+		fields, err := h.itemFields(ctx, card.UserName, internal.ItemTypeCard, card.ID)
+		if err != nil {
+			h.writeUserError(w, r, card.UserName, err)
+			return
+		}
+		card.Fields = fields[card.ID]
+	}
+	writeData(w, http.StatusOK, card)
+}
+
+// cardItem returns the bank card of authorized user with the id from the URL path.
+// The error response is written if there is no such card.
+func (h *handler) cardItem(w http.ResponseWriter, r *http.Request) (internal.Card, bool) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return internal.Card{}, false
+	}
+	cards, err := h.db.GetCard(context.Background(), internal.Card{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("card %d not found", id))
+		return internal.Card{}, false
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return internal.Card{}, false
+	}
+	return cards[0], true
+}
+
+// validateExpiry checks the expiry month of the card in YYYY-MM format.
+func validateExpiry(expiresOn *string) error {
+	if expiresOn == nil {
+		return nil
+	}
+	if _, err := time.Parse("2006-01", *expiresOn); err != nil {
+		return errors.New("expires_on should be a month in YYYY-MM format")
+	}
+	return nil
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"net/http"
+	"strconv"
+)
+
+// ListCredentials is a method for getting credentials of authorized user, optionally filtered by `login` query parameter.
+// The list is returned by pages, see listOptions for the page parameters. With `breach_check=true` the passwords
+// are checked against known data breaches, see checkBreaches.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials?login=some_login
+func (h *handler) ListCredentials(w http.ResponseWriter, r *http.Request) {
+	opts, err := listOptions(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	breachCheck, err := h.breachCheck(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	creds, next, err := h.db.ListCredentials(context.Background(), internal.Credentials{
+		UserName: userName(r),
+		Login:    queryParam(r, "login"),
+	}, opts)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	ids := make([]int64, 0, len(creds))
+	for _, item := range creds {
+		ids = append(ids, item.ID)
+	}
+	fields, err := h.itemFields(context.Background(), userName(r), internal.ItemTypeCredentials, ids...)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	for i := range creds {
+		creds[i].Fields = fields[creds[i].ID]
+	}
+	if breachCheck {
+		h.checkBreaches(context.Background(), userName(r), creds)
+	}
+	if creds == nil {
+		creds = []internal.Credentials{}
+	}
+	writePage(w, creds, opts.Limit, next)
+}
+
+// GetCredentialsItem is a method for getting credentials of authorized user by id,
+// with `breach_check=true` the password is checked against known data breaches.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials/1
+func (h *handler) GetCredentialsItem(w http.ResponseWriter, r *http.Request) {
+	breachCheck, err := h.breachCheck(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	creds, ok := h.credentialsItem(w, r)
+	if !ok {
+		return
+	}
+	fields, err := h.itemFields(context.Background(), creds.UserName, internal.ItemTypeCredentials, creds.ID)
+	if err != nil {
+		h.writeUserError(w, r, creds.UserName, err)
+		return
+	}
+	creds.Fields = fields[creds.ID]
+	if breachCheck {
+		checked := []internal.Credentials{creds}
+		h.checkBreaches(context.Background(), creds.UserName, checked)
+		creds = checked[0]
+	}
+	writeData(w, http.StatusOK, creds)
+}
+
+// CreateCredentials is a method for saving new credentials of authorized user.
+// The body of the HTTP request must contain `login` and `password`, `metadata`, `fields` and `rotation_days` are optional.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials --data `{"login": "some_login", "password": "some_password"}`
+func (h *handler) CreateCredentials(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	var creds internal.Credentials
+	if err := decodeBody(r.Body, &creds); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if creds.Login == nil || *creds.Login == "" || creds.Password == nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login and password should not be empty")
+		return
+	}
+	if err := validateFields(creds.Fields); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateRotation(&creds.Rotation); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	creds.ID = 0
+	// the response echoes the saved item, so the values managed by the server are dropped
+	creds.CreatedAt, creds.UpdatedAt = nil, nil
+	creds.Labels = internal.Labels{}
+	creds.BreachCount = nil
+	creds.UserName = userName(r)
+	// the credentials and their fields are saved together
+	id, err := h.db.SaveCredentials(ctx, creds)
+	if err != nil {
+		h.writeUserError(w, r, creds.UserName, err)
+		return
+	}
+	creds.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/credentials/%d", creds.ID))
+	writeData(w, http.StatusCreated, creds)
+}
+
+// ReplaceCredentials is a method for replacing password and metadata of the credentials by id.
+// The body of the HTTP request must contain `password`, missing `metadata`, `fields` and `rotation_days` are removed.
+// The login can't be changed.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials/1 --data `{"password": "new_password"}`
+func (h *handler) ReplaceCredentials(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentials(w, r, true)
+}
+
+// PatchCredentials is a method for changing password and/or metadata of the credentials by id.
+// Only the fields present in the body are changed, 0 `rotation_days` disables the rotation policy. The login can't be changed.
+// For example: curl -X PATCH -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials/1 --data `{"metadata": "new metadata"}`
+func (h *handler) PatchCredentials(w http.ResponseWriter, r *http.Request) {
+	h.updateCredentials(w, r, false)
+}
+
+// RemoveCredentials is a method for deleting the credentials of authorized user by id.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials/1
+func (h *handler) RemoveCredentials(w http.ResponseWriter, r *http.Request) {
+	creds, ok := h.credentialsItem(w, r)
+	if !ok {
+		return
+	}
+	if err := h.db.DeleteCredentials(context.Background(), internal.Credentials{UserName: creds.UserName, ID: creds.ID}); err != nil {
+		h.writeUserError(w, r, creds.UserName, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) updateCredentials(w http.ResponseWriter, r *http.Request, replace bool) {
+	creds, ok := h.credentialsItem(w, r)
+	if !ok {
+		return
+	}
+	var update internal.Credentials
+	if err := decodeBody(r.Body, &update); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateFields(update.Fields); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateRotation(&update.Rotation); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if update.Login != nil && *update.Login != *creds.Login {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login can't be changed")
+		return
+	}
+	if replace && update.Password == nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "password should not be empty")
+		return
+	}
+	if update.Password != nil {
+		creds.Password = update.Password
+	}
+	if replace || update.Metadata != nil {
+		creds.Metadata = update.Metadata
+	}
+	creds.RotationDays = rotationUpdateDays(update.RotationDays, replace)
+	// the fields are replaced together with the item, PUT without them removes the stored ones
+	creds.Fields = update.Fields
+	if replace && creds.Fields == nil {
+		creds.Fields = []internal.Field{}
+	}
+	ctx := context.Background()
+	rotation, err := h.db.UpdateCredentials(ctx, creds)
+	if err != nil {
+		h.writeUserError(w, r, creds.UserName, err)
+		return
+	}
+	creds.Rotation = rotation
+	if creds.Fields == nil {
+		fields, err := h.itemFields(ctx, creds.UserName, internal.ItemTypeCredentials, creds.ID)
+		if err != nil {
+			h.writeUserError(w, r, creds.UserName, err)
+			return
+		}
+		creds.Fields = fields[creds.ID]
+	}
+	writeData(w, http.StatusOK, creds)
+}
+
+// credentialsItem returns the credentials of authorized user with the id from the URL path.
+// The error response is written if there are no such credentials.
+func (h *handler) credentialsItem(w http.ResponseWriter, r *http.Request) (internal.Credentials, bool) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return internal.Credentials{}, false
+	}
+	creds, err := h.db.GetCredentials(context.Background(), internal.Credentials{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("credentials %d not found", id))
+		return internal.Credentials{}, false
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return internal.Credentials{}, false
+	}
+	return creds[0], true
+}
+
+// breachCheck tells if the `breach_check` query parameter requests the breach check of the passwords.
+func (h *handler) breachCheck(r *http.Request) (bool, error) {
+	value := queryParam(r, "breach_check")
+	if value == nil {
+		return false, nil
+	}
+	check, err := strconv.ParseBool(*value)
+	if err != nil {
+		return false, errors.New("breach_check should be true or false")
+	}
+	if check && h.breachChecker == nil {
+		return false, errors.New("breached passwords source is not configured")
+	}
+	return check, nil
+}
+
+// checkBreaches sets the number of the appearances in known data breaches of the credentials passwords.
+// Only the hash prefixes of the passwords are sent to the source. If the source is unavailable,
+// all the credentials stay unchecked, the counts set before the failure are cleared, and false is returned.
+func (h *handler) checkBreaches(ctx context.Context, userName string, creds []internal.Credentials) bool {
+	for i := range creds {
+		if creds[i].Password == nil || *creds[i].Password == "" {
+			continue
+		}
+		count, err := h.breachChecker.Count(ctx, *creds[i].Password)
+		if err != nil {
+			h.log.Errorf("error while checking credentials of user %q for breaches: %s", userName, err)
+			for j := range creds {
+				creds[j].BreachCount = nil
+			}
+			return false
+		}
+		creds[i].BreachCount = &count
+	}
+	return true
+}
@@ -0,0 +1,56 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// validateFields checks the names and the values of the custom fields against their types.
+// Fields without a type become text fields.
+func validateFields(fields []internal.Field) error {
+	names := make(map[string]bool, len(fields))
+	for i := range fields {
+		field := &fields[i]
+		field.Name = strings.TrimSpace(field.Name)
+		if field.Name == "" {
+			return fmt.Errorf("name of field %d should not be empty", i+1)
+		}
+		if names[field.Name] {
+			return fmt.Errorf("field %q is repeated", field.Name)
+		}
+		names[field.Name] = true
+		switch field.Type {
+		case "":
+			field.Type = internal.FieldTypeText
+		case internal.FieldTypeText, internal.FieldTypeHidden:
+		case internal.FieldTypeBoolean:
+			if _, err := strconv.ParseBool(field.Value); err != nil {
+				return fmt.Errorf("field %q should be true or false", field.Name)
+			}
+		case internal.FieldTypeURL:
+			if u, err := url.Parse(field.Value); err != nil || u.Scheme == "" || u.Host == "" {
+				return fmt.Errorf("field %q should be an absolute URL", field.Name)
+			}
+		case internal.FieldTypeDate:
+			if _, err := time.Parse(time.DateOnly, field.Value); err != nil {
+				return fmt.Errorf("field %q should be a date in YYYY-MM-DD format", field.Name)
+			}
+		default:
+			return fmt.Errorf("field %q has invalid type %q", field.Name, field.Type)
+		}
+	}
+	return nil
+}
+
+// itemFields returns the custom fields of the user's items by their ids, nothing is requested for no items.
+func (h *handler) itemFields(ctx context.Context, userName string, itemType string, ids ...int64) (map[int64][]internal.Field, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return h.db.GetFields(ctx, userName, itemType, ids)
+}
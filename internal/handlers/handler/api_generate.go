@@ -0,0 +1,33 @@
+package handler
+
+import (
+	"errors"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"net/http"
+)
+
+// GeneratePassword is a method for generating a random password or a diceware passphrase, the body describes it.
+// The password has `length` characters (20 by default) of the chosen classes `lower`, `upper`, `digits` and `symbols`
+// (all of them by default), `exclude_ambiguous` removes similar looking characters. With `words` the passphrase
+// of the words joined with `separator` ("-" by default) is generated instead.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/generate --data `{"length": 24, "exclude_ambiguous": true}`
+func (h *handler) GeneratePassword(w http.ResponseWriter, r *http.Request) {
+	var opts password.GenerateOptions
+	if r.ContentLength != 0 {
+		if err := decodeBody(r.Body, &opts); err != nil {
+			writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+			return
+		}
+	}
+	generated, err := password.Generate(opts)
+	if errors.Is(err, password.ErrInvalidOptions) {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	writeData(w, http.StatusOK, internal.GeneratedPassword{Password: generated})
+}
@@ -0,0 +1,49 @@
+package handler
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/health"
+	"net/http"
+	"time"
+)
+
+// HealthReport is a method for getting the password hygiene report of authorized user's credentials: weak passwords
+// (zxcvbn score below `min_score`, 3 by default), passwords reused across the credentials, passwords unchanged
+// for `max_age_days` (365 by default), URL fields using plain http and, if the breached password source is configured,
+// passwords which have appeared in known data breaches. The passwords are not returned.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/report/health?max_age_days=180
+func (h *handler) HealthReport(w http.ResponseWriter, r *http.Request) {
+	minScore, err := intParam(r, "min_score", health.DefaultMinScore, 0, 4)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	maxAgeDays, err := intParam(r, "max_age_days", health.DefaultMaxAgeDays, 1, maxWithinDays)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	ctx := context.Background()
+	creds, _, err := h.db.ListCredentials(ctx, internal.Credentials{UserName: userName(r)}, internal.ListOptions{})
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	ids := make([]int64, 0, len(creds))
+	for _, item := range creds {
+		ids = append(ids, item.ID)
+	}
+	fields, err := h.itemFields(ctx, userName(r), internal.ItemTypeCredentials, ids...)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	for i := range creds {
+		creds[i].Fields = fields[creds[i].ID]
+	}
+	breachChecked := h.breachChecker != nil && h.checkBreaches(ctx, userName(r), creds)
+	report := health.Report(creds, time.Now(), health.Options{MinScore: minScore, MaxAgeDays: maxAgeDays})
+	report.BreachChecked = breachChecked
+	writeData(w, http.StatusOK, report)
+}
@@ -0,0 +1,168 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// documentTypes are the accepted types of the identity documents.
+var documentTypes = map[string]bool{
+	internal.DocumentTypePassport:       true,
+	internal.DocumentTypeIDCard:         true,
+	internal.DocumentTypeDriversLicence: true,
+	internal.DocumentTypeTaxID:          true,
+	internal.DocumentTypeOther:          true,
+}
+
+// ListIdentities is a method for getting the identity documents of authorized user, optionally filtered by `name`,
+// `type` and `expires_before` (YYYY-MM-DD) query parameters. The documents are ordered by name,
+// or by the expiry date if `expires_before` is set.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/identities?expires_before=2027-01-01
+func (h *handler) ListIdentities(w http.ResponseWriter, r *http.Request) {
+	request := internal.Identity{UserName: userName(r), Name: queryParam(r, "name")}
+	if documentType := queryParam(r, "type"); documentType != nil {
+		request.DocumentType = *documentType
+	}
+	var expiresBefore string
+	if date := queryParam(r, "expires_before"); date != nil {
+		if _, err := time.Parse(time.DateOnly, *date); err != nil {
+			writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "expires_before should be a date in YYYY-MM-DD format")
+			return
+		}
+		expiresBefore = *date
+	}
+	identities, err := h.db.GetIdentities(context.Background(), request, expiresBefore)
+	if errors.Is(err, database.ErrNoData) {
+		identities, err = []internal.Identity{}, nil
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	writeData(w, http.StatusOK, identities)
+}
+
+// GetIdentityItem is a method for getting the identity document of authorized user by id.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/identities/1
+func (h *handler) GetIdentityItem(w http.ResponseWriter, r *http.Request) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	identities, err := h.db.GetIdentities(context.Background(), internal.Identity{UserName: userName(r), ID: id}, "")
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("identity %d not found", id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	writeData(w, http.StatusOK, identities[0])
+}
+
+// CreateIdentity is a method for saving new identity document of authorized user.
+// The body of the HTTP request must contain `name`, `document_type` and `number`; `full_name`, `country` (ISO 3166 alpha-2 code),
+// `issued_on` and `expires_on` (YYYY-MM-DD), `scans` (names of the uploaded files) and `metadata` are optional.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/identities --data `{"name": "passport", "document_type": "passport", "number": "AB1234567", "expires_on": "2030-04-30"}`
+func (h *handler) CreateIdentity(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	var identity internal.Identity
+	if err := decodeBody(r.Body, &identity); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateIdentity(&identity); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	identity.ID = 0
+	// the response echoes the saved item, so the values managed by the server are dropped
+	identity.CreatedAt, identity.UpdatedAt = nil, nil
+	identity.UserName = userName(r)
+	for _, scan := range identity.Scans {
+		_, err := h.db.GetFiles(ctx, internal.File{UserName: identity.UserName, Name: &scan})
+		if errors.Is(err, database.ErrNoData) {
+			writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, fmt.Sprintf("scan %q is not uploaded", scan))
+			return
+		}
+		if err != nil {
+			h.writeUserError(w, r, identity.UserName, err)
+			return
+		}
+	}
+	id, err := h.db.SaveIdentity(ctx, identity)
+	if err != nil {
+		h.writeUserError(w, r, identity.UserName, err)
+		return
+	}
+	identity.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/identities/%d", identity.ID))
+	writeData(w, http.StatusCreated, identity)
+}
+
+// RemoveIdentity is a method for deleting the identity document of authorized user by id, the scans are kept.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/identities/1
+func (h *handler) RemoveIdentity(w http.ResponseWriter, r *http.Request) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	err = h.db.DeleteIdentity(context.Background(), internal.Identity{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("identity %d not found", id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateIdentity checks the required fields, the type, the country code and the dates of the document.
+// The country code is uppercased.
+func validateIdentity(identity *internal.Identity) error {
+	if identity.Name == nil || strings.TrimSpace(*identity.Name) == "" || identity.Number == nil || *identity.Number == "" {
+		return errors.New("name and number should not be empty")
+	}
+	if !documentTypes[identity.DocumentType] {
+		return errors.New("document_type should be one of passport, id_card, drivers_licence, tax_id or other")
+	}
+	identity.Country = strings.ToUpper(identity.Country)
+	if identity.Country != "" && (len(identity.Country) != 2 || strings.Trim(identity.Country, "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "") {
+		return errors.New("country should be a two-letter ISO 3166 code")
+	}
+	var issued, expires time.Time
+	for _, date := range []struct {
+		name  string
+		value *string
+		time  *time.Time
+	}{{"issued_on", identity.IssuedOn, &issued}, {"expires_on", identity.ExpiresOn, &expires}} {
+		if date.value == nil {
+			continue
+		}
+		parsed, err := time.Parse(time.DateOnly, *date.value)
+		if err != nil {
+			return fmt.Errorf("%s should be a date in YYYY-MM-DD format", date.name)
+		}
+		*date.time = parsed
+	}
+	if !issued.IsZero() && !expires.IsZero() && expires.Before(issued) {
+		return errors.New("expires_on should not be before issued_on")
+	}
+	for _, scan := range identity.Scans {
+		if scan == "" {
+			return errors.New("names of the scans should not be empty")
+		}
+	}
+	return nil
+}
@@ -0,0 +1,166 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"net/http"
+	"strings"
+)
+
+// SetCredentialsLabels is a method for replacing the tags, the folder and the favourite flag of the credentials by id.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/credentials/1/labels --data `{"tags": ["work"], "folder_id": 2, "favorite": true}`
+func (h *handler) SetCredentialsLabels(w http.ResponseWriter, r *http.Request) {
+	h.setLabels(w, r, internal.ItemTypeCredentials)
+}
+
+// SetNoteLabels is a method for replacing the tags, the folder and the favourite flag of the note by id.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes/1/labels --data `{"tags": ["vpn"]}`
+func (h *handler) SetNoteLabels(w http.ResponseWriter, r *http.Request) {
+	h.setLabels(w, r, internal.ItemTypeNote)
+}
+
+// SetCardLabels is a method for replacing the tags, the folder and the favourite flag of the bank card by id.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/cards/1/labels --data `{"favorite": true}`
+func (h *handler) SetCardLabels(w http.ResponseWriter, r *http.Request) {
+	h.setLabels(w, r, internal.ItemTypeCard)
+}
+
+func (h *handler) setLabels(w http.ResponseWriter, r *http.Request, itemType string) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	var labels internal.Labels
+	if err = decodeBody(r.Body, &labels); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	err = h.db.SetLabels(context.Background(), userName(r), itemType, id, labels)
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("%s %d or the folder not found", itemType, id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// ListTags is a method for getting all the tags of authorized user's items.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/tags
+func (h *handler) ListTags(w http.ResponseWriter, r *http.Request) {
+	tags, err := h.db.GetTags(context.Background(), userName(r))
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	if tags == nil {
+		tags = []string{}
+	}
+	writeData(w, http.StatusOK, tags)
+}
+
+// ListFolders is a method for getting all the folders of authorized user, subfolders have `parent_id`.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/folders
+func (h *handler) ListFolders(w http.ResponseWriter, r *http.Request) {
+	folders, err := h.db.GetFolders(context.Background(), userName(r))
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	if folders == nil {
+		folders = []internal.Folder{}
+	}
+	writeData(w, http.StatusOK, folders)
+}
+
+// CreateFolder is a method for creating the folder of authorized user.
+// The body of the HTTP request must contain `name`, `parent_id` creates a subfolder.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/folders --data `{"name": "work"}`
+func (h *handler) CreateFolder(w http.ResponseWriter, r *http.Request) {
+	folder, ok := folderBody(w, r)
+	if !ok {
+		return
+	}
+	id, err := h.db.SaveFolder(context.Background(), folder)
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("parent folder %d not found", *folder.ParentID))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, folder.UserName, err)
+		return
+	}
+	folder.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/folders/%d", id))
+	writeData(w, http.StatusCreated, folder)
+}
+
+// ReplaceFolder is a method for renaming the folder by id and moving it to another parent.
+// The body of the HTTP request must contain `name`, missing `parent_id` moves the folder to the root.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/folders/2 --data `{"name": "servers", "parent_id": 1}`
+func (h *handler) ReplaceFolder(w http.ResponseWriter, r *http.Request) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	folder, ok := folderBody(w, r)
+	if !ok {
+		return
+	}
+	folder.ID = id
+	err = h.db.UpdateFolder(context.Background(), folder)
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("folder %d or its parent not found", id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, folder.UserName, err)
+		return
+	}
+	writeData(w, http.StatusOK, folder)
+}
+
+// RemoveFolder is a method for deleting the folder by id with its subfolders, the items of the folders are kept.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/folders/2
+func (h *handler) RemoveFolder(w http.ResponseWriter, r *http.Request) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	err = h.db.DeleteFolder(context.Background(), userName(r), id)
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("folder %d not found", id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// folderBody decodes the folder from the request body, the name must not be empty or contain slashes,
+// which separate the names in folder paths.
+func folderBody(w http.ResponseWriter, r *http.Request) (internal.Folder, bool) {
+	var folder internal.Folder
+	if err := decodeBody(r.Body, &folder); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return internal.Folder{}, false
+	}
+	folder.Name = strings.TrimSpace(folder.Name)
+	if folder.Name == "" || strings.Contains(folder.Name, "/") {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "folder name should not be empty or contain slashes")
+		return internal.Folder{}, false
+	}
+	folder.ID = 0
+	folder.UserName = userName(r)
+	return folder, true
+}
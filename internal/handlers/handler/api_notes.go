@@ -0,0 +1,192 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"net/http"
+)
+
+// ListNotes is a method for getting notes of authorized user, optionally filtered by `title` query parameter.
+// The list is returned by pages, see listOptions for the page parameters.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes?title=some_title
+func (h *handler) ListNotes(w http.ResponseWriter, r *http.Request) {
+	opts, err := listOptions(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	notes, next, err := h.db.ListNotes(context.Background(), internal.Note{
+		UserName: userName(r),
+		Title:    queryParam(r, "title"),
+	}, opts)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	ids := make([]int64, 0, len(notes))
+	for _, item := range notes {
+		ids = append(ids, item.ID)
+	}
+	fields, err := h.itemFields(context.Background(), userName(r), internal.ItemTypeNote, ids...)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	for i := range notes {
+		notes[i].Fields = fields[notes[i].ID]
+	}
+	if notes == nil {
+		notes = []internal.Note{}
+	}
+	writePage(w, notes, opts.Limit, next)
+}
+
+// GetNoteItem is a method for getting the note of authorized user by id.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes/1
+func (h *handler) GetNoteItem(w http.ResponseWriter, r *http.Request) {
+	note, ok := h.noteItem(w, r)
+	if !ok {
+		return
+	}
+	fields, err := h.itemFields(context.Background(), note.UserName, internal.ItemTypeNote, note.ID)
+	if err != nil {
+		h.writeUserError(w, r, note.UserName, err)
+		return
+	}
+	note.Fields = fields[note.ID]
+	writeData(w, http.StatusOK, note)
+}
+
+// CreateNote is a method for saving new note of authorized user.
+// The body of the HTTP request must contain `title` and `content`, `metadata` and `fields` are optional.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes --data `{"title": "some_title", "content": "some_content"}`
+func (h *handler) CreateNote(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	var note internal.Note
+	if err := decodeBody(r.Body, &note); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if note.Title == nil || *note.Title == "" || note.Content == nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "title and content should not be empty")
+		return
+	}
+	if err := validateFields(note.Fields); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	note.ID = 0
+	// the response echoes the saved item, so the values managed by the server are dropped
+	note.CreatedAt, note.UpdatedAt = nil, nil
+	note.Labels = internal.Labels{}
+	note.UserName = userName(r)
+	// the note and its fields are saved together
+	id, err := h.db.SaveNote(ctx, note)
+	if err != nil {
+		h.writeUserError(w, r, note.UserName, err)
+		return
+	}
+	note.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/notes/%d", note.ID))
+	writeData(w, http.StatusCreated, note)
+}
+
+// ReplaceNote is a method for replacing content and metadata of the note by id.
+// The body of the HTTP request must contain `content`, missing `metadata` and `fields` are removed. The title can't be changed.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes/1 --data `{"content": "new content"}`
+func (h *handler) ReplaceNote(w http.ResponseWriter, r *http.Request) {
+	h.updateNote(w, r, true)
+}
+
+// PatchNote is a method for changing content and/or metadata of the note by id.
+// Only the fields present in the body are changed. The title can't be changed.
+// For example: curl -X PATCH -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes/1 --data `{"metadata": "new metadata"}`
+func (h *handler) PatchNote(w http.ResponseWriter, r *http.Request) {
+	h.updateNote(w, r, false)
+}
+
+// RemoveNote is a method for deleting the note of authorized user by id.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/notes/1
+func (h *handler) RemoveNote(w http.ResponseWriter, r *http.Request) {
+	note, ok := h.noteItem(w, r)
+	if !ok {
+		return
+	}
+	if err := h.db.DeleteNotes(context.Background(), internal.Note{UserName: note.UserName, ID: note.ID}); err != nil {
+		h.writeUserError(w, r, note.UserName, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *handler) updateNote(w http.ResponseWriter, r *http.Request, replace bool) {
+	note, ok := h.noteItem(w, r)
+	if !ok {
+		return
+	}
+	var update internal.Note
+	if err := decodeBody(r.Body, &update); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := validateFields(update.Fields); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if update.Title != nil && *update.Title != *note.Title {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "title can't be changed")
+		return
+	}
+	if replace && update.Content == nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "content should not be empty")
+		return
+	}
+	if update.Content != nil {
+		note.Content = update.Content
+	}
+	if replace || update.Metadata != nil {
+		note.Metadata = update.Metadata
+	}
+	// the fields are replaced together with the item, PUT without them removes the stored ones
+	note.Fields = update.Fields
+	if replace && note.Fields == nil {
+		note.Fields = []internal.Field{}
+	}
+	ctx := context.Background()
+	if err := h.db.UpdateNote(ctx, note); err != nil {
+		h.writeUserError(w, r, note.UserName, err)
+		return
+	}
+	if note.Fields == nil {
+		fields, err := h.itemFields(ctx, note.UserName, internal.ItemTypeNote, note.ID)
+		if err != nil {
+			h.writeUserError(w, r, note.UserName, err)
+			return
+		}
+		note.Fields = fields[note.ID]
+	}
+	writeData(w, http.StatusOK, note)
+}
+
+// noteItem returns the note of authorized user with the id from the URL path.
+// The error response is written if there is no such note.
+func (h *handler) noteItem(w http.ResponseWriter, r *http.Request) (internal.Note, bool) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return internal.Note{}, false
+	}
+	notes, err := h.db.GetNotes(context.Background(), internal.Note{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("note %d not found", id))
+		return internal.Note{}, false
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return internal.Note{}, false
+	}
+	return notes[0], true
+}
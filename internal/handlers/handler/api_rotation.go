@@ -0,0 +1,52 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/http"
+	"time"
+)
+
+// maxRotationDays limits the rotation interval of the passwords to ten years.
+const maxRotationDays = 3650
+
+// ListDueItems is a method for getting the credentials and the bank cards of authorized user with the passwords due for a change
+// according to their rotation policies, ordered by the due time. With `within` query parameter (number of days)
+// the items which become due in the following days are returned too.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/due?within=7
+func (h *handler) ListDueItems(w http.ResponseWriter, r *http.Request) {
+	within, err := withinDays(r, 0)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	items, err := h.db.GetDueItems(context.Background(), userName(r), time.Now().AddDate(0, 0, within))
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	if items == nil {
+		items = []internal.DueItem{}
+	}
+	writeData(w, http.StatusOK, items)
+}
+
+// validateRotation checks the rotation policy of the item, zero days disable it.
+// The time of the password change is set by the storage, so it's ignored in the requests.
+func validateRotation(rotation *internal.Rotation) error {
+	rotation.PasswordChangedAt = nil
+	if rotation.RotationDays != nil && (*rotation.RotationDays < 0 || *rotation.RotationDays > maxRotationDays) {
+		return fmt.Errorf("rotation_days should be from 0 to %d", maxRotationDays)
+	}
+	return nil
+}
+
+// rotationUpdateDays returns the rotation days passed to the storage on update: nil keeps the stored policy,
+// so PATCH without the days keeps it and PUT without them removes it with zero days.
+func rotationUpdateDays(days *int, replace bool) *int {
+	if days == nil && replace {
+		return new(int)
+	}
+	return days
+}
@@ -0,0 +1,34 @@
+package handler
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/http"
+	"strings"
+)
+
+// Search is a method for finding the items of authorized user by the `q` query parameter.
+// The words and the substring of the names (logins, titles, bank names, file and key names), the public keys
+// and the fingerprints of SSH keys and the metadata are searched, secret fields are not. The results are ordered by relevance, `limit` is 50 by default.
+// For example: curl -H "Authorization: Bearer <token>" "http://127.0.0.1:8080/api/v2/search?q=staging%20vpn"
+func (h *handler) Search(w http.ResponseWriter, r *http.Request) {
+	query := strings.TrimSpace(r.URL.Query().Get("q"))
+	if query == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "search query should not be empty")
+		return
+	}
+	limit, err := pageLimit(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	results, err := h.db.Search(context.Background(), userName(r), query, limit)
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	if results == nil {
+		results = []internal.SearchResult{}
+	}
+	writeData(w, http.StatusOK, results)
+}
@@ -0,0 +1,159 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"net/http"
+	"regexp"
+	"strings"
+)
+
+// secretKey is the name of an environment variable, the keys of secrets are injected as ones.
+var secretKey = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// ListSecrets is a method for getting the secrets of authorized user ordered by name, optionally filtered by `name` query parameter.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/secrets?name=deploy
+func (h *handler) ListSecrets(w http.ResponseWriter, r *http.Request) {
+	secrets, err := h.db.GetSecrets(context.Background(), internal.Secret{UserName: userName(r), Name: queryParam(r, "name")})
+	if errors.Is(err, database.ErrNoData) {
+		secrets, err = []internal.Secret{}, nil
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	writeData(w, http.StatusOK, secrets)
+}
+
+// GetSecretItem is a method for getting the secret of authorized user by id.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/secrets/1
+func (h *handler) GetSecretItem(w http.ResponseWriter, r *http.Request) {
+	secret, ok := h.secretItem(w, r)
+	if !ok {
+		return
+	}
+	writeData(w, http.StatusOK, secret)
+}
+
+// CreateSecret is a method for saving new secret of authorized user.
+// The body of the HTTP request must contain `name` and `values`, the keys of the values are names of environment variables.
+// `metadata` is optional.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/secrets --data `{"name": "deploy", "values": {"API_TOKEN": "..."}}`
+func (h *handler) CreateSecret(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	var secret internal.Secret
+	if err := decodeBody(r.Body, &secret); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if secret.Name == nil || strings.TrimSpace(*secret.Name) == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "name should not be empty")
+		return
+	}
+	if err := validateSecretValues(secret.Values); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	secret.ID = 0
+	// the response echoes the saved item, so the values managed by the server are dropped
+	secret.CreatedAt, secret.UpdatedAt = nil, nil
+	secret.UserName = userName(r)
+	id, err := h.db.SaveSecret(ctx, secret)
+	if err != nil {
+		h.writeUserError(w, r, secret.UserName, err)
+		return
+	}
+	secret.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/secrets/%d", secret.ID))
+	writeData(w, http.StatusCreated, secret)
+}
+
+// ReplaceSecret is a method for replacing the values and the metadata of the secret by id, the name can't be changed.
+// For example: curl -X PUT -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/secrets/1 --data `{"values": {"API_TOKEN": "..."}}`
+func (h *handler) ReplaceSecret(w http.ResponseWriter, r *http.Request) {
+	secret, ok := h.secretItem(w, r)
+	if !ok {
+		return
+	}
+	var update internal.Secret
+	if err := decodeBody(r.Body, &update); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if update.Name != nil && *update.Name != *secret.Name {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "name can't be changed")
+		return
+	}
+	if err := validateSecretValues(update.Values); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	secret.Values, secret.Metadata = update.Values, update.Metadata
+	ctx := context.Background()
+	if err := h.db.UpdateSecret(ctx, secret); err != nil {
+		h.writeUserError(w, r, secret.UserName, err)
+		return
+	}
+	updated, err := h.db.GetSecrets(ctx, internal.Secret{UserName: secret.UserName, ID: secret.ID})
+	if err != nil {
+		h.writeUserError(w, r, secret.UserName, err)
+		return
+	}
+	writeData(w, http.StatusOK, updated[0])
+}
+
+// RemoveSecret is a method for deleting the secret of authorized user by id.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/secrets/1
+func (h *handler) RemoveSecret(w http.ResponseWriter, r *http.Request) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	err = h.db.DeleteSecret(context.Background(), internal.Secret{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("secret %d not found", id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// validateSecretValues checks that there are values and their keys can be used as names of environment variables.
+func validateSecretValues(values map[string]string) error {
+	if len(values) == 0 {
+		return errors.New("values should not be empty")
+	}
+	for key := range values {
+		if !secretKey.MatchString(key) {
+			return fmt.Errorf("key %q should consist of letters, digits and underscores and not start with a digit", key)
+		}
+	}
+	return nil
+}
+
+// secretItem returns the secret of authorized user with the id from the URL path.
+// The error response is written if there is no such secret.
+func (h *handler) secretItem(w http.ResponseWriter, r *http.Request) (internal.Secret, bool) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return internal.Secret{}, false
+	}
+	secrets, err := h.db.GetSecrets(context.Background(), internal.Secret{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("secret %d not found", id))
+		return internal.Secret{}, false
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return internal.Secret{}, false
+	}
+	return secrets[0], true
+}
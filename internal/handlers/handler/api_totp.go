@@ -0,0 +1,170 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/otp"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ListTOTP is a method for getting the authenticators of authorized user ordered by name, optionally filtered by `name` query parameter.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/totp?name=github
+func (h *handler) ListTOTP(w http.ResponseWriter, r *http.Request) {
+	authenticators, err := h.db.GetTOTP(context.Background(), internal.TOTP{UserName: userName(r), Name: queryParam(r, "name")})
+	if errors.Is(err, database.ErrNoData) {
+		authenticators, err = []internal.TOTP{}, nil
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	writeData(w, http.StatusOK, authenticators)
+}
+
+// GetTOTPItem is a method for getting the authenticator of authorized user by id.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/totp/1
+func (h *handler) GetTOTPItem(w http.ResponseWriter, r *http.Request) {
+	totp, ok := h.totpItem(w, r)
+	if !ok {
+		return
+	}
+	writeData(w, http.StatusOK, totp)
+}
+
+// GetTOTPCode is a method for getting the current one-time password of the authenticator by id
+// with the number of seconds it remains valid.
+// For example: curl -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/totp/1/code
+func (h *handler) GetTOTPCode(w http.ResponseWriter, r *http.Request) {
+	totp, ok := h.totpItem(w, r)
+	if !ok {
+		return
+	}
+	key := otp.Key{Secret: *totp.Secret, Digits: totp.Digits, Period: totp.Period, Algorithm: totp.Algorithm}
+	now := time.Now()
+	code, err := key.Code(now)
+	if err != nil {
+		h.writeUserError(w, r, totp.UserName, err)
+		return
+	}
+	writeData(w, http.StatusOK, internal.TOTPCode{Code: code, Remaining: int(key.Remaining(now).Seconds())})
+}
+
+// CreateTOTP is a method for saving new authenticator of authorized user.
+// The body of the HTTP request must contain either `uri` in otpauth://totp/ format or `secret` in base32,
+// `issuer`, `account`, `digits` (6 by default), `period` (30 by default), `algorithm` (SHA1 by default) and `metadata` are optional.
+// The name defaults to the issuer or the account of the authenticator.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/totp --data `{"uri": "otpauth://totp/GitHub:arya?secret=JBSWY3DPEHPK3PXP"}`
+func (h *handler) CreateTOTP(w http.ResponseWriter, r *http.Request) {
+	ctx := context.Background()
+	var totp internal.TOTP
+	if err := decodeBody(r.Body, &totp); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if err := normalizeTOTP(&totp); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	totp.ID = 0
+	// the response echoes the saved item, so the values managed by the server are dropped
+	totp.CreatedAt, totp.UpdatedAt = nil, nil
+	totp.UserName = userName(r)
+	id, err := h.db.SaveTOTP(ctx, totp)
+	if err != nil {
+		h.writeUserError(w, r, totp.UserName, err)
+		return
+	}
+	totp.ID = id
+	w.Header().Set("Location", fmt.Sprintf("/api/v2/totp/%d", totp.ID))
+	writeData(w, http.StatusCreated, totp)
+}
+
+// RemoveTOTP is a method for deleting the authenticator of authorized user by id.
+// For example: curl -X DELETE -H "Authorization: Bearer <token>" http://127.0.0.1:8080/api/v2/totp/1
+func (h *handler) RemoveTOTP(w http.ResponseWriter, r *http.Request) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	err = h.db.DeleteTOTP(context.Background(), internal.TOTP{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("totp %d not found", id))
+		return
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// normalizeTOTP fills the authenticator from its URI and the defaults, then checks that codes can be generated with it.
+func normalizeTOTP(totp *internal.TOTP) error {
+	key := otp.Key{Issuer: totp.Issuer, Account: totp.Account, Digits: totp.Digits, Period: totp.Period, Algorithm: totp.Algorithm}
+	if totp.URI != "" {
+		var err error
+		if key, err = otp.ParseURI(totp.URI); err != nil {
+			return err
+		}
+	} else if totp.Secret != nil {
+		key.Secret = strings.ToUpper(strings.ReplaceAll(*totp.Secret, " ", ""))
+	} else {
+		return errors.New("uri or secret should not be empty")
+	}
+	if key.Digits == 0 {
+		key.Digits = otp.DefaultDigits
+	}
+	if key.Period == 0 {
+		key.Period = otp.DefaultPeriod
+	}
+	if key.Algorithm == "" {
+		key.Algorithm = otp.DefaultAlgorithm
+	}
+	if key.Digits < 6 || key.Digits > 8 || key.Period <= 0 {
+		return errors.New("digits should be between 6 and 8 and period should be a positive number")
+	}
+	if _, err := key.Code(time.Now()); err != nil {
+		return err
+	}
+
+	if totp.Name == nil || *totp.Name == "" {
+		name := key.Issuer
+		if name == "" {
+			name = key.Account
+		}
+		if name == "" {
+			return errors.New("name should not be empty if there is no issuer and account")
+		}
+		totp.Name = &name
+	}
+	totp.Issuer, totp.Account, totp.Secret = key.Issuer, key.Account, &key.Secret
+	totp.Digits, totp.Period, totp.Algorithm = key.Digits, key.Period, key.Algorithm
+	totp.URI = ""
+	return nil
+}
+
+// totpItem returns the authenticator of authorized user with the id from the URL path.
+// The error response is written if there is no such authenticator.
+func (h *handler) totpItem(w http.ResponseWriter, r *http.Request) (internal.TOTP, bool) {
+	id, err := itemID(r)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return internal.TOTP{}, false
+	}
+	totp, err := h.db.GetTOTP(context.Background(), internal.TOTP{UserName: userName(r), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		writeError(w, r, http.StatusNotFound, internal.ErrorCodeItemNotFound, fmt.Sprintf("totp %d not found", id))
+		return internal.TOTP{}, false
+	}
+	if err != nil {
+		h.writeUserError(w, r, userName(r), err)
+		return internal.TOTP{}, false
+	}
+	return totp[0], true
+}
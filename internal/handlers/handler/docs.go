@@ -0,0 +1,20 @@
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// openAPISpec is the OpenAPI 3 document describing the HTTP API, it must be updated together with the router.
+//
+//go:embed openapi.json
+var openAPISpec []byte
+
+// OpenAPI is a method for getting the OpenAPI 3 document of the HTTP API, Swagger UI at /docs is built from it.
+// For example: curl http://127.0.0.1:8080/openapi.json
+func (h *handler) OpenAPI(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+	if _, err := w.Write(openAPISpec); err != nil {
+		h.log.Errorf("error while writing OpenAPI document: %s", err)
+	}
+}
@@ -3,6 +3,13 @@ package handler
 import "errors"
 
 var (
-	ErrTokenIsEmpty = errors.New("token is empty")
-	ErrNoToken      = errors.New("no token")
+	ErrTokenIsEmpty     = errors.New("token is empty")
+	ErrNoToken          = errors.New("no token")
+	ErrInvalidOTP       = errors.New("invalid one-time code")
+	ErrInvalidChallenge = errors.New("invalid login challenge")
+	ErrUnknownKey       = errors.New("unknown key")
+	ErrInvalidToken     = errors.New("invalid token")
+	ErrSessionRevoked   = errors.New("session is no longer valid")
+	// ErrInvalidRecoveryKey is returned for unknown users too, so it doesn't reveal registered ones.
+	ErrInvalidRecoveryKey = errors.New("invalid login or recovery key")
 )
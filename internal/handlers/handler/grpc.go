@@ -0,0 +1,271 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/pkg/keeperpb"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"math"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// errorDomain is the domain of the ErrorInfo details of gRPC errors, the reason is the code from the catalogue.
+const errorDomain = "goph-keeper"
+
+// GRPCServer creates gRPC server with the same storage, sessions and login throttling as HTTP API,
+// so the token returned by one of them is valid for the other one.
+func (h *handler) GRPCServer(opts ...grpc.ServerOption) *grpc.Server {
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(h.unaryAuth),
+		grpc.ChainStreamInterceptor(h.streamAuth),
+	)
+	server := grpc.NewServer(opts...)
+	keeperpb.RegisterAuthServiceServer(server, &authServer{h: h})
+	keeperpb.RegisterCredentialsServiceServer(server, &credentialsServer{h: h})
+	keeperpb.RegisterNotesServiceServer(server, &notesServer{h: h})
+	keeperpb.RegisterCardsServiceServer(server, &cardsServer{h: h})
+	keeperpb.RegisterFilesServiceServer(server, &filesServer{h: h})
+	keeperpb.RegisterSyncServiceServer(server, &syncServer{h: h})
+	return server
+}
+
+// unaryAuth authorizes calls of all services except AuthService with the token from the `authorization` metadata.
+func (h *handler) unaryAuth(ctx context.Context, req any, info *grpc.UnaryServerInfo, next grpc.UnaryHandler) (any, error) {
+	if isAuthMethod(info.FullMethod) {
+		return next(ctx, req)
+	}
+	ctx, err := h.authorizeCall(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return next(ctx, req)
+}
+
+// streamAuth is unaryAuth for streaming calls.
+func (h *handler) streamAuth(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, next grpc.StreamHandler) error {
+	if isAuthMethod(info.FullMethod) {
+		return next(srv, stream)
+	}
+	ctx, err := h.authorizeCall(stream.Context())
+	if err != nil {
+		return err
+	}
+	return next(srv, &authorizedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authorizeCall checks the token the same way as TokenAuth and puts the user's name into the context.
+func (h *handler) authorizeCall(ctx context.Context) (context.Context, error) {
+	var authorization string
+	if values := metadata.ValueFromIncomingContext(ctx, "authorization"); len(values) > 0 {
+		authorization = values[0]
+	}
+	name, err := h.authenticate(authorization)
+	if err != nil {
+		return nil, grpcError(authError(err), http.StatusUnauthorized)
+	}
+	return context.WithValue(ctx, userNameKey{}, name), nil
+}
+
+func isAuthMethod(fullMethod string) bool {
+	return strings.HasPrefix(fullMethod, fmt.Sprintf("/%s/", keeperpb.AuthService_ServiceDesc.ServiceName))
+}
+
+// authorizedStream replaces the context of the stream with the one containing the user's name.
+type authorizedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authorizedStream) Context() context.Context {
+	return s.ctx
+}
+
+// grpcError converts the error from the catalogue with its HTTP status to gRPC status.
+// The code from the catalogue and the details are passed in ErrorInfo.
+func grpcError(apiErr internal.Error, httpStatus int) error {
+	info := &errdetails.ErrorInfo{Reason: apiErr.Code, Domain: errorDomain}
+	if len(apiErr.Details) != 0 {
+		info.Metadata = make(map[string]string, len(apiErr.Details))
+		for key, value := range apiErr.Details {
+			info.Metadata[key] = fmt.Sprint(value)
+		}
+	}
+	st := status.New(grpcCode(httpStatus), apiErr.Message)
+	if withDetails, err := st.WithDetails(info); err == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// grpcUserError is writeUserError for gRPC calls.
+func (h *handler) grpcUserError(userName string, err error) error {
+	apiErr, httpStatus := parseUserError(userName, err)
+	if httpStatus == http.StatusInternalServerError {
+		h.log.Errorf("gRPC call of user %q failed: %s", userName, err)
+	}
+	return grpcError(apiErr, httpStatus)
+}
+
+// invalidArgument returns gRPC error with invalid_request code.
+func invalidArgument(message string) error {
+	return grpcError(internal.Error{Code: internal.ErrorCodeInvalidRequest, Message: message}, http.StatusBadRequest)
+}
+
+// notFound returns gRPC error with item_not_found code.
+func notFound(message string) error {
+	return grpcError(internal.Error{Code: internal.ErrorCodeItemNotFound, Message: message}, http.StatusNotFound)
+}
+
+func grpcCode(httpStatus int) codes.Code {
+	switch httpStatus {
+	case http.StatusBadRequest:
+		return codes.InvalidArgument
+	case http.StatusUnauthorized:
+		return codes.Unauthenticated
+	case http.StatusForbidden:
+		return codes.PermissionDenied
+	case http.StatusNotFound:
+		return codes.NotFound
+	case http.StatusConflict:
+		return codes.AlreadyExists
+	case http.StatusTooManyRequests:
+		return codes.ResourceExhausted
+	default:
+		return codes.Internal
+	}
+}
+
+// peerAddress returns the address of the gRPC client without port.
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return ""
+	}
+	host, _, err := net.SplitHostPort(p.Addr.String())
+	if err != nil {
+		return p.Addr.String()
+	}
+	return host
+}
+
+type authServer struct {
+	keeperpb.UnimplementedAuthServiceServer
+	h *handler
+}
+
+// Register registers the user like the HTTP endpoint, the password must satisfy the same policy.
+func (s *authServer) Register(ctx context.Context, req *keeperpb.RegisterRequest) (*keeperpb.Session, error) {
+	if req.GetLogin() == "" || req.GetPassword() == "" {
+		return nil, invalidArgument("login or password is empty")
+	}
+	user := &internal.User{Login: req.GetLogin(), Password: req.GetPassword()}
+	if err := s.h.checkPassword(ctx, user); err != nil {
+		return nil, s.h.grpcUserError(user.Login, err)
+	}
+	if err := s.h.db.Register(ctx, user.Login, user.Password); err != nil {
+		return nil, s.h.grpcUserError(user.Login, err)
+	}
+	session, err := s.session(user.Login)
+	if err != nil {
+		return nil, err
+	}
+	s.h.log.Infof("user %q was successfully registered via gRPC", user.Login)
+	return session, nil
+}
+
+// Login checks the password like the HTTP endpoint, failed attempts are throttled together with HTTP ones.
+func (s *authServer) Login(ctx context.Context, req *keeperpb.LoginRequest) (*keeperpb.LoginResponse, error) {
+	if req.GetLogin() == "" || req.GetPassword() == "" {
+		return nil, invalidArgument("login or password is empty")
+	}
+	address := peerAddress(ctx)
+	if err := s.throttled(req.GetLogin(), address); err != nil {
+		return nil, err
+	}
+	if err := s.h.db.Login(ctx, req.GetLogin(), req.GetPassword()); err != nil {
+		if errors.Is(err, database.ErrNoSuchUser) || errors.Is(err, database.ErrInvalidCredentials) {
+			s.h.loginFailed(req.GetLogin(), address)
+		}
+		return nil, s.h.grpcUserError(req.GetLogin(), err)
+	}
+	// users with enabled two-factor authentication get a short-lived challenge instead of a token
+	twoFactor, err := s.h.db.GetTwoFactor(ctx, req.GetLogin())
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return nil, s.h.grpcUserError(req.GetLogin(), err)
+	}
+	if twoFactor.Enabled {
+		challenge, err := createChallengeToken(req.GetLogin(), time.Now().Add(challengeTTL))
+		if err != nil {
+			return nil, s.h.grpcUserError(req.GetLogin(), fmt.Errorf("error while create challenge for user: %w", err))
+		}
+		return &keeperpb.LoginResponse{Result: &keeperpb.LoginResponse_Challenge{Challenge: challenge}}, nil
+	}
+	session, err := s.session(req.GetLogin())
+	if err != nil {
+		return nil, err
+	}
+	s.h.accountThrottle.reset(req.GetLogin())
+	s.h.log.Infof("user %q was successfully logined via gRPC", req.GetLogin())
+	return &keeperpb.LoginResponse{Result: &keeperpb.LoginResponse_Session{Session: session}}, nil
+}
+
+// LoginTwoFactor checks the second factor like the HTTP endpoint.
+func (s *authServer) LoginTwoFactor(ctx context.Context, req *keeperpb.LoginTwoFactorRequest) (*keeperpb.Session, error) {
+	if req.GetChallenge() == "" || (req.GetCode() == "" && req.GetRecoveryCode() == "") {
+		return nil, invalidArgument("challenge and code or recovery code should not be empty")
+	}
+	userName, err := parseChallengeToken(req.GetChallenge())
+	if err != nil {
+		return nil, s.h.grpcUserError(userName, err)
+	}
+	address := peerAddress(ctx)
+	if err = s.throttled(userName, address); err != nil {
+		return nil, err
+	}
+	if req.GetRecoveryCode() != "" {
+		err = s.h.db.UseRecoveryCode(ctx, userName, normalizeRecoveryCode(req.GetRecoveryCode()))
+	} else {
+		err = s.h.checkCode(ctx, userName, req.GetCode())
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidOTP) || errors.Is(err, database.ErrInvalidRecoveryCode) {
+			s.h.loginFailed(userName, address)
+		}
+		return nil, s.h.grpcUserError(userName, err)
+	}
+	session, err := s.session(userName)
+	if err != nil {
+		return nil, err
+	}
+	s.h.accountThrottle.reset(userName)
+	s.h.log.Infof("user %q was successfully logined with two-factor authentication via gRPC", userName)
+	return session, nil
+}
+
+// throttled returns ResourceExhausted error if login attempts for the account or the client address are delayed.
+func (s *authServer) throttled(login string, address string) error {
+	wait := s.h.loginDelay(login, address)
+	if wait == 0 {
+		return nil
+	}
+	return grpcError(tooManyRequests(int(math.Ceil(wait.Seconds()))), http.StatusTooManyRequests)
+}
+
+func (s *authServer) session(userName string) (*keeperpb.Session, error) {
+	token, expirationTime, err := s.h.openSession(userName)
+	if err != nil {
+		return nil, s.h.grpcUserError(userName, fmt.Errorf("error while create token for user: %w", err))
+	}
+	return &keeperpb.Session{Token: token, ExpiresAt: expirationTime.Unix()}, nil
+}
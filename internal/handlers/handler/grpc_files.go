@@ -0,0 +1,177 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/pkg/keeperpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"io"
+)
+
+const (
+	// maxFileSize limits the size of uploaded files, the content is kept in memory until it's saved.
+	maxFileSize = 64 << 20
+	// fileChunkSize is the size of chunks of downloaded files.
+	fileChunkSize = 64 << 10
+)
+
+type filesServer struct {
+	keeperpb.UnimplementedFilesServiceServer
+	h *handler
+}
+
+// Upload saves the file of authorized user. The first message must contain the file info with the name,
+// the following ones contain chunks of the content.
+func (s *filesServer) Upload(stream keeperpb.FilesService_UploadServer) error {
+	ctx := stream.Context()
+	first, err := stream.Recv()
+	if err != nil {
+		return invalidArgument(fmt.Sprintf("error while receiving file info: %s", err))
+	}
+	info := first.GetInfo()
+	if info == nil || info.GetName() == "" {
+		return invalidArgument("the first message should contain file info with the name")
+	}
+
+	var content bytes.Buffer
+	for {
+		req, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return invalidArgument(fmt.Sprintf("error while receiving file content: %s", err))
+		}
+		if content.Len()+len(req.GetChunk()) > maxFileSize {
+			return invalidArgument(fmt.Sprintf("file should not be larger than %d bytes", maxFileSize))
+		}
+		content.Write(req.GetChunk())
+	}
+
+	file := internal.File{UserName: contextUserName(ctx), Name: &info.Name, Metadata: info.Metadata}
+	if file.ID, err = s.h.db.SaveFile(ctx, file, content.Bytes()); err != nil {
+		return s.h.grpcUserError(file.UserName, err)
+	}
+	file.Size = int64(content.Len())
+	return stream.SendAndClose(fileMessage(file))
+}
+
+// Download streams the content of the file of authorized user by id.
+func (s *filesServer) Download(req *keeperpb.ItemRequest, stream keeperpb.FilesService_DownloadServer) error {
+	ctx := stream.Context()
+	file, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return err
+	}
+	content, err := s.h.db.GetFileContent(ctx, file)
+	if err != nil {
+		return s.h.grpcUserError(file.UserName, err)
+	}
+	for start := 0; start < len(content); start += fileChunkSize {
+		end := min(start+fileChunkSize, len(content))
+		if err = stream.Send(&keeperpb.FileChunk{Data: content[start:end]}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns files of authorized user without their content, optionally filtered by name.
+func (s *filesServer) List(ctx context.Context, req *keeperpb.ListFilesRequest) (*keeperpb.ListFilesResponse, error) {
+	files, err := s.h.db.GetFiles(ctx, internal.File{UserName: contextUserName(ctx), Name: req.Name})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return nil, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	response := &keeperpb.ListFilesResponse{}
+	for _, file := range files {
+		response.Items = append(response.Items, fileMessage(file))
+	}
+	return response, nil
+}
+
+// Delete deletes the file of authorized user by id.
+func (s *filesServer) Delete(ctx context.Context, req *keeperpb.ItemRequest) (*emptypb.Empty, error) {
+	file, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.h.db.DeleteFiles(ctx, internal.File{UserName: file.UserName, ID: file.ID}); err != nil {
+		return nil, s.h.grpcUserError(file.UserName, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *filesServer) item(ctx context.Context, id int64) (internal.File, error) {
+	if id <= 0 {
+		return internal.File{}, invalidArgument(fmt.Sprintf("invalid item id %d", id))
+	}
+	files, err := s.h.db.GetFiles(ctx, internal.File{UserName: contextUserName(ctx), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		return internal.File{}, notFound(fmt.Sprintf("file %d not found", id))
+	}
+	if err != nil {
+		return internal.File{}, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	return files[0], nil
+}
+
+type syncServer struct {
+	keeperpb.UnimplementedSyncServiceServer
+	h *handler
+}
+
+// Sync streams all credentials, notes, cards and files of authorized user, files are sent without their content.
+func (s *syncServer) Sync(_ *keeperpb.SyncRequest, stream keeperpb.SyncService_SyncServer) error {
+	ctx := stream.Context()
+	name := contextUserName(ctx)
+
+	var items []*keeperpb.SyncItem
+	creds, err := s.h.db.GetCredentials(ctx, internal.Credentials{UserName: name})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return s.h.grpcUserError(name, err)
+	}
+	for _, c := range creds {
+		items = append(items, &keeperpb.SyncItem{Item: &keeperpb.SyncItem_Credentials{Credentials: credentialsMessage(c)}})
+	}
+	notes, err := s.h.db.GetNotes(ctx, internal.Note{UserName: name})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return s.h.grpcUserError(name, err)
+	}
+	for _, note := range notes {
+		items = append(items, &keeperpb.SyncItem{Item: &keeperpb.SyncItem_Note{Note: noteMessage(note)}})
+	}
+	cards, err := s.h.db.GetCard(ctx, internal.Card{UserName: name})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return s.h.grpcUserError(name, err)
+	}
+	for _, card := range cards {
+		items = append(items, &keeperpb.SyncItem{Item: &keeperpb.SyncItem_Card{Card: cardMessage(card)}})
+	}
+	files, err := s.h.db.GetFiles(ctx, internal.File{UserName: name})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return s.h.grpcUserError(name, err)
+	}
+	for _, file := range files {
+		items = append(items, &keeperpb.SyncItem{Item: &keeperpb.SyncItem_File{File: fileMessage(file)}})
+	}
+
+	for _, item := range items {
+		if err = stream.Send(item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func fileMessage(file internal.File) *keeperpb.File {
+	return &keeperpb.File{
+		Id:       file.ID,
+		Name:     stringValue(file.Name),
+		Size:     file.Size,
+		Metadata: file.Metadata,
+	}
+}
@@ -0,0 +1,116 @@
+package handler
+
+import (
+	"bytes"
+	"errors"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/pkg/keeperpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"io"
+	"testing"
+)
+
+func TestHandler_GRPCFiles(t *testing.T) {
+	userName := "sansa"
+	name := "map.png"
+	content := bytes.Repeat([]byte("winterfell"), fileChunkSize/5)
+
+	t.Run("positive: file uploaded in chunks", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("SaveFile", mock.Anything, internal.File{UserName: userName, Name: &name}, content).Return(int64(2), nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		stream, err := keeperpb.NewFilesServiceClient(conn).Upload(authorizedContext(t, h, userName))
+		assert.NoError(t, err)
+		assert.NoError(t, stream.Send(&keeperpb.UploadRequest{Data: &keeperpb.UploadRequest_Info{Info: &keeperpb.File{Name: name}}}))
+		for start := 0; start < len(content); start += 1000 {
+			end := min(start+1000, len(content))
+			assert.NoError(t, stream.Send(&keeperpb.UploadRequest{Data: &keeperpb.UploadRequest_Chunk{Chunk: content[start:end]}}))
+		}
+		file, err := stream.CloseAndRecv()
+		assert.NoError(t, err)
+		assert.Equal(t, int64(2), file.GetId())
+		assert.Equal(t, int64(len(content)), file.GetSize())
+	})
+	t.Run("negative: upload without file info", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		stream, err := keeperpb.NewFilesServiceClient(conn).Upload(authorizedContext(t, h, userName))
+		assert.NoError(t, err)
+		assert.NoError(t, stream.Send(&keeperpb.UploadRequest{Data: &keeperpb.UploadRequest_Chunk{Chunk: content}}))
+		_, err = stream.CloseAndRecv()
+		assertGRPCError(t, err, codes.InvalidArgument, internal.ErrorCodeInvalidRequest)
+	})
+	t.Run("positive: file downloaded in chunks", func(t *testing.T) {
+		file := internal.File{ID: 2, UserName: userName, Name: &name, Size: int64(len(content))}
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetFiles", mock.Anything, internal.File{UserName: userName, ID: 2}).Return([]internal.File{file}, nil)
+		mockedStorage.On("GetFileContent", mock.Anything, file).Return(content, nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		stream, err := keeperpb.NewFilesServiceClient(conn).Download(authorizedContext(t, h, userName), &keeperpb.ItemRequest{Id: 2})
+		assert.NoError(t, err)
+		var downloaded []byte
+		chunks := 0
+		for {
+			chunk, err := stream.Recv()
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			assert.NoError(t, err)
+			downloaded = append(downloaded, chunk.GetData()...)
+			chunks++
+		}
+		assert.Equal(t, content, downloaded)
+		assert.Equal(t, 2, chunks)
+	})
+	t.Run("negative: no such file", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetFiles", mock.Anything, internal.File{UserName: userName, ID: 2}).Return(nil, database.ErrNoData)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		stream, err := keeperpb.NewFilesServiceClient(conn).Download(authorizedContext(t, h, userName), &keeperpb.ItemRequest{Id: 2})
+		assert.NoError(t, err)
+		_, err = stream.Recv()
+		assertGRPCError(t, err, codes.NotFound, internal.ErrorCodeItemNotFound)
+	})
+}
+
+func TestHandler_GRPCSync(t *testing.T) {
+	userName := "sansa"
+	login := "lady_of_winterfell"
+	title := "lessons"
+	name := "map.png"
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName}).
+		Return([]internal.Credentials{{ID: 1, UserName: userName, Login: &login}}, nil)
+	mockedStorage.On("GetNotes", mock.Anything, internal.Note{UserName: userName}).
+		Return([]internal.Note{{ID: 1, UserName: userName, Title: &title}}, nil)
+	mockedStorage.On("GetCard", mock.Anything, internal.Card{UserName: userName}).Return(nil, database.ErrNoData)
+	mockedStorage.On("GetFiles", mock.Anything, internal.File{UserName: userName}).
+		Return([]internal.File{{ID: 1, UserName: userName, Name: &name, Size: 10}}, nil)
+	h, conn := newGRPCServer(t, mockedStorage)
+
+	stream, err := keeperpb.NewSyncServiceClient(conn).Sync(authorizedContext(t, h, userName), &keeperpb.SyncRequest{})
+	assert.NoError(t, err)
+	var items []*keeperpb.SyncItem
+	for {
+		item, err := stream.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		assert.NoError(t, err)
+		items = append(items, item)
+	}
+	if assert.Len(t, items, 3) {
+		assert.Equal(t, login, items[0].GetCredentials().GetLogin())
+		assert.Equal(t, title, items[1].GetNote().GetTitle())
+		assert.Equal(t, name, items[2].GetFile().GetName())
+	}
+}
@@ -0,0 +1,322 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/pkg/keeperpb"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+type credentialsServer struct {
+	keeperpb.UnimplementedCredentialsServiceServer
+	h *handler
+}
+
+// List returns credentials of authorized user, optionally filtered by login.
+func (s *credentialsServer) List(ctx context.Context, req *keeperpb.ListCredentialsRequest) (*keeperpb.ListCredentialsResponse, error) {
+	creds, err := s.h.db.GetCredentials(ctx, internal.Credentials{UserName: contextUserName(ctx), Login: req.Login})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return nil, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	response := &keeperpb.ListCredentialsResponse{}
+	for _, c := range creds {
+		response.Items = append(response.Items, credentialsMessage(c))
+	}
+	return response, nil
+}
+
+// Get returns credentials of authorized user by id.
+func (s *credentialsServer) Get(ctx context.Context, req *keeperpb.ItemRequest) (*keeperpb.Credentials, error) {
+	creds, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return credentialsMessage(creds), nil
+}
+
+// Create saves new credentials of authorized user, login and password are required.
+func (s *credentialsServer) Create(ctx context.Context, req *keeperpb.Credentials) (*keeperpb.Credentials, error) {
+	if req.GetLogin() == "" || req.GetPassword() == "" {
+		return nil, invalidArgument("login and password should not be empty")
+	}
+	creds := internal.Credentials{
+		UserName: contextUserName(ctx),
+		Login:    &req.Login,
+		Password: &req.Password,
+		Metadata: req.Metadata,
+	}
+	id, err := s.h.db.SaveCredentials(ctx, creds)
+	if err != nil {
+		return nil, s.h.grpcUserError(creds.UserName, err)
+	}
+	creds.ID = id
+	return credentialsMessage(creds), nil
+}
+
+// Update changes password and/or metadata of the credentials by id.
+func (s *credentialsServer) Update(ctx context.Context, req *keeperpb.UpdateCredentialsRequest) (*keeperpb.Credentials, error) {
+	creds, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.Password != nil {
+		creds.Password = req.Password
+	}
+	if req.Metadata != nil {
+		creds.Metadata = req.Metadata
+	}
+	if _, err = s.h.db.UpdateCredentials(ctx, creds); err != nil {
+		return nil, s.h.grpcUserError(creds.UserName, err)
+	}
+	return credentialsMessage(creds), nil
+}
+
+// Delete deletes the credentials of authorized user by id.
+func (s *credentialsServer) Delete(ctx context.Context, req *keeperpb.ItemRequest) (*emptypb.Empty, error) {
+	creds, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.h.db.DeleteCredentials(ctx, internal.Credentials{UserName: creds.UserName, ID: creds.ID}); err != nil {
+		return nil, s.h.grpcUserError(creds.UserName, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *credentialsServer) item(ctx context.Context, id int64) (internal.Credentials, error) {
+	if id <= 0 {
+		return internal.Credentials{}, invalidArgument(fmt.Sprintf("invalid item id %d", id))
+	}
+	creds, err := s.h.db.GetCredentials(ctx, internal.Credentials{UserName: contextUserName(ctx), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		return internal.Credentials{}, notFound(fmt.Sprintf("credentials %d not found", id))
+	}
+	if err != nil {
+		return internal.Credentials{}, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	return creds[0], nil
+}
+
+type notesServer struct {
+	keeperpb.UnimplementedNotesServiceServer
+	h *handler
+}
+
+// List returns notes of authorized user, optionally filtered by title.
+func (s *notesServer) List(ctx context.Context, req *keeperpb.ListNotesRequest) (*keeperpb.ListNotesResponse, error) {
+	notes, err := s.h.db.GetNotes(ctx, internal.Note{UserName: contextUserName(ctx), Title: req.Title})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return nil, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	response := &keeperpb.ListNotesResponse{}
+	for _, note := range notes {
+		response.Items = append(response.Items, noteMessage(note))
+	}
+	return response, nil
+}
+
+// Get returns the note of authorized user by id.
+func (s *notesServer) Get(ctx context.Context, req *keeperpb.ItemRequest) (*keeperpb.Note, error) {
+	note, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return noteMessage(note), nil
+}
+
+// Create saves new note of authorized user, title and content are required.
+func (s *notesServer) Create(ctx context.Context, req *keeperpb.Note) (*keeperpb.Note, error) {
+	if req.GetTitle() == "" || req.GetContent() == "" {
+		return nil, invalidArgument("title and content should not be empty")
+	}
+	note := internal.Note{
+		UserName: contextUserName(ctx),
+		Title:    &req.Title,
+		Content:  &req.Content,
+		Metadata: req.Metadata,
+	}
+	id, err := s.h.db.SaveNote(ctx, note)
+	if err != nil {
+		return nil, s.h.grpcUserError(note.UserName, err)
+	}
+	note.ID = id
+	return noteMessage(note), nil
+}
+
+// Update changes content and/or metadata of the note by id.
+func (s *notesServer) Update(ctx context.Context, req *keeperpb.UpdateNoteRequest) (*keeperpb.Note, error) {
+	note, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.Content != nil {
+		note.Content = req.Content
+	}
+	if req.Metadata != nil {
+		note.Metadata = req.Metadata
+	}
+	if err = s.h.db.UpdateNote(ctx, note); err != nil {
+		return nil, s.h.grpcUserError(note.UserName, err)
+	}
+	return noteMessage(note), nil
+}
+
+// Delete deletes the note of authorized user by id.
+func (s *notesServer) Delete(ctx context.Context, req *keeperpb.ItemRequest) (*emptypb.Empty, error) {
+	note, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.h.db.DeleteNotes(ctx, internal.Note{UserName: note.UserName, ID: note.ID}); err != nil {
+		return nil, s.h.grpcUserError(note.UserName, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *notesServer) item(ctx context.Context, id int64) (internal.Note, error) {
+	if id <= 0 {
+		return internal.Note{}, invalidArgument(fmt.Sprintf("invalid item id %d", id))
+	}
+	notes, err := s.h.db.GetNotes(ctx, internal.Note{UserName: contextUserName(ctx), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		return internal.Note{}, notFound(fmt.Sprintf("note %d not found", id))
+	}
+	if err != nil {
+		return internal.Note{}, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	return notes[0], nil
+}
+
+type cardsServer struct {
+	keeperpb.UnimplementedCardsServiceServer
+	h *handler
+}
+
+// List returns bank cards of authorized user, optionally filtered by bank name and number.
+func (s *cardsServer) List(ctx context.Context, req *keeperpb.ListCardsRequest) (*keeperpb.ListCardsResponse, error) {
+	cards, err := s.h.db.GetCard(ctx, internal.Card{UserName: contextUserName(ctx), BankName: req.BankName, Number: req.Number})
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		return nil, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	response := &keeperpb.ListCardsResponse{}
+	for _, card := range cards {
+		response.Items = append(response.Items, cardMessage(card))
+	}
+	return response, nil
+}
+
+// Get returns the bank card of authorized user by id.
+func (s *cardsServer) Get(ctx context.Context, req *keeperpb.ItemRequest) (*keeperpb.Card, error) {
+	card, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return cardMessage(card), nil
+}
+
+// Create saves new bank card of authorized user, all fields except metadata are required.
+func (s *cardsServer) Create(ctx context.Context, req *keeperpb.Card) (*keeperpb.Card, error) {
+	if req.GetBankName() == "" || req.GetNumber() == "" || req.GetCv() == "" || req.GetPassword() == "" {
+		return nil, invalidArgument("bank name, number, cv and password should not be empty")
+	}
+	card := internal.Card{
+		UserName: contextUserName(ctx),
+		BankName: &req.BankName,
+		Number:   &req.Number,
+		CV:       &req.Cv,
+		Password: &req.Password,
+		Metadata: req.Metadata,
+	}
+	id, err := s.h.db.SaveCard(ctx, card)
+	if err != nil {
+		return nil, s.h.grpcUserError(card.UserName, err)
+	}
+	card.ID = id
+	return cardMessage(card), nil
+}
+
+// Update changes cv, password and/or metadata of the bank card by id.
+func (s *cardsServer) Update(ctx context.Context, req *keeperpb.UpdateCardRequest) (*keeperpb.Card, error) {
+	card, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if req.Cv != nil {
+		card.CV = req.Cv
+	}
+	if req.Password != nil {
+		card.Password = req.Password
+	}
+	if req.Metadata != nil {
+		card.Metadata = req.Metadata
+	}
+	if _, err = s.h.db.UpdateCard(ctx, card); err != nil {
+		return nil, s.h.grpcUserError(card.UserName, err)
+	}
+	return cardMessage(card), nil
+}
+
+// Delete deletes the bank card of authorized user by id.
+func (s *cardsServer) Delete(ctx context.Context, req *keeperpb.ItemRequest) (*emptypb.Empty, error) {
+	card, err := s.item(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if err = s.h.db.DeleteCards(ctx, internal.Card{UserName: card.UserName, ID: card.ID}); err != nil {
+		return nil, s.h.grpcUserError(card.UserName, err)
+	}
+	return &emptypb.Empty{}, nil
+}
+
+func (s *cardsServer) item(ctx context.Context, id int64) (internal.Card, error) {
+	if id <= 0 {
+		return internal.Card{}, invalidArgument(fmt.Sprintf("invalid item id %d", id))
+	}
+	cards, err := s.h.db.GetCard(ctx, internal.Card{UserName: contextUserName(ctx), ID: id})
+	if errors.Is(err, database.ErrNoData) {
+		return internal.Card{}, notFound(fmt.Sprintf("card %d not found", id))
+	}
+	if err != nil {
+		return internal.Card{}, s.h.grpcUserError(contextUserName(ctx), err)
+	}
+	return cards[0], nil
+}
+
+func credentialsMessage(creds internal.Credentials) *keeperpb.Credentials {
+	return &keeperpb.Credentials{
+		Id:       creds.ID,
+		Login:    stringValue(creds.Login),
+		Password: stringValue(creds.Password),
+		Metadata: creds.Metadata,
+	}
+}
+
+func noteMessage(note internal.Note) *keeperpb.Note {
+	return &keeperpb.Note{
+		Id:       note.ID,
+		Title:    stringValue(note.Title),
+		Content:  stringValue(note.Content),
+		Metadata: note.Metadata,
+	}
+}
+
+func cardMessage(card internal.Card) *keeperpb.Card {
+	return &keeperpb.Card{
+		Id:       card.ID,
+		BankName: stringValue(card.BankName),
+		Number:   stringValue(card.Number),
+		Cv:       stringValue(card.CV),
+		Password: stringValue(card.Password),
+		Metadata: card.Metadata,
+	}
+}
+
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
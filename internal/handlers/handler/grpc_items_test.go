@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"errors"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/pkg/keeperpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"google.golang.org/grpc/codes"
+	"testing"
+)
+
+func TestHandler_GRPCCredentials(t *testing.T) {
+	userName := "sansa"
+	login := "lady_of_winterfell"
+	password := "lemon cakes"
+	newPassword := "direwolf"
+
+	t.Run("positive: credentials created", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("SaveCredentials", mock.Anything, internal.Credentials{UserName: userName, Login: &login, Password: &password}).Return(int64(7), nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		creds, err := keeperpb.NewCredentialsServiceClient(conn).Create(authorizedContext(t, h, userName), &keeperpb.Credentials{Login: login, Password: password})
+		assert.NoError(t, err)
+		assert.Equal(t, int64(7), creds.GetId())
+		assert.Equal(t, login, creds.GetLogin())
+	})
+	t.Run("positive: password updated", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName, ID: 7}).
+			Return([]internal.Credentials{{ID: 7, UserName: userName, Login: &login, Password: &password}}, nil)
+		mockedStorage.On("UpdateCredentials", mock.Anything, internal.Credentials{ID: 7, UserName: userName, Login: &login, Password: &newPassword}).Return(internal.Rotation{}, nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		creds, err := keeperpb.NewCredentialsServiceClient(conn).Update(authorizedContext(t, h, userName), &keeperpb.UpdateCredentialsRequest{Id: 7, Password: &newPassword})
+		assert.NoError(t, err)
+		assert.Equal(t, newPassword, creds.GetPassword())
+	})
+	t.Run("negative: no such credentials", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName, ID: 7}).Return(nil, database.ErrNoData)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewCredentialsServiceClient(conn).Delete(authorizedContext(t, h, userName), &keeperpb.ItemRequest{Id: 7})
+		assertGRPCError(t, err, codes.NotFound, internal.ErrorCodeItemNotFound)
+	})
+	t.Run("negative: duplicate credentials", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("SaveCredentials", mock.Anything, mock.Anything).Return(int64(0), database.ErrItemAlreadyExists)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewCredentialsServiceClient(conn).Create(authorizedContext(t, h, userName), &keeperpb.Credentials{Login: login, Password: password})
+		assertGRPCError(t, err, codes.AlreadyExists, internal.ErrorCodeDuplicateItem)
+	})
+}
+
+func TestHandler_GRPCNotesAndCards(t *testing.T) {
+	userName := "sansa"
+	title := "lessons"
+	content := "trust no one"
+	bankName := "iron bank"
+	number := "4242 4242 4242 4242"
+
+	t.Run("positive: note listed", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetNotes", mock.Anything, internal.Note{UserName: userName, Title: &title}).
+			Return([]internal.Note{{ID: 3, UserName: userName, Title: &title, Content: &content}}, nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		notes, err := keeperpb.NewNotesServiceClient(conn).List(authorizedContext(t, h, userName), &keeperpb.ListNotesRequest{Title: &title})
+		assert.NoError(t, err)
+		if assert.Len(t, notes.GetItems(), 1) {
+			assert.Equal(t, content, notes.GetItems()[0].GetContent())
+		}
+	})
+	t.Run("negative: note without content", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewNotesServiceClient(conn).Create(authorizedContext(t, h, userName), &keeperpb.Note{Title: title})
+		assertGRPCError(t, err, codes.InvalidArgument, internal.ErrorCodeInvalidRequest)
+	})
+	t.Run("positive: card deleted", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetCard", mock.Anything, internal.Card{UserName: userName, ID: 5}).
+			Return([]internal.Card{{ID: 5, UserName: userName, BankName: &bankName, Number: &number}}, nil)
+		mockedStorage.On("DeleteCards", mock.Anything, internal.Card{UserName: userName, ID: 5}).Return(nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewCardsServiceClient(conn).Delete(authorizedContext(t, h, userName), &keeperpb.ItemRequest{Id: 5})
+		assert.NoError(t, err)
+	})
+	t.Run("negative: storage error is not exposed", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetCard", mock.Anything, internal.Card{UserName: userName, ID: 5}).Return(nil, errors.New("some db error"))
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewCardsServiceClient(conn).Get(authorizedContext(t, h, userName), &keeperpb.ItemRequest{Id: 5})
+		assertGRPCError(t, err, codes.Internal, internal.ErrorCodeInternal)
+		assert.NotContains(t, err.Error(), "some db error")
+	})
+}
@@ -0,0 +1,182 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/pkg/keeperpb"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"net"
+	"testing"
+	"time"
+)
+
+func newGRPCServer(t *testing.T, mockedStorage *mocks.Storage) (*handler, *grpc.ClientConn) {
+	logger, _ := zap.NewProduction()
+	h := New(mockedStorage, logger.Sugar())
+
+	listener := bufconn.Listen(1 << 20)
+	server := h.GRPCServer()
+	go func() {
+		_ = server.Serve(listener)
+	}()
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	assert.NoError(t, err)
+	t.Cleanup(func() {
+		_ = conn.Close()
+		server.Stop()
+	})
+	return h, conn
+}
+
+// authorizedContext opens the session of the user and returns the context with its token.
+func authorizedContext(t *testing.T, h *handler, userName string) context.Context {
+	token, _, err := h.openSession(userName)
+	assert.NoError(t, err)
+	return metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", token))
+}
+
+// assertGRPCError checks gRPC status code and the code from the catalogue.
+func assertGRPCError(t *testing.T, err error, code codes.Code, reason string) {
+	st, ok := status.FromError(err)
+	if !assert.True(t, ok) {
+		return
+	}
+	assert.Equal(t, code, st.Code())
+	if assert.Len(t, st.Details(), 1) {
+		info, ok := st.Details()[0].(*errdetails.ErrorInfo)
+		assert.True(t, ok)
+		assert.Equal(t, reason, info.GetReason())
+		assert.Equal(t, errorDomain, info.GetDomain())
+	}
+}
+
+func TestHandler_GRPCAuth(t *testing.T) {
+	userName := "sansa"
+	password := "lemon cakes"
+
+	t.Run("positive: token from login authorizes gRPC and HTTP calls", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName}).Return(nil, database.ErrNoData)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		resp, err := keeperpb.NewAuthServiceClient(conn).Login(context.Background(), &keeperpb.LoginRequest{Login: userName, Password: password})
+		assert.NoError(t, err)
+		token := resp.GetSession().GetToken()
+		assert.NotEmpty(t, token)
+		assert.Equal(t, fmt.Sprintf("Bearer %s", token), h.cookies.get(userName))
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", token))
+		list, err := keeperpb.NewCredentialsServiceClient(conn).List(ctx, &keeperpb.ListCredentialsRequest{})
+		assert.NoError(t, err)
+		assert.Empty(t, list.GetItems())
+	})
+	t.Run("positive: challenge for two-factor authentication", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Login: userName, Enabled: true}, nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		resp, err := keeperpb.NewAuthServiceClient(conn).Login(context.Background(), &keeperpb.LoginRequest{Login: userName, Password: password})
+		assert.NoError(t, err)
+		assert.NotEmpty(t, resp.GetChallenge())
+		assert.Nil(t, resp.GetSession())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: wrong password", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "wrong").Return(database.ErrInvalidCredentials)
+		_, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewAuthServiceClient(conn).Login(context.Background(), &keeperpb.LoginRequest{Login: userName, Password: "wrong"})
+		assertGRPCError(t, err, codes.Unauthenticated, internal.ErrorCodeInvalidCredentials)
+	})
+	t.Run("negative: throttled login", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, conn := newGRPCServer(t, mockedStorage)
+		for i := 0; i < accountLockoutAttempts; i++ {
+			h.accountThrottle.fail(userName)
+		}
+
+		_, err := keeperpb.NewAuthServiceClient(conn).Login(context.Background(), &keeperpb.LoginRequest{Login: userName, Password: password})
+		assertGRPCError(t, err, codes.ResourceExhausted, internal.ErrorCodeTooManyRequests)
+	})
+	t.Run("negative: no token", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewNotesServiceClient(conn).List(context.Background(), &keeperpb.ListNotesRequest{})
+		assertGRPCError(t, err, codes.Unauthenticated, internal.ErrorCodeInvalidToken)
+	})
+	t.Run("negative: token of the replaced session", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, conn := newGRPCServer(t, mockedStorage)
+		oldToken, err := createToken(userName, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+		_, _, err = h.openSession(userName)
+		assert.NoError(t, err)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", oldToken))
+		_, err = keeperpb.NewCardsServiceClient(conn).List(ctx, &keeperpb.ListCardsRequest{})
+		assertGRPCError(t, err, codes.Unauthenticated, internal.ErrorCodeSessionRevoked)
+	})
+	t.Run("negative: challenge instead of token", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, conn := newGRPCServer(t, mockedStorage)
+		challenge, err := createChallengeToken(userName, time.Now().Add(time.Minute))
+		assert.NoError(t, err)
+
+		ctx := metadata.AppendToOutgoingContext(context.Background(), "authorization", fmt.Sprintf("Bearer %s", challenge))
+		_, err = keeperpb.NewCardsServiceClient(conn).List(ctx, &keeperpb.ListCardsRequest{})
+		assertGRPCError(t, err, codes.Unauthenticated, internal.ErrorCodeInvalidToken)
+	})
+}
+
+func TestHandler_GRPCRegister(t *testing.T) {
+	userName := "sansa"
+	password := "lemon cakes and direwolves"
+
+	t.Run("positive: user registered", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Register", mock.Anything, userName, password).Return(nil)
+		h, conn := newGRPCServer(t, mockedStorage)
+
+		session, err := keeperpb.NewAuthServiceClient(conn).Register(context.Background(), &keeperpb.RegisterRequest{Login: userName, Password: password})
+		assert.NoError(t, err)
+		assert.Equal(t, fmt.Sprintf("Bearer %s", session.GetToken()), h.cookies.get(userName))
+		assert.Greater(t, session.GetExpiresAt(), time.Now().Unix())
+	})
+	t.Run("negative: login is taken", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Register", mock.Anything, userName, password).Return(database.ErrUserAlreadyExists)
+		_, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewAuthServiceClient(conn).Register(context.Background(), &keeperpb.RegisterRequest{Login: userName, Password: password})
+		assertGRPCError(t, err, codes.AlreadyExists, internal.ErrorCodeUserAlreadyExists)
+	})
+	t.Run("negative: empty password", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, conn := newGRPCServer(t, mockedStorage)
+
+		_, err := keeperpb.NewAuthServiceClient(conn).Register(context.Background(), &keeperpb.RegisterRequest{Login: userName})
+		assertGRPCError(t, err, codes.InvalidArgument, internal.ErrorCodeInvalidRequest)
+	})
+}
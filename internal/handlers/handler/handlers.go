@@ -4,33 +4,82 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/password"
 	"go.uber.org/zap"
 	"io"
 	"log"
 	"net/http"
+	"sync"
 	"time"
 )
 
 var jwtKey = []byte("my_secret_key")
 
 type handler struct {
-	db      internal.Storage
-	log     *zap.SugaredLogger
-	cookies map[string]string
+	db              internal.Storage
+	log             *zap.SugaredLogger
+	cookies         *sessions
+	mu              sync.Mutex
+	challenges      map[string]keyChallenge
+	usedCodes       *usedCodes
+	accountThrottle *loginThrottle
+	addressThrottle *loginThrottle
+	adminToken      string
+	passwordPolicy  password.Policy
+	breachChecker   *password.BreachChecker
 }
 
-func New(db internal.Storage, log *zap.SugaredLogger) *handler {
-	return &handler{
-		db:      db,
-		log:     log,
-		cookies: make(map[string]string),
+// Option configures optional handler features.
+type Option func(h *handler)
+
+// WithAdminToken enables admin endpoints for requests authorized with the provided token.
+func WithAdminToken(token string) Option {
+	return func(h *handler) {
+		h.adminToken = token
+	}
+}
+
+// WithPasswordPolicy rejects registration with passwords which don't satisfy the policy.
+func WithPasswordPolicy(policy password.Policy) Option {
+	return func(h *handler) {
+		h.passwordPolicy = policy
+	}
+}
+
+// WithBreachChecker rejects registration with passwords which have appeared in known data breaches
+// and enables the breach check of the stored credentials.
+func WithBreachChecker(checker *password.BreachChecker) Option {
+	return func(h *handler) {
+		h.breachChecker = checker
+	}
+}
+
+func New(db internal.Storage, log *zap.SugaredLogger, opts ...Option) *handler {
+	h := &handler{
+		db:              db,
+		log:             log,
+		cookies:         newSessions(),
+		challenges:      make(map[string]keyChallenge),
+		usedCodes:       newUsedCodes(),
+		accountThrottle: newLoginThrottle(accountLockoutAttempts),
+		addressThrottle: newLoginThrottle(ipLockoutAttempts),
 	}
+	for _, opt := range opts {
+		opt(h)
+	}
+	return h
 }
 
 // Login is a method for login in goph-keeper system.
 // The body of the HTTP request must contain `login` and `password`.
+// Failed attempts are counted per account and per client address: after a few of them each next attempt
+// is delayed exponentially and eventually the account is locked, such requests get 429 status.
+// If user has enabled two-factor authentication, the response has 202 status and contains a challenge
+// which should be exchanged for a token via LoginTwoFactor.
 // For example: curl -X POST http://127.0.0.1:8080/auth/login `{"login": "user_login", "password": "user_password"}`
 func (h *handler) Login(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("content-type", "application/json")
@@ -39,35 +88,67 @@ func (h *handler) Login(w http.ResponseWriter, r *http.Request) {
 	// parse body and get user's login/password
 	user, err := parseInputUser(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	// check password for user, failed attempts slow down the following ones
+	address := clientAddress(r)
+	if h.throttled(w, r, user.Login, address) {
 		return
 	}
-	// check password for user
 	if err = h.db.Login(ctx, user.Login, user.Password); err != nil {
-		message, status := parseUserError(user.Login, err)
-		http.Error(w, message, status)
+		if errors.Is(err, database.ErrNoSuchUser) || errors.Is(err, database.ErrInvalidCredentials) {
+			h.loginFailed(user.Login, address)
+		}
+		h.writeUserError(w, r, user.Login, err)
+		return
+	}
+	// users with enabled two-factor authentication get a short-lived challenge instead of a token
+	if h.challengeTwoFactor(ctx, w, r, user.Login) {
 		return
 	}
 	// create jwt token for user, add Authorization header and remember cookie
-	expirationTime := time.Now().Add(time.Hour)
-	token, err := createToken(user.Login, expirationTime)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("error while create token for user: %s", err.Error()), http.StatusInternalServerError)
+	if err = h.authorize(w, user.Login); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
 		return
 	}
-	w.Header().Add("Authorization", fmt.Sprintf("Bearer %s", token))
-	http.SetCookie(w, &http.Cookie{
-		Name:    "token",
-		Value:   token,
-		Expires: expirationTime,
-	})
+	h.accountThrottle.reset(user.Login)
 	w.WriteHeader(http.StatusOK)
-	h.cookies[user.Login] = fmt.Sprintf("Bearer %s", token)
 	h.log.Infof("user %q was successfully logined", user.Login)
 }
 
+// challengeTwoFactor responds with 202 status and the challenge for LoginTwoFactor if the user has enabled
+// two-factor authentication. It reports whether the response is written, the errors are written too.
+func (h *handler) challengeTwoFactor(ctx context.Context, w http.ResponseWriter, r *http.Request, login string) bool {
+	twoFactor, err := h.db.GetTwoFactor(ctx, login)
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		h.writeUserError(w, r, login, err)
+		return true
+	}
+	if !twoFactor.Enabled {
+		return false
+	}
+	challenge, err := createChallengeToken(login, time.Now().Add(challengeTTL))
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create challenge for user: %s", err.Error()))
+		return true
+	}
+	response, err := json.Marshal(internal.LoginChallenge{Challenge: challenge})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return true
+	}
+	w.WriteHeader(http.StatusAccepted)
+	if _, err = w.Write(response); err != nil {
+		h.log.Errorf("error while writing challenge for user %q: %s", login, err)
+	}
+	return true
+}
+
 // Register is a method for register user in goph-keeper system with provided credentials.
 // The body of the HTTP request must contain `login` and `password`.
+// The password must satisfy the configured policy and must not appear in known data breaches, otherwise 400 status is returned.
+// The body may also contain the `vault` key wrapped on the client with the password and the recovery key.
 // For example: curl -X POST http://127.0.0.1:8080/auth/register --data `{"login": "user_login", "password": "user_password"}`
 func (h *handler) Register(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("content-type", "application/json")
@@ -76,32 +157,87 @@ func (h *handler) Register(w http.ResponseWriter, r *http.Request) {
 	// parse body and get user's login/password
 	user, err := parseInputUser(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	// check password before it's saved
+	if err = h.checkPassword(ctx, user); err != nil {
+		h.writeUserError(w, r, user.Login, err)
+		return
+	}
+	if user.Vault != nil && !validVaultKey(user.Vault, true) {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "vault key should be wrapped with the password and the recovery key")
 		return
 	}
 	// register user in goph-keeper system
 	if err = h.db.Register(ctx, user.Login, user.Password); err != nil {
-		message, status := parseUserError(user.Login, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, user.Login, err)
 		return
 	}
+	if user.Vault != nil {
+		if err = h.db.SaveVaultKey(ctx, user.Login, *user.Vault); err != nil {
+			// the user can't be left without the vault key he has the recovery key for
+			if deleteErr := h.db.DeleteAccount(ctx, user.Login); deleteErr != nil {
+				h.log.Errorf("error while deleting user %q after failed registration: %s", user.Login, deleteErr)
+			}
+			h.writeUserError(w, r, user.Login, err)
+			return
+		}
+	}
 
 	// create jwt token for user, add Authorization header and remember cookie
-	expirationTime := time.Now().Add(time.Hour)
-	token, err := createToken(user.Login, expirationTime)
-	if err != nil {
-		http.Error(w, fmt.Sprintf("error while create token for user: %s", err.Error()), http.StatusInternalServerError)
+	if err = h.authorize(w, user.Login); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
 		return
 	}
+	w.WriteHeader(http.StatusOK)
+	h.log.Infof("user %q was successfully registered", user.Login)
+}
+
+// checkPassword checks user's password against the password policy and known data breaches.
+// Unavailable breached password source doesn't block registration.
+func (h *handler) checkPassword(ctx context.Context, user *internal.User) error {
+	if err := h.passwordPolicy.Check(user.Password, user.Login); err != nil {
+		return err
+	}
+	if h.breachChecker == nil {
+		return nil
+	}
+	err := h.breachChecker.Check(ctx, user.Password)
+	if errors.Is(err, password.ErrBreached) {
+		return err
+	}
+	if err != nil {
+		h.log.Errorf("error while checking password of user %q for breaches: %s", user.Login, err)
+	}
+	return nil
+}
+
+// authorize creates jwt token for user, adds Authorization header and cookie to the response
+// and remembers the token for the following requests.
+func (h *handler) authorize(w http.ResponseWriter, userName string) error {
+	token, expirationTime, err := h.openSession(userName)
+	if err != nil {
+		return err
+	}
 	w.Header().Add("Authorization", fmt.Sprintf("Bearer %s", token))
 	http.SetCookie(w, &http.Cookie{
 		Name:    "token",
 		Value:   token,
 		Expires: expirationTime,
 	})
-	w.WriteHeader(http.StatusOK)
-	h.cookies[user.Login] = fmt.Sprintf("Bearer %s", token)
-	h.log.Infof("user %q was successfully registered", user.Login)
+	return nil
+}
+
+// openSession creates jwt token for user and remembers it, so the sessions opened before are no longer valid.
+func (h *handler) openSession(userName string) (string, time.Time, error) {
+	expirationTime := time.Now().Add(time.Hour)
+	token, err := createToken(userName, expirationTime)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	h.cookies.set(userName, fmt.Sprintf("Bearer %s", token))
+	return token, expirationTime, nil
 }
 
 // GetUserCredentials is a method for getting credentials (pair of login/password and probably metadata)
@@ -114,32 +250,31 @@ func (h *handler) GetUserCredentials(w http.ResponseWriter, r *http.Request) {
 	// parse body to get user's name
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 
 	var userCredentialsRequest internal.Credentials
 	if err = json.Unmarshal(body, &userCredentialsRequest); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 
 	// get user credentials from goph-keeper storage
 	creds, err := h.db.GetCredentials(ctx, userCredentialsRequest)
 	if err != nil {
-		message, status := parseUserError(userCredentialsRequest.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, userCredentialsRequest.UserName, err)
 		return
 	}
 
 	// response
 	credsResponse, err := json.Marshal(creds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	if _, err = io.WriteString(w, string(credsResponse)); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -156,29 +291,28 @@ func (h *handler) SaveUserCredentials(w http.ResponseWriter, r *http.Request) {
 	// parse body
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r.Body); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	var requestCredentials internal.Credentials
 	if err := json.Unmarshal(buf.Bytes(), &requestCredentials); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 	if requestCredentials.Login == nil || requestCredentials.Password == nil {
-		http.Error(w, "login and password should not be empty", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login and password should not be empty")
 		return
 	}
 
 	// save credentials for user in goph-keeper storage
-	if err := h.db.SaveCredentials(ctx, requestCredentials); err != nil {
-		message, status := parseUserError(requestCredentials.UserName, err)
-		http.Error(w, message, status)
+	if _, err := h.db.SaveCredentials(ctx, requestCredentials); err != nil {
+		h.writeUserError(w, r, requestCredentials.UserName, err)
 		return
 	}
 
 	// response
 	if _, err := io.WriteString(w, fmt.Sprintf("saved credentials for user %q", requestCredentials.UserName)); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -195,20 +329,19 @@ func (h *handler) DeleteUserCredentials(w http.ResponseWriter, r *http.Request)
 	// parse body to get user's name
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 
 	var userCredentialsRequest internal.Credentials
 	if err = json.Unmarshal(body, &userCredentialsRequest); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 
 	// delete credentials from goph-keeper storage
 	if err := h.db.DeleteCredentials(ctx, userCredentialsRequest); err != nil {
-		message, status := parseUserError(userCredentialsRequest.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, userCredentialsRequest.UserName, err)
 		return
 	}
 	response := fmt.Sprintf("credentials for user %q was successfully deleted", userCredentialsRequest.UserName)
@@ -217,7 +350,7 @@ func (h *handler) DeleteUserCredentials(w http.ResponseWriter, r *http.Request)
 	}
 
 	if _, err = io.WriteString(w, response); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -235,23 +368,22 @@ func (h *handler) UpdateUserCredentials(w http.ResponseWriter, r *http.Request)
 	// parse body
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r.Body); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	var requestCredentials internal.Credentials
 	if err := json.Unmarshal(buf.Bytes(), &requestCredentials); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 	if requestCredentials.Login == nil || requestCredentials.Password == nil {
-		http.Error(w, "login and password should not be empty", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login and password should not be empty")
 		return
 	}
 
 	// update credentials for user in goph-keeper storage
-	if err := h.db.UpdateCredentials(ctx, requestCredentials); err != nil {
-		message, status := parseUserError(requestCredentials.UserName, err)
-		http.Error(w, message, status)
+	if _, err := h.db.UpdateCredentials(ctx, requestCredentials); err != nil {
+		h.writeUserError(w, r, requestCredentials.UserName, err)
 		return
 	}
 
@@ -274,23 +406,22 @@ func (h *handler) SaveUserNote(w http.ResponseWriter, r *http.Request) {
 	// parse body
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r.Body); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	var requestNote internal.Note
 	if err := json.Unmarshal(buf.Bytes(), &requestNote); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 	if requestNote.Title == nil || requestNote.UserName == "" {
-		http.Error(w, "user name and note title should not be empty", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "user name and note title should not be empty")
 		return
 	}
 
 	// save note for user in goph-keeper storage
-	if err := h.db.SaveNote(ctx, requestNote); err != nil {
-		message, status := parseUserError(requestNote.UserName, err)
-		http.Error(w, message, status)
+	if _, err := h.db.SaveNote(ctx, requestNote); err != nil {
+		h.writeUserError(w, r, requestNote.UserName, err)
 		return
 	}
 
@@ -312,32 +443,31 @@ func (h *handler) GetUserNote(w http.ResponseWriter, r *http.Request) {
 	// parse body to get user's name
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 
 	var userNotesRequest internal.Note
 	if err = json.Unmarshal(body, &userNotesRequest); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 
 	// get user note from goph-keeper storage
 	creds, err := h.db.GetNotes(ctx, userNotesRequest)
 	if err != nil {
-		message, status := parseUserError(userNotesRequest.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, userNotesRequest.UserName, err)
 		return
 	}
 
 	// response
 	notesResponse, err := json.Marshal(creds)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	if _, err = io.WriteString(w, string(notesResponse)); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -354,19 +484,18 @@ func (h *handler) DeleteUserNotes(w http.ResponseWriter, r *http.Request) {
 	// parse body to get user's name
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 
 	var userNotesRequest internal.Note
 	if err := json.Unmarshal(body, &userNotesRequest); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 	// delete notes from goph-keeper storage
 	if err = h.db.DeleteNotes(ctx, userNotesRequest); err != nil {
-		message, status := parseUserError(userNotesRequest.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, userNotesRequest.UserName, err)
 		return
 	}
 
@@ -377,7 +506,7 @@ func (h *handler) DeleteUserNotes(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if _, err = io.WriteString(w, response); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -394,23 +523,22 @@ func (h *handler) UpdateUserNote(w http.ResponseWriter, r *http.Request) {
 	// parse body
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r.Body); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	var requestNote internal.Note
 	if err := json.Unmarshal(buf.Bytes(), &requestNote); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 	if requestNote.Title == nil || requestNote.Content == nil {
-		http.Error(w, "tile and content should not be empty", http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "tile and content should not be empty")
 		return
 	}
 
 	// update note for user in goph-keeper storage
 	if err := h.db.UpdateNote(ctx, requestNote); err != nil {
-		message, status := parseUserError(requestNote.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, requestNote.UserName, err)
 		return
 	}
 
@@ -433,20 +561,19 @@ func (h *handler) SaveCard(w http.ResponseWriter, r *http.Request) {
 	// parse body
 	var buf bytes.Buffer
 	if _, err := buf.ReadFrom(r.Body); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	var requestCard internal.Card
 	if err := json.Unmarshal(buf.Bytes(), &requestCard); err != nil {
 		log.Println(err.Error())
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 
 	// save card in goph-keeper storage
-	if err := h.db.SaveCard(ctx, requestCard); err != nil {
-		message, status := parseUserError(requestCard.UserName, err)
-		http.Error(w, message, status)
+	if _, err := h.db.SaveCard(ctx, requestCard); err != nil {
+		h.writeUserError(w, r, requestCard.UserName, err)
 		return
 	}
 
@@ -468,32 +595,31 @@ func (h *handler) GetCard(w http.ResponseWriter, r *http.Request) {
 	// parse body to get user's name
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 
 	var cardRequest internal.Card
 	if err = json.Unmarshal(body, &cardRequest); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 
 	// get user cards from goph-keeper storage
 	cards, err := h.db.GetCard(ctx, cardRequest)
 	if err != nil {
-		message, status := parseUserError(cardRequest.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, cardRequest.UserName, err)
 		return
 	}
 
 	// response
 	cardsResponse, err := json.Marshal(cards)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	if _, err = io.WriteString(w, string(cardsResponse)); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -510,19 +636,18 @@ func (h *handler) DeleteCard(w http.ResponseWriter, r *http.Request) {
 	// parse body to get user's name
 	body, err := io.ReadAll(r.Body)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 
 	var cardRequest internal.Card
 	if err = json.Unmarshal(body, &cardRequest); err != nil {
-		http.Error(w, err.Error(), http.StatusBadRequest)
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 		return
 	}
 	// delete card from goph-keeper storage
 	if err = h.db.DeleteCards(ctx, cardRequest); err != nil {
-		message, status := parseUserError(cardRequest.UserName, err)
-		http.Error(w, message, status)
+		h.writeUserError(w, r, cardRequest.UserName, err)
 		return
 	}
 
@@ -535,7 +660,7 @@ func (h *handler) DeleteCard(w http.ResponseWriter, r *http.Request) {
 	}
 
 	if _, err = io.WriteString(w, response); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 		return
 	}
 	w.WriteHeader(http.StatusOK)
@@ -547,34 +672,34 @@ func (h *handler) BasicAuth(next http.Handler) http.Handler {
 		// parse body
 		var buf bytes.Buffer
 		if _, err := buf.ReadFrom(r.Body); err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
+			writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
 			return
 		}
 		var user internal.Credentials
 		if err := json.Unmarshal(buf.Bytes(), &user); err != nil {
 			log.Println("BasicAuth")
-			http.Error(w, err.Error(), http.StatusBadRequest)
+			writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
 			return
 		}
 
 		// check cookies
-		if h.cookies[user.UserName] == "" {
-			http.Error(w, fmt.Sprintf("user %q is not authorized", user.UserName), http.StatusUnauthorized)
+		authorization := h.cookies.get(user.UserName)
+		if authorization == "" {
+			writeError(w, r, http.StatusUnauthorized, internal.ErrorCodeUnauthorized, fmt.Sprintf("user %q is not authorized", user.UserName))
 			return
 		}
 
 		// check token
-		tkn, err := extractJwtToken(h.cookies[user.UserName])
+		tkn, err := extractJwtToken(authorization)
 		if err != nil {
-			message, status := parseUserError(user.UserName, err)
-			http.Error(w, message, status)
+			h.writeUserError(w, r, user.UserName, err)
 			return
 		}
 		if !tkn.Valid {
-			http.Error(w, "invalid token", http.StatusUnauthorized)
+			writeError(w, r, http.StatusUnauthorized, internal.ErrorCodeInvalidToken, "invalid token")
 			return
 		}
-		w.Header().Add("Authorization", h.cookies[user.UserName])
+		w.Header().Add("Authorization", authorization)
 		r.Body = io.NopCloser(bytes.NewBuffer(buf.Bytes()))
 		next.ServeHTTP(w, r)
 	})
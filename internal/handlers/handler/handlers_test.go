@@ -8,6 +8,7 @@ import (
 	"github.com/kontik-pk/goph-keeper/internal"
 	"github.com/kontik-pk/goph-keeper/internal/database"
 	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	keeperPassword "github.com/kontik-pk/goph-keeper/internal/password"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"go.uber.org/zap"
@@ -52,6 +53,7 @@ func TestHandler_Login(t *testing.T) {
 		t.Run(tt.name, func(t *testing.T) {
 			mockedStorage := mocks.NewStorage(t)
 			mockedStorage.On("Login", mock.Anything, userName, password).Return(tt.storageResponse)
+			mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData).Maybe()
 
 			r := chi.NewRouter()
 			h := New(mockedStorage, log)
@@ -66,8 +68,8 @@ func TestHandler_Login(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, resp.StatusCode(), tt.expectedCode)
 			if tt.cookies {
-				assert.True(t, len(h.cookies) == 1)
-				assert.True(t, h.cookies[userName] != "")
+				assert.True(t, h.cookies.len() == 1)
+				assert.True(t, h.cookies.get(userName) != "")
 				assert.True(t, len(resp.Header().Get("Authorization")) > 1)
 				assert.True(t, len(resp.Cookies()) == 1)
 			}
@@ -158,8 +160,8 @@ func TestHandler_Register(t *testing.T) {
 			assert.NoError(t, err)
 			assert.Equal(t, resp.StatusCode(), tt.expectedCode)
 			if tt.cookies {
-				assert.True(t, len(h.cookies) == 1)
-				assert.True(t, h.cookies[userName] != "")
+				assert.True(t, h.cookies.len() == 1)
+				assert.True(t, h.cookies.get(userName) != "")
 				assert.True(t, len(resp.Header().Get("Authorization")) > 1)
 				assert.True(t, len(resp.Cookies()) == 1)
 			}
@@ -167,6 +169,71 @@ func TestHandler_Register(t *testing.T) {
 	}
 }
 
+func TestHandler_RegisterPasswordChecks(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	defer logger.Sync() // flushes buffer, if any
+	log := logger.Sugar()
+
+	userName := "joffrey"
+	policy := keeperPassword.Policy{MinLength: keeperPassword.DefaultMinLength, MinScore: keeperPassword.DefaultMinScore}
+
+	testCases := []struct {
+		name         string
+		password     string
+		source       keeperPassword.RangeSource
+		callStorage  bool
+		expectedCode int
+	}{
+		{
+			name:         "positive: strong password",
+			password:     "lions of casterly rock",
+			source:       keeperPassword.NewFileSource("../../password/testdata/breached.txt"),
+			callStorage:  true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "negative: weak password",
+			password:     "joffrey1",
+			source:       keeperPassword.NewFileSource("../../password/testdata/breached.txt"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "negative: breached password",
+			password:     "iloveyou",
+			source:       keeperPassword.NewFileSource("../../password/testdata/breached.txt"),
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "positive: breached passwords source is unavailable",
+			password:     "lions of casterly rock",
+			source:       keeperPassword.NewFileSource("missing.txt"),
+			callStorage:  true,
+			expectedCode: http.StatusOK,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockedStorage := mocks.NewStorage(t)
+			if tt.callStorage {
+				mockedStorage.On("Register", mock.Anything, userName, tt.password).Return(nil)
+			}
+
+			r := chi.NewRouter()
+			h := New(mockedStorage, log, WithPasswordPolicy(policy), WithBreachChecker(keeperPassword.NewBreachChecker(tt.source)))
+			r.Post("/auth/register", h.Register)
+			srv := httptest.NewServer(r)
+			defer srv.Close()
+
+			resp, err := resty.New().R().
+				SetHeader("content-type", "application/json").
+				SetBody(fmt.Sprintf(`{"login": %q, "password": %q}`, userName, tt.password)).
+				Post(fmt.Sprintf("%s/auth/register", srv.URL))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode())
+		})
+	}
+}
+
 func TestHandler_GetUserCredentials(t *testing.T) {
 	logger, _ := zap.NewProduction()
 	defer logger.Sync() // flushes buffer, if any
@@ -200,7 +267,8 @@ func TestHandler_GetUserCredentials(t *testing.T) {
 			name:                 "negative: no data for user",
 			storageResponse:      []internal.Credentials{},
 			storageResponseError: database.ErrNoData,
-			expectedCode:         http.StatusNoContent,
+			expectedCode:         http.StatusNotFound,
+			expectedBody:         `{"error":{"code":"item_not_found","message":"no data for user \"margaery\""}}`,
 		},
 	}
 	for _, tt := range testCases {
@@ -326,14 +394,14 @@ func TestHandler_SaveUserCredentials(t *testing.T) {
 			name:                 "negative: saving error",
 			expectedCode:         http.StatusInternalServerError,
 			storageResponseError: errors.New("save error"),
-			expectedBody:         `user "shae" request error : save error`,
+			expectedBody:         `{"error":{"code":"internal_error","message":"internal server error"}}`,
 		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockedStorage := mocks.NewStorage(t)
 			mockedStorage.On("Register", mock.Anything, systemName, systemPassword).Return(nil)
-			mockedStorage.On("SaveCredentials", mock.Anything, internal.Credentials{UserName: systemName, Login: &loginName, Password: &password, Metadata: &metadata}).Return(tt.storageResponseError)
+			mockedStorage.On("SaveCredentials", mock.Anything, internal.Credentials{UserName: systemName, Login: &loginName, Password: &password, Metadata: &metadata}).Return(int64(1), tt.storageResponseError)
 
 			r := chi.NewRouter()
 			h := New(mockedStorage, log)
@@ -504,14 +572,14 @@ func TestHandler_UpdateUserCredentials(t *testing.T) {
 			name:                 "negative: updating error",
 			expectedCode:         http.StatusInternalServerError,
 			storageResponseError: errors.New("update error"),
-			expectedBody:         `user "shae" request error : update error`,
+			expectedBody:         `{"error":{"code":"internal_error","message":"internal server error"}}`,
 		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockedStorage := mocks.NewStorage(t)
 			mockedStorage.On("Register", mock.Anything, systemName, systemPassword).Return(nil)
-			mockedStorage.On("UpdateCredentials", mock.Anything, internal.Credentials{UserName: systemName, Login: &loginName, Password: &password, Metadata: &metadata}).Return(tt.storageResponseError)
+			mockedStorage.On("UpdateCredentials", mock.Anything, internal.Credentials{UserName: systemName, Login: &loginName, Password: &password, Metadata: &metadata}).Return(internal.Rotation{}, tt.storageResponseError)
 
 			r := chi.NewRouter()
 			h := New(mockedStorage, log)
@@ -602,14 +670,14 @@ func TestHandler_SaveUserNote(t *testing.T) {
 			name:                 "negative: saving error",
 			expectedCode:         http.StatusInternalServerError,
 			storageResponseError: errors.New("save error"),
-			expectedBody:         `user "hound" request error : save error`,
+			expectedBody:         `{"error":{"code":"internal_error","message":"internal server error"}}`,
 		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockedStorage := mocks.NewStorage(t)
 			mockedStorage.On("Register", mock.Anything, systemName, systemPassword).Return(nil)
-			mockedStorage.On("SaveNote", mock.Anything, internal.Note{UserName: systemName, Title: &title, Content: &content, Metadata: &metadata}).Return(tt.storageResponseError)
+			mockedStorage.On("SaveNote", mock.Anything, internal.Note{UserName: systemName, Title: &title, Content: &content, Metadata: &metadata}).Return(int64(1), tt.storageResponseError)
 
 			r := chi.NewRouter()
 			h := New(mockedStorage, log)
@@ -708,7 +776,8 @@ func TestHandler_GetUserNote(t *testing.T) {
 			name:                 "negative: no data for user",
 			storageResponse:      []internal.Note{},
 			storageResponseError: database.ErrNoData,
-			expectedCode:         http.StatusNoContent,
+			expectedCode:         http.StatusNotFound,
+			expectedBody:         `{"error":{"code":"item_not_found","message":"no data for user \"hound\""}}`,
 		},
 	}
 	for _, tt := range testCases {
@@ -911,7 +980,7 @@ func TestHandler_UpdateUserNote(t *testing.T) {
 			name:                 "negative: updating error",
 			expectedCode:         http.StatusInternalServerError,
 			storageResponseError: errors.New("update error"),
-			expectedBody:         `user "shae" request error : update error`,
+			expectedBody:         `{"error":{"code":"internal_error","message":"internal server error"}}`,
 		},
 	}
 	for _, tt := range testCases {
@@ -1010,14 +1079,14 @@ func TestHandler_SaveCard(t *testing.T) {
 			name:                 "negative: saving error",
 			expectedCode:         http.StatusInternalServerError,
 			storageResponseError: errors.New("save error"),
-			expectedBody:         `user "hound" request error : save error`,
+			expectedBody:         `{"error":{"code":"internal_error","message":"internal server error"}}`,
 		},
 	}
 	for _, tt := range testCases {
 		t.Run(tt.name, func(t *testing.T) {
 			mockedStorage := mocks.NewStorage(t)
 			mockedStorage.On("Register", mock.Anything, systemName, systemPassword).Return(nil)
-			mockedStorage.On("SaveCard", mock.Anything, internal.Card{UserName: systemName, BankName: &bankName, Number: &number, CV: &cv, Password: &password, Metadata: &metadata}).Return(tt.storageResponseError)
+			mockedStorage.On("SaveCard", mock.Anything, internal.Card{UserName: systemName, BankName: &bankName, Number: &number, CV: &cv, Password: &password, Metadata: &metadata}).Return(int64(1), tt.storageResponseError)
 
 			r := chi.NewRouter()
 			h := New(mockedStorage, log)
@@ -1089,7 +1158,8 @@ func TestHandler_GetCard(t *testing.T) {
 			name:                 "negative: no data for user",
 			storageResponse:      []internal.Card{},
 			storageResponseError: database.ErrNoData,
-			expectedCode:         http.StatusNoContent,
+			expectedCode:         http.StatusNotFound,
+			expectedBody:         `{"error":{"code":"item_not_found","message":"no data for user \"hound\""}}`,
 		},
 	}
 	for _, tt := range testCases {
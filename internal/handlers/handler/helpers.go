@@ -5,9 +5,12 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/go-chi/chi/middleware"
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/kontik-pk/goph-keeper/internal"
 	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
 	"io"
 	"net/http"
 	"strings"
@@ -61,24 +64,148 @@ func createToken(userName string, expirationTime time.Time) (string, error) {
 	return tokenString, nil
 }
 
-func parseUserError(userName string, err error) (string, int) {
-	if errors.Is(err, database.ErrNoSuchUser) {
-		return fmt.Sprintf("no such user %q", userName), http.StatusUnauthorized
+// createChallengeToken creates a token which confirms that user has passed the password check
+// and only needs to provide the second factor.
+func createChallengeToken(userName string, expirationTime time.Time) (string, error) {
+	claims := &internal.Claims{
+		Username: userName,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   challengeSubject,
+			ExpiresAt: jwt.NewNumericDate(expirationTime),
+		},
 	}
-	if errors.Is(err, database.ErrInvalidCredentials) {
-		return fmt.Sprintf("provided password is wrong for user %q", userName), http.StatusUnauthorized
+	return jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString(jwtKey)
+}
+
+// parseChallengeToken validates challenge token and returns the name of the user it was issued for.
+func parseChallengeToken(challenge string) (string, error) {
+	claims := &internal.Claims{}
+	tkn, err := jwt.ParseWithClaims(challenge, claims, func(token *jwt.Token) (interface{}, error) {
+		return jwtKey, nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if !tkn.Valid || claims.Subject != challengeSubject {
+		return "", ErrInvalidChallenge
+	}
+	return claims.Username, nil
+}
+
+// parseUserError maps the error to the error from the catalogue and its HTTP status.
+// Unexpected errors are not exposed to the client, they should be logged by the caller.
+func parseUserError(userName string, err error) (internal.Error, int) {
+	// unknown users and wrong passwords are indistinguishable to not let anyone enumerate accounts
+	if errors.Is(err, database.ErrNoSuchUser) || errors.Is(err, database.ErrInvalidCredentials) {
+		return internal.Error{Code: internal.ErrorCodeInvalidCredentials, Message: "invalid login or password"}, http.StatusUnauthorized
+	}
+	if errors.Is(err, ErrInvalidRecoveryKey) {
+		return internal.Error{Code: internal.ErrorCodeInvalidRecoveryKey, Message: err.Error()}, http.StatusUnauthorized
+	}
+	if errors.Is(err, password.ErrTooShort) ||
+		errors.Is(err, password.ErrTooLong) ||
+		errors.Is(err, password.ErrTooWeak) ||
+		errors.Is(err, password.ErrBreached) {
+		return internal.Error{Code: internal.ErrorCodeWeakPassword, Message: err.Error()}, http.StatusBadRequest
 	}
 	if errors.Is(err, database.ErrUserAlreadyExists) {
-		return fmt.Sprintf("login %q is already taken", userName), http.StatusConflict
+		return internal.Error{Code: internal.ErrorCodeUserAlreadyExists, Message: fmt.Sprintf("login %q is already taken", userName)}, http.StatusConflict
+	}
+	if errors.Is(err, database.ErrItemAlreadyExists) {
+		return internal.Error{Code: internal.ErrorCodeDuplicateItem, Message: fmt.Sprintf("item already exists for user %q", userName)}, http.StatusConflict
+	}
+	if errors.Is(err, database.ErrTwoFactorEnabled) {
+		return internal.Error{
+			Code:    internal.ErrorCodeTwoFactorEnabled,
+			Message: fmt.Sprintf("two-factor authentication is already enabled for user %q", userName),
+		}, http.StatusConflict
+	}
+	if errors.Is(err, ErrInvalidOTP) || errors.Is(err, database.ErrInvalidRecoveryCode) {
+		return internal.Error{
+			Code:    internal.ErrorCodeInvalidSecondFactor,
+			Message: fmt.Sprintf("invalid second factor for user %q: %s", userName, err.Error()),
+		}, http.StatusUnauthorized
+	}
+	if errors.Is(err, database.ErrKeyAlreadyExists) {
+		return internal.Error{Code: internal.ErrorCodeKeyAlreadyExists, Message: fmt.Sprintf("key is already registered for user %q", userName)}, http.StatusConflict
+	}
+	if errors.Is(err, ErrUnknownKey) ||
+		errors.Is(err, webauthn.ErrInvalidAssertion) ||
+		errors.Is(err, webauthn.ErrInvalidSignature) ||
+		errors.Is(err, webauthn.ErrUnsupportedKey) ||
+		errors.Is(err, webauthn.ErrSignCountDecreased) {
+		return internal.Error{
+			Code:    internal.ErrorCodePublicKeyLoginFailed,
+			Message: fmt.Sprintf("public key login failed for user %q: %s", userName, err.Error()),
+		}, http.StatusUnauthorized
+	}
+	if errors.Is(err, database.ErrInvalidCursor) ||
+		errors.Is(err, database.ErrInvalidSort) ||
+		errors.Is(err, database.ErrInvalidItemType) ||
+		errors.Is(err, database.ErrFolderCycle) {
+		return internal.Error{Code: internal.ErrorCodeInvalidRequest, Message: err.Error()}, http.StatusBadRequest
 	}
 	if errors.Is(err, database.ErrNoData) {
-		return fmt.Sprintf("no data for user %q", userName), http.StatusNoContent
+		return internal.Error{Code: internal.ErrorCodeItemNotFound, Message: fmt.Sprintf("no data for user %q", userName)}, http.StatusNotFound
+	}
+	if errors.Is(err, jwt.ErrTokenExpired) {
+		return internal.Error{Code: internal.ErrorCodeTokenExpired, Message: "token has expired, login again"}, http.StatusUnauthorized
+	}
+	if errors.Is(err, ErrSessionRevoked) {
+		return internal.Error{Code: internal.ErrorCodeSessionRevoked, Message: "session is no longer valid, login again"}, http.StatusUnauthorized
 	}
 	if errors.Is(err, jwt.ErrSignatureInvalid) ||
-		errors.Is(err, jwt.ErrTokenExpired) ||
+		errors.Is(err, jwt.ErrTokenMalformed) ||
 		errors.Is(err, ErrTokenIsEmpty) ||
-		errors.Is(err, ErrNoToken) {
-		return fmt.Sprintf("token problem for user %q: %s", userName, err.Error()), http.StatusUnauthorized
+		errors.Is(err, ErrNoToken) ||
+		errors.Is(err, ErrInvalidToken) ||
+		errors.Is(err, ErrInvalidChallenge) {
+		return internal.Error{
+			Code:    internal.ErrorCodeInvalidToken,
+			Message: fmt.Sprintf("token problem for user %q: %s", userName, err.Error()),
+		}, http.StatusUnauthorized
+	}
+	return internal.Error{Code: internal.ErrorCodeInternal, Message: "internal server error"}, http.StatusInternalServerError
+}
+
+// writeUserError writes the error from the catalogue matching err.
+// Unexpected errors are logged with the request id, so they can be found by the id from the response.
+func (h *handler) writeUserError(w http.ResponseWriter, r *http.Request, userName string, err error) {
+	apiErr, status := parseUserError(userName, err)
+	if status == http.StatusInternalServerError {
+		h.log.Errorf("request %s of user %q failed: %s", middleware.GetReqID(r.Context()), userName, err)
+	}
+	writeErrorResponse(w, r, status, apiErr)
+}
+
+// writeError writes the error with provided status, code from the catalogue and message.
+func writeError(w http.ResponseWriter, r *http.Request, status int, code string, message string) {
+	writeErrorResponse(w, r, status, internal.Error{Code: code, Message: message})
+}
+
+// writeErrorResponse writes the error wrapped in the envelope, the request id is added to it.
+func writeErrorResponse(w http.ResponseWriter, r *http.Request, status int, apiErr internal.Error) {
+	apiErr.RequestID = middleware.GetReqID(r.Context())
+	writeEnvelope(w, status, internal.Envelope{Error: &apiErr})
+}
+
+// writeData writes the data wrapped in the envelope with provided status.
+func writeData(w http.ResponseWriter, status int, data any) {
+	writeEnvelope(w, status, internal.Envelope{Data: data})
+}
+
+// writePage writes the page of the list with the cursor of the next page.
+func writePage(w http.ResponseWriter, data any, limit int, next string) {
+	writeEnvelope(w, http.StatusOK, internal.Envelope{Data: data, Page: &internal.Page{Limit: limit, NextCursor: next}})
+}
+
+func writeEnvelope(w http.ResponseWriter, status int, envelope internal.Envelope) {
+	response, err := json.Marshal(envelope)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
-	return fmt.Sprintf("user %q request error : %s", userName, err.Error()), http.StatusInternalServerError
+	w.Header().Set("content-type", "application/json")
+	w.WriteHeader(status)
+	_, _ = w.Write(response)
 }
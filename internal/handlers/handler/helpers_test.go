@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestParseUserError(t *testing.T) {
+	testCases := []struct {
+		err            error
+		expectedCode   string
+		expectedStatus int
+	}{
+		{err: database.ErrNoSuchUser, expectedCode: internal.ErrorCodeInvalidCredentials, expectedStatus: http.StatusUnauthorized},
+		{err: database.ErrInvalidCredentials, expectedCode: internal.ErrorCodeInvalidCredentials, expectedStatus: http.StatusUnauthorized},
+		{err: ErrInvalidRecoveryKey, expectedCode: internal.ErrorCodeInvalidRecoveryKey, expectedStatus: http.StatusUnauthorized},
+		{err: fmt.Errorf("%w: 5 characters", password.ErrTooShort), expectedCode: internal.ErrorCodeWeakPassword, expectedStatus: http.StatusBadRequest},
+		{err: password.ErrBreached, expectedCode: internal.ErrorCodeWeakPassword, expectedStatus: http.StatusBadRequest},
+		{err: database.ErrUserAlreadyExists, expectedCode: internal.ErrorCodeUserAlreadyExists, expectedStatus: http.StatusConflict},
+		{err: database.ErrItemAlreadyExists, expectedCode: internal.ErrorCodeDuplicateItem, expectedStatus: http.StatusConflict},
+		{err: database.ErrTwoFactorEnabled, expectedCode: internal.ErrorCodeTwoFactorEnabled, expectedStatus: http.StatusConflict},
+		{err: ErrInvalidOTP, expectedCode: internal.ErrorCodeInvalidSecondFactor, expectedStatus: http.StatusUnauthorized},
+		{err: database.ErrKeyAlreadyExists, expectedCode: internal.ErrorCodeKeyAlreadyExists, expectedStatus: http.StatusConflict},
+		{err: webauthn.ErrInvalidSignature, expectedCode: internal.ErrorCodePublicKeyLoginFailed, expectedStatus: http.StatusUnauthorized},
+		{err: database.ErrNoData, expectedCode: internal.ErrorCodeItemNotFound, expectedStatus: http.StatusNotFound},
+		{err: fmt.Errorf("token is invalid: %w", jwt.ErrTokenExpired), expectedCode: internal.ErrorCodeTokenExpired, expectedStatus: http.StatusUnauthorized},
+		{err: ErrNoToken, expectedCode: internal.ErrorCodeInvalidToken, expectedStatus: http.StatusUnauthorized},
+		{err: errors.New("connection refused"), expectedCode: internal.ErrorCodeInternal, expectedStatus: http.StatusInternalServerError},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.err.Error(), func(t *testing.T) {
+			apiErr, status := parseUserError("varys", tt.err)
+			assert.Equal(t, tt.expectedCode, apiErr.Code)
+			assert.Equal(t, tt.expectedStatus, status)
+			assert.NotEmpty(t, apiErr.Message)
+		})
+	}
+}
+
+func TestHandler_writeUserError(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	h := New(mocks.NewStorage(t), logger.Sugar())
+
+	r := chi.NewRouter()
+	r.Use(middleware.RequestID)
+	r.Get("/", func(w http.ResponseWriter, r *http.Request) {
+		h.writeUserError(w, r, "varys", errors.New("connection refused"))
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL)
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusInternalServerError, resp.StatusCode)
+	assert.Equal(t, "application/json", resp.Header.Get("content-type"))
+
+	var envelope internal.Envelope
+	assert.NoError(t, json.NewDecoder(resp.Body).Decode(&envelope))
+	assert.Nil(t, envelope.Data)
+	assert.Equal(t, internal.ErrorCodeInternal, envelope.Error.Code)
+	// internal details are logged, not returned
+	assert.Equal(t, "internal server error", envelope.Error.Message)
+	assert.NotEmpty(t, envelope.Error.RequestID)
+}
@@ -0,0 +1,234 @@
+package handler
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	keyChallengeTTL = time.Minute
+	// maxKeyChallenges limits the challenges waiting for the assertion, the endpoint issuing them is public.
+	maxKeyChallenges = 10000
+)
+
+// keyChallenge is a challenge issued for public key login, each challenge can be used only once.
+type keyChallenge struct {
+	login   string
+	expires time.Time
+}
+
+// RegisterKey is a method for registering public key of the user authorized by TokenAuth which can be used for login
+// instead of password. The body of the HTTP request must contain the current `password` of the user, key id,
+// base64 encoded public key and its algorithm, the key is stored only after the password is checked again.
+// For example:
+// curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/auth/key/register --data `{"password": "some_password", "key_id": "some_id", "public_key": "base64 key", "algorithm": "EdDSA"}`
+func (h *handler) RegisterKey(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var registration internal.KeyRegistration
+	if err = json.Unmarshal(body, &registration); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	// the key always belongs to the user of the token
+	key := registration.PublicKey
+	key.UserName = userName(r)
+	if registration.Password == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "password should not be empty")
+		return
+	}
+	if key.KeyID == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "key id should not be empty")
+		return
+	}
+	if key.Algorithm != webauthn.AlgorithmEdDSA {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, fmt.Sprintf("%s: %q", webauthn.ErrUnsupportedKey, key.Algorithm))
+		return
+	}
+	if decoded, err := base64.StdEncoding.DecodeString(key.PublicKey); err != nil || len(decoded) != ed25519.PublicKeySize {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "public key should be a base64 encoded Ed25519 key")
+		return
+	}
+
+	if !h.reauthenticate(ctx, w, r, internal.AccountRequest{
+		Login:    key.UserName,
+		Password: registration.Password,
+		Code:     registration.Code,
+	}) {
+		return
+	}
+
+	// save key in goph-keeper storage
+	if err = h.db.SavePublicKey(ctx, key); err != nil {
+		h.writeUserError(w, r, key.UserName, err)
+		return
+	}
+
+	// response
+	if _, err = io.WriteString(w, fmt.Sprintf("registered public key for user %q", key.UserName)); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+}
+
+// KeyChallenge is a method for getting a challenge which should be signed by the user's registered key.
+// The body of the HTTP request must contain `login`.
+// For example: curl -X POST http://127.0.0.1:8080/auth/key/challenge --data `{"login": "user_login"}`
+func (h *handler) KeyChallenge(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	// parse body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var keyLogin internal.KeyLogin
+	if err = json.Unmarshal(body, &keyLogin); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if keyLogin.Login == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login should not be empty")
+		return
+	}
+
+	// issue challenge, it is not bound to existing keys to not reveal registered users
+	challenge, err := webauthn.NewChallenge()
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	h.mu.Lock()
+	now := time.Now()
+	h.sweepChallenges(now)
+	if len(h.challenges) >= maxKeyChallenges {
+		h.mu.Unlock()
+		seconds := int(keyChallengeTTL.Seconds())
+		w.Header().Set("Retry-After", strconv.Itoa(seconds))
+		writeErrorResponse(w, r, http.StatusTooManyRequests, tooManyRequests(seconds))
+		return
+	}
+	h.challenges[challenge] = keyChallenge{login: keyLogin.Login, expires: now.Add(keyChallengeTTL)}
+	h.mu.Unlock()
+
+	// response
+	challengeResponse, err := json.Marshal(internal.LoginChallenge{Challenge: challenge})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if _, err = w.Write(challengeResponse); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+}
+
+// KeyLogin is a method for login with the assertion signed by the user's registered key.
+// The body of the HTTP request must contain `login` and the `assertion` for the challenge issued by KeyChallenge.
+// Failed attempts are throttled as password logins. The key replaces the password only, so users with enabled
+// two-factor authentication get 202 status and a challenge for LoginTwoFactor as after the password check.
+// For example: curl -X POST http://127.0.0.1:8080/auth/key/login --data `{"login": "user_login", "assertion": {...}}`
+func (h *handler) KeyLogin(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var keyLogin internal.KeyLogin
+	if err = json.Unmarshal(body, &keyLogin); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if keyLogin.Login == "" || keyLogin.Assertion == nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "login and assertion should not be empty")
+		return
+	}
+	address := clientAddress(r)
+	if h.throttled(w, r, keyLogin.Login, address) {
+		return
+	}
+	var clientData webauthn.ClientData
+	if err = json.Unmarshal(keyLogin.Assertion.ClientDataJSON, &clientData); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, fmt.Sprintf("%s: %s", webauthn.ErrInvalidAssertion, err))
+		return
+	}
+
+	// the challenge is spent even if the assertion is invalid
+	h.mu.Lock()
+	now := time.Now()
+	issued, ok := h.challenges[clientData.Challenge]
+	delete(h.challenges, clientData.Challenge)
+	h.sweepChallenges(now)
+	h.mu.Unlock()
+	if !ok || issued.login != keyLogin.Login || now.After(issued.expires) {
+		h.loginFailed(keyLogin.Login, address)
+		h.writeUserError(w, r, keyLogin.Login, ErrInvalidChallenge)
+		return
+	}
+
+	// verify assertion with the registered key
+	key, err := h.db.GetPublicKey(ctx, keyLogin.Login, keyLogin.Assertion.KeyID)
+	if errors.Is(err, database.ErrNoData) {
+		h.loginFailed(keyLogin.Login, address)
+		err = ErrUnknownKey
+	}
+	if err != nil {
+		h.writeUserError(w, r, keyLogin.Login, err)
+		return
+	}
+	signCount, err := webauthn.Verify(*keyLogin.Assertion, clientData.Challenge, key.Algorithm, key.PublicKey, key.SignCount)
+	if err != nil {
+		h.loginFailed(keyLogin.Login, address)
+		h.writeUserError(w, r, keyLogin.Login, err)
+		return
+	}
+	if err = h.db.UpdateSignCount(ctx, keyLogin.Login, key.KeyID, signCount); err != nil {
+		h.writeUserError(w, r, keyLogin.Login, err)
+		return
+	}
+	// the key replaces the password, not the second factor
+	if h.challengeTwoFactor(ctx, w, r, keyLogin.Login) {
+		return
+	}
+
+	// create jwt token for user, add Authorization header and remember cookie
+	if err = h.authorize(w, keyLogin.Login); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
+		return
+	}
+	h.accountThrottle.reset(keyLogin.Login)
+	w.WriteHeader(http.StatusOK)
+	h.log.Infof("user %q was successfully logined with public key", keyLogin.Login)
+}
+
+// sweepChallenges removes the expired challenges which were never used, it's called with the lock held.
+func (h *handler) sweepChallenges(now time.Time) {
+	for c, issued := range h.challenges {
+		if now.After(issued.expires) {
+			delete(h.challenges, c)
+		}
+	}
+}
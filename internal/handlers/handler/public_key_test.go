@@ -0,0 +1,347 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func newPublicKeyServer(mockedStorage *mocks.Storage) (*handler, *httptest.Server) {
+	logger, _ := zap.NewProduction()
+	h := New(mockedStorage, logger.Sugar())
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Post("/auth/register", h.Register)
+		r.Post("/auth/key/challenge", h.KeyChallenge)
+		r.Post("/auth/key/login", h.KeyLogin)
+		r.With(h.TokenAuth).Post("/auth/key/register", h.RegisterKey)
+	})
+	return h, httptest.NewServer(r)
+}
+
+func TestHandler_RegisterKey(t *testing.T) {
+	userName := "sandor"
+	password := "ihatefire"
+	authenticator, err := webauthn.Load(filepath.Join(t.TempDir(), "authenticator.json"))
+	assert.NoError(t, err)
+	credential, err := authenticator.Create(userName)
+	assert.NoError(t, err)
+	key := internal.PublicKey{
+		UserName:  userName,
+		KeyID:     credential.KeyID,
+		PublicKey: credential.PublicKey(),
+		Algorithm: webauthn.AlgorithmEdDSA,
+	}
+
+	testCases := []struct {
+		name         string
+		registration internal.KeyRegistration
+		loginError   error
+		storageError error
+		callLogin    bool
+		callStorage  bool
+		token        bool
+		expectedCode int
+	}{
+		{
+			name:         "positive: key registered",
+			registration: internal.KeyRegistration{PublicKey: key, Password: password},
+			callLogin:    true,
+			callStorage:  true,
+			token:        true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name: "positive: user name from body is ignored",
+			registration: internal.KeyRegistration{
+				PublicKey: internal.PublicKey{UserName: "hound", KeyID: key.KeyID, PublicKey: key.PublicKey, Algorithm: key.Algorithm},
+				Password:  password,
+			},
+			callLogin:    true,
+			callStorage:  true,
+			token:        true,
+			expectedCode: http.StatusOK,
+		},
+		{
+			name:         "negative: key exists",
+			registration: internal.KeyRegistration{PublicKey: key, Password: password},
+			callLogin:    true,
+			callStorage:  true,
+			token:        true,
+			storageError: database.ErrKeyAlreadyExists,
+			expectedCode: http.StatusConflict,
+		},
+		{
+			name:         "negative: wrong password",
+			registration: internal.KeyRegistration{PublicKey: key, Password: "ilovefire"},
+			callLogin:    true,
+			token:        true,
+			loginError:   database.ErrInvalidCredentials,
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name:         "negative: no password",
+			registration: internal.KeyRegistration{PublicKey: key},
+			token:        true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name:         "negative: no token",
+			registration: internal.KeyRegistration{PublicKey: key, Password: password},
+			expectedCode: http.StatusUnauthorized,
+		},
+		{
+			name: "negative: unsupported algorithm",
+			registration: internal.KeyRegistration{
+				PublicKey: internal.PublicKey{KeyID: key.KeyID, PublicKey: key.PublicKey, Algorithm: "ES256"},
+				Password:  password,
+			},
+			token:        true,
+			expectedCode: http.StatusBadRequest,
+		},
+		{
+			name: "negative: invalid key",
+			registration: internal.KeyRegistration{
+				PublicKey: internal.PublicKey{KeyID: key.KeyID, PublicKey: "c2hvcnQ=", Algorithm: webauthn.AlgorithmEdDSA},
+				Password:  password,
+			},
+			token:        true,
+			expectedCode: http.StatusBadRequest,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			mockedStorage := mocks.NewStorage(t)
+			mockedStorage.On("Register", mock.Anything, userName, password).Return(nil)
+			if tt.callLogin {
+				mockedStorage.On("Login", mock.Anything, userName, tt.registration.Password).Return(tt.loginError)
+			}
+			if tt.callLogin && tt.loginError == nil {
+				mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+			}
+			if tt.callStorage {
+				mockedStorage.On("SavePublicKey", mock.Anything, key).Return(tt.storageError)
+			}
+			h, srv := newPublicKeyServer(mockedStorage)
+			defer srv.Close()
+
+			_, err := resty.New().R().
+				SetHeader("content-type", "application/json").
+				SetBody(fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password)).
+				Post(fmt.Sprintf("%s/auth/register", srv.URL))
+			assert.NoError(t, err)
+
+			request := resty.New().R().
+				SetHeader("content-type", "application/json").
+				SetBody(tt.registration)
+			if tt.token {
+				request.SetHeader("Authorization", h.cookies.get(userName))
+			}
+			resp, err := request.Post(fmt.Sprintf("%s/auth/key/register", srv.URL))
+			assert.NoError(t, err)
+			assert.Equal(t, tt.expectedCode, resp.StatusCode())
+		})
+	}
+}
+
+func TestHandler_KeyLogin(t *testing.T) {
+	userName := "sandor"
+	authenticator, err := webauthn.Load(filepath.Join(t.TempDir(), "authenticator.json"))
+	assert.NoError(t, err)
+	credential, err := authenticator.Create(userName)
+	assert.NoError(t, err)
+	storedKey := internal.PublicKey{
+		UserName:  userName,
+		KeyID:     credential.KeyID,
+		PublicKey: credential.PublicKey(),
+		Algorithm: webauthn.AlgorithmEdDSA,
+	}
+
+	getChallenge := func(t *testing.T, srv *httptest.Server, login string) string {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q}`, login)).
+			Post(fmt.Sprintf("%s/auth/key/challenge", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		var challenge internal.LoginChallenge
+		assert.NoError(t, json.Unmarshal(resp.Body(), &challenge))
+		return challenge.Challenge
+	}
+	keyLogin := func(t *testing.T, srv *httptest.Server, assertion webauthn.Assertion) *resty.Response {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(internal.KeyLogin{Login: userName, Assertion: &assertion}).
+			Post(fmt.Sprintf("%s/auth/key/login", srv.URL))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("positive: login with key", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPublicKey", mock.Anything, userName, credential.KeyID).Return(storedKey, nil)
+		mockedStorage.On("UpdateSignCount", mock.Anything, userName, credential.KeyID, mock.AnythingOfType("uint32")).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		h, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+		h.accountThrottle.fail(userName)
+
+		assertion, err := authenticator.Sign(userName, getChallenge(t, srv, userName))
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.NotEmpty(t, resp.Header().Get("Authorization"))
+		assert.NotEmpty(t, h.cookies.get(userName))
+		// the successful login resets the failed attempts
+		assert.NotContains(t, h.accountThrottle.attempts, userName)
+
+		// the challenge can't be replayed
+		resp = keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("positive: second factor required after key", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPublicKey", mock.Anything, userName, credential.KeyID).Return(storedKey, nil)
+		mockedStorage.On("UpdateSignCount", mock.Anything, userName, credential.KeyID, mock.AnythingOfType("uint32")).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Enabled: true}, nil)
+		h, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		assertion, err := authenticator.Sign(userName, getChallenge(t, srv, userName))
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode())
+		assert.Empty(t, resp.Header().Get("Authorization"))
+		assert.Empty(t, h.cookies.get(userName))
+		var challenge internal.LoginChallenge
+		assert.NoError(t, json.Unmarshal(resp.Body(), &challenge))
+		assert.NotEmpty(t, challenge.Challenge)
+	})
+	t.Run("negative: failed attempts throttled", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPublicKey", mock.Anything, userName, credential.KeyID).Return(internal.PublicKey{}, database.ErrNoData)
+		_, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		for i := 0; i <= freeLoginAttempts; i++ {
+			assertion, err := authenticator.Sign(userName, getChallenge(t, srv, userName))
+			assert.NoError(t, err)
+			assert.Equal(t, http.StatusUnauthorized, keyLogin(t, srv, assertion).StatusCode())
+		}
+		assertion, err := authenticator.Sign(userName, getChallenge(t, srv, userName))
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+		assert.NotEmpty(t, resp.Header().Get("Retry-After"))
+	})
+	t.Run("negative: challenge issued for other user", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		assertion, err := authenticator.Sign(userName, getChallenge(t, srv, "gregor"))
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: unknown challenge", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		challenge, err := webauthn.NewChallenge()
+		assert.NoError(t, err)
+		assertion, err := authenticator.Sign(userName, challenge)
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("negative: unknown key", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPublicKey", mock.Anything, userName, credential.KeyID).Return(internal.PublicKey{}, database.ErrNoData)
+		_, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		assertion, err := authenticator.Sign(userName, getChallenge(t, srv, userName))
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("negative: signed by other key", func(t *testing.T) {
+		other, err := webauthn.Load(filepath.Join(t.TempDir(), "other.json"))
+		assert.NoError(t, err)
+		_, err = other.Create(userName)
+		assert.NoError(t, err)
+
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPublicKey", mock.Anything, userName, mock.AnythingOfType("string")).Return(storedKey, nil)
+		h, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		assertion, err := other.Sign(userName, getChallenge(t, srv, userName))
+		assert.NoError(t, err)
+		resp := keyLogin(t, srv, assertion)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: no assertion", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q}`, userName)).
+			Post(fmt.Sprintf("%s/auth/key/login", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+	t.Run("positive: expired challenges are swept", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		h.mu.Lock()
+		h.challenges["stale"] = keyChallenge{login: userName, expires: time.Now().Add(-time.Second)}
+		h.mu.Unlock()
+		challenge := getChallenge(t, srv, userName)
+
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		assert.Len(t, h.challenges, 1)
+		assert.Contains(t, h.challenges, challenge)
+	})
+	t.Run("negative: too many challenges", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, srv := newPublicKeyServer(mockedStorage)
+		defer srv.Close()
+
+		h.mu.Lock()
+		for i := 0; i < maxKeyChallenges; i++ {
+			h.challenges[fmt.Sprint(i)] = keyChallenge{login: userName, expires: time.Now().Add(keyChallengeTTL)}
+		}
+		h.mu.Unlock()
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q}`, userName)).
+			Post(fmt.Sprintf("%s/auth/key/challenge", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+		assert.Equal(t, "60", resp.Header().Get("Retry-After"))
+	})
+}
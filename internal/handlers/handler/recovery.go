@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/vault"
+	"io"
+	"net/http"
+)
+
+// GetRecoveryKey is a method for getting the vault key wrapped with the recovery key.
+// The body of the HTTP request must contain `login` and `recovery_auth` derived from the recovery key on the client,
+// the recovery key itself is never sent. Failed attempts are counted like failed logins.
+// For example: curl -X POST http://127.0.0.1:8080/auth/recover/key --data `{"login": "user_login", "recovery_auth": "base64 value"}`
+func (h *handler) GetRecoveryKey(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	request, err := parseRecoveryRequest(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	key, ok := h.checkRecoveryAuth(ctx, w, r, request)
+	if !ok {
+		return
+	}
+
+	// response
+	keyResponse, err := json.Marshal(internal.VaultKey{RecoveryWrappedKey: key.RecoveryWrappedKey})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if _, err = w.Write(keyResponse); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+}
+
+// Recover is a method for setting a new password for the user who has forgotten the old one.
+// The body of the HTTP request must contain `login`, `recovery_auth` derived from the recovery key, `new_password`
+// and the `vault` key unwrapped with the recovery key and re-wrapped with the new password on the client.
+// Sessions opened before are invalidated, the response contains a new token for the user.
+// For example: curl -X POST http://127.0.0.1:8080/auth/recover --data `{"login": "user_login", "recovery_auth": "base64 value", "new_password": "new", "vault": {...}}`
+func (h *handler) Recover(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	request, err := parseRecoveryRequest(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if request.NewPassword == "" || request.Vault == nil || !validVaultKey(request.Vault, false) {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "new password and vault key wrapped with it should not be empty")
+		return
+	}
+	if _, ok := h.checkRecoveryAuth(ctx, w, r, request); !ok {
+		return
+	}
+	if err = h.checkPassword(ctx, &internal.User{Login: request.Login, Password: request.NewPassword}); err != nil {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+
+	// replace password hash and the password wrapping of the vault key
+	if err = h.db.ChangePassword(ctx, request.Login, request.NewPassword, request.Vault); err != nil {
+		h.writeUserError(w, r, request.Login, err)
+		return
+	}
+
+	// the new token replaces the remembered one, so other sessions are no longer valid
+	if err = h.authorize(w, request.Login); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+	h.log.Infof("password of user %q was reset with the recovery key", request.Login)
+}
+
+// checkRecoveryAuth compares the recovery auth value with the stored verifier and returns the user's vault key.
+// Unknown users and users without the vault key get the same response as for the wrong value.
+func (h *handler) checkRecoveryAuth(ctx context.Context, w http.ResponseWriter, r *http.Request, request internal.RecoveryRequest) (internal.VaultKey, bool) {
+	address := clientAddress(r)
+	if h.throttled(w, r, request.Login, address) {
+		return internal.VaultKey{}, false
+	}
+	key, err := h.db.GetVaultKey(ctx, request.Login)
+	if err != nil && !errors.Is(err, database.ErrNoData) {
+		h.writeUserError(w, r, request.Login, err)
+		return internal.VaultKey{}, false
+	}
+	verifier := vault.Verifier(request.RecoveryAuth)
+	if err != nil || subtle.ConstantTimeCompare([]byte(verifier), []byte(key.RecoveryVerifier)) != 1 {
+		h.loginFailed(request.Login, address)
+		h.writeUserError(w, r, request.Login, ErrInvalidRecoveryKey)
+		return internal.VaultKey{}, false
+	}
+	h.accountThrottle.reset(request.Login)
+	return key, true
+}
+
+// validVaultKey checks that the vault key is wrapped with the password and, if required, with the recovery key.
+func validVaultKey(key *internal.VaultKey, withRecovery bool) bool {
+	if key.PasswordSalt == "" || key.PasswordWrappedKey == "" {
+		return false
+	}
+	return !withRecovery || key.RecoveryWrappedKey != "" && key.RecoveryVerifier != ""
+}
+
+func parseRecoveryRequest(r io.Reader) (internal.RecoveryRequest, error) {
+	var request internal.RecoveryRequest
+	body, err := io.ReadAll(r)
+	if err != nil {
+		return request, fmt.Errorf("error while reading request body: %w", err)
+	}
+	if err = json.Unmarshal(body, &request); err != nil {
+		return request, fmt.Errorf("error while unmarshalling request body: %w", err)
+	}
+	if request.Login == "" || request.RecoveryAuth == "" {
+		return request, fmt.Errorf("login or recovery auth is empty")
+	}
+	return request, nil
+}
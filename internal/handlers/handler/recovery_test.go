@@ -0,0 +1,154 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/vault"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func newRecoveryServer(mockedStorage *mocks.Storage) (*handler, *httptest.Server) {
+	logger, _ := zap.NewProduction()
+	h := New(mockedStorage, logger.Sugar())
+
+	r := chi.NewRouter()
+	r.Post("/auth/register", h.Register)
+	r.Post("/auth/recover/key", h.GetRecoveryKey)
+	r.Post("/auth/recover", h.Recover)
+	return h, httptest.NewServer(r)
+}
+
+func TestHandler_RegisterWithVaultKey(t *testing.T) {
+	userName := "arya"
+	password := "valar morghulis"
+	key, _, err := vault.New(password)
+	assert.NoError(t, err)
+
+	register := func(srv *httptest.Server, user internal.User) *resty.Response {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(user).
+			Post(fmt.Sprintf("%s/auth/register", srv.URL))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("positive: vault key saved", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Register", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("SaveVaultKey", mock.Anything, userName, key).Return(nil)
+		_, srv := newRecoveryServer(mockedStorage)
+		defer srv.Close()
+
+		resp := register(srv, internal.User{Login: userName, Password: password, Vault: &key})
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+	})
+	t.Run("negative: user is deleted if vault key isn't saved", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Register", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("SaveVaultKey", mock.Anything, userName, key).Return(errors.New("some db error"))
+		mockedStorage.On("DeleteAccount", mock.Anything, userName).Return(nil)
+		h, srv := newRecoveryServer(mockedStorage)
+		defer srv.Close()
+
+		resp := register(srv, internal.User{Login: userName, Password: password, Vault: &key})
+		assert.Equal(t, http.StatusInternalServerError, resp.StatusCode())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: vault key without recovery wrapping", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newRecoveryServer(mockedStorage)
+		defer srv.Close()
+
+		resp := register(srv, internal.User{Login: userName, Password: password, Vault: &internal.VaultKey{
+			PasswordSalt:       key.PasswordSalt,
+			PasswordWrappedKey: key.PasswordWrappedKey,
+		}})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
+
+func TestHandler_Recover(t *testing.T) {
+	userName := "arya"
+	password := "valar morghulis"
+	newPassword := "a girl has no name"
+	storedKey, recoveryKey, err := vault.New(password)
+	assert.NoError(t, err)
+	auth, err := recoveryKey.Auth()
+	assert.NoError(t, err)
+
+	post := func(srv *httptest.Server, path string, request internal.RecoveryRequest) *resty.Response {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(request).
+			Post(fmt.Sprintf("%s%s", srv.URL, path))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("positive: password reset with recovery key", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetVaultKey", mock.Anything, userName).Return(storedKey, nil)
+		h, srv := newRecoveryServer(mockedStorage)
+		defer srv.Close()
+
+		// the client gets the vault key wrapped with the recovery key and unwraps it locally
+		resp := post(srv, "/auth/recover/key", internal.RecoveryRequest{Login: userName, RecoveryAuth: auth})
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		var wrapped internal.VaultKey
+		assert.NoError(t, json.Unmarshal(resp.Body(), &wrapped))
+		assert.Empty(t, wrapped.PasswordWrappedKey)
+		assert.Empty(t, wrapped.RecoveryVerifier)
+		vaultKey, err := vault.UnwrapWithRecoveryKey(wrapped, recoveryKey)
+		assert.NoError(t, err)
+		rewrapped, err := vault.WrapWithPassword(vaultKey, newPassword)
+		assert.NoError(t, err)
+
+		mockedStorage.On("ChangePassword", mock.Anything, userName, newPassword, &rewrapped).Return(nil)
+		resp = post(srv, "/auth/recover", internal.RecoveryRequest{
+			Login:        userName,
+			RecoveryAuth: auth,
+			NewPassword:  newPassword,
+			Vault:        &rewrapped,
+		})
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.NotEmpty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: wrong recovery key", func(t *testing.T) {
+		other, err := vault.NewRecoveryKey()
+		assert.NoError(t, err)
+		otherAuth, err := other.Auth()
+		assert.NoError(t, err)
+
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetVaultKey", mock.Anything, userName).Return(storedKey, nil)
+		mockedStorage.On("GetVaultKey", mock.Anything, "nobody").Return(internal.VaultKey{}, database.ErrNoData)
+		_, srv := newRecoveryServer(mockedStorage)
+		defer srv.Close()
+
+		wrongKey := post(srv, "/auth/recover/key", internal.RecoveryRequest{Login: userName, RecoveryAuth: otherAuth})
+		unknownUser := post(srv, "/auth/recover/key", internal.RecoveryRequest{Login: "nobody", RecoveryAuth: auth})
+		assert.Equal(t, http.StatusUnauthorized, wrongKey.StatusCode())
+		assert.Equal(t, wrongKey.StatusCode(), unknownUser.StatusCode())
+		assert.Equal(t, wrongKey.String(), unknownUser.String())
+	})
+	t.Run("negative: new password without vault key", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newRecoveryServer(mockedStorage)
+		defer srv.Close()
+
+		resp := post(srv, "/auth/recover", internal.RecoveryRequest{Login: userName, RecoveryAuth: auth, NewPassword: newPassword})
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+}
@@ -0,0 +1,42 @@
+package handler
+
+import "sync"
+
+// sessions keeps the latest authorization value (`Bearer <token>`) of each user. The sessions are shared
+// by the HTTP and gRPC servers, so they are safe for concurrent use.
+type sessions struct {
+	mu     sync.RWMutex
+	tokens map[string]string
+}
+
+func newSessions() *sessions {
+	return &sessions{tokens: make(map[string]string)}
+}
+
+// get returns the authorization value of the user, empty if the user has no session.
+func (s *sessions) get(userName string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.tokens[userName]
+}
+
+// set replaces the session of the user, so the sessions opened before are no longer valid.
+func (s *sessions) set(userName, authorization string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[userName] = authorization
+}
+
+// delete closes the session of the user.
+func (s *sessions) delete(userName string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.tokens, userName)
+}
+
+// len returns the number of the open sessions.
+func (s *sessions) len() int {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return len(s.tokens)
+}
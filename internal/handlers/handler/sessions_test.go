@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// TestHandler_ConcurrentSessions runs logins and authorized requests of several users in parallel,
+// it's meaningful with -race.
+func TestHandler_ConcurrentSessions(t *testing.T) {
+	password := "winter is coming"
+	users := []string{"robb", "sansa", "arya", "bran"}
+	mockedStorage := mocks.NewStorage(t)
+	for _, userName := range users {
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	}
+	logger, _ := zap.NewProduction()
+	h := New(mockedStorage, logger.Sugar())
+	r := chi.NewRouter()
+	r.Post("/auth/login", h.Login)
+	r.With(h.TokenAuth).Get("/api/v2/ping", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	srv := httptest.NewServer(r)
+	defer srv.Close()
+
+	var wg sync.WaitGroup
+	for _, userName := range users {
+		for i := 0; i < 5; i++ {
+			wg.Add(1)
+			go func(userName string) {
+				defer wg.Done()
+				client := resty.New()
+				for j := 0; j < 5; j++ {
+					resp, err := client.R().
+						SetBody(fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password)).
+						Post(fmt.Sprintf("%s/auth/login", srv.URL))
+					if !assert.NoError(t, err) || !assert.Equal(t, http.StatusOK, resp.StatusCode()) {
+						return
+					}
+					// the session may be replaced by a parallel login of the same user
+					resp, err = client.R().
+						SetHeader("Authorization", resp.Header().Get("Authorization")).
+						Get(fmt.Sprintf("%s/api/v2/ping", srv.URL))
+					assert.NoError(t, err)
+					assert.Contains(t, []int{http.StatusOK, http.StatusUnauthorized}, resp.StatusCode())
+				}
+			}(userName)
+		}
+	}
+	wg.Wait()
+	assert.Equal(t, len(users), h.cookies.len())
+}
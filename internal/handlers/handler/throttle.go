@@ -0,0 +1,169 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"math"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// freeLoginAttempts is the number of failed attempts allowed without any delay.
+	freeLoginAttempts = 3
+	// accountLockoutAttempts is the number of failed attempts after which the account is locked.
+	accountLockoutAttempts = 10
+	// ipLockoutAttempts is higher than the account one because many users can share an address.
+	ipLockoutAttempts = 50
+	baseLoginBackoff  = time.Second
+	maxLoginBackoff   = 5 * time.Minute
+	lockoutDuration   = time.Hour
+	// sweepInterval is how often the counters of the keys which are no longer delayed are removed.
+	sweepInterval = time.Minute
+)
+
+type loginAttempts struct {
+	failures     int
+	blockedUntil time.Time
+	lastFailure  time.Time
+}
+
+// loginThrottle counts failed login attempts per key (account or client address).
+// Every failure after the free ones doubles the delay before the next attempt is allowed,
+// and after the lockout threshold the key is blocked for lockoutDuration or until it is unlocked.
+type loginThrottle struct {
+	mu        sync.Mutex
+	attempts  map[string]*loginAttempts
+	lockout   int
+	now       func() time.Time
+	lastSweep time.Time
+}
+
+func newLoginThrottle(lockout int) *loginThrottle {
+	return &loginThrottle{
+		attempts: make(map[string]*loginAttempts),
+		lockout:  lockout,
+		now:      time.Now,
+	}
+}
+
+// retryAfter returns the time left until the next attempt for the key is allowed, zero if it's allowed now.
+func (t *loginThrottle) retryAfter(key string) time.Duration {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	attempts, ok := t.attempts[key]
+	if !ok {
+		return 0
+	}
+	now := t.now()
+	if now.Before(attempts.blockedUntil) {
+		return attempts.blockedUntil.Sub(now)
+	}
+	if attempts.expired(now) {
+		delete(t.attempts, key)
+	}
+	return 0
+}
+
+// expired reports whether the counters can be forgotten: the key is not blocked and has been quiet for the lockout duration.
+func (a *loginAttempts) expired(now time.Time) bool {
+	return !now.Before(a.blockedUntil) && now.Sub(a.lastFailure) > lockoutDuration
+}
+
+// sweep removes the expired counters of all the keys, so the keys which never come back don't stay in memory.
+// It's called with the lock held and does the work at most once in sweepInterval.
+func (t *loginThrottle) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < sweepInterval {
+		return
+	}
+	t.lastSweep = now
+	for key, attempts := range t.attempts {
+		if attempts.expired(now) {
+			delete(t.attempts, key)
+		}
+	}
+}
+
+// fail registers a failed attempt for the key.
+func (t *loginThrottle) fail(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := t.now()
+	t.sweep(now)
+	attempts, ok := t.attempts[key]
+	if !ok {
+		attempts = &loginAttempts{}
+		t.attempts[key] = attempts
+	}
+	attempts.failures++
+	attempts.lastFailure = now
+	switch {
+	case attempts.failures >= t.lockout:
+		attempts.blockedUntil = now.Add(lockoutDuration)
+	case attempts.failures > freeLoginAttempts:
+		backoff := baseLoginBackoff << (attempts.failures - freeLoginAttempts - 1)
+		if backoff > maxLoginBackoff {
+			backoff = maxLoginBackoff
+		}
+		attempts.blockedUntil = now.Add(backoff)
+	}
+}
+
+// reset forgets all failed attempts for the key.
+func (t *loginThrottle) reset(key string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	delete(t.attempts, key)
+}
+
+// throttled responds with 429 status if login attempts for the account or the client address are delayed.
+func (h *handler) throttled(w http.ResponseWriter, r *http.Request, login string, address string) bool {
+	wait := h.loginDelay(login, address)
+	if wait == 0 {
+		return false
+	}
+	seconds := int(math.Ceil(wait.Seconds()))
+	w.Header().Set("Retry-After", strconv.Itoa(seconds))
+	writeErrorResponse(w, r, http.StatusTooManyRequests, tooManyRequests(seconds))
+	return true
+}
+
+// loginDelay returns how long login attempts for the account or the client address are delayed.
+func (h *handler) loginDelay(login string, address string) time.Duration {
+	wait := h.accountThrottle.retryAfter(login)
+	if addressWait := h.addressThrottle.retryAfter(address); addressWait > wait {
+		wait = addressWait
+	}
+	return wait
+}
+
+// tooManyRequests returns the error for login attempts delayed for provided number of seconds.
+func tooManyRequests(seconds int) internal.Error {
+	return internal.Error{
+		Code:    internal.ErrorCodeTooManyRequests,
+		Message: fmt.Sprintf("too many login attempts, try again in %d seconds", seconds),
+		Details: map[string]any{"retry_after": seconds},
+	}
+}
+
+// loginFailed registers failed login attempt for the account and the client address.
+func (h *handler) loginFailed(login string, address string) {
+	h.accountThrottle.fail(login)
+	h.addressThrottle.fail(address)
+	h.log.Warnf("failed login attempt for user %q from %s", login, address)
+}
+
+// clientAddress returns the address of the client without port.
+func clientAddress(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
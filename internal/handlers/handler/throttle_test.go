@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestLoginThrottle(t *testing.T) {
+	now := time.Now()
+	throttle := newLoginThrottle(accountLockoutAttempts)
+	throttle.now = func() time.Time { return now }
+	key := "cersei"
+
+	t.Run("free attempts", func(t *testing.T) {
+		for i := 0; i < freeLoginAttempts; i++ {
+			throttle.fail(key)
+			assert.Zero(t, throttle.retryAfter(key))
+		}
+	})
+	t.Run("exponential backoff", func(t *testing.T) {
+		for i := 0; i < 3; i++ {
+			throttle.fail(key)
+			assert.Equal(t, baseLoginBackoff<<i, throttle.retryAfter(key))
+		}
+		now = now.Add(10 * time.Second)
+		assert.Zero(t, throttle.retryAfter(key))
+	})
+	t.Run("lockout", func(t *testing.T) {
+		for i := freeLoginAttempts + 3; i < accountLockoutAttempts; i++ {
+			throttle.fail(key)
+		}
+		assert.Equal(t, lockoutDuration, throttle.retryAfter(key))
+		assert.Zero(t, throttle.retryAfter("jaime"))
+	})
+	t.Run("reset", func(t *testing.T) {
+		throttle.reset(key)
+		assert.Zero(t, throttle.retryAfter(key))
+		throttle.fail(key)
+		assert.Zero(t, throttle.retryAfter(key))
+	})
+	t.Run("quiet keys are forgotten", func(t *testing.T) {
+		for i := 0; i < freeLoginAttempts; i++ {
+			throttle.fail(key)
+		}
+		now = now.Add(2 * lockoutDuration)
+		assert.Zero(t, throttle.retryAfter(key))
+		throttle.fail(key)
+		assert.Zero(t, throttle.retryAfter(key))
+	})
+	t.Run("expired keys are swept", func(t *testing.T) {
+		throttle.fail("10.0.0.1")
+		throttle.fail("10.0.0.2")
+		now = now.Add(lockoutDuration / 2)
+		for i := 0; i < accountLockoutAttempts; i++ {
+			throttle.fail("10.0.0.3")
+		}
+		now = now.Add(lockoutDuration/2 + time.Second)
+		throttle.fail(key)
+
+		throttle.mu.Lock()
+		defer throttle.mu.Unlock()
+		assert.Len(t, throttle.attempts, 2)
+		assert.Contains(t, throttle.attempts, key)
+		assert.Contains(t, throttle.attempts, "10.0.0.3")
+	})
+}
+
+func TestHandler_LoginBruteForce(t *testing.T) {
+	userName := "cersei"
+	adminToken := "kingslanding"
+	logger, _ := zap.NewProduction()
+	defer logger.Sync() // flushes buffer, if any
+	log := logger.Sugar()
+
+	newServer := func(mockedStorage *mocks.Storage) (*handler, *httptest.Server) {
+		h := New(mockedStorage, log, WithAdminToken(adminToken))
+		r := chi.NewRouter()
+		r.Post("/auth/login", h.Login)
+		r.Group(func(r chi.Router) {
+			r.Use(h.AdminAuth)
+			r.Post("/admin/unlock", h.UnlockAccount)
+		})
+		return h, httptest.NewServer(r)
+	}
+	login := func(srv *httptest.Server, login, password string) *resty.Response {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q, "password": %q}`, login, password)).
+			Post(fmt.Sprintf("%s/auth/login", srv.URL))
+		assert.NoError(t, err)
+		return resp
+	}
+
+	t.Run("uniform errors for unknown users", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "wrong").Return(database.ErrInvalidCredentials)
+		mockedStorage.On("Login", mock.Anything, "nobody", "wrong").Return(database.ErrNoSuchUser)
+		_, srv := newServer(mockedStorage)
+		defer srv.Close()
+
+		wrongPassword := login(srv, userName, "wrong")
+		unknownUser := login(srv, "nobody", "wrong")
+		assert.Equal(t, http.StatusUnauthorized, wrongPassword.StatusCode())
+		assert.Equal(t, wrongPassword.StatusCode(), unknownUser.StatusCode())
+		assert.Equal(t, wrongPassword.String(), unknownUser.String())
+	})
+	t.Run("attempts are delayed after failures", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "wrong").Return(database.ErrInvalidCredentials).Times(freeLoginAttempts + 1)
+		_, srv := newServer(mockedStorage)
+		defer srv.Close()
+
+		for i := 0; i <= freeLoginAttempts; i++ {
+			assert.Equal(t, http.StatusUnauthorized, login(srv, userName, "wrong").StatusCode())
+		}
+		// the storage is not called while the account is delayed
+		resp := login(srv, userName, "right")
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode())
+		assert.Equal(t, "1", resp.Header().Get("Retry-After"))
+		assert.JSONEq(t, `{"error": {"code": "too_many_requests", "message": "too many login attempts, try again in 1 seconds", "details": {"retry_after": 1}}}`, resp.String())
+	})
+	t.Run("admin unlocks account", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "right").Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		h, srv := newServer(mockedStorage)
+		defer srv.Close()
+
+		for i := 0; i < accountLockoutAttempts; i++ {
+			h.accountThrottle.fail(userName)
+		}
+		assert.Equal(t, http.StatusTooManyRequests, login(srv, userName, "right").StatusCode())
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q}`, userName)).
+			Post(fmt.Sprintf("%s/admin/unlock", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+
+		resp, err = resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", fmt.Sprintf("Bearer %s", adminToken)).
+			SetBody(fmt.Sprintf(`{"login": %q}`, userName)).
+			Post(fmt.Sprintf("%s/admin/unlock", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+		assert.Equal(t, http.StatusOK, login(srv, userName, "right").StatusCode())
+	})
+	t.Run("admin endpoints are disabled without token", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h := New(mockedStorage, log)
+		r := chi.NewRouter()
+		r.Use(h.AdminAuth)
+		r.Post("/admin/unlock", h.UnlockAccount)
+		srv := httptest.NewServer(r)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", "Bearer ").
+			SetBody(fmt.Sprintf(`{"login": %q}`, userName)).
+			Post(fmt.Sprintf("%s/admin/unlock", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode())
+	})
+}
@@ -0,0 +1,285 @@
+package handler
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/otp"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	otpIssuer          = "goph-keeper"
+	challengeSubject   = "2fa"
+	challengeTTL       = 5 * time.Minute
+	recoveryCodesCount = 10
+	recoveryCodeLength = 10
+	// recoveryCodeAlphabet excludes characters which are easy to confuse when copied from paper.
+	recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+)
+
+// EnableTwoFactor is a method for starting two-factor authentication setup for the user authorized by TokenAuth.
+// The body of the HTTP request must contain the current `password` of the user, so a stolen token can't be used
+// to take over the account. A new TOTP secret is generated and returned together with the otpauth:// URI
+// for authenticator apps. Two-factor authentication is not enabled until the code is confirmed via ConfirmTwoFactor.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/auth/2fa/enable --data `{"password": "some_password"}`
+func (h *handler) EnableTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body to get user's password, the name is taken from the token
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var twoFactorRequest internal.TwoFactorRequest
+	if err = json.Unmarshal(body, &twoFactorRequest); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	twoFactorRequest.UserName = userName(r)
+	if twoFactorRequest.Password == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "password should not be empty")
+		return
+	}
+	if !h.reauthenticate(ctx, w, r, internal.AccountRequest{
+		Login:    twoFactorRequest.UserName,
+		Password: twoFactorRequest.Password,
+		Code:     twoFactorRequest.Code,
+	}) {
+		return
+	}
+
+	// generate and save new secret
+	key, err := otp.NewKey(otpIssuer, twoFactorRequest.UserName)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if err = h.db.SaveTwoFactorSecret(ctx, twoFactorRequest.UserName, key.Secret); err != nil {
+		h.writeUserError(w, r, twoFactorRequest.UserName, err)
+		return
+	}
+
+	// response
+	setupResponse, err := json.Marshal(internal.TwoFactorSetup{Secret: key.Secret, URI: key.URI()})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if _, err = w.Write(setupResponse); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+}
+
+// ConfirmTwoFactor is a method for finishing two-factor authentication setup for the user authorized by TokenAuth.
+// The body of the HTTP request must contain the current code from authenticator app.
+// On success two-factor authentication is enabled and one-time recovery codes are returned.
+// For example: curl -X POST -H "Authorization: Bearer <token>" http://127.0.0.1:8080/auth/2fa/confirm --data `{"code": "123456"}`
+func (h *handler) ConfirmTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body to get user's name and code
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var twoFactorRequest internal.TwoFactorRequest
+	if err = json.Unmarshal(body, &twoFactorRequest); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	twoFactorRequest.UserName = userName(r)
+	if twoFactorRequest.Code == "" {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "code should not be empty")
+		return
+	}
+
+	// check the code against the pending secret
+	twoFactor, err := h.db.GetTwoFactor(ctx, twoFactorRequest.UserName)
+	if err != nil {
+		h.writeUserError(w, r, twoFactorRequest.UserName, err)
+		return
+	}
+	if twoFactor.Enabled {
+		h.writeUserError(w, r, twoFactorRequest.UserName, database.ErrTwoFactorEnabled)
+		return
+	}
+	key := otp.Key{Secret: twoFactor.Secret}
+	if !h.usedCodes.accept(twoFactorRequest.UserName, key, twoFactorRequest.Code, time.Now()) {
+		h.writeUserError(w, r, twoFactorRequest.UserName, ErrInvalidOTP)
+		return
+	}
+
+	// enable two-factor authentication with fresh recovery codes
+	codes, err := generateRecoveryCodes()
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if err = h.db.EnableTwoFactor(ctx, twoFactorRequest.UserName, codes); err != nil {
+		h.writeUserError(w, r, twoFactorRequest.UserName, err)
+		return
+	}
+
+	// response
+	codesResponse, err := json.Marshal(internal.RecoveryCodes{Codes: codes})
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	if _, err = w.Write(codesResponse); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	h.log.Infof("two-factor authentication was enabled for user %q", twoFactorRequest.UserName)
+}
+
+// LoginTwoFactor is a method for the second step of login for users with enabled two-factor authentication.
+// The body of the HTTP request must contain the challenge returned by Login and either the current code
+// from authenticator app or one of the recovery codes.
+// For example: curl -X POST http://127.0.0.1:8080/auth/login/2fa --data `{"challenge": "some_challenge", "code": "123456"}`
+func (h *handler) LoginTwoFactor(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("content-type", "application/json")
+
+	ctx := context.Background()
+	// parse body
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, err.Error())
+		return
+	}
+	var loginRequest internal.TwoFactorLogin
+	if err = json.Unmarshal(body, &loginRequest); err != nil {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, err.Error())
+		return
+	}
+	if loginRequest.Challenge == "" || (loginRequest.Code == "" && loginRequest.RecoveryCode == "") {
+		writeError(w, r, http.StatusBadRequest, internal.ErrorCodeInvalidRequest, "challenge and code or recovery code should not be empty")
+		return
+	}
+	userName, err := parseChallengeToken(loginRequest.Challenge)
+	if err != nil {
+		h.writeUserError(w, r, userName, err)
+		return
+	}
+
+	// check the second factor, failed attempts are throttled the same way as password ones
+	address := clientAddress(r)
+	if h.throttled(w, r, userName, address) {
+		return
+	}
+	if loginRequest.RecoveryCode != "" {
+		err = h.db.UseRecoveryCode(ctx, userName, normalizeRecoveryCode(loginRequest.RecoveryCode))
+	} else {
+		err = h.checkCode(ctx, userName, loginRequest.Code)
+	}
+	if err != nil {
+		if errors.Is(err, ErrInvalidOTP) || errors.Is(err, database.ErrInvalidRecoveryCode) {
+			h.loginFailed(userName, address)
+		}
+		h.writeUserError(w, r, userName, err)
+		return
+	}
+
+	// create jwt token for user, add Authorization header and remember cookie
+	if err = h.authorize(w, userName); err != nil {
+		writeError(w, r, http.StatusInternalServerError, internal.ErrorCodeInternal, fmt.Sprintf("error while create token for user: %s", err.Error()))
+		return
+	}
+	h.accountThrottle.reset(userName)
+	w.WriteHeader(http.StatusOK)
+	h.log.Infof("user %q was successfully logined with two-factor authentication", userName)
+}
+
+func (h *handler) checkCode(ctx context.Context, userName string, code string) error {
+	twoFactor, err := h.db.GetTwoFactor(ctx, userName)
+	if err != nil {
+		return err
+	}
+	key := otp.Key{Secret: twoFactor.Secret}
+	if !twoFactor.Enabled || !h.usedCodes.accept(userName, key, code, time.Now()) {
+		return ErrInvalidOTP
+	}
+	return nil
+}
+
+type usedStep struct {
+	step    int64
+	expires time.Time
+}
+
+// usedCodes remembers the last accepted TOTP time step of each user, so an intercepted code can't be used
+// again while it's still valid. The steps are forgotten when their codes expire.
+type usedCodes struct {
+	mu    sync.Mutex
+	steps map[string]usedStep
+}
+
+func newUsedCodes() *usedCodes {
+	return &usedCodes{steps: make(map[string]usedStep)}
+}
+
+// accept validates the code of the user and marks its step as used.
+// Codes of the accepted step and of the earlier ones are refused.
+func (u *usedCodes) accept(userName string, key otp.Key, code string, now time.Time) bool {
+	step, ok := key.ValidateStep(code, now)
+	if !ok {
+		return false
+	}
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	for name, used := range u.steps {
+		if now.After(used.expires) {
+			delete(u.steps, name)
+		}
+	}
+	if used, ok := u.steps[userName]; ok && step <= used.step {
+		return false
+	}
+	u.steps[userName] = usedStep{step: step, expires: key.StepExpires(step)}
+	return true
+}
+
+func generateRecoveryCodes() ([]string, error) {
+	codes := make([]string, 0, recoveryCodesCount)
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+	for i := 0; i < recoveryCodesCount; i++ {
+		code := make([]byte, 0, recoveryCodeLength+1)
+		for j := 0; j < recoveryCodeLength; j++ {
+			if j == recoveryCodeLength/2 {
+				code = append(code, '-')
+			}
+			n, err := rand.Int(rand.Reader, alphabetSize)
+			if err != nil {
+				return nil, fmt.Errorf("error while generating recovery codes: %w", err)
+			}
+			code = append(code, recoveryCodeAlphabet[n.Int64()])
+		}
+		codes = append(codes, string(code))
+	}
+	return codes, nil
+}
+
+// normalizeRecoveryCode allows users to type recovery codes without the dash and in any case.
+func normalizeRecoveryCode(code string) string {
+	code = strings.ToLower(strings.ReplaceAll(strings.TrimSpace(code), "-", ""))
+	if len(code) != recoveryCodeLength {
+		return code
+	}
+	return code[:recoveryCodeLength/2] + "-" + code[recoveryCodeLength/2:]
+}
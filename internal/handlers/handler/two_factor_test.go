@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"github.com/go-chi/chi"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/otp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func newTwoFactorServer(t *testing.T, mockedStorage *mocks.Storage, userName, password string) (*handler, *httptest.Server) {
+	logger, _ := zap.NewProduction()
+	h := New(mockedStorage, logger.Sugar())
+
+	r := chi.NewRouter()
+	r.Group(func(r chi.Router) {
+		r.Post("/auth/register", h.Register)
+		r.Post("/auth/login", h.Login)
+		r.Post("/auth/login/2fa", h.LoginTwoFactor)
+		r.With(h.TokenAuth).Post("/auth/2fa/enable", h.EnableTwoFactor)
+		r.With(h.TokenAuth).Post("/auth/2fa/confirm", h.ConfirmTwoFactor)
+	})
+	srv := httptest.NewServer(r)
+
+	if password != "" {
+		mockedStorage.On("Register", mock.Anything, userName, password).Return(nil)
+		_, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password)).
+			Post(fmt.Sprintf("%s/auth/register", srv.URL))
+		assert.NoError(t, err)
+	}
+	return h, srv
+}
+
+func TestHandler_EnableTwoFactor(t *testing.T) {
+	userName := "bran"
+	password := "threeeyedraven"
+
+	t.Run("positive: setup started", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("SaveTwoFactorSecret", mock.Anything, userName, mock.AnythingOfType("string")).Return(nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(fmt.Sprintf(`{"password": %q}`, password)).
+			Post(fmt.Sprintf("%s/auth/2fa/enable", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+		var setup internal.TwoFactorSetup
+		assert.NoError(t, json.Unmarshal(resp.Body(), &setup))
+		key, err := otp.ParseURI(setup.URI)
+		assert.NoError(t, err)
+		assert.Equal(t, setup.Secret, key.Secret)
+		assert.Equal(t, otpIssuer, key.Issuer)
+		assert.Equal(t, userName, key.Account)
+	})
+	t.Run("positive: user name from body is ignored", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("SaveTwoFactorSecret", mock.Anything, userName, mock.AnythingOfType("string")).Return(nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(fmt.Sprintf(`{"user_name": "hodor", "password": %q}`, password)).
+			Post(fmt.Sprintf("%s/auth/2fa/enable", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+	})
+	t.Run("negative: already enabled", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("SaveTwoFactorSecret", mock.Anything, userName, mock.AnythingOfType("string")).Return(database.ErrTwoFactorEnabled)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(fmt.Sprintf(`{"password": %q}`, password)).
+			Post(fmt.Sprintf("%s/auth/2fa/enable", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusConflict, resp.StatusCode())
+	})
+	t.Run("negative: wrong password", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "hodor").Return(database.ErrInvalidCredentials)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(`{"password": "hodor"}`).
+			Post(fmt.Sprintf("%s/auth/2fa/enable", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("negative: no password", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(`{}`).
+			Post(fmt.Sprintf("%s/auth/2fa/enable", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+	t.Run("negative: unauthorized", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"user_name": %q, "password": %q}`, userName, password)).
+			Post(fmt.Sprintf("%s/auth/2fa/enable", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+}
+
+func TestHandler_ConfirmTwoFactor(t *testing.T) {
+	userName := "bran"
+	password := "threeeyedraven"
+	key, err := otp.NewKey(otpIssuer, userName)
+	assert.NoError(t, err)
+
+	t.Run("positive: enabled", func(t *testing.T) {
+		code, err := key.Code(time.Now())
+		assert.NoError(t, err)
+
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Login: userName, Secret: key.Secret}, nil)
+		mockedStorage.On("EnableTwoFactor", mock.Anything, userName, mock.AnythingOfType("[]string")).Return(nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(fmt.Sprintf(`{"code": %q}`, code)).
+			Post(fmt.Sprintf("%s/auth/2fa/confirm", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+
+		var codes internal.RecoveryCodes
+		assert.NoError(t, json.Unmarshal(resp.Body(), &codes))
+		assert.Len(t, codes.Codes, recoveryCodesCount)
+		for _, c := range codes.Codes {
+			assert.Equal(t, c, normalizeRecoveryCode(c))
+		}
+	})
+	t.Run("negative: wrong code", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Login: userName, Secret: key.Secret}, nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(`{"code": "000000x"}`).
+			Post(fmt.Sprintf("%s/auth/2fa/confirm", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("negative: setup was not started", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(`{"code": "123456"}`).
+			Post(fmt.Sprintf("%s/auth/2fa/confirm", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode())
+	})
+	t.Run("negative: no code", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, password)
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetHeader("Authorization", h.cookies.get(userName)).
+			SetBody(`{}`).
+			Post(fmt.Sprintf("%s/auth/2fa/confirm", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusBadRequest, resp.StatusCode())
+	})
+	t.Run("negative: unauthorized", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"user_name": %q, "code": "123456"}`, userName)).
+			Post(fmt.Sprintf("%s/auth/2fa/confirm", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+}
+
+func TestHandler_LoginTwoFactor(t *testing.T) {
+	userName := "bran"
+	password := "threeeyedraven"
+	key, err := otp.NewKey(otpIssuer, userName)
+	assert.NoError(t, err)
+	twoFactor := internal.TwoFactor{Login: userName, Secret: key.Secret, Enabled: true}
+
+	login := func(t *testing.T, srv *httptest.Server) string {
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"login": %q, "password": %q}`, userName, password)).
+			Post(fmt.Sprintf("%s/auth/login", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusAccepted, resp.StatusCode())
+		assert.Empty(t, resp.Header().Get("Authorization"))
+
+		var challenge internal.LoginChallenge
+		assert.NoError(t, json.Unmarshal(resp.Body(), &challenge))
+		assert.NotEmpty(t, challenge.Challenge)
+		return challenge.Challenge
+	}
+
+	t.Run("positive: login with code", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(twoFactor, nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		challenge := login(t, srv)
+		assert.Empty(t, h.cookies.get(userName))
+
+		code, err := key.Code(time.Now())
+		assert.NoError(t, err)
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"challenge": %q, "code": %q}`, challenge, code)).
+			Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.NotEmpty(t, resp.Header().Get("Authorization"))
+		assert.NotEmpty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: code can't be used twice", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(twoFactor, nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		code, err := key.Code(time.Now())
+		assert.NoError(t, err)
+		for _, status := range []int{http.StatusOK, http.StatusUnauthorized} {
+			h.cookies.delete(userName)
+			resp, err := resty.New().R().
+				SetHeader("content-type", "application/json").
+				SetBody(fmt.Sprintf(`{"challenge": %q, "code": %q}`, login(t, srv), code)).
+				Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+			assert.NoError(t, err)
+			assert.Equal(t, status, resp.StatusCode())
+		}
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("positive: login with recovery code", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(twoFactor, nil)
+		mockedStorage.On("UseRecoveryCode", mock.Anything, userName, "abcde-fghjk").Return(nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		challenge := login(t, srv)
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"challenge": %q, "recovery_code": "ABCDEFGHJK"}`, challenge)).
+			Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusOK, resp.StatusCode())
+		assert.NotEmpty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: used recovery code", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(twoFactor, nil)
+		mockedStorage.On("UseRecoveryCode", mock.Anything, userName, "abcde-fghjk").Return(database.ErrInvalidRecoveryCode)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		challenge := login(t, srv)
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"challenge": %q, "recovery_code": "abcde-fghjk"}`, challenge)).
+			Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: wrong code", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(twoFactor, nil)
+		h, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		challenge := login(t, srv)
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"challenge": %q, "code": "1234567"}`, challenge)).
+			Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+		assert.Empty(t, h.cookies.get(userName))
+	})
+	t.Run("negative: session token used as challenge", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		token, err := createToken(userName, time.Now().Add(time.Hour))
+		assert.NoError(t, err)
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"challenge": %q, "code": "123456"}`, token)).
+			Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+	t.Run("negative: expired challenge", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		_, srv := newTwoFactorServer(t, mockedStorage, userName, "")
+		defer srv.Close()
+
+		challenge, err := createChallengeToken(userName, time.Now().Add(-time.Minute))
+		assert.NoError(t, err)
+		resp, err := resty.New().R().
+			SetHeader("content-type", "application/json").
+			SetBody(fmt.Sprintf(`{"challenge": %q, "code": "123456"}`, challenge)).
+			Post(fmt.Sprintf("%s/auth/login/2fa", srv.URL))
+		assert.NoError(t, err)
+		assert.Equal(t, http.StatusUnauthorized, resp.StatusCode())
+	})
+}
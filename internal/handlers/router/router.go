@@ -2,19 +2,51 @@ package router
 
 import (
 	"github.com/go-chi/chi"
+	"github.com/go-chi/chi/middleware"
 	"github.com/kontik-pk/goph-keeper/internal"
 	"github.com/kontik-pk/goph-keeper/internal/handlers/handler"
+	httpSwagger "github.com/swaggo/http-swagger/v2"
 	"go.uber.org/zap"
+	"google.golang.org/grpc"
+	"net/http"
 )
 
-func New(db internal.Storage, log *zap.SugaredLogger) *chi.Mux {
-	httpHandler := handler.New(db, log)
+func New(db internal.Storage, log *zap.SugaredLogger, opts ...handler.Option) *chi.Mux {
+	r, _ := NewServers(db, log, opts...)
+	return r
+}
+
+// NewServers creates HTTP router and gRPC server sharing the same handler,
+// so the sessions opened with one protocol are valid for the other one.
+func NewServers(db internal.Storage, log *zap.SugaredLogger, opts ...handler.Option) (*chi.Mux, *grpc.Server) {
+	httpHandler := handler.New(db, log, opts...)
 
 	r := chi.NewRouter()
+	// the request id is returned in error responses and written to the logs
+	r.Use(middleware.RequestID)
+	// API documentation, Swagger UI assets are embedded in the binary
+	r.Get("/openapi.json", httpHandler.OpenAPI)
+	r.Get("/docs", http.RedirectHandler("/docs/index.html", http.StatusMovedPermanently).ServeHTTP)
+	r.Get("/docs/*", httpSwagger.Handler(httpSwagger.URL("/openapi.json")))
 	r.Group(func(r chi.Router) {
 		r.Post("/auth/register", httpHandler.Register)
 		r.Post("/auth/login", httpHandler.Login)
+		r.Post("/auth/login/2fa", httpHandler.LoginTwoFactor)
+		r.With(httpHandler.TokenAuth).Post("/auth/refresh", httpHandler.RefreshToken)
+		// the user of the token enables the second factor or registers a key, the password is checked again
+		r.With(httpHandler.TokenAuth).Post("/auth/2fa/enable", httpHandler.EnableTwoFactor)
+		r.With(httpHandler.TokenAuth).Post("/auth/2fa/confirm", httpHandler.ConfirmTwoFactor)
+		r.With(httpHandler.TokenAuth).Post("/auth/key/register", httpHandler.RegisterKey)
+		r.Post("/auth/key/challenge", httpHandler.KeyChallenge)
+		r.Post("/auth/key/login", httpHandler.KeyLogin)
+		// account changes require the password instead of the token
+		r.Post("/auth/change-password", httpHandler.ChangePassword)
+		r.Post("/auth/delete-account", httpHandler.DeleteAccount)
+		r.Post("/auth/vault", httpHandler.GetVaultKey)
+		r.Post("/auth/recover/key", httpHandler.GetRecoveryKey)
+		r.Post("/auth/recover", httpHandler.Recover)
 	})
+	// API v1 is kept for compatibility with existing clients
 	r.Group(func(r chi.Router) {
 		r.Use(httpHandler.BasicAuth)
 		r.Post("/save/credentials", httpHandler.SaveUserCredentials)
@@ -32,6 +64,79 @@ func New(db internal.Storage, log *zap.SugaredLogger) *chi.Mux {
 		r.Post("/delete/card", httpHandler.DeleteCard)
 		r.Post("/get/card", httpHandler.GetCard)
 	})
+	// API v2 is resource oriented and authorized with the token from the Authorization header
+	r.Route("/api/v2", func(r chi.Router) {
+		r.Use(httpHandler.TokenAuth)
+		r.Route("/credentials", func(r chi.Router) {
+			r.Get("/", httpHandler.ListCredentials)
+			r.Post("/", httpHandler.CreateCredentials)
+			r.Get("/{id}", httpHandler.GetCredentialsItem)
+			r.Put("/{id}", httpHandler.ReplaceCredentials)
+			r.Patch("/{id}", httpHandler.PatchCredentials)
+			r.Delete("/{id}", httpHandler.RemoveCredentials)
+			r.Put("/{id}/labels", httpHandler.SetCredentialsLabels)
+		})
+		r.Route("/notes", func(r chi.Router) {
+			r.Get("/", httpHandler.ListNotes)
+			r.Post("/", httpHandler.CreateNote)
+			r.Get("/{id}", httpHandler.GetNoteItem)
+			r.Put("/{id}", httpHandler.ReplaceNote)
+			r.Patch("/{id}", httpHandler.PatchNote)
+			r.Delete("/{id}", httpHandler.RemoveNote)
+			r.Put("/{id}/labels", httpHandler.SetNoteLabels)
+		})
+		r.Route("/cards", func(r chi.Router) {
+			r.Get("/", httpHandler.ListCards)
+			r.Post("/", httpHandler.CreateCard)
+			r.Get("/expiring", httpHandler.ListExpiringCards)
+			r.Get("/{id}", httpHandler.GetCardItem)
+			r.Put("/{id}", httpHandler.ReplaceCard)
+			r.Patch("/{id}", httpHandler.PatchCard)
+			r.Delete("/{id}", httpHandler.RemoveCard)
+			r.Put("/{id}/labels", httpHandler.SetCardLabels)
+		})
+		r.Route("/totp", func(r chi.Router) {
+			r.Get("/", httpHandler.ListTOTP)
+			r.Post("/", httpHandler.CreateTOTP)
+			r.Get("/{id}", httpHandler.GetTOTPItem)
+			r.Delete("/{id}", httpHandler.RemoveTOTP)
+			r.Get("/{id}/code", httpHandler.GetTOTPCode)
+		})
+		r.Route("/ssh-keys", func(r chi.Router) {
+			r.Get("/", httpHandler.ListSSHKeys)
+			r.Post("/", httpHandler.CreateSSHKey)
+			r.Get("/{id}", httpHandler.GetSSHKeyItem)
+			r.Delete("/{id}", httpHandler.RemoveSSHKey)
+		})
+		r.Route("/identities", func(r chi.Router) {
+			r.Get("/", httpHandler.ListIdentities)
+			r.Post("/", httpHandler.CreateIdentity)
+			r.Get("/{id}", httpHandler.GetIdentityItem)
+			r.Delete("/{id}", httpHandler.RemoveIdentity)
+		})
+		r.Route("/secrets", func(r chi.Router) {
+			r.Get("/", httpHandler.ListSecrets)
+			r.Post("/", httpHandler.CreateSecret)
+			r.Get("/{id}", httpHandler.GetSecretItem)
+			r.Put("/{id}", httpHandler.ReplaceSecret)
+			r.Delete("/{id}", httpHandler.RemoveSecret)
+		})
+		r.Get("/due", httpHandler.ListDueItems)
+		r.Get("/report/health", httpHandler.HealthReport)
+		r.Get("/search", httpHandler.Search)
+		r.Get("/tags", httpHandler.ListTags)
+		r.Post("/generate", httpHandler.GeneratePassword)
+		r.Route("/folders", func(r chi.Router) {
+			r.Get("/", httpHandler.ListFolders)
+			r.Post("/", httpHandler.CreateFolder)
+			r.Put("/{id}", httpHandler.ReplaceFolder)
+			r.Delete("/{id}", httpHandler.RemoveFolder)
+		})
+	})
+	r.Group(func(r chi.Router) {
+		r.Use(httpHandler.AdminAuth)
+		r.Post("/admin/unlock", httpHandler.UnlockAccount)
+	})
 
-	return r
+	return r, httpHandler.GRPCServer()
 }
@@ -0,0 +1,93 @@
+package router
+
+import (
+	"encoding/json"
+	"github.com/go-chi/chi"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"testing"
+)
+
+// undocumentedRoutes are served by the router but are not a part of the API.
+var undocumentedRoutes = map[string]bool{
+	"GET /openapi.json": true,
+	"GET /docs":         true,
+	"GET /docs/*":       true,
+}
+
+func TestNew_OpenAPIMatchesRoutes(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	r := New(mocks.NewStorage(t), logger.Sugar())
+
+	// routes served by the router
+	var routes []string
+	err := chi.Walk(r, func(method string, route string, _ http.Handler, _ ...func(http.Handler) http.Handler) error {
+		// chi registers the index of the sub-router with the trailing slash
+		if route != "/" {
+			route = strings.TrimSuffix(route, "/")
+		}
+		if key := method + " " + route; !undocumentedRoutes[key] {
+			routes = append(routes, key)
+		}
+		return nil
+	})
+	assert.NoError(t, err)
+
+	// operations described by the served document
+	resp := httptest.NewRecorder()
+	r.ServeHTTP(resp, httptest.NewRequest(http.MethodGet, "/openapi.json", nil))
+	assert.Equal(t, http.StatusOK, resp.Code)
+	var spec struct {
+		OpenAPI string                            `json:"openapi"`
+		Paths   map[string]map[string]interface{} `json:"paths"`
+	}
+	assert.NoError(t, json.Unmarshal(resp.Body.Bytes(), &spec))
+	assert.True(t, strings.HasPrefix(spec.OpenAPI, "3."))
+	var operations []string
+	for path, methods := range spec.Paths {
+		for method := range methods {
+			operations = append(operations, strings.ToUpper(method)+" "+path)
+		}
+	}
+
+	sort.Strings(routes)
+	sort.Strings(operations)
+	assert.Equal(t, routes, operations, "routes in router.New and paths in openapi.json have drifted apart")
+}
+
+func TestNew_Docs(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	srv := httptest.NewServer(New(mocks.NewStorage(t), logger.Sugar()))
+	defer srv.Close()
+
+	resp, err := http.Get(srv.URL + "/docs")
+	assert.NoError(t, err)
+	defer resp.Body.Close()
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "/docs/index.html", resp.Request.URL.Path)
+	assert.Contains(t, resp.Header.Get("Content-Type"), "text/html")
+}
+
+func TestNewServers(t *testing.T) {
+	logger, _ := zap.NewProduction()
+	_, grpcServer := NewServers(mocks.NewStorage(t), logger.Sugar())
+
+	var services []string
+	for name := range grpcServer.GetServiceInfo() {
+		services = append(services, name)
+	}
+	sort.Strings(services)
+	assert.Equal(t, []string{
+		"keeper.v1.AuthService",
+		"keeper.v1.CardsService",
+		"keeper.v1.CredentialsService",
+		"keeper.v1.FilesService",
+		"keeper.v1.NotesService",
+		"keeper.v1.SyncService",
+	}, services)
+}
@@ -0,0 +1,119 @@
+package health
+
+import (
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultMinScore is the zxcvbn strength score (0-4) below which the passwords are weak.
+	DefaultMinScore = 3
+	// DefaultMaxAgeDays is the age of the passwords after which they are old.
+	DefaultMaxAgeDays = 365
+)
+
+// Options are the thresholds of the report.
+type Options struct {
+	MinScore   int
+	MaxAgeDays int
+}
+
+// Report checks the decrypted credentials for weak, reused and old passwords and plain http URLs in the custom fields.
+// The credentials should have the passwords, the fields and the time of the password change. The passwords
+// with the breach count are reported as breached if they have appeared in known data breaches.
+func Report(creds []internal.Credentials, now time.Time, opts Options) internal.HealthReport {
+	report := internal.HealthReport{
+		GeneratedAt: now,
+		MinScore:    opts.MinScore,
+		MaxAgeDays:  opts.MaxAgeDays,
+		Total:       len(creds),
+		Credentials: []internal.CredentialsHealth{},
+	}
+	reused := reusedPasswords(creds)
+	for _, item := range creds {
+		var login, pass string
+		if item.Login != nil {
+			login = *item.Login
+		}
+		if item.Password != nil {
+			pass = *item.Password
+		}
+		result := internal.CredentialsHealth{
+			ID:           item.ID,
+			Login:        login,
+			Score:        password.Score(pass, login),
+			ReusedWith:   reused[item.ID],
+			InsecureURLs: insecureURLs(item.Fields),
+			BreachCount:  item.BreachCount,
+		}
+		if item.PasswordChangedAt != nil {
+			result.PasswordAgeDays = int(now.Sub(*item.PasswordChangedAt).Hours() / 24)
+		}
+		if result.Score < opts.MinScore {
+			result.Issues = append(result.Issues, internal.HealthIssueWeak)
+			report.Summary.Weak++
+		}
+		if len(result.ReusedWith) > 0 {
+			result.Issues = append(result.Issues, internal.HealthIssueReused)
+			report.Summary.Reused++
+		}
+		if item.PasswordChangedAt != nil && result.PasswordAgeDays >= opts.MaxAgeDays {
+			result.Issues = append(result.Issues, internal.HealthIssueOld)
+			report.Summary.Old++
+		}
+		if len(result.InsecureURLs) > 0 {
+			result.Issues = append(result.Issues, internal.HealthIssueInsecureURL)
+			report.Summary.InsecureURLs++
+		}
+		if item.BreachCount != nil && *item.BreachCount > 0 {
+			result.Issues = append(result.Issues, internal.HealthIssueBreached)
+			report.Summary.Breached++
+		}
+		if len(result.Issues) > 0 {
+			report.Credentials = append(report.Credentials, result)
+		}
+	}
+	return report
+}
+
+// reusedPasswords returns the ids of the other credentials with the same password for each credentials.
+func reusedPasswords(creds []internal.Credentials) map[int64][]int64 {
+	byPassword := make(map[string][]int64)
+	for _, item := range creds {
+		if item.Password != nil && *item.Password != "" {
+			byPassword[*item.Password] = append(byPassword[*item.Password], item.ID)
+		}
+	}
+	reused := make(map[int64][]int64)
+	for _, ids := range byPassword {
+		if len(ids) < 2 {
+			continue
+		}
+		for _, id := range ids {
+			for _, other := range ids {
+				if other != id {
+					reused[id] = append(reused[id], other)
+				}
+			}
+		}
+	}
+	return reused
+}
+
+// insecureURLs returns the values of the URL fields using plain http.
+func insecureURLs(fields []internal.Field) []string {
+	var urls []string
+	for _, field := range fields {
+		if field.Type != internal.FieldTypeURL {
+			continue
+		}
+		u, err := url.Parse(strings.TrimSpace(field.Value))
+		if err == nil && strings.EqualFold(u.Scheme, "http") {
+			urls = append(urls, field.Value)
+		}
+	}
+	return urls
+}
@@ -0,0 +1,59 @@
+package health
+
+import (
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestReport(t *testing.T) {
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	recent := now.AddDate(0, 0, -10)
+	old := now.AddDate(-2, 0, 0)
+	strong := "correct-horse-battery-staple-42"
+	creds := []internal.Credentials{
+		{ID: 1, Login: Ptr("sansa"), Password: Ptr("qwerty12"), Rotation: internal.Rotation{PasswordChangedAt: &recent}},
+		{ID: 2, Login: Ptr("arya"), Password: &strong, Rotation: internal.Rotation{PasswordChangedAt: &recent}},
+		{ID: 3, Login: Ptr("bran"), Password: &strong, Rotation: internal.Rotation{PasswordChangedAt: &old},
+			Fields: []internal.Field{
+				{Name: "site", Type: internal.FieldTypeURL, Value: "http://winterfell.example"},
+				{Name: "admin", Type: internal.FieldTypeURL, Value: "https://winterfell.example/admin"},
+				{Name: "note", Type: internal.FieldTypeText, Value: "http://not-a-url-field.example"},
+			}},
+		{ID: 4, Login: Ptr("rickon"), Password: Ptr("W1nter-is-c0ming-for-real"), Rotation: internal.Rotation{PasswordChangedAt: &recent},
+			BreachCount: Ptr(3)},
+		{ID: 5, Login: Ptr("hodor"), Password: Ptr("Hold-the-door-h0d0r!"), Rotation: internal.Rotation{PasswordChangedAt: &recent},
+			BreachCount: Ptr(0)},
+	}
+
+	report := Report(creds, now, Options{MinScore: DefaultMinScore, MaxAgeDays: DefaultMaxAgeDays})
+	assert.Equal(t, now, report.GeneratedAt)
+	assert.Equal(t, 5, report.Total)
+	assert.Equal(t, internal.HealthSummary{Weak: 1, Reused: 2, Old: 1, InsecureURLs: 1, Breached: 1}, report.Summary)
+	assert.Len(t, report.Credentials, 4)
+
+	assert.Equal(t, int64(1), report.Credentials[0].ID)
+	assert.Equal(t, []string{internal.HealthIssueWeak}, report.Credentials[0].Issues)
+	assert.Less(t, report.Credentials[0].Score, DefaultMinScore)
+
+	assert.Equal(t, internal.CredentialsHealth{
+		ID: 2, Login: "arya", Issues: []string{internal.HealthIssueReused}, Score: 4, ReusedWith: []int64{3}, PasswordAgeDays: 10,
+	}, report.Credentials[1])
+	assert.Equal(t, internal.CredentialsHealth{
+		ID: 3, Login: "bran", Issues: []string{internal.HealthIssueReused, internal.HealthIssueOld, internal.HealthIssueInsecureURL},
+		Score: 4, ReusedWith: []int64{2}, PasswordAgeDays: 730, InsecureURLs: []string{"http://winterfell.example"},
+	}, report.Credentials[2])
+	assert.Equal(t, []string{internal.HealthIssueBreached}, report.Credentials[3].Issues)
+	assert.Equal(t, Ptr(3), report.Credentials[3].BreachCount)
+}
+
+func TestReport_NoCredentials(t *testing.T) {
+	report := Report(nil, time.Now(), Options{MinScore: DefaultMinScore, MaxAgeDays: DefaultMaxAgeDays})
+	assert.Equal(t, 0, report.Total)
+	assert.NotNil(t, report.Credentials)
+}
+
+func Ptr[T any](v T) *T {
+	return &v
+}
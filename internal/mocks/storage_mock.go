@@ -7,6 +7,8 @@ import (
 
 	internal "github.com/kontik-pk/goph-keeper/internal"
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // Storage is an autogenerated mock type for the Storage type
@@ -14,6 +16,20 @@ type Storage struct {
 	mock.Mock
 }
 
+// ChangePassword provides a mock function with given fields: ctx, login, newPassword, key
+func (_m *Storage) ChangePassword(ctx context.Context, login string, newPassword string, key *internal.VaultKey) error {
+	ret := _m.Called(ctx, login, newPassword, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *internal.VaultKey) error); ok {
+		r0 = rf(ctx, login, newPassword, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // Close provides a mock function with given fields:
 func (_m *Storage) Close() error {
 	ret := _m.Called()
@@ -28,6 +44,20 @@ func (_m *Storage) Close() error {
 	return r0
 }
 
+// DeleteAccount provides a mock function with given fields: ctx, login
+func (_m *Storage) DeleteAccount(ctx context.Context, login string) error {
+	ret := _m.Called(ctx, login)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) error); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteCards provides a mock function with given fields: ctx, cardRequest
 func (_m *Storage) DeleteCards(ctx context.Context, cardRequest internal.Card) error {
 	ret := _m.Called(ctx, cardRequest)
@@ -56,6 +86,48 @@ func (_m *Storage) DeleteCredentials(ctx context.Context, credentialsRequest int
 	return r0
 }
 
+// DeleteFiles provides a mock function with given fields: ctx, fileRequest
+func (_m *Storage) DeleteFiles(ctx context.Context, fileRequest internal.File) error {
+	ret := _m.Called(ctx, fileRequest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File) error); ok {
+		r0 = rf(ctx, fileRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteFolder provides a mock function with given fields: ctx, userName, id
+func (_m *Storage) DeleteFolder(ctx context.Context, userName string, id int64) error {
+	ret := _m.Called(ctx, userName, id)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int64) error); ok {
+		r0 = rf(ctx, userName, id)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteIdentity provides a mock function with given fields: ctx, identityRequest
+func (_m *Storage) DeleteIdentity(ctx context.Context, identityRequest internal.Identity) error {
+	ret := _m.Called(ctx, identityRequest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Identity) error); ok {
+		r0 = rf(ctx, identityRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // DeleteNotes provides a mock function with given fields: ctx, noteRequest
 func (_m *Storage) DeleteNotes(ctx context.Context, noteRequest internal.Note) error {
 	ret := _m.Called(ctx, noteRequest)
@@ -70,6 +142,62 @@ func (_m *Storage) DeleteNotes(ctx context.Context, noteRequest internal.Note) e
 	return r0
 }
 
+// DeleteSSHKey provides a mock function with given fields: ctx, keyRequest
+func (_m *Storage) DeleteSSHKey(ctx context.Context, keyRequest internal.SSHKey) error {
+	ret := _m.Called(ctx, keyRequest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.SSHKey) error); ok {
+		r0 = rf(ctx, keyRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteSecret provides a mock function with given fields: ctx, secretRequest
+func (_m *Storage) DeleteSecret(ctx context.Context, secretRequest internal.Secret) error {
+	ret := _m.Called(ctx, secretRequest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Secret) error); ok {
+		r0 = rf(ctx, secretRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// DeleteTOTP provides a mock function with given fields: ctx, totpRequest
+func (_m *Storage) DeleteTOTP(ctx context.Context, totpRequest internal.TOTP) error {
+	ret := _m.Called(ctx, totpRequest)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.TOTP) error); ok {
+		r0 = rf(ctx, totpRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// EnableTwoFactor provides a mock function with given fields: ctx, login, recoveryCodes
+func (_m *Storage) EnableTwoFactor(ctx context.Context, login string, recoveryCodes []string) error {
+	ret := _m.Called(ctx, login, recoveryCodes)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, login, recoveryCodes)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // GetCard provides a mock function with given fields: ctx, cardRequest
 func (_m *Storage) GetCard(ctx context.Context, cardRequest internal.Card) ([]internal.Card, error) {
 	ret := _m.Called(ctx, cardRequest)
@@ -122,6 +250,188 @@ func (_m *Storage) GetCredentials(ctx context.Context, credentialsRequest intern
 	return r0, r1
 }
 
+// GetDueItems provides a mock function with given fields: ctx, userName, before
+func (_m *Storage) GetDueItems(ctx context.Context, userName string, before time.Time) ([]internal.DueItem, error) {
+	ret := _m.Called(ctx, userName, before)
+
+	var r0 []internal.DueItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]internal.DueItem, error)); ok {
+		return rf(ctx, userName, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []internal.DueItem); ok {
+		r0 = rf(ctx, userName, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.DueItem)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userName, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetExpiringCards provides a mock function with given fields: ctx, userName, before
+func (_m *Storage) GetExpiringCards(ctx context.Context, userName string, before time.Time) ([]internal.ExpiringCard, error) {
+	ret := _m.Called(ctx, userName, before)
+
+	var r0 []internal.ExpiringCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) ([]internal.ExpiringCard, error)); ok {
+		return rf(ctx, userName, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) []internal.ExpiringCard); ok {
+		r0 = rf(ctx, userName, before)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.ExpiringCard)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, time.Time) error); ok {
+		r1 = rf(ctx, userName, before)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFields provides a mock function with given fields: ctx, userName, itemType, ids
+func (_m *Storage) GetFields(ctx context.Context, userName string, itemType string, ids []int64) (map[int64][]internal.Field, error) {
+	ret := _m.Called(ctx, userName, itemType, ids)
+
+	var r0 map[int64][]internal.Field
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []int64) (map[int64][]internal.Field, error)); ok {
+		return rf(ctx, userName, itemType, ids)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, []int64) map[int64][]internal.Field); ok {
+		r0 = rf(ctx, userName, itemType, ids)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[int64][]internal.Field)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, []int64) error); ok {
+		r1 = rf(ctx, userName, itemType, ids)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFileContent provides a mock function with given fields: ctx, fileRequest
+func (_m *Storage) GetFileContent(ctx context.Context, fileRequest internal.File) ([]byte, error) {
+	ret := _m.Called(ctx, fileRequest)
+
+	var r0 []byte
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File) ([]byte, error)); ok {
+		return rf(ctx, fileRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File) []byte); ok {
+		r0 = rf(ctx, fileRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]byte)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.File) error); ok {
+		r1 = rf(ctx, fileRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFiles provides a mock function with given fields: ctx, fileRequest
+func (_m *Storage) GetFiles(ctx context.Context, fileRequest internal.File) ([]internal.File, error) {
+	ret := _m.Called(ctx, fileRequest)
+
+	var r0 []internal.File
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File) ([]internal.File, error)); ok {
+		return rf(ctx, fileRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File) []internal.File); ok {
+		r0 = rf(ctx, fileRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.File)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.File) error); ok {
+		r1 = rf(ctx, fileRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetFolders provides a mock function with given fields: ctx, userName
+func (_m *Storage) GetFolders(ctx context.Context, userName string) ([]internal.Folder, error) {
+	ret := _m.Called(ctx, userName)
+
+	var r0 []internal.Folder
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]internal.Folder, error)); ok {
+		return rf(ctx, userName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []internal.Folder); ok {
+		r0 = rf(ctx, userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Folder)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetIdentities provides a mock function with given fields: ctx, identityRequest, expiresBefore
+func (_m *Storage) GetIdentities(ctx context.Context, identityRequest internal.Identity, expiresBefore string) ([]internal.Identity, error) {
+	ret := _m.Called(ctx, identityRequest, expiresBefore)
+
+	var r0 []internal.Identity
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Identity, string) ([]internal.Identity, error)); ok {
+		return rf(ctx, identityRequest, expiresBefore)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Identity, string) []internal.Identity); ok {
+		r0 = rf(ctx, identityRequest, expiresBefore)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Identity)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Identity, string) error); ok {
+		r1 = rf(ctx, identityRequest, expiresBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetNotes provides a mock function with given fields: ctx, noteRequest
 func (_m *Storage) GetNotes(ctx context.Context, noteRequest internal.Note) ([]internal.Note, error) {
 	ret := _m.Called(ctx, noteRequest)
@@ -148,88 +458,747 @@ func (_m *Storage) GetNotes(ctx context.Context, noteRequest internal.Note) ([]i
 	return r0, r1
 }
 
-// Login provides a mock function with given fields: ctx, login, password
-func (_m *Storage) Login(ctx context.Context, login string, password string) error {
-	ret := _m.Called(ctx, login, password)
+// GetPendingCardExpiries provides a mock function with given fields: ctx, before
+func (_m *Storage) GetPendingCardExpiries(ctx context.Context, before time.Time) ([]internal.ExpiringCard, error) {
+	ret := _m.Called(ctx, before)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, login, password)
+	var r0 []internal.ExpiringCard
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]internal.ExpiringCard, error)); ok {
+		return rf(ctx, before)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []internal.ExpiringCard); ok {
+		r0 = rf(ctx, before)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.ExpiringCard)
+		}
 	}
 
-	return r0
-}
-
-// Register provides a mock function with given fields: ctx, login, password
-func (_m *Storage) Register(ctx context.Context, login string, password string) error {
-	ret := _m.Called(ctx, login, password)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
-		r0 = rf(ctx, login, password)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, before)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// SaveCard provides a mock function with given fields: ctx, card
-func (_m *Storage) SaveCard(ctx context.Context, card internal.Card) error {
-	ret := _m.Called(ctx, card)
+// GetPendingRotations provides a mock function with given fields: ctx, at
+func (_m *Storage) GetPendingRotations(ctx context.Context, at time.Time) ([]internal.DueItem, error) {
+	ret := _m.Called(ctx, at)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, internal.Card) error); ok {
-		r0 = rf(ctx, card)
+	var r0 []internal.DueItem
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) ([]internal.DueItem, error)); ok {
+		return rf(ctx, at)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, time.Time) []internal.DueItem); ok {
+		r0 = rf(ctx, at)
 	} else {
-		r0 = ret.Error(0)
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.DueItem)
+		}
 	}
 
-	return r0
-}
-
-// SaveCredentials provides a mock function with given fields: ctx, credentialsRequest
-func (_m *Storage) SaveCredentials(ctx context.Context, credentialsRequest internal.Credentials) error {
-	ret := _m.Called(ctx, credentialsRequest)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials) error); ok {
-		r0 = rf(ctx, credentialsRequest)
+	if rf, ok := ret.Get(1).(func(context.Context, time.Time) error); ok {
+		r1 = rf(ctx, at)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
 }
 
-// SaveNote provides a mock function with given fields: ctx, note
-func (_m *Storage) SaveNote(ctx context.Context, note internal.Note) error {
-	ret := _m.Called(ctx, note)
+// GetPublicKey provides a mock function with given fields: ctx, login, keyID
+func (_m *Storage) GetPublicKey(ctx context.Context, login string, keyID string) (internal.PublicKey, error) {
+	ret := _m.Called(ctx, login, keyID)
 
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, internal.Note) error); ok {
-		r0 = rf(ctx, note)
+	var r0 internal.PublicKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) (internal.PublicKey, error)); ok {
+		return rf(ctx, login, keyID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) internal.PublicKey); ok {
+		r0 = rf(ctx, login, keyID)
 	} else {
-		r0 = ret.Error(0)
+		r0 = ret.Get(0).(internal.PublicKey)
 	}
 
-	return r0
-}
-
-// UpdateCredentials provides a mock function with given fields: ctx, credentials
-func (_m *Storage) UpdateCredentials(ctx context.Context, credentials internal.Credentials) error {
-	ret := _m.Called(ctx, credentials)
-
-	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials) error); ok {
-		r0 = rf(ctx, credentials)
+	if rf, ok := ret.Get(1).(func(context.Context, string, string) error); ok {
+		r1 = rf(ctx, login, keyID)
 	} else {
-		r0 = ret.Error(0)
+		r1 = ret.Error(1)
 	}
 
-	return r0
+	return r0, r1
+}
+
+// GetSSHKeys provides a mock function with given fields: ctx, keyRequest
+func (_m *Storage) GetSSHKeys(ctx context.Context, keyRequest internal.SSHKey) ([]internal.SSHKey, error) {
+	ret := _m.Called(ctx, keyRequest)
+
+	var r0 []internal.SSHKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.SSHKey) ([]internal.SSHKey, error)); ok {
+		return rf(ctx, keyRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.SSHKey) []internal.SSHKey); ok {
+		r0 = rf(ctx, keyRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.SSHKey)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.SSHKey) error); ok {
+		r1 = rf(ctx, keyRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetSecrets provides a mock function with given fields: ctx, secretRequest
+func (_m *Storage) GetSecrets(ctx context.Context, secretRequest internal.Secret) ([]internal.Secret, error) {
+	ret := _m.Called(ctx, secretRequest)
+
+	var r0 []internal.Secret
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Secret) ([]internal.Secret, error)); ok {
+		return rf(ctx, secretRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Secret) []internal.Secret); ok {
+		r0 = rf(ctx, secretRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Secret)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Secret) error); ok {
+		r1 = rf(ctx, secretRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTOTP provides a mock function with given fields: ctx, totpRequest
+func (_m *Storage) GetTOTP(ctx context.Context, totpRequest internal.TOTP) ([]internal.TOTP, error) {
+	ret := _m.Called(ctx, totpRequest)
+
+	var r0 []internal.TOTP
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.TOTP) ([]internal.TOTP, error)); ok {
+		return rf(ctx, totpRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.TOTP) []internal.TOTP); ok {
+		r0 = rf(ctx, totpRequest)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.TOTP)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.TOTP) error); ok {
+		r1 = rf(ctx, totpRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTags provides a mock function with given fields: ctx, userName
+func (_m *Storage) GetTags(ctx context.Context, userName string) ([]string, error) {
+	ret := _m.Called(ctx, userName)
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]string, error)); ok {
+		return rf(ctx, userName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []string); ok {
+		r0 = rf(ctx, userName)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTwoFactor provides a mock function with given fields: ctx, login
+func (_m *Storage) GetTwoFactor(ctx context.Context, login string) (internal.TwoFactor, error) {
+	ret := _m.Called(ctx, login)
+
+	var r0 internal.TwoFactor
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (internal.TwoFactor, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) internal.TwoFactor); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(internal.TwoFactor)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetVaultKey provides a mock function with given fields: ctx, login
+func (_m *Storage) GetVaultKey(ctx context.Context, login string) (internal.VaultKey, error) {
+	ret := _m.Called(ctx, login)
+
+	var r0 internal.VaultKey
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (internal.VaultKey, error)); ok {
+		return rf(ctx, login)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) internal.VaultKey); ok {
+		r0 = rf(ctx, login)
+	} else {
+		r0 = ret.Get(0).(internal.VaultKey)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, login)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListCards provides a mock function with given fields: ctx, cardRequest, opts
+func (_m *Storage) ListCards(ctx context.Context, cardRequest internal.Card, opts internal.ListOptions) ([]internal.Card, string, error) {
+	ret := _m.Called(ctx, cardRequest, opts)
+
+	var r0 []internal.Card
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Card, internal.ListOptions) ([]internal.Card, string, error)); ok {
+		return rf(ctx, cardRequest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Card, internal.ListOptions) []internal.Card); ok {
+		r0 = rf(ctx, cardRequest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Card)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Card, internal.ListOptions) string); ok {
+		r1 = rf(ctx, cardRequest, opts)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, internal.Card, internal.ListOptions) error); ok {
+		r2 = rf(ctx, cardRequest, opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListCredentials provides a mock function with given fields: ctx, credentialsRequest, opts
+func (_m *Storage) ListCredentials(ctx context.Context, credentialsRequest internal.Credentials, opts internal.ListOptions) ([]internal.Credentials, string, error) {
+	ret := _m.Called(ctx, credentialsRequest, opts)
+
+	var r0 []internal.Credentials
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials, internal.ListOptions) ([]internal.Credentials, string, error)); ok {
+		return rf(ctx, credentialsRequest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials, internal.ListOptions) []internal.Credentials); ok {
+		r0 = rf(ctx, credentialsRequest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Credentials)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Credentials, internal.ListOptions) string); ok {
+		r1 = rf(ctx, credentialsRequest, opts)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, internal.Credentials, internal.ListOptions) error); ok {
+		r2 = rf(ctx, credentialsRequest, opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// ListNotes provides a mock function with given fields: ctx, noteRequest, opts
+func (_m *Storage) ListNotes(ctx context.Context, noteRequest internal.Note, opts internal.ListOptions) ([]internal.Note, string, error) {
+	ret := _m.Called(ctx, noteRequest, opts)
+
+	var r0 []internal.Note
+	var r1 string
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Note, internal.ListOptions) ([]internal.Note, string, error)); ok {
+		return rf(ctx, noteRequest, opts)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Note, internal.ListOptions) []internal.Note); ok {
+		r0 = rf(ctx, noteRequest, opts)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.Note)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Note, internal.ListOptions) string); ok {
+		r1 = rf(ctx, noteRequest, opts)
+	} else {
+		r1 = ret.Get(1).(string)
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, internal.Note, internal.ListOptions) error); ok {
+		r2 = rf(ctx, noteRequest, opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Login provides a mock function with given fields: ctx, login, password
+func (_m *Storage) Login(ctx context.Context, login string, password string) error {
+	ret := _m.Called(ctx, login, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, login, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Register provides a mock function with given fields: ctx, login, password
+func (_m *Storage) Register(ctx context.Context, login string, password string) error {
+	ret := _m.Called(ctx, login, password)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, login, password)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveCard provides a mock function with given fields: ctx, card
+func (_m *Storage) SaveCard(ctx context.Context, card internal.Card) (int64, error) {
+	ret := _m.Called(ctx, card)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Card) (int64, error)); ok {
+		return rf(ctx, card)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Card) int64); ok {
+		r0 = rf(ctx, card)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Card) error); ok {
+		r1 = rf(ctx, card)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveCredentials provides a mock function with given fields: ctx, credentialsRequest
+func (_m *Storage) SaveCredentials(ctx context.Context, credentialsRequest internal.Credentials) (int64, error) {
+	ret := _m.Called(ctx, credentialsRequest)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials) (int64, error)); ok {
+		return rf(ctx, credentialsRequest)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials) int64); ok {
+		r0 = rf(ctx, credentialsRequest)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Credentials) error); ok {
+		r1 = rf(ctx, credentialsRequest)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveFile provides a mock function with given fields: ctx, file, content
+func (_m *Storage) SaveFile(ctx context.Context, file internal.File, content []byte) (int64, error) {
+	ret := _m.Called(ctx, file, content)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File, []byte) (int64, error)); ok {
+		return rf(ctx, file, content)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.File, []byte) int64); ok {
+		r0 = rf(ctx, file, content)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.File, []byte) error); ok {
+		r1 = rf(ctx, file, content)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveFolder provides a mock function with given fields: ctx, folder
+func (_m *Storage) SaveFolder(ctx context.Context, folder internal.Folder) (int64, error) {
+	ret := _m.Called(ctx, folder)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Folder) (int64, error)); ok {
+		return rf(ctx, folder)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Folder) int64); ok {
+		r0 = rf(ctx, folder)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Folder) error); ok {
+		r1 = rf(ctx, folder)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveIdentity provides a mock function with given fields: ctx, identity
+func (_m *Storage) SaveIdentity(ctx context.Context, identity internal.Identity) (int64, error) {
+	ret := _m.Called(ctx, identity)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Identity) (int64, error)); ok {
+		return rf(ctx, identity)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Identity) int64); ok {
+		r0 = rf(ctx, identity)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Identity) error); ok {
+		r1 = rf(ctx, identity)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveNote provides a mock function with given fields: ctx, note
+func (_m *Storage) SaveNote(ctx context.Context, note internal.Note) (int64, error) {
+	ret := _m.Called(ctx, note)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Note) (int64, error)); ok {
+		return rf(ctx, note)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Note) int64); ok {
+		r0 = rf(ctx, note)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Note) error); ok {
+		r1 = rf(ctx, note)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SavePublicKey provides a mock function with given fields: ctx, key
+func (_m *Storage) SavePublicKey(ctx context.Context, key internal.PublicKey) error {
+	ret := _m.Called(ctx, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.PublicKey) error); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveSSHKey provides a mock function with given fields: ctx, key
+func (_m *Storage) SaveSSHKey(ctx context.Context, key internal.SSHKey) (int64, error) {
+	ret := _m.Called(ctx, key)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.SSHKey) (int64, error)); ok {
+		return rf(ctx, key)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.SSHKey) int64); ok {
+		r0 = rf(ctx, key)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.SSHKey) error); ok {
+		r1 = rf(ctx, key)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveSecret provides a mock function with given fields: ctx, secret
+func (_m *Storage) SaveSecret(ctx context.Context, secret internal.Secret) (int64, error) {
+	ret := _m.Called(ctx, secret)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Secret) (int64, error)); ok {
+		return rf(ctx, secret)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Secret) int64); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Secret) error); ok {
+		r1 = rf(ctx, secret)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTOTP provides a mock function with given fields: ctx, totp
+func (_m *Storage) SaveTOTP(ctx context.Context, totp internal.TOTP) (int64, error) {
+	ret := _m.Called(ctx, totp)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.TOTP) (int64, error)); ok {
+		return rf(ctx, totp)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.TOTP) int64); ok {
+		r0 = rf(ctx, totp)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.TOTP) error); ok {
+		r1 = rf(ctx, totp)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SaveTwoFactorSecret provides a mock function with given fields: ctx, login, secret
+func (_m *Storage) SaveTwoFactorSecret(ctx context.Context, login string, secret string) error {
+	ret := _m.Called(ctx, login, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, login, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveVaultKey provides a mock function with given fields: ctx, login, key
+func (_m *Storage) SaveVaultKey(ctx context.Context, login string, key internal.VaultKey) error {
+	ret := _m.Called(ctx, login, key)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, internal.VaultKey) error); ok {
+		r0 = rf(ctx, login, key)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Search provides a mock function with given fields: ctx, userName, query, limit
+func (_m *Storage) Search(ctx context.Context, userName string, query string, limit int) ([]internal.SearchResult, error) {
+	ret := _m.Called(ctx, userName, query, limit)
+
+	var r0 []internal.SearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) ([]internal.SearchResult, error)); ok {
+		return rf(ctx, userName, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int) []internal.SearchResult); ok {
+		r0 = rf(ctx, userName, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]internal.SearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, string, int) error); ok {
+		r1 = rf(ctx, userName, query, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// SetCardExpiryNotified provides a mock function with given fields: ctx, cards
+func (_m *Storage) SetCardExpiryNotified(ctx context.Context, cards []internal.ExpiringCard) error {
+	ret := _m.Called(ctx, cards)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []internal.ExpiringCard) error); ok {
+		r0 = rf(ctx, cards)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLabels provides a mock function with given fields: ctx, userName, itemType, id, labels
+func (_m *Storage) SetLabels(ctx context.Context, userName string, itemType string, id int64, labels internal.Labels) error {
+	ret := _m.Called(ctx, userName, itemType, id, labels)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, int64, internal.Labels) error); ok {
+		r0 = rf(ctx, userName, itemType, id, labels)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetRotationNotified provides a mock function with given fields: ctx, items, at
+func (_m *Storage) SetRotationNotified(ctx context.Context, items []internal.DueItem, at time.Time) error {
+	ret := _m.Called(ctx, items, at)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []internal.DueItem, time.Time) error); ok {
+		r0 = rf(ctx, items, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateCard provides a mock function with given fields: ctx, card
+func (_m *Storage) UpdateCard(ctx context.Context, card internal.Card) (internal.Rotation, error) {
+	ret := _m.Called(ctx, card)
+
+	var r0 internal.Rotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Card) (internal.Rotation, error)); ok {
+		return rf(ctx, card)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Card) internal.Rotation); ok {
+		r0 = rf(ctx, card)
+	} else {
+		r0 = ret.Get(0).(internal.Rotation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Card) error); ok {
+		r1 = rf(ctx, card)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateCredentials provides a mock function with given fields: ctx, credentials
+func (_m *Storage) UpdateCredentials(ctx context.Context, credentials internal.Credentials) (internal.Rotation, error) {
+	ret := _m.Called(ctx, credentials)
+
+	var r0 internal.Rotation
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials) (internal.Rotation, error)); ok {
+		return rf(ctx, credentials)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Credentials) internal.Rotation); ok {
+		r0 = rf(ctx, credentials)
+	} else {
+		r0 = ret.Get(0).(internal.Rotation)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, internal.Credentials) error); ok {
+		r1 = rf(ctx, credentials)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// UpdateFolder provides a mock function with given fields: ctx, folder
+func (_m *Storage) UpdateFolder(ctx context.Context, folder internal.Folder) error {
+	ret := _m.Called(ctx, folder)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Folder) error); ok {
+		r0 = rf(ctx, folder)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
 }
 
 // UpdateNote provides a mock function with given fields: ctx, note
@@ -246,6 +1215,48 @@ func (_m *Storage) UpdateNote(ctx context.Context, note internal.Note) error {
 	return r0
 }
 
+// UpdateSecret provides a mock function with given fields: ctx, secret
+func (_m *Storage) UpdateSecret(ctx context.Context, secret internal.Secret) error {
+	ret := _m.Called(ctx, secret)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, internal.Secret) error); ok {
+		r0 = rf(ctx, secret)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateSignCount provides a mock function with given fields: ctx, login, keyID, signCount
+func (_m *Storage) UpdateSignCount(ctx context.Context, login string, keyID string, signCount uint32) error {
+	ret := _m.Called(ctx, login, keyID, signCount)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, uint32) error); ok {
+		r0 = rf(ctx, login, keyID, signCount)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UseRecoveryCode provides a mock function with given fields: ctx, login, code
+func (_m *Storage) UseRecoveryCode(ctx context.Context, login string, code string) error {
+	ret := _m.Called(ctx, login, code)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, login, code)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 type mockConstructorTestingTNewStorage interface {
 	mock.TestingT
 	Cleanup(func())
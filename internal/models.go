@@ -1,17 +1,59 @@
 package internal
 
-import "github.com/golang-jwt/jwt/v4"
+import (
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
+	"time"
+)
 
 type Credentials struct {
+	ID       int64   `json:"id,omitempty"`
 	UserName string  `json:"user_name"`
 	Login    *string `json:"login,omitempty"`
 	Password *string `json:"password,omitempty"`
 	Metadata *string `json:"metadata,omitempty"`
+	// Fields are the custom fields of the item, they are stored separately from the item.
+	Fields []Field `json:"fields,omitempty"`
+	// CreatedAt and UpdatedAt are returned by the list methods.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Rotation is the password rotation policy, it's returned by the list methods.
+	Rotation
+	// BreachCount is the number of the password appearances in known data breaches,
+	// it's returned by the API on request if the breached password source is configured.
+	BreachCount *int `json:"breach_count,omitempty"`
+	// Labels are returned by the list methods, they are changed separately from the item.
+	Labels
 }
 
 type User struct {
-	Login    string `json:"login"`
-	Password string `json:"password"`
+	Login    string    `json:"login"`
+	Password string    `json:"password"`
+	Vault    *VaultKey `json:"vault,omitempty"`
+}
+
+// VaultKey is the user's vault key wrapped on the client with the password and with the recovery key.
+// The server can't unwrap it, the recovery verifier is a hash of the value derived from the recovery key.
+type VaultKey struct {
+	PasswordSalt       string `json:"password_salt,omitempty"`
+	PasswordWrappedKey string `json:"password_wrapped_key,omitempty"`
+	RecoveryWrappedKey string `json:"recovery_wrapped_key,omitempty"`
+	RecoveryVerifier   string `json:"recovery_verifier,omitempty"`
+}
+
+type RecoveryRequest struct {
+	Login        string    `json:"login"`
+	RecoveryAuth string    `json:"recovery_auth"`
+	NewPassword  string    `json:"new_password,omitempty"`
+	Vault        *VaultKey `json:"vault,omitempty"`
+}
+
+type AccountRequest struct {
+	Login       string    `json:"login"`
+	Password    string    `json:"password"`
+	NewPassword string    `json:"new_password,omitempty"`
+	Code        string    `json:"code,omitempty"`
+	Vault       *VaultKey `json:"vault,omitempty"`
 }
 
 type Claims struct {
@@ -20,19 +62,360 @@ type Claims struct {
 }
 
 type Note struct {
+	ID       int64   `json:"id,omitempty"`
 	UserName string  `json:"user_name"`
 	Title    *string `json:"title,omitempty"`
 	Content  *string `json:"content,omitempty"`
 	Metadata *string `json:"metadata,omitempty"`
+	// Fields are the custom fields of the item, they are stored separately from the item.
+	Fields []Field `json:"fields,omitempty"`
+	// CreatedAt and UpdatedAt are returned by the list methods.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Labels are returned by the list methods, they are changed separately from the item.
+	Labels
 }
 
 type Card struct {
+	ID       int64   `json:"id,omitempty"`
 	UserName string  `json:"user_name"`
 	BankName *string `json:"bank_name,omitempty"`
 	Number   *string `json:"number,omitempty"`
 	CV       *string `json:"cv,omitempty"`
 	Password *string `json:"password,omitempty"`
 	Metadata *string `json:"metadata,omitempty"`
+	// ExpiresOn is the last month of the card validity in YYYY-MM format.
+	ExpiresOn *string `json:"expires_on,omitempty"`
+	// Fields are the custom fields of the item, they are stored separately from the item.
+	Fields []Field `json:"fields,omitempty"`
+	// CreatedAt and UpdatedAt are returned by the list methods.
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	// Rotation is the password rotation policy, it's returned by the list methods.
+	Rotation
+	// Labels are returned by the list methods, they are changed separately from the item.
+	Labels
+}
+
+// TOTP is the authenticator of the user generating time-based one-time passwords (RFC 6238).
+// The secret is base32 encoded, URI is only accepted on creation: the otpauth:// URI is parsed into the other fields.
+type TOTP struct {
+	ID        int64      `json:"id,omitempty"`
+	UserName  string     `json:"user_name"`
+	Name      *string    `json:"name,omitempty"`
+	Issuer    string     `json:"issuer,omitempty"`
+	Account   string     `json:"account,omitempty"`
+	Secret    *string    `json:"secret,omitempty"`
+	Digits    int        `json:"digits,omitempty"`
+	Period    int        `json:"period,omitempty"`
+	Algorithm string     `json:"algorithm,omitempty"`
+	Metadata  *string    `json:"metadata,omitempty"`
+	URI       string     `json:"uri,omitempty"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+}
+
+// TOTPCode is the current one-time password of the authenticator and the number of seconds it remains valid.
+type TOTPCode struct {
+	Code      string `json:"code"`
+	Remaining int    `json:"remaining"`
+}
+
+// SSHKey is the SSH key pair of the user. The private key is in PEM format and is stored encrypted,
+// the type, the public key and the fingerprint are derived from it by the server and are searchable.
+type SSHKey struct {
+	ID          int64      `json:"id,omitempty"`
+	UserName    string     `json:"user_name"`
+	Name        *string    `json:"name,omitempty"`
+	Type        string     `json:"type,omitempty"`
+	PublicKey   string     `json:"public_key,omitempty"`
+	Fingerprint string     `json:"fingerprint,omitempty"`
+	PrivateKey  *string    `json:"private_key,omitempty"`
+	Comment     string     `json:"comment,omitempty"`
+	Metadata    *string    `json:"metadata,omitempty"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty"`
+}
+
+// Identity is the identity document of the user: a passport, an ID card, a driver's licence or a tax ID.
+// The number is stored encrypted, the dates are in YYYY-MM-DD format. Scans are the names of the files
+// of the user with the scanned pages, the files are uploaded through the gRPC API.
+type Identity struct {
+	ID           int64      `json:"id,omitempty"`
+	UserName     string     `json:"user_name"`
+	Name         *string    `json:"name,omitempty"`
+	DocumentType string     `json:"document_type,omitempty"`
+	FullName     string     `json:"full_name,omitempty"`
+	Number       *string    `json:"number,omitempty"`
+	Country      string     `json:"country,omitempty"`
+	IssuedOn     *string    `json:"issued_on,omitempty"`
+	ExpiresOn    *string    `json:"expires_on,omitempty"`
+	Scans        []string   `json:"scans,omitempty"`
+	Metadata     *string    `json:"metadata,omitempty"`
+	CreatedAt    *time.Time `json:"created_at,omitempty"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty"`
+}
+
+// Types of the identity documents.
+const (
+	DocumentTypePassport       = "passport"
+	DocumentTypeIDCard         = "id_card"
+	DocumentTypeDriversLicence = "drivers_licence"
+	DocumentTypeTaxID          = "tax_id"
+	DocumentTypeOther          = "other"
+)
+
+// Secret is the set of key/value pairs of the user, e.g. API tokens of a deployment injected as environment variables.
+// The keys are names of environment variables, the pairs are stored encrypted together.
+type Secret struct {
+	ID        int64             `json:"id,omitempty"`
+	UserName  string            `json:"user_name"`
+	Name      *string           `json:"name,omitempty"`
+	Values    map[string]string `json:"values,omitempty"`
+	Metadata  *string           `json:"metadata,omitempty"`
+	CreatedAt *time.Time        `json:"created_at,omitempty"`
+	UpdatedAt *time.Time        `json:"updated_at,omitempty"`
+}
+
+// File is a binary file of the user. The content is transferred separately from the metadata,
+// so listing files doesn't load them.
+type File struct {
+	ID       int64   `json:"id,omitempty"`
+	UserName string  `json:"user_name"`
+	Name     *string `json:"name,omitempty"`
+	Size     int64   `json:"size,omitempty"`
+	Metadata *string `json:"metadata,omitempty"`
+}
+
+// Envelope wraps every response body of API v2 and error responses of all endpoints, either data or error is set.
+type Envelope struct {
+	Data  any    `json:"data,omitempty"`
+	Page  *Page  `json:"page,omitempty"`
+	Error *Error `json:"error,omitempty"`
+}
+
+// Page describes the page of the list response, the next page is requested with the returned cursor.
+type Page struct {
+	Limit      int    `json:"limit"`
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+// Sort fields of the list methods.
+const (
+	SortByName    = "name"
+	SortByCreated = "created"
+	SortByUpdated = "updated"
+)
+
+// ListOptions selects the page of items returned by the list methods of the storage.
+type ListOptions struct {
+	// Limit is the maximum number of items on the page.
+	Limit int
+	// Cursor is the position after the last item of the previous page, empty for the first page.
+	Cursor string
+	// Sort is one of SortBy* fields, items are ordered by name by default. Descending reverses the order.
+	Sort       string
+	Descending bool
+	// Metadata selects items with the metadata containing the value, case-insensitive.
+	Metadata string
+	// Tags selects items having all the tags.
+	Tags []string
+	// FolderID selects items of the folder and its subfolders.
+	FolderID *int64
+	// Favorite selects favourite or other items.
+	Favorite *bool
+}
+
+// Types of the custom fields.
+const (
+	FieldTypeText    = "text"
+	FieldTypeHidden  = "hidden"
+	FieldTypeBoolean = "boolean"
+	FieldTypeURL     = "url"
+	FieldTypeDate    = "date"
+)
+
+// Field is a typed custom field of the item. Values of hidden fields are stored encrypted like passwords.
+type Field struct {
+	Name  string `json:"name"`
+	Type  string `json:"type"`
+	Value string `json:"value"`
+}
+
+// Labels organize the items of the user: any number of tags, a folder and a favourite flag.
+type Labels struct {
+	Tags     []string `json:"tags,omitempty"`
+	FolderID *int64   `json:"folder_id,omitempty"`
+	Favorite bool     `json:"favorite,omitempty"`
+}
+
+// Rotation is the policy of the regular password change. The password is due for a change RotationDays after
+// PasswordChangedAt, zero days disable the policy. PasswordChangedAt is set by the storage when the password changes.
+type Rotation struct {
+	RotationDays      *int       `json:"rotation_days,omitempty"`
+	PasswordChangedAt *time.Time `json:"password_changed_at,omitempty"`
+}
+
+// DueItem is the credentials or the bank card with the password due for a change according to its rotation policy.
+type DueItem struct {
+	Type              string    `json:"type"`
+	ID                int64     `json:"id"`
+	UserName          string    `json:"user_name"`
+	Name              string    `json:"name"`
+	RotationDays      int       `json:"rotation_days"`
+	PasswordChangedAt time.Time `json:"password_changed_at"`
+	DueAt             time.Time `json:"due_at"`
+}
+
+// ExpiringCard is the bank card which expires soon or has expired. The card is valid through the month of ExpiresOn
+// (YYYY-MM), ExpiresAt is the beginning of the next month. Only the last digits of the number are returned.
+type ExpiringCard struct {
+	ID         int64     `json:"id"`
+	UserName   string    `json:"user_name"`
+	BankName   string    `json:"bank_name"`
+	LastDigits string    `json:"last_digits"`
+	ExpiresOn  string    `json:"expires_on"`
+	ExpiresAt  time.Time `json:"expires_at"`
+}
+
+// Folder groups the items of the user, folders are nested by ParentID. Root folders have no parent.
+type Folder struct {
+	ID       int64  `json:"id,omitempty"`
+	UserName string `json:"user_name"`
+	Name     string `json:"name"`
+	ParentID *int64 `json:"parent_id,omitempty"`
+}
+
+// Types of the vault items.
+const (
+	ItemTypeCredentials = "credentials"
+	ItemTypeNote        = "notes"
+	ItemTypeCard        = "cards"
+	ItemTypeFile        = "files"
+	ItemTypeTOTP        = "totp"
+	ItemTypeSSHKey      = "ssh_keys"
+	ItemTypeIdentity    = "identities"
+	ItemTypeSecret      = "secrets"
+)
+
+// SearchResult is the item found by the search. Only non-secret fields are returned, the item is requested by its type and id.
+type SearchResult struct {
+	Type     string  `json:"type"`
+	ID       int64   `json:"id"`
+	Name     string  `json:"name"`
+	Metadata *string `json:"metadata,omitempty"`
+	// Rank is the relevance of the item, the results are ordered by it.
+	Rank float64 `json:"rank"`
+}
+
+// GeneratedPassword is the password or the passphrase generated by the server.
+type GeneratedPassword struct {
+	Password string `json:"password"`
+}
+
+type TwoFactor struct {
+	Login   string
+	Secret  string
+	Enabled bool
+}
+
+// TwoFactorRequest enables two-factor authentication of the user authorized by the token,
+// the current password is required to start the setup.
+type TwoFactorRequest struct {
+	UserName string `json:"user_name,omitempty"`
+	Password string `json:"password,omitempty"`
+	Code     string `json:"code,omitempty"`
+}
+
+type TwoFactorSetup struct {
+	Secret string `json:"secret"`
+	URI    string `json:"uri"`
+}
+
+type TwoFactorLogin struct {
+	Challenge    string `json:"challenge"`
+	Code         string `json:"code,omitempty"`
+	RecoveryCode string `json:"recovery_code,omitempty"`
+}
+
+type LoginChallenge struct {
+	Challenge string `json:"challenge"`
+}
+
+type RecoveryCodes struct {
+	Codes []string `json:"recovery_codes"`
+}
+
+type PublicKey struct {
+	UserName  string `json:"user_name"`
+	KeyID     string `json:"key_id"`
+	PublicKey string `json:"public_key"`
+	Algorithm string `json:"algorithm"`
+	SignCount uint32 `json:"-"`
+}
+
+// KeyRegistration registers the public key of the user authorized by the token,
+// the current password and the two-factor code if it is enabled are checked again.
+type KeyRegistration struct {
+	PublicKey
+	Password string `json:"password"`
+	Code     string `json:"code,omitempty"`
+}
+
+type KeyLogin struct {
+	Login     string              `json:"login"`
+	Assertion *webauthn.Assertion `json:"assertion,omitempty"`
+}
+
+type UnlockRequest struct {
+	Login   string `json:"login,omitempty"`
+	Address string `json:"address,omitempty"`
+}
+
+// Issues of the credentials found by the health report.
+const (
+	HealthIssueWeak        = "weak"
+	HealthIssueReused      = "reused"
+	HealthIssueOld         = "old"
+	HealthIssueInsecureURL = "insecure_url"
+	HealthIssueBreached    = "breached"
+)
+
+// HealthReport describes the password hygiene of the user's credentials. Only the credentials with issues are listed,
+// the passwords are never included.
+type HealthReport struct {
+	GeneratedAt time.Time `json:"generated_at"`
+	// MinScore and MaxAgeDays are the thresholds of weak and old passwords.
+	MinScore    int                 `json:"min_score"`
+	MaxAgeDays  int                 `json:"max_age_days"`
+	Total       int                 `json:"total"`
+	Summary     HealthSummary       `json:"summary"`
+	Credentials []CredentialsHealth `json:"credentials"`
+	// BreachChecked tells if all the passwords were checked against known data breaches.
+	BreachChecked bool `json:"breach_checked"`
+}
+
+// HealthSummary is the number of the credentials with each issue.
+type HealthSummary struct {
+	Weak         int `json:"weak"`
+	Reused       int `json:"reused"`
+	Old          int `json:"old"`
+	InsecureURLs int `json:"insecure_urls"`
+	Breached     int `json:"breached"`
+}
+
+// CredentialsHealth lists the issues of the credentials: the zxcvbn strength score (0-4) of the password,
+// other credentials with the same password, the age of the password, the custom URL fields using plain http
+// and the number of the password appearances in known data breaches if it was checked.
+type CredentialsHealth struct {
+	ID              int64    `json:"id"`
+	Login           string   `json:"login"`
+	Issues          []string `json:"issues"`
+	Score           int      `json:"score"`
+	ReusedWith      []int64  `json:"reused_with,omitempty"`
+	PasswordAgeDays int      `json:"password_age_days"`
+	InsecureURLs    []string `json:"insecure_urls,omitempty"`
+	BreachCount     *int     `json:"breach_count,omitempty"`
 }
 
 type Params struct {
@@ -44,4 +427,35 @@ type Params struct {
 	ApplicationPort string `envconfig:"APPLICATION_PORT"`
 	ApplicationHost string `envconfig:"APPLICATION_HOST"`
 	EncryptionKey   string `envconfig:"KEEPER_ENCRYPTION_KEY"`
+	// GRPCPort enables gRPC API on provided port alongside HTTP API.
+	GRPCPort string `envconfig:"GRPC_PORT"`
+	// AdminToken enables admin endpoints, e.g. unlocking accounts after too many failed logins.
+	AdminToken string `envconfig:"KEEPER_ADMIN_TOKEN"`
+	// AuthenticatorFile is the location of the software authenticator keys used by the client.
+	AuthenticatorFile string `envconfig:"KEEPER_AUTHENTICATOR_FILE"`
+	// SessionFile is the location of the session tokens saved by the client after login.
+	SessionFile string `envconfig:"KEEPER_SESSION_FILE"`
+	// PasswordMinLength and PasswordMinScore (zxcvbn score from 0 to 4) describe account password policy.
+	PasswordMinLength int `envconfig:"KEEPER_PASSWORD_MIN_LENGTH" default:"8"`
+	PasswordMinScore  int `envconfig:"KEEPER_PASSWORD_MIN_SCORE" default:"2"`
+	// BreachedPasswordsFile or BreachedPasswordsURL enable the check of account and stored passwords against known breaches,
+	// the file contains "HASH:COUNT" lines of SHA-1 hashes, the URL points to a HIBP-compatible range API.
+	// The ranges of the API are cached for BreachCacheTTL.
+	BreachedPasswordsFile string        `envconfig:"KEEPER_BREACHED_PASSWORDS_FILE"`
+	BreachedPasswordsURL  string        `envconfig:"KEEPER_BREACHED_PASSWORDS_URL"`
+	BreachCacheTTL        time.Duration `envconfig:"KEEPER_BREACH_CACHE_TTL" default:"24h"`
+	// ReminderInterval is the period of the checks for the passwords due for rotation and the cards expiring
+	// within CardExpiryDays. The owners are notified with Notifiers: webhook, smtp and log. By default the webhook
+	// is used if NotifyWebhookURL is set, otherwise the notifications are only logged. Zero interval disables the checks.
+	ReminderInterval time.Duration `envconfig:"KEEPER_REMINDER_INTERVAL" default:"1h"`
+	CardExpiryDays   int           `envconfig:"KEEPER_CARD_EXPIRY_DAYS" default:"30"`
+	Notifiers        []string      `envconfig:"KEEPER_NOTIFIERS"`
+	NotifyWebhookURL string        `envconfig:"KEEPER_NOTIFY_WEBHOOK_URL"`
+	// SMTPAddr (host:port) and SMTPFrom enable e-mail notifications. The users with e-mail logins get them,
+	// the notifications of other users go to SMTPTo. SMTPUser and SMTPPassword are used for PLAIN authentication.
+	SMTPAddr     string `envconfig:"KEEPER_SMTP_ADDR"`
+	SMTPFrom     string `envconfig:"KEEPER_SMTP_FROM"`
+	SMTPTo       string `envconfig:"KEEPER_SMTP_TO"`
+	SMTPUser     string `envconfig:"KEEPER_SMTP_USER"`
+	SMTPPassword string `envconfig:"KEEPER_SMTP_PASSWORD"`
 }
@@ -0,0 +1,101 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"go.uber.org/zap"
+	"net/http"
+)
+
+// Events of the notifications.
+const (
+	EventRotationDue  = "rotation_due"
+	EventCardExpiring = "card_expiring"
+)
+
+// Notification tells the owner of the vault about the items needing attention.
+type Notification struct {
+	Event    string `json:"event"`
+	UserName string `json:"user_name"`
+	// Subject and Text describe the event for people, e.g. in the messages of chats.
+	Subject string `json:"subject"`
+	Text    string `json:"text"`
+	// Items are the vault items of the event, the secret fields are never included.
+	Items any `json:"items,omitempty"`
+}
+
+// Notifier delivers the notifications to the users.
+type Notifier interface {
+	Notify(ctx context.Context, notification Notification) error
+}
+
+// multi delivers the notifications by all the notifiers.
+type multi []Notifier
+
+// Multi creates the notifier which delivers the notifications by all the notifiers, the notification fails if any of them fails.
+func Multi(notifiers ...Notifier) Notifier {
+	return multi(notifiers)
+}
+
+func (m multi) Notify(ctx context.Context, notification Notification) error {
+	var errs []error
+	for _, notifier := range m {
+		if err := notifier.Notify(ctx, notification); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// logNotifier writes the notifications to the log of the server.
+type logNotifier struct {
+	logger *zap.SugaredLogger
+}
+
+// NewLog creates the notifier which writes the notifications to the log, e.g. when no delivery is configured.
+func NewLog(logger *zap.SugaredLogger) Notifier {
+	return &logNotifier{logger: logger}
+}
+
+func (l *logNotifier) Notify(_ context.Context, notification Notification) error {
+	l.logger.Infow(notification.Subject, "event", notification.Event, "user_name", notification.UserName, "text", notification.Text)
+	return nil
+}
+
+// webhook posts the notifications as JSON to the URL.
+type webhook struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhook creates the notifier which posts the notifications as JSON to the URL, any 2xx status is a delivery.
+func NewWebhook(url string, client *http.Client) Notifier {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &webhook{url: url, client: client}
+}
+
+func (w *webhook) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("error while encoding notification: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, w.url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("error while creating webhook request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := w.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("error while calling notification webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("error while calling notification webhook: unexpected status %s", resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,62 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestWebhook_Notify(t *testing.T) {
+	notification := Notification{Event: EventRotationDue, UserName: "jaime", Subject: "subject", Text: "text", Items: []string{"item"}}
+
+	t.Run("positive: notification is posted", func(t *testing.T) {
+		var received map[string]any
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.Equal(t, http.MethodPost, r.Method)
+			assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+			w.WriteHeader(http.StatusAccepted)
+		}))
+		defer srv.Close()
+
+		assert.NoError(t, NewWebhook(srv.URL, nil).Notify(context.Background(), notification))
+		assert.Equal(t, map[string]any{"event": "rotation_due", "user_name": "jaime", "subject": "subject", "text": "text",
+			"items": []any{"item"}}, received)
+	})
+	t.Run("negative: unexpected status", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer srv.Close()
+
+		err := NewWebhook(srv.URL, srv.Client()).Notify(context.Background(), notification)
+		assert.EqualError(t, err, "error while calling notification webhook: unexpected status 502 Bad Gateway")
+	})
+}
+
+func TestMulti_Notify(t *testing.T) {
+	var calls int
+	ok := notifierFunc(func(context.Context, Notification) error {
+		calls++
+		return nil
+	})
+	failed := notifierFunc(func(context.Context, Notification) error {
+		calls++
+		return errors.New("delivery error")
+	})
+
+	assert.NoError(t, Multi(ok, ok).Notify(context.Background(), Notification{}))
+	assert.EqualError(t, Multi(failed, ok).Notify(context.Background(), Notification{}), "delivery error")
+	assert.Equal(t, 4, calls)
+}
+
+// notifierFunc adapts the function to Notifier.
+type notifierFunc func(ctx context.Context, notification Notification) error
+
+func (f notifierFunc) Notify(ctx context.Context, notification Notification) error {
+	return f(ctx, notification)
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// smtpNotifier sends the notifications by e-mail.
+type smtpNotifier struct {
+	addr     string
+	from     string
+	to       string
+	user     string
+	password string
+}
+
+// NewSMTP creates the notifier which sends the notifications by e-mail through the SMTP server at addr (host:port).
+// The users with e-mail logins get the notifications themselves, the notifications of other users are sent to `to`.
+// PLAIN authentication is used if the user is set, it requires TLS unless the server is local.
+func NewSMTP(addr, from, to, user, password string) Notifier {
+	return &smtpNotifier{addr: addr, from: from, to: to, user: user, password: password}
+}
+
+func (s *smtpNotifier) Notify(_ context.Context, notification Notification) error {
+	recipient := s.to
+	if strings.Contains(notification.UserName, "@") {
+		recipient = notification.UserName
+	}
+	if recipient == "" {
+		return fmt.Errorf("error while sending notification e-mail: no address of user %q", notification.UserName)
+	}
+	var auth smtp.Auth
+	if s.user != "" {
+		host, _, err := net.SplitHostPort(s.addr)
+		if err != nil {
+			return fmt.Errorf("error while sending notification e-mail: %w", err)
+		}
+		auth = smtp.PlainAuth("", s.user, s.password, host)
+	}
+	if err := smtp.SendMail(s.addr, auth, s.from, []string{recipient}, s.message(recipient, notification)); err != nil {
+		return fmt.Errorf("error while sending notification e-mail: %w", err)
+	}
+	return nil
+}
+
+func (s *smtpNotifier) message(recipient string, notification Notification) []byte {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", s.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", recipient)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", headerValue(notification.Subject))
+	fmt.Fprintf(&msg, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=utf-8\r\n\r\n")
+	msg.WriteString(strings.ReplaceAll(notification.Text, "\n", "\r\n"))
+	return []byte(msg.String())
+}
+
+// headerValue removes the line breaks, so the value can't inject other headers.
+func headerValue(value string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(value)
+}
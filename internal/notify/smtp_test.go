@@ -0,0 +1,104 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"github.com/stretchr/testify/assert"
+	"net"
+	"net/textproto"
+	"strings"
+	"testing"
+)
+
+// mail is the message received by the test SMTP server.
+type mail struct {
+	from string
+	to   []string
+	data string
+}
+
+// newSMTPServer starts the minimal SMTP server which accepts one message, the message is sent to the channel.
+func newSMTPServer(t *testing.T) (string, <-chan mail) {
+	t.Helper()
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("an error '%s' was not expected when starting a stub smtp server", err)
+	}
+	t.Cleanup(func() { _ = listener.Close() })
+
+	mails := make(chan mail, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		text := textproto.NewConn(conn)
+		var received mail
+		_ = text.PrintfLine("220 localhost ESMTP")
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(strings.SplitN(line, " ", 2)[0])
+			switch command {
+			case "EHLO", "HELO":
+				_ = text.PrintfLine("250 localhost")
+			case "MAIL":
+				received.from = strings.Trim(strings.TrimPrefix(line, "MAIL FROM:"), "<>")
+				_ = text.PrintfLine("250 OK")
+			case "RCPT":
+				received.to = append(received.to, strings.Trim(strings.TrimPrefix(line, "RCPT TO:"), "<>"))
+				_ = text.PrintfLine("250 OK")
+			case "DATA":
+				_ = text.PrintfLine("354 go ahead")
+				data, err := text.ReadDotBytes()
+				if err != nil {
+					return
+				}
+				received.data = string(data)
+				_ = text.PrintfLine("250 OK")
+			case "QUIT":
+				_ = text.PrintfLine("221 bye")
+				mails <- received
+				return
+			default:
+				_ = text.PrintfLine("250 OK")
+			}
+		}
+	}()
+	return listener.Addr().String(), mails
+}
+
+func TestSMTP_Notify(t *testing.T) {
+	notification := Notification{Event: EventCardExpiring, Subject: "Bank cards\nexpiring", Text: "1 card(s) expire:\n- iron bank *4444\n"}
+
+	t.Run("positive: mail to user with e-mail login", func(t *testing.T) {
+		addr, mails := newSMTPServer(t)
+		notification.UserName = "tyrion@lannister.example"
+
+		assert.NoError(t, NewSMTP(addr, "keeper@example.com", "admin@example.com", "", "").Notify(context.Background(), notification))
+		received := <-mails
+		assert.Equal(t, "keeper@example.com", received.from)
+		assert.Equal(t, []string{"tyrion@lannister.example"}, received.to)
+		msg, err := textproto.NewReader(bufio.NewReader(strings.NewReader(received.data))).ReadMIMEHeader()
+		assert.NoError(t, err)
+		assert.Equal(t, "Bank cards expiring", msg.Get("Subject"))
+		assert.Equal(t, "tyrion@lannister.example", msg.Get("To"))
+		assert.Contains(t, received.data, "- iron bank *4444\n")
+	})
+	t.Run("positive: mail of other users to fallback address", func(t *testing.T) {
+		addr, mails := newSMTPServer(t)
+		notification.UserName = "tyrion"
+
+		assert.NoError(t, NewSMTP(addr, "keeper@example.com", "admin@example.com", "", "").Notify(context.Background(), notification))
+		assert.Equal(t, []string{"admin@example.com"}, (<-mails).to)
+	})
+	t.Run("negative: no address of user", func(t *testing.T) {
+		notification.UserName = "tyrion"
+
+		err := NewSMTP("127.0.0.1:25", "keeper@example.com", "", "", "").Notify(context.Background(), notification)
+		assert.EqualError(t, err, "error while sending notification e-mail: no address of user \"tyrion\"")
+	})
+}
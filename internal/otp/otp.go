@@ -0,0 +1,243 @@
+package otp
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base32"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	DefaultDigits    = 6
+	DefaultPeriod    = 30
+	DefaultAlgorithm = "SHA1"
+	// secretSize is the length of generated secrets in bytes, as recommended by RFC 4226.
+	secretSize = 20
+	// skew is the number of periods before and after the current one which are still accepted.
+	skew = 1
+)
+
+var (
+	ErrInvalidSecret    = errors.New("invalid otp secret")
+	ErrInvalidAlgorithm = errors.New("unsupported otp algorithm")
+	ErrInvalidURI       = errors.New("invalid otpauth uri")
+)
+
+var encoding = base32.StdEncoding.WithPadding(base32.NoPadding)
+
+// Key describes a time-based one-time password generator (RFC 6238).
+type Key struct {
+	Secret    string
+	Issuer    string
+	Account   string
+	Digits    int
+	Period    int
+	Algorithm string
+}
+
+// NewKey returns a key with a freshly generated secret and default parameters.
+func NewKey(issuer, account string) (Key, error) {
+	secret, err := GenerateSecret()
+	if err != nil {
+		return Key{}, err
+	}
+	return Key{
+		Secret:    secret,
+		Issuer:    issuer,
+		Account:   account,
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+		Algorithm: DefaultAlgorithm,
+	}, nil
+}
+
+// GenerateSecret returns a random base32 encoded secret.
+func GenerateSecret() (string, error) {
+	secret := make([]byte, secretSize)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("error while generating otp secret: %w", err)
+	}
+	return encoding.EncodeToString(secret), nil
+}
+
+// Code returns the one-time password for the provided moment.
+func (k Key) Code(t time.Time) (string, error) {
+	return k.code(uint64(t.Unix()) / uint64(k.period()))
+}
+
+// Remaining returns the time left until the code for the provided moment expires.
+func (k Key) Remaining(t time.Time) time.Duration {
+	period := int64(k.period())
+	return time.Duration(period-t.Unix()%period) * time.Second
+}
+
+// Validate checks the provided code against the codes for the provided moment
+// and the adjacent periods to tolerate clock drift.
+func (k Key) Validate(code string, t time.Time) bool {
+	_, ok := k.ValidateStep(code, t)
+	return ok
+}
+
+// ValidateStep is Validate which also returns the time step of the matched code,
+// so the caller can refuse the code of the step which was already accepted.
+func (k Key) ValidateStep(code string, t time.Time) (int64, bool) {
+	counter := int64(t.Unix()) / int64(k.period())
+	for i := -skew; i <= skew; i++ {
+		expected, err := k.code(uint64(counter + int64(i)))
+		if err != nil {
+			return 0, false
+		}
+		if hmac.Equal([]byte(expected), []byte(code)) {
+			return counter + int64(i), true
+		}
+	}
+	return 0, false
+}
+
+// StepExpires returns the moment after which the code of the time step is no longer accepted.
+func (k Key) StepExpires(step int64) time.Time {
+	return time.Unix((step+skew+1)*int64(k.period()), 0)
+}
+
+// URI returns the key in the otpauth:// format understood by authenticator apps.
+func (k Key) URI() string {
+	label := k.Account
+	if k.Issuer != "" {
+		label = k.Issuer + ":" + k.Account
+	}
+	params := url.Values{}
+	params.Set("secret", k.Secret)
+	if k.Issuer != "" {
+		params.Set("issuer", k.Issuer)
+	}
+	params.Set("algorithm", k.algorithm())
+	params.Set("digits", strconv.Itoa(k.digits()))
+	params.Set("period", strconv.Itoa(k.period()))
+	u := url.URL{
+		Scheme:   "otpauth",
+		Host:     "totp",
+		Path:     "/" + label,
+		RawQuery: params.Encode(),
+	}
+	return u.String()
+}
+
+// ParseURI parses the otpauth://totp/ uri into a key.
+func ParseURI(uri string) (Key, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return Key{}, fmt.Errorf("%w: %s", ErrInvalidURI, err)
+	}
+	if u.Scheme != "otpauth" || u.Host != "totp" {
+		return Key{}, fmt.Errorf("%w: only otpauth://totp/ uris are supported", ErrInvalidURI)
+	}
+	key := Key{
+		Account:   strings.TrimPrefix(u.Path, "/"),
+		Digits:    DefaultDigits,
+		Period:    DefaultPeriod,
+		Algorithm: DefaultAlgorithm,
+	}
+	if issuer, account, found := strings.Cut(key.Account, ":"); found {
+		key.Issuer, key.Account = issuer, strings.TrimSpace(account)
+	}
+	params := u.Query()
+	key.Secret = strings.ToUpper(params.Get("secret"))
+	if _, err = key.secret(); err != nil {
+		return Key{}, err
+	}
+	if issuer := params.Get("issuer"); issuer != "" {
+		key.Issuer = issuer
+	}
+	if algorithm := params.Get("algorithm"); algorithm != "" {
+		key.Algorithm = strings.ToUpper(algorithm)
+		if _, err = key.hash(); err != nil {
+			return Key{}, err
+		}
+	}
+	if digits := params.Get("digits"); digits != "" {
+		if key.Digits, err = strconv.Atoi(digits); err != nil || key.Digits < 6 || key.Digits > 8 {
+			return Key{}, fmt.Errorf("%w: digits should be between 6 and 8", ErrInvalidURI)
+		}
+	}
+	if period := params.Get("period"); period != "" {
+		if key.Period, err = strconv.Atoi(period); err != nil || key.Period <= 0 {
+			return Key{}, fmt.Errorf("%w: period should be a positive number", ErrInvalidURI)
+		}
+	}
+	return key, nil
+}
+
+func (k Key) code(counter uint64) (string, error) {
+	secret, err := k.secret()
+	if err != nil {
+		return "", err
+	}
+	h, err := k.hash()
+	if err != nil {
+		return "", err
+	}
+	var msg [8]byte
+	binary.BigEndian.PutUint64(msg[:], counter)
+	mac := hmac.New(h, secret)
+	mac.Write(msg[:])
+	sum := mac.Sum(nil)
+
+	// dynamic truncation, see RFC 4226 section 5.3
+	offset := sum[len(sum)-1] & 0x0f
+	value := binary.BigEndian.Uint32(sum[offset:offset+4]) & 0x7fffffff
+	digits := k.digits()
+	return fmt.Sprintf("%0*d", digits, value%uint32(math.Pow10(digits))), nil
+}
+
+func (k Key) secret() ([]byte, error) {
+	normalized := strings.ToUpper(strings.ReplaceAll(strings.TrimRight(k.Secret, "="), " ", ""))
+	secret, err := encoding.DecodeString(normalized)
+	if err != nil || len(secret) == 0 {
+		return nil, ErrInvalidSecret
+	}
+	return secret, nil
+}
+
+func (k Key) hash() (func() hash.Hash, error) {
+	switch k.algorithm() {
+	case "SHA1":
+		return sha1.New, nil
+	case "SHA256":
+		return sha256.New, nil
+	case "SHA512":
+		return sha512.New, nil
+	}
+	return nil, fmt.Errorf("%w: %q", ErrInvalidAlgorithm, k.Algorithm)
+}
+
+func (k Key) digits() int {
+	if k.Digits == 0 {
+		return DefaultDigits
+	}
+	return k.Digits
+}
+
+func (k Key) period() int {
+	if k.Period == 0 {
+		return DefaultPeriod
+	}
+	return k.Period
+}
+
+func (k Key) algorithm() string {
+	if k.Algorithm == "" {
+		return DefaultAlgorithm
+	}
+	return strings.ToUpper(k.Algorithm)
+}
@@ -0,0 +1,119 @@
+package otp
+
+import (
+	"encoding/base32"
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestKey_Code(t *testing.T) {
+	// test vectors from RFC 6238, appendix B
+	secrets := map[string]string{
+		"SHA1":   base32.StdEncoding.EncodeToString([]byte("12345678901234567890")),
+		"SHA256": base32.StdEncoding.EncodeToString([]byte("12345678901234567890123456789012")),
+		"SHA512": base32.StdEncoding.EncodeToString([]byte("1234567890123456789012345678901234567890123456789012345678901234")),
+	}
+	testCases := []struct {
+		unix     int64
+		expected map[string]string
+	}{
+		{unix: 59, expected: map[string]string{"SHA1": "94287082", "SHA256": "46119246", "SHA512": "90693936"}},
+		{unix: 1111111109, expected: map[string]string{"SHA1": "07081804", "SHA256": "68084774", "SHA512": "25091201"}},
+		{unix: 1111111111, expected: map[string]string{"SHA1": "14050471", "SHA256": "67062674", "SHA512": "99943326"}},
+		{unix: 1234567890, expected: map[string]string{"SHA1": "89005924", "SHA256": "91819424", "SHA512": "93441116"}},
+		{unix: 2000000000, expected: map[string]string{"SHA1": "69279037", "SHA256": "90698825", "SHA512": "38618901"}},
+		{unix: 20000000000, expected: map[string]string{"SHA1": "65353130", "SHA256": "77737706", "SHA512": "47863826"}},
+	}
+	for _, tt := range testCases {
+		for algorithm, expected := range tt.expected {
+			key := Key{Secret: secrets[algorithm], Digits: 8, Period: 30, Algorithm: algorithm}
+			code, err := key.Code(time.Unix(tt.unix, 0))
+			assert.NoError(t, err)
+			assert.Equal(t, expected, code, "%s at %d", algorithm, tt.unix)
+		}
+	}
+	t.Run("negative: invalid secret", func(t *testing.T) {
+		_, err := Key{Secret: "not base32!"}.Code(time.Now())
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+	})
+	t.Run("negative: unsupported algorithm", func(t *testing.T) {
+		_, err := Key{Secret: secrets["SHA1"], Algorithm: "MD5"}.Code(time.Now())
+		assert.ErrorIs(t, err, ErrInvalidAlgorithm)
+	})
+}
+
+func TestKey_Validate(t *testing.T) {
+	key, err := NewKey("goph-keeper", "arya")
+	assert.NoError(t, err)
+	now := time.Now()
+
+	code, err := key.Code(now)
+	assert.NoError(t, err)
+	assert.True(t, key.Validate(code, now))
+
+	previous, err := key.Code(now.Add(-30 * time.Second))
+	assert.NoError(t, err)
+	assert.True(t, key.Validate(previous, now))
+
+	stale, err := key.Code(now.Add(-5 * time.Minute))
+	assert.NoError(t, err)
+	assert.False(t, key.Validate(stale, now))
+	assert.False(t, key.Validate("", now))
+}
+
+func TestKey_ValidateStep(t *testing.T) {
+	key, err := NewKey("goph-keeper", "arya")
+	assert.NoError(t, err)
+	now := time.Unix(95, 0)
+
+	previous, err := key.Code(now.Add(-30 * time.Second))
+	assert.NoError(t, err)
+	step, ok := key.ValidateStep(previous, now)
+	assert.True(t, ok)
+	assert.Equal(t, int64(2), step)
+	assert.Equal(t, time.Unix(120, 0), key.StepExpires(step))
+
+	_, ok = key.ValidateStep("000000x", now)
+	assert.False(t, ok)
+}
+
+func TestKey_Remaining(t *testing.T) {
+	key := Key{Period: 30}
+	assert.Equal(t, 30*time.Second, key.Remaining(time.Unix(60, 0)))
+	assert.Equal(t, 11*time.Second, key.Remaining(time.Unix(79, 0)))
+}
+
+func TestParseURI(t *testing.T) {
+	t.Run("positive: round trip", func(t *testing.T) {
+		key, err := NewKey("goph-keeper", "brienne")
+		assert.NoError(t, err)
+		parsed, err := ParseURI(key.URI())
+		assert.NoError(t, err)
+		assert.Equal(t, key, parsed)
+	})
+	t.Run("positive: defaults", func(t *testing.T) {
+		key, err := ParseURI("otpauth://totp/ACME%20Co:john@example.com?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&issuer=ACME%20Co")
+		assert.NoError(t, err)
+		assert.Equal(t, Key{
+			Secret:    "HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ",
+			Issuer:    "ACME Co",
+			Account:   "john@example.com",
+			Digits:    DefaultDigits,
+			Period:    DefaultPeriod,
+			Algorithm: DefaultAlgorithm,
+		}, key)
+	})
+	t.Run("negative: hotp", func(t *testing.T) {
+		_, err := ParseURI("otpauth://hotp/john?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&counter=1")
+		assert.ErrorIs(t, err, ErrInvalidURI)
+	})
+	t.Run("negative: no secret", func(t *testing.T) {
+		_, err := ParseURI("otpauth://totp/john")
+		assert.ErrorIs(t, err, ErrInvalidSecret)
+	})
+	t.Run("negative: invalid digits", func(t *testing.T) {
+		_, err := ParseURI("otpauth://totp/john?secret=HXDMVJECJJWSRB3HWIZR4IFUGFTMXBOZ&digits=12")
+		assert.ErrorIs(t, err, ErrInvalidURI)
+	})
+}
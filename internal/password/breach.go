@@ -0,0 +1,215 @@
+package password
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// prefixLength is the number of hash characters sent to the range service,
+	// the rest of the hash never leaves the process (k-anonymity).
+	prefixLength = 5
+	// maxCachedRanges limits the memory of the cached source, a range has about a thousand suffixes.
+	maxCachedRanges = 1024
+)
+
+// RangeSource returns breached password hash suffixes with the number of occurrences
+// for the provided upper-case SHA-1 prefix.
+type RangeSource interface {
+	Range(ctx context.Context, prefix string) (map[string]int, error)
+}
+
+// BreachChecker checks passwords against breached password range source.
+type BreachChecker struct {
+	source RangeSource
+}
+
+func NewBreachChecker(source RangeSource) *BreachChecker {
+	return &BreachChecker{source: source}
+}
+
+// Count returns how many times the password has appeared in known data breaches.
+func (c *BreachChecker) Count(ctx context.Context, password string) (int, error) {
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	suffixes, err := c.source.Range(ctx, hash[:prefixLength])
+	if err != nil {
+		return 0, err
+	}
+	return suffixes[hash[prefixLength:]], nil
+}
+
+// Check returns ErrBreached if the password has appeared in known data breaches.
+func (c *BreachChecker) Check(ctx context.Context, password string) error {
+	count, err := c.Count(ctx, password)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return fmt.Errorf("%w %d times, choose another one", ErrBreached, count)
+	}
+	return nil
+}
+
+// cachedSource keeps the ranges of the source for the TTL, the oldest ranges are evicted when the cache is full.
+type cachedSource struct {
+	source RangeSource
+	ttl    time.Duration
+	now    func() time.Time
+
+	mu     sync.Mutex
+	ranges map[string]cachedRange
+	order  []string
+}
+
+type cachedRange struct {
+	suffixes map[string]int
+	expires  time.Time
+}
+
+// NewCachedSource creates range source which caches the ranges of the source for the TTL.
+// Only the public ranges are cached, the errors of the source are not.
+func NewCachedSource(source RangeSource, ttl time.Duration) RangeSource {
+	return &cachedSource{source: source, ttl: ttl, now: time.Now, ranges: make(map[string]cachedRange)}
+}
+
+func (s *cachedSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	s.mu.Lock()
+	cached, ok := s.ranges[prefix]
+	s.mu.Unlock()
+	if ok && s.now().Before(cached.expires) {
+		return cached.suffixes, nil
+	}
+	suffixes, err := s.source.Range(ctx, prefix)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok = s.ranges[prefix]; !ok {
+		if len(s.order) >= maxCachedRanges {
+			delete(s.ranges, s.order[0])
+			s.order = s.order[1:]
+		}
+		s.order = append(s.order, prefix)
+	}
+	s.ranges[prefix] = cachedRange{suffixes: suffixes, expires: s.now().Add(s.ttl)}
+	return suffixes, nil
+}
+
+// fileSource is a local breached password list with "HASH:COUNT" lines of upper-case SHA-1 hashes.
+type fileSource struct {
+	path string
+}
+
+// NewFileSource creates range source from the local file in the "HASH:COUNT" format,
+// lines starting with # are ignored.
+func NewFileSource(path string) RangeSource {
+	return &fileSource{path: path}
+}
+
+func (s *fileSource) Range(_ context.Context, prefix string) (map[string]int, error) {
+	file, err := os.Open(s.path)
+	if err != nil {
+		return nil, fmt.Errorf("error while opening breached passwords file: %w", err)
+	}
+	defer file.Close()
+
+	suffixes := make(map[string]int)
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		hash, count, err := parseRangeLine(line)
+		if err != nil {
+			return nil, err
+		}
+		if len(hash) > prefixLength && strings.HasPrefix(hash, prefix) {
+			suffixes[hash[prefixLength:]] = count
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error while reading breached passwords file: %w", err)
+	}
+	return suffixes, nil
+}
+
+// httpSource is a HIBP-compatible range API, e.g. https://api.pwnedpasswords.com.
+type httpSource struct {
+	baseURL string
+	client  *http.Client
+}
+
+// NewHTTPSource creates range source which requests {baseURL}/range/{prefix}.
+func NewHTTPSource(baseURL string, client *http.Client) RangeSource {
+	if client == nil {
+		client = http.DefaultClient
+	}
+	return &httpSource{baseURL: strings.TrimSuffix(baseURL, "/"), client: client}
+}
+
+func (s *httpSource) Range(ctx context.Context, prefix string) (map[string]int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, fmt.Sprintf("%s/range/%s", s.baseURL, prefix), nil)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating range request: %w", err)
+	}
+	// padding hides the real number of suffixes for the prefix from observers
+	req.Header.Set("Add-Padding", "true")
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("error while requesting breached passwords range: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("error while requesting breached passwords range: unexpected status %s", resp.Status)
+	}
+	return parseRange(resp.Body)
+}
+
+func parseRange(r io.Reader) (map[string]int, error) {
+	suffixes := make(map[string]int)
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		suffix, count, err := parseRangeLine(line)
+		if err != nil {
+			return nil, err
+		}
+		// padding entries have zero count
+		if count > 0 {
+			suffixes[suffix] = count
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("error while reading breached passwords range: %w", err)
+	}
+	return suffixes, nil
+}
+
+func parseRangeLine(line string) (string, int, error) {
+	hash, countStr, ok := strings.Cut(line, ":")
+	if !ok {
+		return "", 0, fmt.Errorf("invalid breached passwords line %q", line)
+	}
+	count, err := strconv.Atoi(countStr)
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid breached passwords count in line %q: %w", line, err)
+	}
+	return strings.ToUpper(hash), count, nil
+}
@@ -0,0 +1,153 @@
+package password
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal/wordlist"
+	"math"
+	"math/big"
+	"strings"
+)
+
+const (
+	// DefaultGenerateLength is the length of generated passwords if it's not provided.
+	DefaultGenerateLength = 20
+	// DefaultSeparator joins the words of generated passphrases if it's not provided.
+	DefaultSeparator = "-"
+	// minGenerateBits is the minimal entropy of generated passwords and passphrases: passwords of the smaller
+	// alphabets should be longer, e.g. 14 characters of digits alone, and passphrases have 4 words at least
+	// (11 bits per word).
+	minGenerateBits   = 44
+	minGenerateLength = 8
+	maxGenerateLength = 128
+	minWords          = 4
+	maxWords          = 20
+)
+
+// Character classes of generated passwords.
+const (
+	lowerChars   = "abcdefghijklmnopqrstuvwxyz"
+	upperChars   = "ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+	digitChars   = "0123456789"
+	symbolChars  = "!#$%&*+-=?@^_~"
+	similarChars = "Il1O0o|"
+)
+
+var ErrInvalidOptions = errors.New("invalid generator options")
+
+// GenerateOptions describes the generated password. With Words set a diceware passphrase of the words
+// from the BIP39 English list is generated, the character options are ignored then.
+// If no character class is chosen, all of them are used.
+type GenerateOptions struct {
+	Length           int    `json:"length,omitempty"`
+	Lower            bool   `json:"lower,omitempty"`
+	Upper            bool   `json:"upper,omitempty"`
+	Digits           bool   `json:"digits,omitempty"`
+	Symbols          bool   `json:"symbols,omitempty"`
+	ExcludeAmbiguous bool   `json:"exclude_ambiguous,omitempty"`
+	Words            int    `json:"words,omitempty"`
+	Separator        string `json:"separator,omitempty"`
+}
+
+// Generate returns a random password or passphrase described by the options, crypto/rand is the source of randomness.
+// Every chosen character class is present in the password.
+func Generate(opts GenerateOptions) (string, error) {
+	if opts.Words != 0 {
+		return passphrase(opts)
+	}
+	length := opts.Length
+	if length == 0 {
+		length = DefaultGenerateLength
+	}
+	if length < minGenerateLength || length > maxGenerateLength {
+		return "", fmt.Errorf("%w: length should be from %d to %d", ErrInvalidOptions, minGenerateLength, maxGenerateLength)
+	}
+	if !opts.Lower && !opts.Upper && !opts.Digits && !opts.Symbols {
+		opts.Lower, opts.Upper, opts.Digits, opts.Symbols = true, true, true, true
+	}
+	var classes []string
+	for _, class := range []struct {
+		chars  string
+		chosen bool
+	}{{lowerChars, opts.Lower}, {upperChars, opts.Upper}, {digitChars, opts.Digits}, {symbolChars, opts.Symbols}} {
+		if !class.chosen {
+			continue
+		}
+		chars := class.chars
+		if opts.ExcludeAmbiguous {
+			chars = strings.Map(func(r rune) rune {
+				if strings.ContainsRune(similarChars, r) {
+					return -1
+				}
+				return r
+			}, chars)
+		}
+		classes = append(classes, chars)
+	}
+	all := strings.Join(classes, "")
+	if minLength := int(math.Ceil(minGenerateBits / math.Log2(float64(len(all))))); length < minLength {
+		return "", fmt.Errorf("%w: length should be at least %d for the chosen characters", ErrInvalidOptions, minLength)
+	}
+
+	// one character of every class, the rest from all of them, then shuffled
+	password := make([]byte, 0, length)
+	for _, chars := range classes {
+		c, err := randomChar(chars)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for len(password) < length {
+		c, err := randomChar(all)
+		if err != nil {
+			return "", err
+		}
+		password = append(password, c)
+	}
+	for i := len(password) - 1; i > 0; i-- {
+		j, err := randomInt(i + 1)
+		if err != nil {
+			return "", err
+		}
+		password[i], password[j] = password[j], password[i]
+	}
+	return string(password), nil
+}
+
+// passphrase returns the words chosen from the BIP39 English list, 11 bits of entropy each.
+func passphrase(opts GenerateOptions) (string, error) {
+	if opts.Words < minWords || opts.Words > maxWords {
+		return "", fmt.Errorf("%w: number of words should be from %d to %d", ErrInvalidOptions, minWords, maxWords)
+	}
+	separator := opts.Separator
+	if separator == "" {
+		separator = DefaultSeparator
+	}
+	words := make([]string, 0, opts.Words)
+	for len(words) < opts.Words {
+		i, err := randomInt(len(wordlist.English))
+		if err != nil {
+			return "", err
+		}
+		words = append(words, wordlist.English[i])
+	}
+	return strings.Join(words, separator), nil
+}
+
+func randomChar(chars string) (byte, error) {
+	i, err := randomInt(len(chars))
+	if err != nil {
+		return 0, err
+	}
+	return chars[i], nil
+}
+
+func randomInt(n int) (int, error) {
+	i, err := rand.Int(rand.Reader, big.NewInt(int64(n)))
+	if err != nil {
+		return 0, fmt.Errorf("error while generating random number: %w", err)
+	}
+	return int(i.Int64()), nil
+}
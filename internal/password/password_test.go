@@ -0,0 +1,184 @@
+package password
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal/wordlist"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestPolicy_Check(t *testing.T) {
+	policy := Policy{MinLength: DefaultMinLength, MinScore: DefaultMinScore}
+
+	testCases := []struct {
+		name          string
+		password      string
+		userInputs    []string
+		expectedError error
+	}{
+		{
+			name:     "positive: strong password",
+			password: "correct horse battery staple",
+		},
+		{
+			name:          "negative: too short",
+			password:      "x7#Qa",
+			expectedError: ErrTooShort,
+		},
+		{
+			name:          "negative: too long",
+			password:      "correct horse battery staple correct horse battery staple correct horse battery",
+			expectedError: ErrTooLong,
+		},
+		{
+			name:          "negative: common password",
+			password:      "password123",
+			expectedError: ErrTooWeak,
+		},
+		{
+			name:          "negative: password made of login",
+			password:      "tyrion.lannister",
+			userInputs:    []string{"tyrion.lannister"},
+			expectedError: ErrTooWeak,
+		},
+	}
+	for _, tt := range testCases {
+		t.Run(tt.name, func(t *testing.T) {
+			err := policy.Check(tt.password, tt.userInputs...)
+			if tt.expectedError != nil {
+				assert.ErrorIs(t, err, tt.expectedError)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+	t.Run("positive: zero policy", func(t *testing.T) {
+		assert.NoError(t, Policy{}.Check("1"))
+	})
+}
+
+func TestBreachChecker_File(t *testing.T) {
+	checker := NewBreachChecker(NewFileSource("testdata/breached.txt"))
+	ctx := context.Background()
+
+	count, err := checker.Count(ctx, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, 9545824, count)
+	assert.ErrorIs(t, checker.Check(ctx, "qwerty"), ErrBreached)
+	assert.NoError(t, checker.Check(ctx, "correct horse battery staple"))
+
+	_, err = NewBreachChecker(NewFileSource("testdata/missing.txt")).Count(ctx, "password")
+	assert.Error(t, err)
+}
+
+func TestBreachChecker_HTTP(t *testing.T) {
+	var requested []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requested = append(requested, r.URL.Path)
+		assert.Equal(t, "true", r.Header.Get("Add-Padding"))
+		if r.URL.Path != "/range/5BAA6" {
+			fmt.Fprint(w, "0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n")
+			return
+		}
+		fmt.Fprint(w, "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n0018A45C4D1DEF81644B54AB7F969B88D65:0\r\n")
+	}))
+	defer srv.Close()
+
+	checker := NewBreachChecker(NewHTTPSource(srv.URL, srv.Client()))
+	ctx := context.Background()
+
+	count, err := checker.Count(ctx, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, 9545824, count)
+	assert.NoError(t, checker.Check(ctx, "correct horse battery staple"))
+	// only hash prefixes are sent
+	assert.Equal(t, []string{"/range/5BAA6", "/range/ABF7A"}, requested)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer failing.Close()
+	_, err = NewBreachChecker(NewHTTPSource(failing.URL, nil)).Count(ctx, "password")
+	assert.Error(t, err)
+}
+
+func TestCachedSource(t *testing.T) {
+	var requests int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		if requests == 2 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		fmt.Fprint(w, "1E4C9B93F3F0682250B6CF8331B7EE68FD8:9545824\r\n")
+	}))
+	defer srv.Close()
+
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	source := NewCachedSource(NewHTTPSource(srv.URL, srv.Client()), time.Hour)
+	source.(*cachedSource).now = func() time.Time { return now }
+	checker := NewBreachChecker(source)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		count, err := checker.Count(ctx, "password")
+		assert.NoError(t, err)
+		assert.Equal(t, 9545824, count)
+	}
+	assert.Equal(t, 1, requests)
+
+	// expired range is requested again, errors are not cached
+	now = now.Add(2 * time.Hour)
+	_, err := checker.Count(ctx, "password")
+	assert.Error(t, err)
+	count, err := checker.Count(ctx, "password")
+	assert.NoError(t, err)
+	assert.Equal(t, 9545824, count)
+	assert.Equal(t, 3, requests)
+}
+
+func TestGenerate(t *testing.T) {
+	t.Run("positive: default password has all character classes", func(t *testing.T) {
+		password, err := Generate(GenerateOptions{})
+		assert.NoError(t, err)
+		assert.Len(t, password, DefaultGenerateLength)
+		for _, class := range []string{lowerChars, upperChars, digitChars, symbolChars} {
+			assert.True(t, strings.ContainsAny(password, class), class)
+		}
+	})
+	t.Run("positive: digits without ambiguous characters", func(t *testing.T) {
+		for i := 0; i < 20; i++ {
+			password, err := Generate(GenerateOptions{Length: 32, Digits: true, ExcludeAmbiguous: true})
+			assert.NoError(t, err)
+			assert.Len(t, password, 32)
+			assert.Empty(t, strings.Trim(password, "23456789"))
+		}
+	})
+	t.Run("positive: digits of the minimal entropy", func(t *testing.T) {
+		password, err := Generate(GenerateOptions{Length: 14, Digits: true})
+		assert.NoError(t, err)
+		assert.Len(t, password, 14)
+		assert.Empty(t, strings.Trim(password, digitChars))
+	})
+	t.Run("positive: passphrase", func(t *testing.T) {
+		passphrase, err := Generate(GenerateOptions{Words: 6, Separator: " ", Length: 1})
+		assert.NoError(t, err)
+		words := strings.Split(passphrase, " ")
+		assert.Len(t, words, 6)
+		for _, word := range words {
+			assert.NotEqual(t, -1, wordlist.Index(word), word)
+		}
+	})
+	t.Run("negative: invalid options", func(t *testing.T) {
+		for _, opts := range []GenerateOptions{{Length: 7}, {Length: 129}, {Words: 3}, {Words: 21}, {Words: -1},
+			{Length: 8, Digits: true}, {Length: 13, Digits: true}, {Length: 14, Digits: true, ExcludeAmbiguous: true}, {Length: 9, Lower: true}} {
+			_, err := Generate(opts)
+			assert.ErrorIs(t, err, ErrInvalidOptions, opts)
+		}
+	})
+}
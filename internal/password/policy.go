@@ -0,0 +1,53 @@
+package password
+
+import (
+	"errors"
+	"fmt"
+	"github.com/nbutton23/zxcvbn-go"
+	"unicode/utf8"
+)
+
+const (
+	// DefaultMinLength is the minimal length of account password if it's not configured.
+	DefaultMinLength = 8
+	// DefaultMinScore is the minimal zxcvbn strength score (0-4) of account password if it's not configured.
+	DefaultMinScore = 2
+	// maxLength is the bcrypt limit, longer passwords are rejected by the storage.
+	maxLength = 72
+)
+
+var (
+	ErrTooShort = errors.New("password is too short")
+	ErrTooLong  = errors.New("password is too long")
+	ErrTooWeak  = errors.New("password is too weak")
+	ErrBreached = errors.New("password has appeared in a data breach")
+)
+
+// Policy describes requirements for account passwords. Zero policy accepts any password.
+type Policy struct {
+	MinLength int
+	MinScore  int
+}
+
+// Check returns an error if the password doesn't satisfy the policy.
+// User inputs (e.g. login) are penalized by the strength estimation.
+func (p Policy) Check(password string, userInputs ...string) error {
+	if length := utf8.RuneCountInString(password); length < p.MinLength {
+		return fmt.Errorf("%w: %d characters, at least %d required", ErrTooShort, length, p.MinLength)
+	}
+	if len(password) > maxLength {
+		return fmt.Errorf("%w: at most %d bytes allowed", ErrTooLong, maxLength)
+	}
+	if p.MinScore <= 0 {
+		return nil
+	}
+	if score := Score(password, userInputs...); score < p.MinScore {
+		return fmt.Errorf("%w: strength score %d, at least %d required", ErrTooWeak, score, p.MinScore)
+	}
+	return nil
+}
+
+// Score estimates password strength from 0 (guessable) to 4 (very unguessable).
+func Score(password string, userInputs ...string) int {
+	return zxcvbn.PasswordStrength(password, userInputs).Score
+}
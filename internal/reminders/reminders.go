@@ -0,0 +1,131 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/notify"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// Check looks for the vault items needing attention at the time and notifies their owners.
+type Check func(ctx context.Context, now time.Time) error
+
+// Run performs the checks at once and then every interval until the context is done, errors of the checks are logged.
+// Not positive interval disables the checks.
+func Run(ctx context.Context, interval time.Duration, logger *zap.SugaredLogger, checks ...Check) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		for _, check := range checks {
+			if err := check(ctx, time.Now()); err != nil {
+				logger.Errorf("error while checking reminders: %s", err)
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RotationCheck notifies the owners of the credentials and the bank cards with the passwords due for a change.
+// Each user gets one notification with all the due items, the items are not reported again until their passwords change.
+// If the notification fails, the items are reported again by the next check.
+func RotationCheck(storage internal.Storage, notifier notify.Notifier) Check {
+	return func(ctx context.Context, now time.Time) error {
+		items, err := storage.GetPendingRotations(ctx, now)
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, userItems := range byUser(items, func(item internal.DueItem) string { return item.UserName }) {
+			userName := userItems[0].UserName
+			if err = notifier.Notify(ctx, rotationNotification(userName, userItems)); err != nil {
+				errs = append(errs, fmt.Errorf("error while notifying user %q about rotation: %w", userName, err))
+				continue
+			}
+			if err = storage.SetRotationNotified(ctx, userItems, now); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+// CardExpiryCheck notifies the owners of the bank cards which won't be valid in the number of days, including the expired ones.
+// Each user gets one notification with all the expiring cards, a card is reported again only when its expiry month changes.
+// If the notification fails, the cards are reported again by the next check.
+func CardExpiryCheck(storage internal.Storage, notifier notify.Notifier, days int) Check {
+	return func(ctx context.Context, now time.Time) error {
+		cards, err := storage.GetPendingCardExpiries(ctx, now.AddDate(0, 0, days))
+		if err != nil {
+			return err
+		}
+		var errs []error
+		for _, userCards := range byUser(cards, func(card internal.ExpiringCard) string { return card.UserName }) {
+			userName := userCards[0].UserName
+			if err = notifier.Notify(ctx, cardExpiryNotification(userName, userCards, now)); err != nil {
+				errs = append(errs, fmt.Errorf("error while notifying user %q about card expiry: %w", userName, err))
+				continue
+			}
+			if err = storage.SetCardExpiryNotified(ctx, userCards); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+}
+
+func rotationNotification(userName string, items []internal.DueItem) notify.Notification {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d password(s) should be changed:\n", len(items))
+	for _, item := range items {
+		fmt.Fprintf(&text, "- %s %q, due since %s\n", item.Type, item.Name, item.DueAt.Format(time.DateOnly))
+	}
+	return notify.Notification{
+		Event:    notify.EventRotationDue,
+		UserName: userName,
+		Subject:  "Passwords due for rotation",
+		Text:     text.String(),
+		Items:    items,
+	}
+}
+
+func cardExpiryNotification(userName string, cards []internal.ExpiringCard, now time.Time) notify.Notification {
+	var text strings.Builder
+	fmt.Fprintf(&text, "%d bank card(s) expire soon:\n", len(cards))
+	for _, card := range cards {
+		state := "valid through"
+		if !card.ExpiresAt.After(now) {
+			state = "expired after"
+		}
+		fmt.Fprintf(&text, "- %s *%s, %s %s\n", card.BankName, card.LastDigits, state, card.ExpiresOn)
+	}
+	return notify.Notification{
+		Event:    notify.EventCardExpiring,
+		UserName: userName,
+		Subject:  "Bank cards expiring",
+		Text:     text.String(),
+		Items:    cards,
+	}
+}
+
+// byUser splits the items ordered by user into the items of each user.
+func byUser[T any](items []T, user func(T) string) [][]T {
+	var groups [][]T
+	for i, item := range items {
+		if i == 0 || user(item) != user(items[i-1]) {
+			groups = append(groups, nil)
+		}
+		groups[len(groups)-1] = append(groups[len(groups)-1], item)
+	}
+	return groups
+}
@@ -0,0 +1,163 @@
+package reminders
+
+import (
+	"context"
+	"errors"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/notify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"testing"
+	"time"
+)
+
+type notifierFunc func(ctx context.Context, notification notify.Notification) error
+
+func (f notifierFunc) Notify(ctx context.Context, notification notify.Notification) error {
+	return f(ctx, notification)
+}
+
+func TestRotationCheck(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	changed := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	items := []internal.DueItem{
+		{Type: internal.ItemTypeCredentials, ID: 1, UserName: "cersei", Name: "lannister", RotationDays: 30, PasswordChangedAt: changed, DueAt: changed.AddDate(0, 0, 30)},
+		{Type: internal.ItemTypeCard, ID: 2, UserName: "cersei", Name: "iron bank *4444", RotationDays: 90, PasswordChangedAt: changed, DueAt: changed.AddDate(0, 0, 90)},
+		{Type: internal.ItemTypeCredentials, ID: 3, UserName: "jaime", Name: "kingsguard", RotationDays: 30, PasswordChangedAt: changed, DueAt: changed.AddDate(0, 0, 30)},
+	}
+
+	t.Run("positive: users are notified once", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPendingRotations", mock.Anything, now).Return(items, nil)
+		mockedStorage.On("SetRotationNotified", mock.Anything, items[:2], now).Return(nil)
+		mockedStorage.On("SetRotationNotified", mock.Anything, items[2:], now).Return(nil)
+
+		var notifications []notify.Notification
+		check := RotationCheck(mockedStorage, notifierFunc(func(_ context.Context, notification notify.Notification) error {
+			notifications = append(notifications, notification)
+			return nil
+		}))
+		assert.NoError(t, check(context.Background(), now))
+		assert.Len(t, notifications, 2)
+		assert.Equal(t, notify.Notification{
+			Event:    notify.EventRotationDue,
+			UserName: "cersei",
+			Subject:  "Passwords due for rotation",
+			Text:     "2 password(s) should be changed:\n- credentials \"lannister\", due since 2026-07-31\n- cards \"iron bank *4444\", due since 2026-09-29\n",
+			Items:    items[:2],
+		}, notifications[0])
+		assert.Equal(t, "jaime", notifications[1].UserName)
+	})
+	t.Run("negative: failed notification is retried later", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPendingRotations", mock.Anything, now).Return(items, nil)
+		mockedStorage.On("SetRotationNotified", mock.Anything, items[2:], now).Return(nil)
+
+		check := RotationCheck(mockedStorage, notifierFunc(func(_ context.Context, notification notify.Notification) error {
+			if notification.UserName == "cersei" {
+				return errors.New("webhook is down")
+			}
+			return nil
+		}))
+		err := check(context.Background(), now)
+		assert.EqualError(t, err, "error while notifying user \"cersei\" about rotation: webhook is down")
+	})
+	t.Run("negative: storage error", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPendingRotations", mock.Anything, now).Return(nil, errors.New("storage error"))
+
+		check := RotationCheck(mockedStorage, notifierFunc(func(context.Context, notify.Notification) error {
+			t.Fatal("nobody should be notified")
+			return nil
+		}))
+		assert.EqualError(t, check(context.Background(), now), "storage error")
+	})
+}
+
+func TestCardExpiryCheck(t *testing.T) {
+	now := time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)
+	cards := []internal.ExpiringCard{
+		{ID: 1, UserName: "cersei", BankName: "iron bank", LastDigits: "4444", ExpiresOn: "2026-09", ExpiresAt: time.Date(2026, 10, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 2, UserName: "cersei", BankName: "gold bank", LastDigits: "1111", ExpiresOn: "2026-10", ExpiresAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+		{ID: 3, UserName: "jaime", BankName: "iron bank", LastDigits: "2222", ExpiresOn: "2026-10", ExpiresAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	t.Run("positive: users are notified once", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPendingCardExpiries", mock.Anything, now.AddDate(0, 0, 30)).Return(cards, nil)
+		mockedStorage.On("SetCardExpiryNotified", mock.Anything, cards[:2]).Return(nil)
+		mockedStorage.On("SetCardExpiryNotified", mock.Anything, cards[2:]).Return(nil)
+
+		var notifications []notify.Notification
+		check := CardExpiryCheck(mockedStorage, notifierFunc(func(_ context.Context, notification notify.Notification) error {
+			notifications = append(notifications, notification)
+			return nil
+		}), 30)
+		assert.NoError(t, check(context.Background(), now))
+		assert.Len(t, notifications, 2)
+		assert.Equal(t, notify.Notification{
+			Event:    notify.EventCardExpiring,
+			UserName: "cersei",
+			Subject:  "Bank cards expiring",
+			Text:     "2 bank card(s) expire soon:\n- iron bank *4444, expired after 2026-09\n- gold bank *1111, valid through 2026-10\n",
+			Items:    cards[:2],
+		}, notifications[0])
+		assert.Equal(t, "jaime", notifications[1].UserName)
+	})
+	t.Run("negative: failed notification is retried later", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("GetPendingCardExpiries", mock.Anything, now.AddDate(0, 0, 30)).Return(cards, nil)
+		mockedStorage.On("SetCardExpiryNotified", mock.Anything, cards[2:]).Return(nil)
+
+		check := CardExpiryCheck(mockedStorage, notifierFunc(func(_ context.Context, notification notify.Notification) error {
+			if notification.UserName == "cersei" {
+				return errors.New("smtp is down")
+			}
+			return nil
+		}), 30)
+		err := check(context.Background(), now)
+		assert.EqualError(t, err, "error while notifying user \"cersei\" about card expiry: smtp is down")
+	})
+}
+
+func TestRun(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := make(chan struct{}, 10)
+	done := make(chan struct{})
+	go func() {
+		Run(ctx, time.Millisecond, zap.NewNop().Sugar(), func(context.Context, time.Time) error {
+			select {
+			case calls <- struct{}{}:
+			default:
+			}
+			return errors.New("logged")
+		})
+		close(done)
+	}()
+	<-calls
+	<-calls
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return when the context is done")
+	}
+}
+
+func TestRun_Disabled(t *testing.T) {
+	done := make(chan struct{})
+	go func() {
+		Run(context.Background(), 0, zap.NewNop().Sugar(), func(context.Context, time.Time) error {
+			t.Error("checks should not run with zero interval")
+			return nil
+		})
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run should return at once with zero interval")
+	}
+}
@@ -0,0 +1,128 @@
+package sshkey
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+	"net"
+)
+
+var (
+	ErrReadOnlyAgent = errors.New("keys of the agent are managed in the vault")
+	ErrUseDenied     = errors.New("use of the key was denied")
+)
+
+// Key is the private key served by the agent, the name is shown to the clients as the comment of the key.
+type Key struct {
+	Name       string
+	PrivateKey string
+}
+
+// ConfirmFunc is asked before every signature with the name and the fingerprint of the key, the key is only used if it returns true.
+type ConfirmFunc func(name string, fingerprint string) bool
+
+// vaultAgent is a read-only SSH agent with the keys loaded from the vault.
+type vaultAgent struct {
+	keyring agent.ExtendedAgent
+	confirm ConfirmFunc
+}
+
+// NewAgent returns the SSH agent serving the keys. If confirm is not nil, it's asked before each use of a key.
+// Keys can't be added or removed through the agent protocol, they are managed in the vault.
+func NewAgent(keys []Key, confirm ConfirmFunc) (agent.ExtendedAgent, error) {
+	keyring := agent.NewKeyring()
+	for _, key := range keys {
+		private, err := ssh.ParseRawPrivateKey([]byte(key.PrivateKey))
+		if err != nil {
+			return nil, fmt.Errorf("error while parsing ssh key %q: %w", key.Name, err)
+		}
+		if err = keyring.Add(agent.AddedKey{PrivateKey: private, Comment: key.Name}); err != nil {
+			return nil, fmt.Errorf("error while adding ssh key %q to agent: %w", key.Name, err)
+		}
+	}
+	return &vaultAgent{keyring: keyring.(agent.ExtendedAgent), confirm: confirm}, nil
+}
+
+// Serve accepts the connections of the listener and serves the agent protocol on them until the listener is closed.
+func Serve(listener net.Listener, sshAgent agent.Agent) error {
+	for {
+		conn, err := listener.Accept()
+		if errors.Is(err, net.ErrClosed) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("error while accepting ssh agent connection: %w", err)
+		}
+		go func() {
+			defer conn.Close()
+			_ = agent.ServeAgent(sshAgent, conn)
+		}()
+	}
+}
+
+func (a *vaultAgent) List() ([]*agent.Key, error) {
+	return a.keyring.List()
+}
+
+func (a *vaultAgent) Sign(key ssh.PublicKey, data []byte) (*ssh.Signature, error) {
+	return a.SignWithFlags(key, data, 0)
+}
+
+// SignWithFlags signs the data with the key after the confirmation, the flags choose SHA-2 signatures of RSA keys.
+func (a *vaultAgent) SignWithFlags(key ssh.PublicKey, data []byte, flags agent.SignatureFlags) (*ssh.Signature, error) {
+	if a.confirm != nil {
+		name, err := a.keyName(key)
+		if err != nil {
+			return nil, err
+		}
+		if !a.confirm(name, ssh.FingerprintSHA256(key)) {
+			return nil, ErrUseDenied
+		}
+	}
+	return a.keyring.SignWithFlags(key, data, flags)
+}
+
+func (a *vaultAgent) Signers() ([]ssh.Signer, error) {
+	return a.keyring.Signers()
+}
+
+func (a *vaultAgent) Add(agent.AddedKey) error {
+	return ErrReadOnlyAgent
+}
+
+func (a *vaultAgent) Remove(ssh.PublicKey) error {
+	return ErrReadOnlyAgent
+}
+
+func (a *vaultAgent) RemoveAll() error {
+	return ErrReadOnlyAgent
+}
+
+func (a *vaultAgent) Lock([]byte) error {
+	return ErrReadOnlyAgent
+}
+
+func (a *vaultAgent) Unlock([]byte) error {
+	return ErrReadOnlyAgent
+}
+
+func (a *vaultAgent) Extension(string, []byte) ([]byte, error) {
+	return nil, agent.ErrExtensionUnsupported
+}
+
+// keyName returns the name of the served key, the agent doesn't sign with unknown keys.
+func (a *vaultAgent) keyName(key ssh.PublicKey) (string, error) {
+	keys, err := a.keyring.List()
+	if err != nil {
+		return "", err
+	}
+	wanted := key.Marshal()
+	for _, k := range keys {
+		if bytes.Equal(k.Blob, wanted) {
+			return k.Comment, nil
+		}
+	}
+	return "", errors.New("ssh key not found in agent")
+}
@@ -0,0 +1,88 @@
+package sshkey
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"golang.org/x/crypto/ssh"
+	"strings"
+)
+
+const (
+	TypeEd25519 = "ed25519"
+	TypeRSA     = "rsa"
+	// DefaultRSABits is the size of generated RSA keys if it's not provided, the same as ssh-keygen uses.
+	DefaultRSABits = 3072
+	minRSABits     = 2048
+	maxRSABits     = 8192
+)
+
+var (
+	ErrInvalidKey       = errors.New("invalid ssh private key")
+	ErrEncryptedKey     = errors.New("ssh private key is protected with a passphrase")
+	ErrInvalidGenerator = errors.New("invalid ssh key generator options")
+)
+
+// Info describes the public part of the private key.
+type Info struct {
+	// Type is the SSH name of the key algorithm, e.g. ssh-ed25519 or ssh-rsa.
+	Type string
+	// PublicKey is the key in authorized_keys format with the comment if it's provided.
+	PublicKey string
+	// Fingerprint is the SHA256 fingerprint of the public key as printed by ssh-keygen -l.
+	Fingerprint string
+}
+
+// Generate returns a new private key of the type (ed25519 or rsa) in OpenSSH PEM format, bits are only used for RSA keys.
+func Generate(keyType string, bits int, comment string) (string, error) {
+	var key any
+	switch keyType {
+	case TypeEd25519, "":
+		_, private, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			return "", fmt.Errorf("error while generating ed25519 key: %w", err)
+		}
+		key = private
+	case TypeRSA:
+		if bits == 0 {
+			bits = DefaultRSABits
+		}
+		if bits < minRSABits || bits > maxRSABits {
+			return "", fmt.Errorf("%w: rsa key size should be from %d to %d bits", ErrInvalidGenerator, minRSABits, maxRSABits)
+		}
+		private, err := rsa.GenerateKey(rand.Reader, bits)
+		if err != nil {
+			return "", fmt.Errorf("error while generating rsa key: %w", err)
+		}
+		key = private
+	default:
+		return "", fmt.Errorf("%w: %q, use ed25519 or rsa", ErrInvalidGenerator, keyType)
+	}
+	block, err := ssh.MarshalPrivateKey(key, comment)
+	if err != nil {
+		return "", fmt.Errorf("error while encoding ssh private key: %w", err)
+	}
+	return string(pem.EncodeToMemory(block)), nil
+}
+
+// Parse checks the unencrypted private key in PEM format (OpenSSH, PKCS#1, PKCS#8 or SEC1)
+// and returns its public part, the comment is appended to the public key.
+func Parse(privateKey string, comment string) (Info, error) {
+	signer, err := ssh.ParsePrivateKey([]byte(privateKey))
+	if err != nil {
+		var passphraseErr *ssh.PassphraseMissingError
+		if errors.As(err, &passphraseErr) {
+			return Info{}, ErrEncryptedKey
+		}
+		return Info{}, fmt.Errorf("%w: %s", ErrInvalidKey, err)
+	}
+	public := signer.PublicKey()
+	authorizedKey := strings.TrimSpace(string(ssh.MarshalAuthorizedKey(public)))
+	if comment != "" {
+		authorizedKey += " " + comment
+	}
+	return Info{Type: public.Type(), PublicKey: authorizedKey, Fingerprint: ssh.FingerprintSHA256(public)}, nil
+}
@@ -0,0 +1,115 @@
+package vault
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal/wordlist"
+	"golang.org/x/crypto/hkdf"
+	"io"
+	"strings"
+)
+
+const (
+	// recoveryEntropySize is 128 bits of entropy, encoded as 12 words with the BIP39 checksum.
+	recoveryEntropySize = 16
+	recoveryWords       = 12
+	wordBits            = 11
+
+	recoveryKEKInfo  = "goph-keeper vault key"
+	recoveryAuthInfo = "goph-keeper recovery auth"
+)
+
+var ErrInvalidRecoveryKey = errors.New("invalid recovery key")
+
+// RecoveryKey is a random key shown to the user once as a list of BIP39 words.
+// It never leaves the client: the vault key is wrapped with a key derived from it,
+// and the server only gets another derived value to check the recovery requests.
+type RecoveryKey struct {
+	entropy []byte
+}
+
+// NewRecoveryKey generates random recovery key.
+func NewRecoveryKey() (RecoveryKey, error) {
+	entropy := make([]byte, recoveryEntropySize)
+	if _, err := rand.Read(entropy); err != nil {
+		return RecoveryKey{}, fmt.Errorf("error while generating recovery key: %w", err)
+	}
+	return RecoveryKey{entropy: entropy}, nil
+}
+
+// ParseRecoveryKey parses recovery key from the space separated words, the case and extra spaces are ignored.
+func ParseRecoveryKey(phrase string) (RecoveryKey, error) {
+	words := strings.Fields(strings.ToLower(phrase))
+	if len(words) != recoveryWords {
+		return RecoveryKey{}, fmt.Errorf("%w: %d words, %d expected", ErrInvalidRecoveryKey, len(words), recoveryWords)
+	}
+	// 12 words of 11 bits are 128 bits of entropy followed by 4 bits of checksum
+	bits := make([]byte, 0, recoveryWords*wordBits)
+	for _, word := range words {
+		index := wordlist.Index(word)
+		if index < 0 {
+			return RecoveryKey{}, fmt.Errorf("%w: unknown word %q", ErrInvalidRecoveryKey, word)
+		}
+		for i := wordBits - 1; i >= 0; i-- {
+			bits = append(bits, byte(index>>i)&1)
+		}
+	}
+	entropy := make([]byte, recoveryEntropySize)
+	for i := 0; i < recoveryEntropySize*8; i++ {
+		entropy[i/8] |= bits[i] << (7 - i%8)
+	}
+	key := RecoveryKey{entropy: entropy}
+	if key.String() != strings.Join(words, " ") {
+		return RecoveryKey{}, fmt.Errorf("%w: checksum mismatch", ErrInvalidRecoveryKey)
+	}
+	return key, nil
+}
+
+// String returns recovery key as the space separated words.
+func (k RecoveryKey) String() string {
+	checksum := sha256.Sum256(k.entropy)
+	data := append(append([]byte{}, k.entropy...), checksum[0])
+	words := make([]string, 0, recoveryWords)
+	for i := 0; i < recoveryWords; i++ {
+		index := 0
+		for j := 0; j < wordBits; j++ {
+			bit := i*wordBits + j
+			index = index<<1 | int(data[bit/8]>>(7-bit%8)&1)
+		}
+		words = append(words, wordlist.English[index])
+	}
+	return strings.Join(words, " ")
+}
+
+// kek derives the key which wraps the vault key.
+func (k RecoveryKey) kek() ([]byte, error) {
+	return k.derive(recoveryKEKInfo)
+}
+
+// Auth derives the value which proves the knowledge of the recovery key to the server.
+// It can't be used to unwrap the vault key.
+func (k RecoveryKey) Auth() (string, error) {
+	auth, err := k.derive(recoveryAuthInfo)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(auth), nil
+}
+
+func (k RecoveryKey) derive(info string) ([]byte, error) {
+	key := make([]byte, keySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, k.entropy, nil, []byte(info)), key); err != nil {
+		return nil, fmt.Errorf("error while deriving key: %w", err)
+	}
+	return key, nil
+}
+
+// Verifier returns the value stored by the server to check recovery auth values.
+func Verifier(auth string) string {
+	sum := sha256.Sum256([]byte(auth))
+	return hex.EncodeToString(sum[:])
+}
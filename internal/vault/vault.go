@@ -0,0 +1,140 @@
+// Package vault manages the user's vault key on the client side.
+// The vault key is random, it is stored by the server only wrapped with keys derived
+// from the account password and from the recovery key. For now the vault key backs the password
+// recovery only, the items are not encrypted with it.
+package vault
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"golang.org/x/crypto/argon2"
+)
+
+const (
+	keySize  = 32
+	saltSize = 16
+
+	argonTime    = 1
+	argonMemory  = 64 * 1024
+	argonThreads = 4
+)
+
+var ErrUnwrap = errors.New("vault key can't be unwrapped")
+
+// New generates the vault key for a new account and returns it wrapped with the password and the recovery key.
+// The recovery key should be shown to the user, it isn't stored anywhere.
+func New(password string) (internal.VaultKey, RecoveryKey, error) {
+	vaultKey := make([]byte, keySize)
+	if _, err := rand.Read(vaultKey); err != nil {
+		return internal.VaultKey{}, RecoveryKey{}, fmt.Errorf("error while generating vault key: %w", err)
+	}
+	wrapped, err := WrapWithPassword(vaultKey, password)
+	if err != nil {
+		return internal.VaultKey{}, RecoveryKey{}, err
+	}
+	recoveryKey, err := NewRecoveryKey()
+	if err != nil {
+		return internal.VaultKey{}, RecoveryKey{}, err
+	}
+	kek, err := recoveryKey.kek()
+	if err != nil {
+		return internal.VaultKey{}, RecoveryKey{}, err
+	}
+	if wrapped.RecoveryWrappedKey, err = wrap(kek, vaultKey); err != nil {
+		return internal.VaultKey{}, RecoveryKey{}, err
+	}
+	auth, err := recoveryKey.Auth()
+	if err != nil {
+		return internal.VaultKey{}, RecoveryKey{}, err
+	}
+	wrapped.RecoveryVerifier = Verifier(auth)
+	return wrapped, recoveryKey, nil
+}
+
+// WrapWithPassword wraps the vault key with the key derived from the password and a new salt.
+// Only password related fields of the result are filled.
+func WrapWithPassword(vaultKey []byte, password string) (internal.VaultKey, error) {
+	salt := make([]byte, saltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return internal.VaultKey{}, fmt.Errorf("error while generating salt: %w", err)
+	}
+	wrapped, err := wrap(passwordKEK(password, salt), vaultKey)
+	if err != nil {
+		return internal.VaultKey{}, err
+	}
+	return internal.VaultKey{
+		PasswordSalt:       base64.StdEncoding.EncodeToString(salt),
+		PasswordWrappedKey: wrapped,
+	}, nil
+}
+
+// UnwrapWithPassword returns the vault key wrapped with the password.
+func UnwrapWithPassword(key internal.VaultKey, password string) ([]byte, error) {
+	salt, err := base64.StdEncoding.DecodeString(key.PasswordSalt)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid salt: %s", ErrUnwrap, err)
+	}
+	return unwrap(passwordKEK(password, salt), key.PasswordWrappedKey)
+}
+
+// UnwrapWithRecoveryKey returns the vault key wrapped with the recovery key.
+func UnwrapWithRecoveryKey(key internal.VaultKey, recoveryKey RecoveryKey) ([]byte, error) {
+	kek, err := recoveryKey.kek()
+	if err != nil {
+		return nil, err
+	}
+	return unwrap(kek, key.RecoveryWrappedKey)
+}
+
+func passwordKEK(password string, salt []byte) []byte {
+	return argon2.IDKey([]byte(password), salt, argonTime, argonMemory, argonThreads, keySize)
+}
+
+// wrap encrypts the key with AES-GCM, the nonce is prepended to the result.
+func wrap(kek []byte, key []byte) (string, error) {
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = rand.Read(nonce); err != nil {
+		return "", fmt.Errorf("error while generating nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(gcm.Seal(nonce, nonce, key, nil)), nil
+}
+
+func unwrap(kek []byte, wrapped string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(wrapped)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s", ErrUnwrap, err)
+	}
+	gcm, err := newGCM(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, ErrUnwrap
+	}
+	key, err := gcm.Open(nil, data[:gcm.NonceSize()], data[gcm.NonceSize():], nil)
+	if err != nil {
+		return nil, ErrUnwrap
+	}
+	return key, nil
+}
+
+func newGCM(kek []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(kek)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("error while creating cipher: %w", err)
+	}
+	return gcm, nil
+}
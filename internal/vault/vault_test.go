@@ -0,0 +1,104 @@
+package vault
+
+import (
+	"bytes"
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestRecoveryKey(t *testing.T) {
+	t.Run("BIP39 vectors", func(t *testing.T) {
+		testCases := []struct {
+			entropy []byte
+			phrase  string
+		}{
+			{
+				entropy: bytes.Repeat([]byte{0x00}, 16),
+				phrase:  "abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon about",
+			},
+			{
+				entropy: bytes.Repeat([]byte{0x7f}, 16),
+				phrase:  "legal winner thank year wave sausage worth useful legal winner thank yellow",
+			},
+			{
+				entropy: bytes.Repeat([]byte{0xff}, 16),
+				phrase:  "zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo zoo wrong",
+			},
+		}
+		for _, tt := range testCases {
+			key := RecoveryKey{entropy: tt.entropy}
+			assert.Equal(t, tt.phrase, key.String())
+			parsed, err := ParseRecoveryKey(tt.phrase)
+			assert.NoError(t, err)
+			assert.Equal(t, tt.entropy, parsed.entropy)
+		}
+	})
+	t.Run("generated key round trip", func(t *testing.T) {
+		key, err := NewRecoveryKey()
+		assert.NoError(t, err)
+		parsed, err := ParseRecoveryKey("  " + key.String() + "\n")
+		assert.NoError(t, err)
+		assert.Equal(t, key, parsed)
+	})
+	t.Run("invalid keys", func(t *testing.T) {
+		for _, phrase := range []string{
+			"abandon abandon abandon",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon",
+			"abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon abandon gopher",
+		} {
+			_, err := ParseRecoveryKey(phrase)
+			assert.ErrorIs(t, err, ErrInvalidRecoveryKey)
+		}
+	})
+	t.Run("auth differs from wrapping key", func(t *testing.T) {
+		key, err := NewRecoveryKey()
+		assert.NoError(t, err)
+		auth, err := key.Auth()
+		assert.NoError(t, err)
+		kek, err := key.kek()
+		assert.NoError(t, err)
+		assert.NotContains(t, auth, key.String())
+		assert.NotEqual(t, kek, []byte(auth))
+		assert.Len(t, Verifier(auth), 64)
+	})
+}
+
+func TestVault(t *testing.T) {
+	password := "valar morghulis"
+	wrapped, recoveryKey, err := New(password)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, wrapped.PasswordSalt)
+	assert.NotEmpty(t, wrapped.PasswordWrappedKey)
+	assert.NotEmpty(t, wrapped.RecoveryWrappedKey)
+	auth, err := recoveryKey.Auth()
+	assert.NoError(t, err)
+	assert.Equal(t, Verifier(auth), wrapped.RecoveryVerifier)
+
+	vaultKey, err := UnwrapWithPassword(wrapped, password)
+	assert.NoError(t, err)
+	assert.Len(t, vaultKey, keySize)
+
+	t.Run("recovery key unwraps the same key", func(t *testing.T) {
+		recovered, err := UnwrapWithRecoveryKey(wrapped, recoveryKey)
+		assert.NoError(t, err)
+		assert.Equal(t, vaultKey, recovered)
+	})
+	t.Run("wrong password", func(t *testing.T) {
+		_, err := UnwrapWithPassword(wrapped, "valar dohaeris")
+		assert.ErrorIs(t, err, ErrUnwrap)
+	})
+	t.Run("wrong recovery key", func(t *testing.T) {
+		other, err := NewRecoveryKey()
+		assert.NoError(t, err)
+		_, err = UnwrapWithRecoveryKey(wrapped, other)
+		assert.ErrorIs(t, err, ErrUnwrap)
+	})
+	t.Run("re-wrap with new password", func(t *testing.T) {
+		rewrapped, err := WrapWithPassword(vaultKey, "a girl has no name")
+		assert.NoError(t, err)
+		assert.NotEqual(t, wrapped.PasswordSalt, rewrapped.PasswordSalt)
+		unwrapped, err := UnwrapWithPassword(rewrapped, "a girl has no name")
+		assert.NoError(t, err)
+		assert.Equal(t, vaultKey, unwrapped)
+	})
+}
@@ -0,0 +1,137 @@
+package webauthn
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+var ErrNoCredential = errors.New("no credential for user in authenticator")
+
+// Credential is a key pair registered by the software authenticator for a single user.
+type Credential struct {
+	Login      string             `json:"login"`
+	KeyID      string             `json:"key_id"`
+	PrivateKey ed25519.PrivateKey `json:"private_key"`
+	SignCount  uint32             `json:"sign_count"`
+}
+
+// PublicKey returns the base64 encoded public key of the credential.
+func (c Credential) PublicKey() string {
+	return base64.StdEncoding.EncodeToString(c.PrivateKey.Public().(ed25519.PublicKey))
+}
+
+// Authenticator is a software authenticator which keeps Ed25519 keys in a local file.
+// It produces the same assertions as a security key, which makes public key login testable offline.
+type Authenticator struct {
+	path        string
+	Credentials []Credential `json:"credentials"`
+}
+
+// DefaultPath returns the default location of the authenticator file in the user's home directory.
+func DefaultPath() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("error while getting home directory: %w", err)
+	}
+	return filepath.Join(home, ".goph-keeper", "authenticator.json"), nil
+}
+
+// Load reads the authenticator from the provided file. Missing file means an empty authenticator.
+func Load(path string) (*Authenticator, error) {
+	a := &Authenticator{path: path}
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return a, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error while reading authenticator file: %w", err)
+	}
+	if err = json.Unmarshal(data, a); err != nil {
+		return nil, fmt.Errorf("error while parsing authenticator file: %w", err)
+	}
+	return a, nil
+}
+
+// Save writes the authenticator to its file, the file is readable only by the owner.
+func (a *Authenticator) Save() error {
+	data, err := json.MarshalIndent(a, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(a.path), 0o700); err != nil {
+		return fmt.Errorf("error while creating authenticator directory: %w", err)
+	}
+	if err = os.WriteFile(a.path, data, 0o600); err != nil {
+		return fmt.Errorf("error while writing authenticator file: %w", err)
+	}
+	return nil
+}
+
+// Create generates a new key pair for the user, replacing the previous one if any.
+func (a *Authenticator) Create(login string) (Credential, error) {
+	_, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return Credential{}, fmt.Errorf("error while generating key: %w", err)
+	}
+	keyID, err := NewChallenge()
+	if err != nil {
+		return Credential{}, err
+	}
+	credential := Credential{
+		Login:      login,
+		KeyID:      keyID,
+		PrivateKey: privateKey,
+	}
+	for i := range a.Credentials {
+		if a.Credentials[i].Login == login {
+			a.Credentials[i] = credential
+			return credential, nil
+		}
+	}
+	a.Credentials = append(a.Credentials, credential)
+	return credential, nil
+}
+
+// Sign produces an assertion for the challenge with the user's key and increases the sign counter.
+// The authenticator should be saved afterwards to persist the counter.
+func (a *Authenticator) Sign(login string, challenge string) (Assertion, error) {
+	var credential *Credential
+	for i := range a.Credentials {
+		if a.Credentials[i].Login == login {
+			credential = &a.Credentials[i]
+		}
+	}
+	if credential == nil {
+		return Assertion{}, ErrNoCredential
+	}
+	credential.SignCount++
+
+	clientDataJSON, err := json.Marshal(ClientData{
+		Type:      assertionType,
+		Challenge: challenge,
+		Origin:    Origin,
+	})
+	if err != nil {
+		return Assertion{}, err
+	}
+	rpIDHash := sha256.Sum256([]byte(RelyingPartyID))
+	authenticatorData := make([]byte, authenticatorDataSize)
+	copy(authenticatorData, rpIDHash[:])
+	authenticatorData[sha256.Size] = flagUserPresent
+	binary.BigEndian.PutUint32(authenticatorData[sha256.Size+1:], credential.SignCount)
+
+	return Assertion{
+		KeyID:             credential.KeyID,
+		ClientDataJSON:    clientDataJSON,
+		AuthenticatorData: authenticatorData,
+		Signature:         ed25519.Sign(credential.PrivateKey, signedData(authenticatorData, clientDataJSON)),
+	}, nil
+}
@@ -0,0 +1,105 @@
+package webauthn
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// The assertion format follows the WebAuthn specification, so hardware security keys
+// can be supported later by adding their signature algorithms to Verify.
+const (
+	RelyingPartyID = "goph-keeper"
+	Origin         = "goph-keeper"
+	// AlgorithmEdDSA is the name of COSE algorithm -8 used by Ed25519 keys.
+	AlgorithmEdDSA = "EdDSA"
+	assertionType  = "webauthn.get"
+	challengeSize  = 32
+	// authenticator data consists of rp id hash (32 bytes), flags (1 byte) and sign counter (4 bytes)
+	authenticatorDataSize = sha256.Size + 1 + 4
+	flagUserPresent       = 0x01
+)
+
+var (
+	ErrInvalidAssertion   = errors.New("invalid assertion")
+	ErrInvalidSignature   = errors.New("invalid assertion signature")
+	ErrUnsupportedKey     = errors.New("unsupported public key algorithm")
+	ErrSignCountDecreased = errors.New("sign counter has not increased, the authenticator may be cloned")
+)
+
+// ClientData is the data collected by the client and signed by the authenticator (CollectedClientData in WebAuthn).
+type ClientData struct {
+	Type      string `json:"type"`
+	Challenge string `json:"challenge"`
+	Origin    string `json:"origin"`
+}
+
+// Assertion is the authenticator response to the login challenge.
+type Assertion struct {
+	KeyID             string `json:"key_id"`
+	ClientDataJSON    []byte `json:"client_data_json"`
+	AuthenticatorData []byte `json:"authenticator_data"`
+	Signature         []byte `json:"signature"`
+}
+
+// NewChallenge returns a random challenge encoded with base64url.
+func NewChallenge() (string, error) {
+	challenge := make([]byte, challengeSize)
+	if _, err := rand.Read(challenge); err != nil {
+		return "", fmt.Errorf("error while generating challenge: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(challenge), nil
+}
+
+// Verify checks that the assertion was produced for the expected challenge by the owner of the public key.
+// The public key is expected in the base64 encoded form. The sign counter from the assertion is returned
+// on success, it should be stored and passed as storedSignCount for the next verification.
+func Verify(assertion Assertion, challenge string, algorithm string, publicKey string, storedSignCount uint32) (uint32, error) {
+	var clientData ClientData
+	if err := json.Unmarshal(assertion.ClientDataJSON, &clientData); err != nil {
+		return 0, fmt.Errorf("%w: %s", ErrInvalidAssertion, err)
+	}
+	if clientData.Type != assertionType || clientData.Challenge != challenge || clientData.Origin != Origin {
+		return 0, fmt.Errorf("%w: client data doesn't match the challenge", ErrInvalidAssertion)
+	}
+	if len(assertion.AuthenticatorData) < authenticatorDataSize {
+		return 0, fmt.Errorf("%w: authenticator data is too short", ErrInvalidAssertion)
+	}
+	rpIDHash := sha256.Sum256([]byte(RelyingPartyID))
+	if !bytes.Equal(assertion.AuthenticatorData[:sha256.Size], rpIDHash[:]) {
+		return 0, fmt.Errorf("%w: unexpected relying party", ErrInvalidAssertion)
+	}
+	if assertion.AuthenticatorData[sha256.Size]&flagUserPresent == 0 {
+		return 0, fmt.Errorf("%w: user presence is not confirmed", ErrInvalidAssertion)
+	}
+
+	if algorithm != AlgorithmEdDSA {
+		return 0, ErrUnsupportedKey
+	}
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return 0, ErrUnsupportedKey
+	}
+	if !ed25519.Verify(key, signedData(assertion.AuthenticatorData, assertion.ClientDataJSON), assertion.Signature) {
+		return 0, ErrInvalidSignature
+	}
+
+	// authenticators which don't support counters always report zero
+	signCount := binary.BigEndian.Uint32(assertion.AuthenticatorData[sha256.Size+1 : authenticatorDataSize])
+	if (signCount != 0 || storedSignCount != 0) && signCount <= storedSignCount {
+		return 0, ErrSignCountDecreased
+	}
+	return signCount, nil
+}
+
+// signedData returns the data covered by the assertion signature: authenticator data || sha256(client data).
+func signedData(authenticatorData []byte, clientDataJSON []byte) []byte {
+	clientDataHash := sha256.Sum256(clientDataJSON)
+	return append(append([]byte{}, authenticatorData...), clientDataHash[:]...)
+}
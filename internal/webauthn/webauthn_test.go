@@ -0,0 +1,100 @@
+package webauthn
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"path/filepath"
+	"testing"
+)
+
+func TestVerify(t *testing.T) {
+	login := "daenerys"
+	a, err := Load(filepath.Join(t.TempDir(), "authenticator.json"))
+	assert.NoError(t, err)
+	credential, err := a.Create(login)
+	assert.NoError(t, err)
+
+	challenge, err := NewChallenge()
+	assert.NoError(t, err)
+
+	t.Run("positive: valid assertion", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+		assert.Equal(t, credential.KeyID, assertion.KeyID)
+
+		signCount, err := Verify(assertion, challenge, AlgorithmEdDSA, credential.PublicKey(), 0)
+		assert.NoError(t, err)
+		assert.Equal(t, uint32(1), signCount)
+	})
+	t.Run("negative: other challenge", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+		other, err := NewChallenge()
+		assert.NoError(t, err)
+
+		_, err = Verify(assertion, other, AlgorithmEdDSA, credential.PublicKey(), 0)
+		assert.ErrorIs(t, err, ErrInvalidAssertion)
+	})
+	t.Run("negative: tampered client data", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+		assertion.ClientDataJSON, err = json.Marshal(ClientData{Type: assertionType, Challenge: challenge, Origin: Origin + " "})
+		assert.NoError(t, err)
+
+		_, err = Verify(assertion, challenge, AlgorithmEdDSA, credential.PublicKey(), 0)
+		assert.ErrorIs(t, err, ErrInvalidAssertion)
+	})
+	t.Run("negative: tampered counter", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+		assertion.AuthenticatorData[len(assertion.AuthenticatorData)-1]++
+
+		_, err = Verify(assertion, challenge, AlgorithmEdDSA, credential.PublicKey(), 0)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+	t.Run("negative: replayed counter", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+
+		_, err = Verify(assertion, challenge, AlgorithmEdDSA, credential.PublicKey(), 100)
+		assert.ErrorIs(t, err, ErrSignCountDecreased)
+	})
+	t.Run("negative: other key", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+		other, err := a.Create("viserys")
+		assert.NoError(t, err)
+
+		_, err = Verify(assertion, challenge, AlgorithmEdDSA, other.PublicKey(), 0)
+		assert.ErrorIs(t, err, ErrInvalidSignature)
+	})
+	t.Run("negative: unsupported algorithm", func(t *testing.T) {
+		assertion, err := a.Sign(login, challenge)
+		assert.NoError(t, err)
+
+		_, err = Verify(assertion, challenge, "ES256", credential.PublicKey(), 0)
+		assert.ErrorIs(t, err, ErrUnsupportedKey)
+	})
+}
+
+func TestAuthenticator(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "nested", "authenticator.json")
+	a, err := Load(path)
+	assert.NoError(t, err)
+	assert.Empty(t, a.Credentials)
+
+	credential, err := a.Create("jorah")
+	assert.NoError(t, err)
+	_, err = a.Sign("jorah", "challenge")
+	assert.NoError(t, err)
+	assert.NoError(t, a.Save())
+
+	loaded, err := Load(path)
+	assert.NoError(t, err)
+	assert.Len(t, loaded.Credentials, 1)
+	assert.Equal(t, credential.PublicKey(), loaded.Credentials[0].PublicKey())
+	assert.Equal(t, uint32(1), loaded.Credentials[0].SignCount)
+
+	_, err = loaded.Sign("missandei", "challenge")
+	assert.ErrorIs(t, err, ErrNoCredential)
+}
@@ -0,0 +1,31 @@
+// Package wordlist provides the BIP39 English word list used for human-readable keys.
+package wordlist
+
+import (
+	_ "embed"
+	"strings"
+)
+
+//go:embed english.txt
+var english string
+
+// English is the list of 2048 words from the BIP39 specification, sorted alphabetically.
+// The first four letters of each word are unique.
+var English = strings.Fields(english)
+
+// Index returns position of the word in the English list, -1 if there is no such word.
+func Index(word string) int {
+	low, high := 0, len(English)
+	for low < high {
+		mid := (low + high) / 2
+		switch {
+		case English[mid] == word:
+			return mid
+		case English[mid] < word:
+			low = mid + 1
+		default:
+			high = mid
+		}
+	}
+	return -1
+}
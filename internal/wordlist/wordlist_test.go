@@ -0,0 +1,21 @@
+package wordlist
+
+import (
+	"github.com/stretchr/testify/assert"
+	"sort"
+	"testing"
+)
+
+func TestEnglish(t *testing.T) {
+	assert.Len(t, English, 2048)
+	assert.True(t, sort.StringsAreSorted(English))
+	assert.Equal(t, "abandon", English[0])
+	assert.Equal(t, "zoo", English[2047])
+}
+
+func TestIndex(t *testing.T) {
+	assert.Equal(t, 0, Index("abandon"))
+	assert.Equal(t, 1024, Index(English[1024]))
+	assert.Equal(t, 2047, Index("zoo"))
+	assert.Equal(t, -1, Index("gophers"))
+}
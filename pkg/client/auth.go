@@ -0,0 +1,216 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/http"
+)
+
+// Register registers the user and opens the session. The vault key should be wrapped
+// with the user's password locally, see internal/vault.
+func (c *Client) Register(ctx context.Context, user User) error {
+	resp, err := c.send(c.request(ctx).SetBody(user), http.MethodPost, "/auth/register")
+	if err != nil {
+		return err
+	}
+	c.setToken(resp)
+	return nil
+}
+
+// Login checks the password and opens the session. If two-factor authentication is enabled
+// for the user, the session is not opened and the challenge for LoginTwoFactor is returned.
+func (c *Client) Login(ctx context.Context, login string, password string) (string, error) {
+	resp, err := c.send(c.request(ctx).SetBody(User{Login: login, Password: password}), http.MethodPost, "/auth/login")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode() == http.StatusAccepted {
+		var challenge internal.LoginChallenge
+		if err = json.Unmarshal(resp.Body(), &challenge); err != nil {
+			return "", fmt.Errorf("error while parsing server response: %w", err)
+		}
+		return challenge.Challenge, nil
+	}
+	c.setToken(resp)
+	return "", nil
+}
+
+// LoginTwoFactor opens the session with the challenge returned by Login and the code from
+// authenticator app or one of the recovery codes.
+func (c *Client) LoginTwoFactor(ctx context.Context, challenge string, code string, recoveryCode string) error {
+	request := internal.TwoFactorLogin{Challenge: challenge, Code: code, RecoveryCode: recoveryCode}
+	resp, err := c.send(c.request(ctx).SetBody(request), http.MethodPost, "/auth/login/2fa")
+	if err != nil {
+		return err
+	}
+	c.setToken(resp)
+	return nil
+}
+
+// KeyChallenge returns the challenge which should be signed with the registered public key.
+func (c *Client) KeyChallenge(ctx context.Context, login string) (string, error) {
+	var challenge internal.LoginChallenge
+	if err := c.post(ctx, "/auth/key/challenge", internal.KeyLogin{Login: login}, &challenge); err != nil {
+		return "", err
+	}
+	return challenge.Challenge, nil
+}
+
+// KeyLogin opens the session with the assertion of the challenge returned by KeyChallenge. The key replaces
+// the password only: if two-factor authentication is enabled for the user, the session is not opened
+// and the challenge for LoginTwoFactor is returned.
+func (c *Client) KeyLogin(ctx context.Context, login string, assertion Assertion) (string, error) {
+	request := internal.KeyLogin{Login: login, Assertion: &assertion}
+	resp, err := c.send(c.request(ctx).SetBody(request), http.MethodPost, "/auth/key/login")
+	if err != nil {
+		return "", err
+	}
+	if resp.StatusCode() == http.StatusAccepted {
+		var challenge internal.LoginChallenge
+		if err = json.Unmarshal(resp.Body(), &challenge); err != nil {
+			return "", fmt.Errorf("error while parsing server response: %w", err)
+		}
+		return challenge.Challenge, nil
+	}
+	c.setToken(resp)
+	return "", nil
+}
+
+// EnableTwoFactor starts enabling two-factor authentication for the logged in user, the current password
+// of the user is required. The setup should be confirmed with ConfirmTwoFactor.
+func (c *Client) EnableTwoFactor(ctx context.Context, password string) (TwoFactorSetup, error) {
+	var setup TwoFactorSetup
+	if err := c.authorizedPost(ctx, "/auth/2fa/enable", internal.TwoFactorRequest{Password: password}, &setup); err != nil {
+		return TwoFactorSetup{}, err
+	}
+	return setup, nil
+}
+
+// ConfirmTwoFactor enables two-factor authentication with the code from authenticator app
+// and returns one-time recovery codes.
+func (c *Client) ConfirmTwoFactor(ctx context.Context, code string) ([]string, error) {
+	var codes internal.RecoveryCodes
+	if err := c.authorizedPost(ctx, "/auth/2fa/confirm", internal.TwoFactorRequest{Code: code}, &codes); err != nil {
+		return nil, err
+	}
+	return codes.Codes, nil
+}
+
+// RegisterKey registers the public key of the logged in user for KeyLogin,
+// the current password of the user is required.
+func (c *Client) RegisterKey(ctx context.Context, registration KeyRegistration) error {
+	return c.authorizedPost(ctx, "/auth/key/register", registration, nil)
+}
+
+// ChangePassword changes the password of the user and opens the new session,
+// other sessions of the user are closed.
+func (c *Client) ChangePassword(ctx context.Context, request AccountRequest) error {
+	resp, err := c.send(c.request(ctx).SetBody(request), http.MethodPost, "/auth/change-password")
+	if err != nil {
+		return err
+	}
+	c.setToken(resp)
+	return nil
+}
+
+// DeleteAccount deletes the user with all saved data and closes the session.
+func (c *Client) DeleteAccount(ctx context.Context, request AccountRequest) error {
+	if err := c.post(ctx, "/auth/delete-account", request, nil); err != nil {
+		return err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.token = ""
+	return nil
+}
+
+// GetVaultKey returns the vault key of the user wrapped with the password,
+// ErrNotFound is returned for users registered without the vault key.
+func (c *Client) GetVaultKey(ctx context.Context, request AccountRequest) (VaultKey, error) {
+	var wrapped VaultKey
+	if err := c.post(ctx, "/auth/vault", request, &wrapped); err != nil {
+		return VaultKey{}, err
+	}
+	return wrapped, nil
+}
+
+// GetRecoveryKey returns the vault key of the user wrapped with the recovery key.
+func (c *Client) GetRecoveryKey(ctx context.Context, request RecoveryRequest) (VaultKey, error) {
+	var wrapped VaultKey
+	if err := c.post(ctx, "/auth/recover/key", request, &wrapped); err != nil {
+		return VaultKey{}, err
+	}
+	return wrapped, nil
+}
+
+// Recover sets the new password with the proof of the recovery key knowledge and opens the session.
+func (c *Client) Recover(ctx context.Context, request RecoveryRequest) error {
+	resp, err := c.send(c.request(ctx).SetBody(request), http.MethodPost, "/auth/recover")
+	if err != nil {
+		return err
+	}
+	c.setToken(resp)
+	return nil
+}
+
+// Unlock resets login throttling of the account or the address, it requires the admin token of the server.
+func (c *Client) Unlock(ctx context.Context, adminToken string, request UnlockRequest) error {
+	req := c.request(ctx).
+		SetHeader("Authorization", fmt.Sprintf("Bearer %s", adminToken)).
+		SetBody(request)
+	_, err := c.send(req, http.MethodPost, "/admin/unlock")
+	return err
+}
+
+// UserName returns the name of the logged in user from the token.
+func (c *Client) UserName() (string, error) {
+	token := c.Token()
+	if token == "" {
+		return "", ErrNotLoggedIn
+	}
+	var claims internal.Claims
+	if _, _, err := jwt.NewParser().ParseUnverified(token, &claims); err != nil {
+		return "", fmt.Errorf("error while parsing token: %w", err)
+	}
+	return claims.Username, nil
+}
+
+// post sends the request to the authentication endpoints, which return the data without the envelope,
+// and decodes the response into v, if it's not nil.
+func (c *Client) post(ctx context.Context, path string, body any, v any) error {
+	resp, err := c.send(c.request(ctx).SetBody(body), http.MethodPost, path)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, v)
+}
+
+// authorizedPost sends the body with the token of the session to the endpoint outside of API v2
+// and decodes the response into v, if it's not nil.
+func (c *Client) authorizedPost(ctx context.Context, path string, body any, v any) error {
+	req, err := c.authorizedRequest(ctx)
+	if err != nil {
+		return err
+	}
+	resp, err := c.send(req.SetBody(body), http.MethodPost, path)
+	if err != nil {
+		return err
+	}
+	return decodeResponse(resp, v)
+}
+
+// decodeResponse decodes the body of the response into v, if it's not nil.
+func decodeResponse(resp *resty.Response, v any) error {
+	if v == nil {
+		return nil
+	}
+	if err := json.Unmarshal(resp.Body(), v); err != nil {
+		return fmt.Errorf("error while parsing server response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,20 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetExpiringCards returns the bank cards which won't be valid within the number of days, including the expired ones,
+// ordered by the expiry month. Zero days return only the expired cards.
+func (c *Client) GetExpiringCards(ctx context.Context, within int) ([]ExpiringCard, error) {
+	query := url.Values{}
+	query.Set("within", strconv.Itoa(within))
+	var cards []ExpiringCard
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/cards/expiring", query), nil, &cards); err != nil {
+		return nil, err
+	}
+	return cards, nil
+}
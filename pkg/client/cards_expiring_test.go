@@ -0,0 +1,34 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestClient_GetExpiringCards(t *testing.T) {
+	userName := "davos"
+	password := "onion knight"
+	expiring := []internal.ExpiringCard{{ID: 2, UserName: userName, BankName: "iron bank", LastDigits: "4444",
+		ExpiresOn: "2026-10", ExpiresAt: time.Date(2026, 11, 1, 0, 0, 0, 0, time.UTC)}}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("GetExpiringCards", mock.Anything, userName, mock.MatchedBy(func(before time.Time) bool {
+		return before.Before(time.Now().Add(time.Minute))
+	})).Return(expiring, nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	cards, err := c.GetExpiringCards(ctx, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, expiring, cards)
+}
@@ -0,0 +1,247 @@
+// Package client is the Go SDK of goph-keeper HTTP API.
+//
+// The client keeps the token of the opened session and sends it with every request of API v2.
+// The token is refreshed before it expires, failed idempotent requests are retried with exponential backoff
+// and the errors returned by the server are converted to *Error.
+//
+//	c := client.New("http://127.0.0.1:8080")
+//	if _, err := c.Login(ctx, "user_login", "user_password"); err != nil {
+//		return err
+//	}
+//	notes, err := c.GetNotes(ctx, client.NotesFilter{})
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultRetryCount   = 3
+	defaultRetryWait    = 100 * time.Millisecond
+	defaultRetryMaxWait = 2 * time.Second
+	// defaultRefreshBefore is how long before the expiration the token is refreshed.
+	defaultRefreshBefore = 5 * time.Minute
+	// idempotencyKeyHeader marks the POST requests which are safe to retry.
+	idempotencyKeyHeader = "Idempotency-Key"
+)
+
+// Client is goph-keeper API client, it's safe for concurrent use.
+type Client struct {
+	http          *resty.Client
+	mu            sync.Mutex
+	token         string
+	refreshBefore time.Duration
+	onRefresh     func(token string)
+}
+
+// Option configures the client.
+type Option func(c *Client)
+
+// WithHTTPClient sends requests with provided HTTP client, e.g. with custom transport or timeout.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Client) {
+		c.http = resty.NewWithClient(httpClient).
+			SetBaseURL(c.http.BaseURL).
+			SetRetryCount(c.http.RetryCount).
+			SetRetryWaitTime(c.http.RetryWaitTime).
+			SetRetryMaxWaitTime(c.http.RetryMaxWaitTime).
+			AddRetryCondition(retryable)
+	}
+}
+
+// WithRetries sets how many times failed requests are retried and the bounds of the exponential backoff.
+// Requests are retried on network errors and when the server or the proxy in front of it is unavailable.
+// POST requests are retried only if they carry the Idempotency-Key header.
+func WithRetries(count int, wait time.Duration, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.http.SetRetryCount(count).
+			SetRetryWaitTime(wait).
+			SetRetryMaxWaitTime(maxWait)
+	}
+}
+
+// WithToken restores the session opened before, e.g. by another process.
+func WithToken(token string) Option {
+	return func(c *Client) {
+		c.token = token
+	}
+}
+
+// WithTokenRefresh sets how long before the expiration the token is refreshed and the function
+// which is called with every new token, e.g. to persist it.
+func WithTokenRefresh(before time.Duration, onRefresh func(token string)) Option {
+	return func(c *Client) {
+		c.refreshBefore = before
+		c.onRefresh = onRefresh
+	}
+}
+
+// New creates the client of goph-keeper server with provided base URL, e.g. http://127.0.0.1:8080.
+func New(baseURL string, opts ...Option) *Client {
+	c := &Client{
+		http: resty.New().
+			SetBaseURL(strings.TrimSuffix(baseURL, "/")).
+			SetRetryCount(defaultRetryCount).
+			SetRetryWaitTime(defaultRetryWait).
+			SetRetryMaxWaitTime(defaultRetryMaxWait).
+			AddRetryCondition(retryable),
+		refreshBefore: defaultRefreshBefore,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
+}
+
+// Token returns the token of the opened session, empty if the client is not logged in.
+func (c *Client) Token() string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.token
+}
+
+// RefreshToken prolongs the session, the new token replaces the current one.
+func (c *Client) RefreshToken(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.refresh(ctx)
+}
+
+// setToken remembers the token returned in the Authorization header of the response.
+func (c *Client) setToken(resp *resty.Response) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.updateToken(resp)
+}
+
+func (c *Client) updateToken(resp *resty.Response) {
+	token := strings.TrimPrefix(resp.Header().Get("Authorization"), "Bearer ")
+	if token == "" {
+		return
+	}
+	c.token = token
+	if c.onRefresh != nil {
+		c.onRefresh(token)
+	}
+}
+
+// refresh requests the new token, the caller must hold the lock.
+func (c *Client) refresh(ctx context.Context) error {
+	if c.token == "" {
+		return ErrNotLoggedIn
+	}
+	resp, err := c.send(c.request(ctx).SetHeader("Authorization", fmt.Sprintf("Bearer %s", c.token)), http.MethodPost, "/auth/refresh")
+	if err != nil {
+		return err
+	}
+	c.updateToken(resp)
+	return nil
+}
+
+// sessionToken returns the token of the session, the token is refreshed if it's about to expire.
+func (c *Client) sessionToken(ctx context.Context) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.token == "" {
+		return "", ErrNotLoggedIn
+	}
+	var claims jwt.RegisteredClaims
+	if _, _, err := jwt.NewParser().ParseUnverified(c.token, &claims); err == nil && claims.ExpiresAt != nil {
+		left := time.Until(claims.ExpiresAt.Time)
+		// expired token can't be refreshed, the server tells the user to login again
+		if left > 0 && left < c.refreshBefore {
+			if err = c.refresh(ctx); err != nil {
+				return "", fmt.Errorf("error while refreshing token: %w", err)
+			}
+		}
+	}
+	return c.token, nil
+}
+
+func (c *Client) request(ctx context.Context) *resty.Request {
+	return c.http.R().
+		SetContext(ctx).
+		SetHeader("Content-Type", "application/json")
+}
+
+// authorizedRequest returns the request with the token of the session.
+func (c *Client) authorizedRequest(ctx context.Context) (*resty.Request, error) {
+	token, err := c.sessionToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return c.request(ctx).SetHeader("Authorization", fmt.Sprintf("Bearer %s", token)), nil
+}
+
+// send executes the request, unsuccessful responses are converted to *Error.
+func (c *Client) send(req *resty.Request, method string, path string) (*resty.Response, error) {
+	resp, err := req.Execute(method, path)
+	if err != nil {
+		return nil, fmt.Errorf("error while sending request: %w", err)
+	}
+	if !resp.IsSuccess() {
+		return resp, responseError(resp)
+	}
+	return resp, nil
+}
+
+// call executes the request of API v2 and decodes the data of the response into v, if it's not nil.
+func (c *Client) call(ctx context.Context, method string, path string, body any, v any) error {
+	req, err := c.authorizedRequest(ctx)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.SetBody(body)
+	}
+	resp, err := c.send(req, method, path)
+	if err != nil {
+		return err
+	}
+	if v == nil {
+		return nil
+	}
+	if err = json.Unmarshal(resp.Body(), &internal.Envelope{Data: v}); err != nil {
+		return fmt.Errorf("error while parsing server response: %w", err)
+	}
+	return nil
+}
+
+// retryable reports whether the request should be retried: on network errors and when the server
+// is unavailable. Other errors won't disappear on retry. Only idempotent requests are retried,
+// a repeated POST could save the item twice if the first one reached the server.
+func retryable(resp *resty.Response, err error) bool {
+	if resp == nil || resp.Request == nil || !idempotent(resp.Request) {
+		return false
+	}
+	if err != nil {
+		return true
+	}
+	switch resp.StatusCode() {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+// idempotent reports whether the request may be sent again without changing the result.
+func idempotent(req *resty.Request) bool {
+	switch req.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return req.Header.Get(idempotencyKeyHeader) != ""
+}
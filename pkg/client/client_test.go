@@ -0,0 +1,155 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/golang-jwt/jwt/v4"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/stretchr/testify/assert"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func testToken(t *testing.T, userName string, expiresAt time.Time) string {
+	claims := internal.Claims{
+		Username:         userName,
+		RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(expiresAt)},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("test"))
+	assert.NoError(t, err)
+	return token
+}
+
+func TestClient_Retries(t *testing.T) {
+	t.Run("positive: request is retried while the server is unavailable", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if calls.Add(1) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.Header().Set("Content-Type", "application/json")
+			_, _ = w.Write([]byte(`{"data":[{"id":1,"user_name":"arya","title":"list"}]}`))
+		}))
+		defer server.Close()
+
+		c := New(server.URL, WithToken(testToken(t, "arya", time.Now().Add(time.Hour))), WithRetries(3, time.Millisecond, 5*time.Millisecond))
+		notes, err := c.GetNotes(context.Background(), NotesFilter{})
+		assert.NoError(t, err)
+		assert.Len(t, notes, 1)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+	t.Run("negative: errors of the request are not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":{"code":"invalid_request","message":"invalid item id","request_id":"req-1"}}`))
+		}))
+		defer server.Close()
+
+		c := New(server.URL, WithToken(testToken(t, "arya", time.Now().Add(time.Hour))), WithRetries(3, time.Millisecond, 5*time.Millisecond))
+		_, err := c.GetNoteByID(context.Background(), 1)
+		assert.ErrorIs(t, err, ErrInvalidRequest)
+		var apiErr *Error
+		if assert.ErrorAs(t, err, &apiErr) {
+			assert.Equal(t, http.StatusBadRequest, apiErr.StatusCode)
+			assert.Equal(t, "invalid item id", apiErr.Message)
+			assert.Equal(t, "req-1", apiErr.RequestID)
+		}
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("negative: POST is not retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		c := New(server.URL, WithToken(testToken(t, "arya", time.Now().Add(time.Hour))), WithRetries(3, time.Millisecond, 5*time.Millisecond))
+		_, err := c.SaveNote(context.Background(), Note{})
+		assert.Error(t, err)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+	t.Run("positive: POST with the idempotency key is retried", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		c := New(server.URL, WithRetries(3, time.Millisecond, 5*time.Millisecond))
+		_, err := c.http.R().SetHeader(idempotencyKeyHeader, "key-1").Post("/api/v2/notes")
+		assert.NoError(t, err)
+		assert.Equal(t, int32(4), calls.Load())
+	})
+	t.Run("negative: retries are stopped by the context", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		c := New(server.URL, WithToken(testToken(t, "arya", time.Now().Add(time.Hour))), WithRetries(100, 10*time.Millisecond, 10*time.Millisecond))
+		_, err := c.GetCards(ctx, CardsFilter{})
+		assert.Error(t, err)
+	})
+}
+
+func TestClient_Errors(t *testing.T) {
+	t.Run("negative: response without the error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadGateway)
+			_, _ = w.Write([]byte("bad gateway"))
+		}))
+		defer server.Close()
+
+		c := New(server.URL, WithRetries(0, 0, 0))
+		err := c.Recover(context.Background(), RecoveryRequest{Login: "arya"})
+		assert.ErrorIs(t, err, ErrInternal)
+		assert.Equal(t, "internal_error: unexpected response 502 Bad Gateway: bad gateway", err.Error())
+	})
+	t.Run("negative: not logged in", func(t *testing.T) {
+		c := New("http://127.0.0.1:0")
+		_, err := c.GetCredentials(context.Background(), CredentialsFilter{})
+		assert.ErrorIs(t, err, ErrNotLoggedIn)
+	})
+	t.Run("positive: errors are compared by code", func(t *testing.T) {
+		err := fmt.Errorf("error while saving: %w", &Error{Code: internal.ErrorCodeDuplicateItem, Message: "credentials already exist"})
+		assert.True(t, errors.Is(err, ErrDuplicateItem))
+		assert.False(t, errors.Is(err, ErrNotFound))
+	})
+}
+
+func TestClient_TokenRefresh(t *testing.T) {
+	oldToken := testToken(t, "arya", time.Now().Add(time.Minute))
+	newToken := testToken(t, "arya", time.Now().Add(time.Hour))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/auth/refresh":
+			assert.Equal(t, "Bearer "+oldToken, r.Header.Get("Authorization"))
+			w.Header().Set("Authorization", "Bearer "+newToken)
+		default:
+			assert.Equal(t, "Bearer "+newToken, r.Header.Get("Authorization"))
+			_, _ = w.Write([]byte(`{"data":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	var refreshed string
+	c := New(server.URL, WithToken(oldToken), WithTokenRefresh(5*time.Minute, func(token string) {
+		refreshed = token
+	}))
+	creds, err := c.GetCredentials(context.Background(), CredentialsFilter{Login: "arya"})
+	assert.NoError(t, err)
+	assert.Empty(t, creds)
+	assert.Equal(t, newToken, c.Token())
+	assert.Equal(t, newToken, refreshed)
+}
@@ -0,0 +1,22 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetDueItems returns the credentials and the bank cards with the passwords due for a change according to their
+// rotation policies, ordered by the due time. The items which become due within the number of days are returned too.
+func (c *Client) GetDueItems(ctx context.Context, within int) ([]DueItem, error) {
+	query := url.Values{}
+	if within > 0 {
+		query.Set("within", strconv.Itoa(within))
+	}
+	var items []DueItem
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/due", query), nil, &items); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestClient_GetDueItems(t *testing.T) {
+	userName := "davos"
+	password := "onion knight"
+	changed := time.Date(2026, 7, 1, 12, 0, 0, 0, time.UTC)
+	due := []internal.DueItem{{Type: ItemTypeCard, ID: 2, UserName: userName, Name: "iron bank *4444", RotationDays: 90,
+		PasswordChangedAt: changed, DueAt: changed.AddDate(0, 0, 90)}}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("GetDueItems", mock.Anything, userName, mock.MatchedBy(func(before time.Time) bool {
+		return before.After(time.Now().AddDate(0, 0, 13))
+	})).Return(due, nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	items, err := c.GetDueItems(ctx, 14)
+	assert.NoError(t, err)
+	assert.Equal(t, due, items)
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/go-resty/resty/v2"
+	"github.com/kontik-pk/goph-keeper/internal"
+)
+
+// ErrNotLoggedIn is returned by the methods which require the session before the login.
+var ErrNotLoggedIn = errors.New("client is not logged in")
+
+// Errors from the server error catalogue, use errors.Is to check the returned error, e.g.
+//
+//	if errors.Is(err, client.ErrTokenExpired) {
+//		// login again
+//	}
+var (
+	ErrInvalidRequest      = &Error{Code: internal.ErrorCodeInvalidRequest}
+	ErrWeakPassword        = &Error{Code: internal.ErrorCodeWeakPassword}
+	ErrInvalidCredentials  = &Error{Code: internal.ErrorCodeInvalidCredentials}
+	ErrInvalidSecondFactor = &Error{Code: internal.ErrorCodeInvalidSecondFactor}
+	ErrInvalidRecoveryKey  = &Error{Code: internal.ErrorCodeInvalidRecoveryKey}
+	ErrPublicKeyLogin      = &Error{Code: internal.ErrorCodePublicKeyLoginFailed}
+	ErrUnauthorized        = &Error{Code: internal.ErrorCodeUnauthorized}
+	ErrInvalidToken        = &Error{Code: internal.ErrorCodeInvalidToken}
+	ErrTokenExpired        = &Error{Code: internal.ErrorCodeTokenExpired}
+	ErrSessionRevoked      = &Error{Code: internal.ErrorCodeSessionRevoked}
+	ErrForbidden           = &Error{Code: internal.ErrorCodeForbidden}
+	ErrNotFound            = &Error{Code: internal.ErrorCodeItemNotFound}
+	ErrUserAlreadyExists   = &Error{Code: internal.ErrorCodeUserAlreadyExists}
+	ErrDuplicateItem       = &Error{Code: internal.ErrorCodeDuplicateItem}
+	ErrTwoFactorEnabled    = &Error{Code: internal.ErrorCodeTwoFactorEnabled}
+	ErrKeyAlreadyExists    = &Error{Code: internal.ErrorCodeKeyAlreadyExists}
+	ErrTooManyRequests     = &Error{Code: internal.ErrorCodeTooManyRequests}
+	ErrInternal            = &Error{Code: internal.ErrorCodeInternal}
+)
+
+// Error is the error returned by the server, the code is one of the catalogue codes.
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Details    map[string]any
+	RequestID  string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s: %s", e.Code, e.Message)
+}
+
+// Is reports whether the target is the error with the same code, e.g. one of the Err* variables.
+func (e *Error) Is(target error) bool {
+	var t *Error
+	if !errors.As(target, &t) {
+		return false
+	}
+	return e.Code == t.Code
+}
+
+// responseError decodes the error returned by the server.
+// Responses which don't contain the error (e.g. from a proxy) get the internal error code.
+func responseError(resp *resty.Response) *Error {
+	var envelope internal.Envelope
+	if err := json.Unmarshal(resp.Body(), &envelope); err != nil || envelope.Error == nil {
+		return &Error{
+			StatusCode: resp.StatusCode(),
+			Code:       internal.ErrorCodeInternal,
+			Message:    fmt.Sprintf("unexpected response %s: %s", resp.Status(), resp.String()),
+		}
+	}
+	return &Error{
+		StatusCode: resp.StatusCode(),
+		Code:       envelope.Error.Code,
+		Message:    envelope.Error.Message,
+		Details:    envelope.Error.Details,
+		RequestID:  envelope.Error.RequestID,
+	}
+}
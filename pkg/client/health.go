@@ -0,0 +1,25 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// GetHealthReport returns the password hygiene report of the credentials: weak, reused and old passwords
+// and URL fields using plain http. Only the credentials with issues are listed, the passwords are not returned.
+func (c *Client) GetHealthReport(ctx context.Context, opts HealthOptions) (HealthReport, error) {
+	query := url.Values{}
+	if opts.MinScore != nil {
+		query.Set("min_score", strconv.Itoa(*opts.MinScore))
+	}
+	if opts.MaxAgeDays > 0 {
+		query.Set("max_age_days", strconv.Itoa(opts.MaxAgeDays))
+	}
+	var report HealthReport
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/report/health", query), nil, &report); err != nil {
+		return HealthReport{}, err
+	}
+	return report, nil
+}
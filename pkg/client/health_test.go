@@ -0,0 +1,39 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+)
+
+func TestClient_GetHealthReport(t *testing.T) {
+	userName := "davos"
+	password := "onion knight"
+	login := "smuggler"
+	changed := time.Now().AddDate(0, 0, -100)
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("ListCredentials", mock.Anything, internal.Credentials{UserName: userName}, internal.ListOptions{}).
+		Return([]internal.Credentials{{ID: 7, UserName: userName, Login: &login, Password: &password,
+			Rotation: internal.Rotation{PasswordChangedAt: &changed}}}, "", nil)
+	mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeCredentials, []int64{7}).Return(nil, nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	minScore := 4
+	report, err := c.GetHealthReport(ctx, HealthOptions{MinScore: &minScore, MaxAgeDays: 90})
+	assert.NoError(t, err)
+	assert.Equal(t, 4, report.MinScore)
+	assert.Equal(t, 90, report.MaxAgeDays)
+	assert.Equal(t, internal.HealthSummary{Weak: 1, Old: 1}, report.Summary)
+	assert.Equal(t, []string{internal.HealthIssueWeak, internal.HealthIssueOld}, report.Credentials[0].Issues)
+}
@@ -0,0 +1,38 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SaveIdentity saves the identity document and returns it with the id assigned by the server.
+// The scans must be uploaded before.
+func (c *Client) SaveIdentity(ctx context.Context, identity Identity) (Identity, error) {
+	var saved Identity
+	if err := c.call(ctx, http.MethodPost, "/api/v2/identities", identity, &saved); err != nil {
+		return Identity{}, err
+	}
+	return saved, nil
+}
+
+// GetIdentities returns the identity documents selected by the filter ordered by name,
+// or by the expiry date if ExpiresBefore is set.
+func (c *Client) GetIdentities(ctx context.Context, filter IdentitiesFilter) ([]Identity, error) {
+	query := url.Values{}
+	setQuery(query, "name", filter.Name)
+	setQuery(query, "type", filter.DocumentType)
+	setQuery(query, "expires_before", filter.ExpiresBefore)
+
+	var identities []Identity
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/identities", query), nil, &identities); err != nil {
+		return nil, err
+	}
+	return identities, nil
+}
+
+// DeleteIdentity deletes the identity document by id, the scans are kept.
+func (c *Client) DeleteIdentity(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/identities/%d", id), nil, nil)
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestClient_Identities(t *testing.T) {
+	userName := "samwell"
+	password := "the citadel"
+	name := "licence"
+	number := "TARLY912"
+	expires := "2027-03-01"
+	stored := internal.Identity{ID: 4, UserName: userName, Name: &name, DocumentType: DocumentTypeDriversLicence,
+		FullName: "Samwell Tarly", Number: &number, ExpiresOn: &expires}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("SaveIdentity", mock.Anything, internal.Identity{UserName: userName, Name: &name, DocumentType: DocumentTypeDriversLicence,
+		FullName: "Samwell Tarly", Number: &number, ExpiresOn: &expires}).Return(int64(4), nil)
+	mockedStorage.On("GetIdentities", mock.Anything, internal.Identity{UserName: userName}, "2027-06-01").Return([]internal.Identity{stored}, nil)
+	mockedStorage.On("DeleteIdentity", mock.Anything, internal.Identity{UserName: userName, ID: 4}).Return(nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	saved, err := c.SaveIdentity(ctx, Identity{Name: &name, DocumentType: DocumentTypeDriversLicence, FullName: "Samwell Tarly",
+		Number: &number, ExpiresOn: &expires})
+	assert.NoError(t, err)
+	assert.Equal(t, stored, saved)
+
+	expiring, err := c.GetIdentities(ctx, IdentitiesFilter{ExpiresBefore: "2027-06-01"})
+	assert.NoError(t, err)
+	assert.Equal(t, []Identity{stored}, expiring)
+
+	assert.NoError(t, c.DeleteIdentity(ctx, saved.ID))
+}
@@ -0,0 +1,285 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/http"
+	"net/url"
+	"strconv"
+)
+
+// SaveCredentials saves the credentials and returns them with the id assigned by the server.
+func (c *Client) SaveCredentials(ctx context.Context, creds Credentials) (Credentials, error) {
+	var saved Credentials
+	if err := c.call(ctx, http.MethodPost, "/api/v2/credentials", creds, &saved); err != nil {
+		return Credentials{}, err
+	}
+	return saved, nil
+}
+
+// GetCredentials returns all the credentials matching the filter, the pages are requested one by one.
+func (c *Client) GetCredentials(ctx context.Context, filter CredentialsFilter) ([]Credentials, error) {
+	var creds []Credentials
+	opts := ListOptions{}
+	for {
+		page, next, err := c.ListCredentials(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		creds = append(creds, page...)
+		if next == "" {
+			return creds, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+// ListCredentials returns the page of the credentials matching the filter and the cursor of the next page,
+// which is empty for the last page.
+func (c *Client) ListCredentials(ctx context.Context, filter CredentialsFilter, opts ListOptions) ([]Credentials, string, error) {
+	query := url.Values{}
+	setQuery(query, "login", filter.Login)
+	if filter.BreachCheck {
+		query.Set("breach_check", "true")
+	}
+
+	var creds []Credentials
+	next, err := c.list(ctx, "/api/v2/credentials", query, opts, &creds)
+	if err != nil {
+		return nil, "", err
+	}
+	return creds, next, nil
+}
+
+// GetCredentialsByID returns the credentials by id.
+func (c *Client) GetCredentialsByID(ctx context.Context, id int64) (Credentials, error) {
+	var creds Credentials
+	if err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/v2/credentials/%d", id), nil, &creds); err != nil {
+		return Credentials{}, err
+	}
+	return creds, nil
+}
+
+// UpdateCredentials replaces the credentials with the id of provided ones.
+func (c *Client) UpdateCredentials(ctx context.Context, creds Credentials) (Credentials, error) {
+	var updated Credentials
+	if err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/v2/credentials/%d", creds.ID), creds, &updated); err != nil {
+		return Credentials{}, err
+	}
+	return updated, nil
+}
+
+// DeleteCredentials deletes the credentials by id.
+func (c *Client) DeleteCredentials(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/credentials/%d", id), nil, nil)
+}
+
+// SaveNote saves the note and returns it with the id assigned by the server.
+func (c *Client) SaveNote(ctx context.Context, note Note) (Note, error) {
+	var saved Note
+	if err := c.call(ctx, http.MethodPost, "/api/v2/notes", note, &saved); err != nil {
+		return Note{}, err
+	}
+	return saved, nil
+}
+
+// GetNotes returns all the notes matching the filter, the pages are requested one by one.
+func (c *Client) GetNotes(ctx context.Context, filter NotesFilter) ([]Note, error) {
+	var notes []Note
+	opts := ListOptions{}
+	for {
+		page, next, err := c.ListNotes(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		notes = append(notes, page...)
+		if next == "" {
+			return notes, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+// ListNotes returns the page of the notes matching the filter and the cursor of the next page,
+// which is empty for the last page.
+func (c *Client) ListNotes(ctx context.Context, filter NotesFilter, opts ListOptions) ([]Note, string, error) {
+	query := url.Values{}
+	setQuery(query, "title", filter.Title)
+
+	var notes []Note
+	next, err := c.list(ctx, "/api/v2/notes", query, opts, &notes)
+	if err != nil {
+		return nil, "", err
+	}
+	return notes, next, nil
+}
+
+// GetNoteByID returns the note by id.
+func (c *Client) GetNoteByID(ctx context.Context, id int64) (Note, error) {
+	var note Note
+	if err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/v2/notes/%d", id), nil, &note); err != nil {
+		return Note{}, err
+	}
+	return note, nil
+}
+
+// UpdateNote replaces the note with the id of provided one.
+func (c *Client) UpdateNote(ctx context.Context, note Note) (Note, error) {
+	var updated Note
+	if err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/v2/notes/%d", note.ID), note, &updated); err != nil {
+		return Note{}, err
+	}
+	return updated, nil
+}
+
+// DeleteNote deletes the note by id.
+func (c *Client) DeleteNote(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/notes/%d", id), nil, nil)
+}
+
+// SaveCard saves the bank card and returns it with the id assigned by the server.
+func (c *Client) SaveCard(ctx context.Context, card Card) (Card, error) {
+	var saved Card
+	if err := c.call(ctx, http.MethodPost, "/api/v2/cards", card, &saved); err != nil {
+		return Card{}, err
+	}
+	return saved, nil
+}
+
+// GetCards returns all the bank cards matching the filter, the pages are requested one by one.
+func (c *Client) GetCards(ctx context.Context, filter CardsFilter) ([]Card, error) {
+	var cards []Card
+	opts := ListOptions{}
+	for {
+		page, next, err := c.ListCards(ctx, filter, opts)
+		if err != nil {
+			return nil, err
+		}
+		cards = append(cards, page...)
+		if next == "" {
+			return cards, nil
+		}
+		opts.Cursor = next
+	}
+}
+
+// ListCards returns the page of the bank cards matching the filter and the cursor of the next page,
+// which is empty for the last page.
+func (c *Client) ListCards(ctx context.Context, filter CardsFilter, opts ListOptions) ([]Card, string, error) {
+	query := url.Values{}
+	setQuery(query, "bank_name", filter.BankName)
+	setQuery(query, "number", filter.Number)
+
+	var cards []Card
+	next, err := c.list(ctx, "/api/v2/cards", query, opts, &cards)
+	if err != nil {
+		return nil, "", err
+	}
+	return cards, next, nil
+}
+
+// GetCardByID returns the bank card by id.
+func (c *Client) GetCardByID(ctx context.Context, id int64) (Card, error) {
+	var card Card
+	if err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/v2/cards/%d", id), nil, &card); err != nil {
+		return Card{}, err
+	}
+	return card, nil
+}
+
+// UpdateCard replaces the bank card with the id of provided one.
+func (c *Client) UpdateCard(ctx context.Context, card Card) (Card, error) {
+	var updated Card
+	if err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/v2/cards/%d", card.ID), card, &updated); err != nil {
+		return Card{}, err
+	}
+	return updated, nil
+}
+
+// DeleteCard deletes the bank card by id.
+func (c *Client) DeleteCard(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/cards/%d", id), nil, nil)
+}
+
+// Search returns the items with the names or the metadata matching the query, ordered by relevance.
+// Zero limit means the default limit of the server.
+func (c *Client) Search(ctx context.Context, query string, limit int) ([]SearchResult, error) {
+	params := url.Values{}
+	params.Set("q", query)
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+
+	var results []SearchResult
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/search", params), nil, &results); err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+// list requests the page of the items and returns the cursor of the next page.
+func (c *Client) list(ctx context.Context, path string, query url.Values, opts ListOptions, v any) (string, error) {
+	if opts.Limit > 0 {
+		query.Set("limit", strconv.Itoa(opts.Limit))
+	}
+	setQuery(query, "cursor", opts.Cursor)
+	if opts.Sort != "" {
+		sort := opts.Sort
+		if opts.Descending {
+			sort = "-" + sort
+		}
+		query.Set("sort", sort)
+	}
+	setQuery(query, "metadata", opts.Metadata)
+	for _, tag := range opts.Tags {
+		query.Add("tag", tag)
+	}
+	if opts.FolderID != nil {
+		query.Set("folder", strconv.FormatInt(*opts.FolderID, 10))
+	}
+	if opts.Favorite != nil {
+		query.Set("favorite", strconv.FormatBool(*opts.Favorite))
+	}
+
+	req, err := c.authorizedRequest(ctx)
+	if err != nil {
+		return "", err
+	}
+	resp, err := c.send(req, http.MethodGet, withQuery(path, query))
+	if err != nil {
+		return "", err
+	}
+	envelope := internal.Envelope{Data: v}
+	if err = json.Unmarshal(resp.Body(), &envelope); err != nil {
+		return "", fmt.Errorf("error while parsing server response: %w", err)
+	}
+	if envelope.Page == nil {
+		return "", nil
+	}
+	return envelope.Page.NextCursor, nil
+}
+
+// setQuery sets the query parameter if the value is not empty.
+func setQuery(query url.Values, name string, value string) {
+	if value != "" {
+		query.Set(name, value)
+	}
+}
+
+func withQuery(path string, query url.Values) string {
+	if len(query) == 0 {
+		return path
+	}
+	return fmt.Sprintf("%s?%s", path, query.Encode())
+}
+
+// GeneratePassword returns the password or the passphrase generated by the server with the options.
+func (c *Client) GeneratePassword(ctx context.Context, opts GenerateOptions) (string, error) {
+	var generated GeneratedPassword
+	if err := c.call(ctx, http.MethodPost, "/api/v2/generate", opts, &generated); err != nil {
+		return "", err
+	}
+	return generated.Password, nil
+}
@@ -0,0 +1,178 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/handlers/router"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.uber.org/zap"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func newTestServer(t *testing.T, mockedStorage *mocks.Storage) *Client {
+	logger, _ := zap.NewProduction()
+	server := httptest.NewServer(router.New(mockedStorage, logger.Sugar()))
+	t.Cleanup(server.Close)
+	return New(server.URL, WithRetries(0, 0, 0))
+}
+
+func TestClient_Credentials(t *testing.T) {
+	userName := "arya"
+	password := "needle and valar morghulis"
+	login := "faceless"
+	secret := "no one"
+
+	t.Run("positive: login, save, list and delete credentials", func(t *testing.T) {
+		fields := []Field{{Name: "house", Type: FieldTypeText, Value: "none"}}
+		stored := internal.Credentials{ID: 7, UserName: userName, Login: &login, Password: &secret}
+		saved := internal.Credentials{ID: 7, UserName: userName, Login: &login, Password: &secret, Fields: fields}
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("SaveCredentials", mock.Anything, internal.Credentials{UserName: userName, Login: &login, Password: &secret, Fields: fields}).Return(int64(7), nil)
+		mockedStorage.On("ListCredentials", mock.Anything, internal.Credentials{UserName: userName, Login: &login}, internal.ListOptions{Limit: 50}).Return([]internal.Credentials{stored}, "", nil)
+		mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeCredentials, []int64{7}).Return(map[int64][]internal.Field{7: fields}, nil)
+		mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName, ID: 7}).Return([]internal.Credentials{stored}, nil)
+		mockedStorage.On("DeleteCredentials", mock.Anything, internal.Credentials{UserName: userName, ID: 7}).Return(nil)
+		c := newTestServer(t, mockedStorage)
+		ctx := context.Background()
+
+		challenge, err := c.Login(ctx, userName, password)
+		assert.NoError(t, err)
+		assert.Empty(t, challenge)
+		name, err := c.UserName()
+		assert.NoError(t, err)
+		assert.Equal(t, userName, name)
+
+		creds, err := c.SaveCredentials(ctx, Credentials{Login: &login, Password: &secret, Fields: fields})
+		assert.NoError(t, err)
+		assert.Equal(t, saved, creds)
+
+		list, err := c.GetCredentials(ctx, CredentialsFilter{Login: login})
+		assert.NoError(t, err)
+		assert.Equal(t, []Credentials{saved}, list)
+
+		assert.NoError(t, c.DeleteCredentials(ctx, creds.ID))
+	})
+	t.Run("positive: login with two-factor authentication returns the challenge", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{Login: userName, Enabled: true}, nil)
+		c := newTestServer(t, mockedStorage)
+
+		challenge, err := c.Login(context.Background(), userName, password)
+		assert.NoError(t, err)
+		assert.NotEmpty(t, challenge)
+		assert.Empty(t, c.Token())
+	})
+	t.Run("negative: wrong password", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, "wrong").Return(database.ErrInvalidCredentials)
+		c := newTestServer(t, mockedStorage)
+
+		_, err := c.Login(context.Background(), userName, "wrong")
+		assert.ErrorIs(t, err, ErrInvalidCredentials)
+	})
+	t.Run("positive: all pages are returned", func(t *testing.T) {
+		first := internal.Credentials{ID: 1, UserName: userName, Login: &login, Password: &secret}
+		second := internal.Credentials{ID: 2, UserName: userName, Login: &login, Password: &secret}
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("ListCredentials", mock.Anything, internal.Credentials{UserName: userName}, internal.ListOptions{Limit: 1, Sort: SortByUpdated, Descending: true}).
+			Return([]internal.Credentials{first}, "next", nil)
+		mockedStorage.On("ListCredentials", mock.Anything, internal.Credentials{UserName: userName}, internal.ListOptions{Limit: 50}).
+			Return([]internal.Credentials{first}, "next", nil)
+		mockedStorage.On("ListCredentials", mock.Anything, internal.Credentials{UserName: userName}, internal.ListOptions{Limit: 50, Cursor: "next"}).
+			Return([]internal.Credentials{second}, "", nil)
+		mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeCredentials, mock.Anything).
+			Return(map[int64][]internal.Field{}, nil)
+		c := newTestServer(t, mockedStorage)
+		ctx := context.Background()
+
+		_, err := c.Login(ctx, userName, password)
+		assert.NoError(t, err)
+		page, next, err := c.ListCredentials(ctx, CredentialsFilter{}, ListOptions{Limit: 1, Sort: SortByUpdated, Descending: true})
+		assert.NoError(t, err)
+		assert.Equal(t, []Credentials{first}, page)
+		assert.Equal(t, "next", next)
+
+		all, err := c.GetCredentials(ctx, CredentialsFilter{})
+		assert.NoError(t, err)
+		assert.Equal(t, []Credentials{first, second}, all)
+	})
+	t.Run("negative: credentials not found", func(t *testing.T) {
+		mockedStorage := mocks.NewStorage(t)
+		mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+		mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+		mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName, ID: 3}).Return(nil, database.ErrNoData)
+		c := newTestServer(t, mockedStorage)
+		ctx := context.Background()
+
+		_, err := c.Login(ctx, userName, password)
+		assert.NoError(t, err)
+		_, err = c.GetCredentialsByID(ctx, 3)
+		assert.ErrorIs(t, err, ErrNotFound)
+	})
+}
+
+func TestClient_Search(t *testing.T) {
+	userName := "bran"
+	password := "three-eyed raven"
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("Search", mock.Anything, userName, "vpn & co", 5).
+		Return([]internal.SearchResult{{Type: internal.ItemTypeNote, ID: 3, Name: "vpn", Rank: 0.5}}, nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	results, err := c.Search(ctx, "vpn & co", 5)
+	assert.NoError(t, err)
+	assert.Equal(t, []SearchResult{{Type: ItemTypeNote, ID: 3, Name: "vpn", Rank: 0.5}}, results)
+}
+
+func TestClient_GeneratePassword(t *testing.T) {
+	userName := "bran"
+	password := "three-eyed raven"
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	passphrase, err := c.GeneratePassword(ctx, GenerateOptions{Words: 5, Separator: "."})
+	assert.NoError(t, err)
+	assert.Len(t, strings.Split(passphrase, "."), 5)
+	_, err = c.GeneratePassword(ctx, GenerateOptions{Length: 4})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+}
+
+func TestClient_CredentialsBreachCheck(t *testing.T) {
+	userName := "arya"
+	password := "needle and valar morghulis"
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	// the test server has no breached password source
+	_, _, err = c.ListCredentials(ctx, CredentialsFilter{BreachCheck: true}, ListOptions{})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.ErrorContains(t, err, "breached passwords source is not configured")
+}
@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"net/http"
+)
+
+// SetLabels replaces the tags, the folder and the favourite flag of the item, itemType is ItemTypeCredentials,
+// ItemTypeNote or ItemTypeCard. Other items have no labels, ErrInvalidRequest is returned for them.
+func (c *Client) SetLabels(ctx context.Context, itemType string, id int64, labels Labels) error {
+	switch itemType {
+	case ItemTypeCredentials, ItemTypeNote, ItemTypeCard:
+	default:
+		return &Error{Code: internal.ErrorCodeInvalidRequest, Message: fmt.Sprintf("%s have no labels", itemType)}
+	}
+	return c.call(ctx, http.MethodPut, fmt.Sprintf("/api/v2/%s/%d/labels", itemType, id), labels, nil)
+}
+
+// GetTags returns all the tags of the user's items.
+func (c *Client) GetTags(ctx context.Context) ([]string, error) {
+	var tags []string
+	if err := c.call(ctx, http.MethodGet, "/api/v2/tags", nil, &tags); err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// GetFolders returns all the folders of the user, parents go before their subfolders.
+func (c *Client) GetFolders(ctx context.Context) ([]Folder, error) {
+	var folders []Folder
+	if err := c.call(ctx, http.MethodGet, "/api/v2/folders", nil, &folders); err != nil {
+		return nil, err
+	}
+	return folders, nil
+}
+
+// CreateFolder creates the folder and returns it with the id assigned by the server.
+func (c *Client) CreateFolder(ctx context.Context, folder Folder) (Folder, error) {
+	var created Folder
+	if err := c.call(ctx, http.MethodPost, "/api/v2/folders", folder, &created); err != nil {
+		return Folder{}, err
+	}
+	return created, nil
+}
+
+// UpdateFolder renames the folder with the id of provided one and moves it to its parent.
+func (c *Client) UpdateFolder(ctx context.Context, folder Folder) (Folder, error) {
+	var updated Folder
+	if err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/v2/folders/%d", folder.ID), folder, &updated); err != nil {
+		return Folder{}, err
+	}
+	return updated, nil
+}
+
+// DeleteFolder deletes the folder by id with its subfolders, the items of the folders are kept.
+func (c *Client) DeleteFolder(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/folders/%d", id), nil, nil)
+}
@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestClient_Labels(t *testing.T) {
+	userName := "tyrion"
+	password := "i drink and i know things"
+	folderID := int64(2)
+	favorite := true
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("SaveFolder", mock.Anything, internal.Folder{UserName: userName, Name: "wine"}).Return(folderID, nil)
+	mockedStorage.On("SetLabels", mock.Anything, userName, internal.ItemTypeNote, int64(7),
+		internal.Labels{Tags: []string{"dornish"}, FolderID: &folderID, Favorite: true}).Return(nil)
+	mockedStorage.On("ListNotes", mock.Anything, internal.Note{UserName: userName},
+		internal.ListOptions{Limit: 50, Tags: []string{"dornish"}, FolderID: &folderID, Favorite: &favorite}).Return(nil, "", nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	folder, err := c.CreateFolder(ctx, Folder{Name: "wine"})
+	assert.NoError(t, err)
+	assert.Equal(t, Folder{ID: folderID, UserName: userName, Name: "wine"}, folder)
+
+	err = c.SetLabels(ctx, ItemTypeNote, 7, Labels{Tags: []string{"dornish"}, FolderID: &folder.ID, Favorite: true})
+	assert.NoError(t, err)
+
+	notes, _, err := c.ListNotes(ctx, NotesFilter{}, ListOptions{Tags: []string{"dornish"}, FolderID: &folderID, Favorite: &favorite})
+	assert.NoError(t, err)
+	assert.Empty(t, notes)
+
+	err = c.SetLabels(ctx, ItemTypeFile, 7, Labels{Favorite: true})
+	assert.ErrorIs(t, err, ErrInvalidRequest)
+	assert.EqualError(t, err, "invalid_request: files have no labels")
+}
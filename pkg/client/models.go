@@ -0,0 +1,112 @@
+package client
+
+import (
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/password"
+	"github.com/kontik-pk/goph-keeper/internal/webauthn"
+)
+
+// The models are shared with the server, so the client always sends what the server expects.
+type (
+	Credentials       = internal.Credentials
+	Note              = internal.Note
+	Card              = internal.Card
+	User              = internal.User
+	VaultKey          = internal.VaultKey
+	AccountRequest    = internal.AccountRequest
+	RecoveryRequest   = internal.RecoveryRequest
+	PublicKey         = internal.PublicKey
+	KeyRegistration   = internal.KeyRegistration
+	TwoFactorSetup    = internal.TwoFactorSetup
+	UnlockRequest     = internal.UnlockRequest
+	Assertion         = webauthn.Assertion
+	ListOptions       = internal.ListOptions
+	SearchResult      = internal.SearchResult
+	Labels            = internal.Labels
+	Rotation          = internal.Rotation
+	DueItem           = internal.DueItem
+	ExpiringCard      = internal.ExpiringCard
+	HealthReport      = internal.HealthReport
+	CredentialsHealth = internal.CredentialsHealth
+	Folder            = internal.Folder
+	Field             = internal.Field
+	GeneratedPassword = internal.GeneratedPassword
+	GenerateOptions   = password.GenerateOptions
+	TOTP              = internal.TOTP
+	TOTPCode          = internal.TOTPCode
+	SSHKey            = internal.SSHKey
+	Identity          = internal.Identity
+	Secret            = internal.Secret
+)
+
+// Sort fields of ListOptions.
+const (
+	SortByName    = internal.SortByName
+	SortByCreated = internal.SortByCreated
+	SortByUpdated = internal.SortByUpdated
+)
+
+// Types of the vault items, authenticators, identities and secrets are not searched.
+const (
+	ItemTypeCredentials = internal.ItemTypeCredentials
+	ItemTypeNote        = internal.ItemTypeNote
+	ItemTypeCard        = internal.ItemTypeCard
+	ItemTypeFile        = internal.ItemTypeFile
+	ItemTypeTOTP        = internal.ItemTypeTOTP
+	ItemTypeSSHKey      = internal.ItemTypeSSHKey
+	ItemTypeIdentity    = internal.ItemTypeIdentity
+	ItemTypeSecret      = internal.ItemTypeSecret
+)
+
+// Types of the custom fields in Field.
+const (
+	FieldTypeText    = internal.FieldTypeText
+	FieldTypeHidden  = internal.FieldTypeHidden
+	FieldTypeBoolean = internal.FieldTypeBoolean
+	FieldTypeURL     = internal.FieldTypeURL
+	FieldTypeDate    = internal.FieldTypeDate
+)
+
+// Types of the identity documents in Identity.
+const (
+	DocumentTypePassport       = internal.DocumentTypePassport
+	DocumentTypeIDCard         = internal.DocumentTypeIDCard
+	DocumentTypeDriversLicence = internal.DocumentTypeDriversLicence
+	DocumentTypeTaxID          = internal.DocumentTypeTaxID
+	DocumentTypeOther          = internal.DocumentTypeOther
+)
+
+// CredentialsFilter selects credentials in GetCredentials, empty fields match all credentials.
+type CredentialsFilter struct {
+	Login string
+	// BreachCheck requests the number of the password appearances in known data breaches (Credentials.BreachCount),
+	// the server should have the breached password source.
+	BreachCheck bool
+}
+
+// HealthOptions are the thresholds of GetHealthReport, nil or zero fields use the defaults of the server.
+type HealthOptions struct {
+	// MinScore is the zxcvbn strength score (0-4) below which the passwords are weak.
+	MinScore *int
+	// MaxAgeDays is the age of the passwords after which they are old.
+	MaxAgeDays int
+}
+
+// NotesFilter selects notes in GetNotes, empty fields match all notes.
+type NotesFilter struct {
+	Title string
+}
+
+// CardsFilter selects bank cards in GetCards, empty fields match all cards.
+type CardsFilter struct {
+	BankName string
+	Number   string
+}
+
+// IdentitiesFilter selects identity documents in GetIdentities, empty fields match all documents.
+// ExpiresBefore is a date in YYYY-MM-DD format, documents expiring before it are returned.
+type IdentitiesFilter struct {
+	Name          string
+	DocumentType  string
+	ExpiresBefore string
+}
@@ -0,0 +1,316 @@
+package client
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// ReferenceScheme prefixes the references to the fields of vault items, e.g. keeper://notes/vpn/content.
+const ReferenceScheme = "keeper://"
+
+// ErrInvalidReference is returned for references which can't be resolved: malformed, to unknown item types,
+// missing items or missing fields.
+var ErrInvalidReference = errors.New("invalid reference")
+
+// ErrAmbiguousReference is returned for references by name matching several items, e.g. the cards of the same bank,
+// they should refer to the item by id. It wraps ErrInvalidReference.
+var ErrAmbiguousReference = fmt.Errorf("%w: ambiguous reference", ErrInvalidReference)
+
+// Prefixes of the custom fields and the keys of secrets in the references, the built-in attributes of the items
+// take precedence over the fields and the keys with the same names, so they are only available with the prefixes.
+const (
+	fieldsPrefix = "fields."
+	valuesPrefix = "values."
+)
+
+var (
+	// referencePattern finds the references in the text, a reference ends at a space, a quote, a bracket or a brace.
+	referencePattern = regexp.MustCompile(`keeper://[^\s"'<>(){}\[\]]+`)
+	// actionPattern finds the template actions, the references inside them are left to the template.
+	actionPattern = regexp.MustCompile(`(?s)\{\{.*?\}\}`)
+)
+
+// Reference points to the field of the vault item: keeper://<type>/<name>/<field> or keeper://<type>/#<id>/<field>.
+// The name may contain slashes, the segments may be URL-escaped, so the names starting with # are written as %23.
+type Reference struct {
+	// Type is one of ItemType* types.
+	Type string
+	// Name is the login of the credentials, the title of the note, the bank name of the card
+	// or the name of the other items.
+	Name string
+	// ID refers to the item by id instead of the name, e.g. one of the cards of the same bank.
+	ID int64
+	// Field is the attribute of the item (password, content, cv...), the name of the custom field
+	// or the key of the secret. The fields and the keys named as the attributes are referred with fields.
+	// and values. prefixes. Authenticators have the current one-time password in the code field.
+	Field string
+}
+
+func (r Reference) String() string {
+	return fmt.Sprintf("%s%s/%s/%s", ReferenceScheme, r.Type, r.item(), url.PathEscape(r.Field))
+}
+
+// item returns the escaped name or the id of the referenced item.
+func (r Reference) item() string {
+	if r.ID != 0 {
+		return fmt.Sprintf("#%d", r.ID)
+	}
+	return url.PathEscape(r.Name)
+}
+
+// ParseReference parses the reference with or without keeper:// prefix.
+func ParseReference(ref string) (Reference, error) {
+	path := strings.TrimPrefix(ref, ReferenceScheme)
+	first, last := strings.Index(path, "/"), strings.LastIndex(path, "/")
+	if first <= 0 || last == first || last == len(path)-1 {
+		return Reference{}, fmt.Errorf("%w %q: should be %s<type>/<name>/<field>", ErrInvalidReference, ref, ReferenceScheme)
+	}
+	field, err := url.PathUnescape(path[last+1:])
+	if err != nil {
+		return Reference{}, fmt.Errorf("%w %q: %s", ErrInvalidReference, ref, err)
+	}
+	item := path[first+1 : last]
+	if strings.HasPrefix(item, "#") {
+		id, err := strconv.ParseInt(item[1:], 10, 64)
+		if err != nil || id <= 0 {
+			return Reference{}, fmt.Errorf("%w %q: id should be a positive number", ErrInvalidReference, ref)
+		}
+		return Reference{Type: path[:first], ID: id, Field: field}, nil
+	}
+	name, err := url.PathUnescape(item)
+	if err != nil {
+		return Reference{}, fmt.Errorf("%w %q: %s", ErrInvalidReference, ref, err)
+	}
+	return Reference{Type: path[:first], Name: name, Field: field}, nil
+}
+
+// Resolver resolves the references to the fields of the vault items with the client.
+// The items are requested once and cached, so the resolver should not outlive a single rendering.
+type Resolver struct {
+	client *Client
+	items  map[string]map[string]any
+}
+
+// NewResolver creates the resolver of the references with the client of the logged-in user.
+func NewResolver(c *Client) *Resolver {
+	return &Resolver{client: c, items: make(map[string]map[string]any)}
+}
+
+// Resolve returns the value of the referenced field, the reference is in keeper://<type>/<name>/<field> format.
+func (r *Resolver) Resolve(ctx context.Context, ref string) (string, error) {
+	reference, err := ParseReference(ref)
+	if err != nil {
+		return "", err
+	}
+	return r.ResolveReference(ctx, reference)
+}
+
+// ResolveReference returns the value of the referenced field.
+func (r *Resolver) ResolveReference(ctx context.Context, ref Reference) (string, error) {
+	attributes, err := r.item(ctx, ref)
+	if err != nil {
+		return "", err
+	}
+	if ref.Type == ItemTypeTOTP && ref.Field == "code" {
+		id, err := attributes["id"].(json.Number).Int64()
+		if err != nil {
+			return "", err
+		}
+		code, err := r.client.GetTOTPCode(ctx, id)
+		if err != nil {
+			return "", err
+		}
+		return code.Code, nil
+	}
+	if value, ok := attributes[ref.Field]; ok && value != nil {
+		return fmt.Sprint(value), nil
+	}
+	return "", fmt.Errorf("%w %s: %s has no field %q", ErrInvalidReference, ref, ref.Type, ref.Field)
+}
+
+// Render executes the template with the keeper function and replaces the references in the text with the values
+// of the fields. Both {{ keeper "credentials/db-prod" "password" }} and keeper://credentials/db-prod/password are resolved,
+// the template name is used in the error messages. The substituted values are not searched for references.
+func (r *Resolver) Render(ctx context.Context, name string, text string) (string, error) {
+	tmpl, err := template.New(name).Funcs(template.FuncMap{
+		"keeper": func(item string, field ...string) (string, error) {
+			if len(field) == 0 {
+				return r.Resolve(ctx, item)
+			}
+			if len(field) > 1 {
+				return "", fmt.Errorf("%w: keeper takes the item and the field", ErrInvalidReference)
+			}
+			return r.Resolve(ctx, strings.TrimSuffix(item, "/")+"/"+url.PathEscape(field[0]))
+		},
+	}).Parse(referenceActions(text))
+	if err != nil {
+		return "", fmt.Errorf("error while parsing template %s: %w", name, err)
+	}
+	var rendered bytes.Buffer
+	if err = tmpl.Execute(&rendered, nil); err != nil {
+		return "", fmt.Errorf("error while rendering template %s: %w", name, err)
+	}
+	return rendered.String(), nil
+}
+
+// referenceActions replaces the references outside of the template actions with the keeper calls.
+func referenceActions(text string) string {
+	var result strings.Builder
+	position := 0
+	for _, action := range actionPattern.FindAllStringIndex(text, -1) {
+		result.WriteString(referencePattern.ReplaceAllStringFunc(text[position:action[0]], func(ref string) string {
+			return fmt.Sprintf("{{ keeper %s }}", strconv.Quote(ref))
+		}))
+		result.WriteString(text[action[0]:action[1]])
+		position = action[1]
+	}
+	result.WriteString(referencePattern.ReplaceAllStringFunc(text[position:], func(ref string) string {
+		return fmt.Sprintf("{{ keeper %s }}", strconv.Quote(ref))
+	}))
+	return result.String()
+}
+
+// item returns the attributes of the referenced item with the custom fields and the values of secrets among them.
+func (r *Resolver) item(ctx context.Context, ref Reference) (map[string]any, error) {
+	key := ref.Type + "/" + ref.item()
+	if attributes, ok := r.items[key]; ok {
+		return attributes, nil
+	}
+	found, err := r.find(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+	if len(found) == 0 {
+		return nil, fmt.Errorf("%w: there is no %s item %s", ErrInvalidReference, ref.Type, ref.item())
+	}
+	if len(found) > 1 {
+		return nil, fmt.Errorf("%w: %d %s items match %q, refer to one of them by id as %s%s/#<id>/%s",
+			ErrAmbiguousReference, len(found), ref.Type, ref.Name, ReferenceScheme, ref.Type, url.PathEscape(ref.Field))
+	}
+
+	data, err := json.Marshal(found[0])
+	if err != nil {
+		return nil, err
+	}
+	var attributes map[string]any
+	var nested struct {
+		Fields []Field           `json:"fields"`
+		Values map[string]string `json:"values"`
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err = decoder.Decode(&attributes); err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &nested); err != nil {
+		return nil, err
+	}
+	delete(attributes, "fields")
+	delete(attributes, "values")
+	builtin := attributeNames(reflect.TypeOf(found[0]), make(map[string]bool))
+	for _, field := range nested.Fields {
+		attributes[fieldsPrefix+field.Name] = field.Value
+		if !builtin[field.Name] {
+			attributes[field.Name] = field.Value
+		}
+	}
+	for k, v := range nested.Values {
+		attributes[valuesPrefix+k] = v
+		if !builtin[k] {
+			attributes[k] = v
+		}
+	}
+	r.items[key] = attributes
+	return attributes, nil
+}
+
+// find returns the items of the reference: the items with the name or the item with the id.
+func (r *Resolver) find(ctx context.Context, ref Reference) ([]any, error) {
+	if ref.ID != 0 {
+		switch ref.Type {
+		case ItemTypeCredentials:
+			return itemByID(r.client.GetCredentialsByID(ctx, ref.ID))
+		case ItemTypeNote:
+			return itemByID(r.client.GetNoteByID(ctx, ref.ID))
+		case ItemTypeCard:
+			return itemByID(r.client.GetCardByID(ctx, ref.ID))
+		}
+	}
+	var found []any
+	var err error
+	switch ref.Type {
+	case ItemTypeCredentials:
+		found, err = items(r.client.GetCredentials(ctx, CredentialsFilter{Login: ref.Name}))
+	case ItemTypeNote:
+		found, err = items(r.client.GetNotes(ctx, NotesFilter{Title: ref.Name}))
+	case ItemTypeCard:
+		found, err = items(r.client.GetCards(ctx, CardsFilter{BankName: ref.Name}))
+	case ItemTypeTOTP:
+		found, err = items(r.client.GetTOTP(ctx, ref.Name))
+	case ItemTypeSSHKey:
+		found, err = items(r.client.GetSSHKeys(ctx, ref.Name))
+	case ItemTypeIdentity:
+		found, err = items(r.client.GetIdentities(ctx, IdentitiesFilter{Name: ref.Name}))
+	case ItemTypeSecret:
+		found, err = items(r.client.GetSecrets(ctx, ref.Name))
+	default:
+		return nil, fmt.Errorf("%w: unknown item type %q", ErrInvalidReference, ref.Type)
+	}
+	if err != nil || ref.ID == 0 {
+		return found, err
+	}
+	// the other items have no methods to get them by id, so all of them are listed
+	for _, f := range found {
+		if reflect.ValueOf(f).FieldByName("ID").Int() == ref.ID {
+			return []any{f}, nil
+		}
+	}
+	return nil, nil
+}
+
+// attributeNames adds the JSON names of the struct fields to the names, the embedded structs are flattened.
+func attributeNames(t reflect.Type, names map[string]bool) map[string]bool {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			attributeNames(field.Type, names)
+			continue
+		}
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name != "" && name != "-" {
+			names[name] = true
+		}
+	}
+	return names
+}
+
+// itemByID converts the item returned by the client by id for the resolver, missing item is not an error.
+func itemByID[T any](typed T, err error) ([]any, error) {
+	if errors.Is(err, ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return []any{typed}, nil
+}
+
+// items converts the typed items returned by the client for the resolver.
+func items[T any](typed []T, err error) ([]any, error) {
+	if err != nil {
+		return nil, err
+	}
+	found := make([]any, 0, len(typed))
+	for _, item := range typed {
+		found = append(found, item)
+	}
+	return found, nil
+}
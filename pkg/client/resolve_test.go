@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestParseReference(t *testing.T) {
+	testCases := []struct {
+		ref      string
+		expected Reference
+	}{
+		{ref: "keeper://notes/vpn/content", expected: Reference{Type: ItemTypeNote, Name: "vpn", Field: "content"}},
+		{ref: "credentials/db-prod/password", expected: Reference{Type: ItemTypeCredentials, Name: "db-prod", Field: "password"}},
+		{ref: "keeper://notes/team/vpn%20config/content", expected: Reference{Type: ItemTypeNote, Name: "team/vpn config", Field: "content"}},
+		{ref: "keeper://cards/#5/cv", expected: Reference{Type: ItemTypeCard, ID: 5, Field: "cv"}},
+		{ref: "keeper://notes/%23todo/content", expected: Reference{Type: ItemTypeNote, Name: "#todo", Field: "content"}},
+		{ref: "keeper://credentials/db-prod/fields.password", expected: Reference{Type: ItemTypeCredentials, Name: "db-prod", Field: "fields.password"}},
+	}
+	for _, tt := range testCases {
+		ref, err := ParseReference(tt.ref)
+		assert.NoError(t, err)
+		assert.Equal(t, tt.expected, ref)
+	}
+	for _, ref := range []string{"keeper://notes/vpn", "keeper://notes//", "keeper:///vpn/content", "keeper://notes/vpn/",
+		"keeper://cards/#iron/cv", "keeper://cards/#0/cv"} {
+		_, err := ParseReference(ref)
+		assert.ErrorIs(t, err, ErrInvalidReference, ref)
+	}
+}
+
+func TestResolver_Render(t *testing.T) {
+	userName := "arya"
+	password := "needle and valar morghulis"
+	login := "db-prod"
+	secret := "no one"
+	title := "vpn"
+	content := "remote vpn.winterfell.north 1194"
+	name := "deploy"
+	bankName := "iron bank"
+	cv := "123"
+	otherCV := "321"
+	creds := internal.Credentials{ID: 7, UserName: userName, Login: &login, Password: &secret}
+	note := internal.Note{ID: 3, UserName: userName, Title: &title, Content: &content}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("ListCredentials", mock.Anything, internal.Credentials{UserName: userName, Login: &login}, internal.ListOptions{Limit: 50}).
+		Return([]internal.Credentials{creds}, "", nil).Once()
+	mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeCredentials, []int64{7}).
+		Return(map[int64][]internal.Field{7: {
+			{Name: "host", Type: FieldTypeText, Value: "db.winterfell.north"},
+			{Name: "password", Type: FieldTypeHidden, Value: "valar dohaeris"},
+		}}, nil).Once()
+	mockedStorage.On("ListNotes", mock.Anything, internal.Note{UserName: userName, Title: &title}, internal.ListOptions{Limit: 50}).
+		Return([]internal.Note{note}, "", nil)
+	mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeNote, []int64{3}).Return(map[int64][]internal.Field{}, nil)
+	mockedStorage.On("GetSecrets", mock.Anything, internal.Secret{UserName: userName, Name: &name}).
+		Return([]internal.Secret{{ID: 2, UserName: userName, Name: &name, Values: map[string]string{"API_TOKEN": "keeper://notes/vpn/content"}}}, nil)
+	mockedStorage.On("ListCards", mock.Anything, internal.Card{UserName: userName, BankName: &bankName}, internal.ListOptions{Limit: 50}).
+		Return([]internal.Card{{ID: 5, UserName: userName, BankName: &bankName, CV: &cv}, {ID: 6, UserName: userName, BankName: &bankName, CV: &otherCV}}, "", nil)
+	mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeCard, []int64{5, 6}).Return(map[int64][]internal.Field{}, nil)
+	mockedStorage.On("GetCard", mock.Anything, internal.Card{UserName: userName, ID: 6}).
+		Return([]internal.Card{{ID: 6, UserName: userName, BankName: &bankName, CV: &otherCV}}, nil)
+	mockedStorage.On("GetFields", mock.Anything, userName, internal.ItemTypeCard, []int64{6}).Return(map[int64][]internal.Field{}, nil)
+	mockedStorage.On("GetCredentials", mock.Anything, internal.Credentials{UserName: userName, ID: 9}).Return(nil, database.ErrNoData)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+
+	t.Run("positive: template functions and references", func(t *testing.T) {
+		rendered, err := NewResolver(c).Render(ctx, "config.tpl", `db:
+  host: {{ keeper "credentials/db-prod" "host" }}
+  password: "{{ keeper "credentials/db-prod" "password" }}"
+  user: keeper://credentials/db-prod/login
+  field: keeper://credentials/db-prod/fields.password
+vpn: {{ keeper "keeper://notes/vpn/content" }}
+token: keeper://secrets/deploy/API_TOKEN
+`)
+		assert.NoError(t, err)
+		assert.Equal(t, `db:
+  host: db.winterfell.north
+  password: "no one"
+  user: db-prod
+  field: valar dohaeris
+vpn: remote vpn.winterfell.north 1194
+token: keeper://notes/vpn/content
+`, rendered)
+	})
+	t.Run("positive: card by id", func(t *testing.T) {
+		value, err := NewResolver(c).Resolve(ctx, "keeper://cards/#6/cv")
+		assert.NoError(t, err)
+		assert.Equal(t, otherCV, value)
+	})
+	t.Run("negative: cards of the same bank", func(t *testing.T) {
+		_, err := NewResolver(c).Resolve(ctx, "keeper://cards/iron%20bank/cv")
+		assert.ErrorIs(t, err, ErrAmbiguousReference)
+		assert.ErrorIs(t, err, ErrInvalidReference)
+	})
+	t.Run("negative: no item with the id", func(t *testing.T) {
+		_, err := NewResolver(c).Resolve(ctx, "keeper://credentials/#9/password")
+		assert.ErrorIs(t, err, ErrInvalidReference)
+	})
+	t.Run("negative: missing field", func(t *testing.T) {
+		_, err := NewResolver(c).Render(ctx, "config.tpl", `vpn: keeper://notes/vpn/password`)
+		assert.ErrorIs(t, err, ErrInvalidReference)
+	})
+	t.Run("negative: unknown item type", func(t *testing.T) {
+		_, err := NewResolver(c).Resolve(ctx, "keeper://wallets/vpn/content")
+		assert.ErrorIs(t, err, ErrInvalidReference)
+	})
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SaveSecret saves the secret and returns it with the id assigned by the server.
+func (c *Client) SaveSecret(ctx context.Context, secret Secret) (Secret, error) {
+	var saved Secret
+	if err := c.call(ctx, http.MethodPost, "/api/v2/secrets", secret, &saved); err != nil {
+		return Secret{}, err
+	}
+	return saved, nil
+}
+
+// GetSecrets returns the secrets ordered by name, empty name matches all of them.
+func (c *Client) GetSecrets(ctx context.Context, name string) ([]Secret, error) {
+	query := url.Values{}
+	setQuery(query, "name", name)
+
+	var secrets []Secret
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/secrets", query), nil, &secrets); err != nil {
+		return nil, err
+	}
+	return secrets, nil
+}
+
+// UpdateSecret replaces the values and the metadata of the secret by id and returns the updated secret.
+func (c *Client) UpdateSecret(ctx context.Context, secret Secret) (Secret, error) {
+	var updated Secret
+	if err := c.call(ctx, http.MethodPut, fmt.Sprintf("/api/v2/secrets/%d", secret.ID), secret, &updated); err != nil {
+		return Secret{}, err
+	}
+	return updated, nil
+}
+
+// DeleteSecret deletes the secret by id.
+func (c *Client) DeleteSecret(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/secrets/%d", id), nil, nil)
+}
@@ -0,0 +1,43 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestClient_Secrets(t *testing.T) {
+	userName := "samwell"
+	password := "the citadel"
+	name := "deploy"
+	stored := internal.Secret{ID: 2, UserName: userName, Name: &name, Values: map[string]string{"API_TOKEN": "old"}}
+	updated := internal.Secret{ID: 2, UserName: userName, Name: &name, Values: map[string]string{"API_TOKEN": "new"}}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("SaveSecret", mock.Anything, internal.Secret{UserName: userName, Name: &name, Values: stored.Values}).Return(int64(2), nil)
+	mockedStorage.On("GetSecrets", mock.Anything, internal.Secret{UserName: userName, ID: 2}).Return([]internal.Secret{stored}, nil).Once()
+	mockedStorage.On("UpdateSecret", mock.Anything, updated).Return(nil)
+	mockedStorage.On("GetSecrets", mock.Anything, internal.Secret{UserName: userName, ID: 2}).Return([]internal.Secret{updated}, nil).Once()
+	mockedStorage.On("DeleteSecret", mock.Anything, internal.Secret{UserName: userName, ID: 2}).Return(nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	saved, err := c.SaveSecret(ctx, Secret{Name: &name, Values: map[string]string{"API_TOKEN": "old"}})
+	assert.NoError(t, err)
+	assert.Equal(t, stored, saved)
+
+	saved.Values = map[string]string{"API_TOKEN": "new"}
+	replaced, err := c.UpdateSecret(ctx, saved)
+	assert.NoError(t, err)
+	assert.Equal(t, updated, replaced)
+
+	assert.NoError(t, c.DeleteSecret(ctx, saved.ID))
+}
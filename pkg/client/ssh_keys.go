@@ -0,0 +1,35 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SaveSSHKey saves the SSH key and returns it with the id, the public key and the fingerprint assigned by the server.
+// The private key must be unencrypted, in PEM format.
+func (c *Client) SaveSSHKey(ctx context.Context, key SSHKey) (SSHKey, error) {
+	var saved SSHKey
+	if err := c.call(ctx, http.MethodPost, "/api/v2/ssh-keys", key, &saved); err != nil {
+		return SSHKey{}, err
+	}
+	return saved, nil
+}
+
+// GetSSHKeys returns the SSH keys ordered by name, empty name matches all of them.
+func (c *Client) GetSSHKeys(ctx context.Context, name string) ([]SSHKey, error) {
+	query := url.Values{}
+	setQuery(query, "name", name)
+
+	var keys []SSHKey
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/ssh-keys", query), nil, &keys); err != nil {
+		return nil, err
+	}
+	return keys, nil
+}
+
+// DeleteSSHKey deletes the SSH key by id.
+func (c *Client) DeleteSSHKey(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/ssh-keys/%d", id), nil, nil)
+}
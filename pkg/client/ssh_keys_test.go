@@ -0,0 +1,46 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/kontik-pk/goph-keeper/internal/sshkey"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestClient_SSHKeys(t *testing.T) {
+	userName := "samwell"
+	password := "the citadel"
+	name := "deploy"
+	privateKey, err := sshkey.Generate(sshkey.TypeEd25519, 0, "")
+	assert.NoError(t, err)
+	info, err := sshkey.Parse(privateKey, "")
+	assert.NoError(t, err)
+	stored := internal.SSHKey{ID: 2, UserName: userName, Name: &name, Type: info.Type, PublicKey: info.PublicKey,
+		Fingerprint: info.Fingerprint, PrivateKey: &privateKey}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("SaveSSHKey", mock.Anything, internal.SSHKey{UserName: userName, Name: &name, Type: info.Type,
+		PublicKey: info.PublicKey, Fingerprint: info.Fingerprint, PrivateKey: &privateKey}).Return(int64(2), nil)
+	mockedStorage.On("GetSSHKeys", mock.Anything, internal.SSHKey{UserName: userName, Name: &name}).Return([]internal.SSHKey{stored}, nil)
+	mockedStorage.On("DeleteSSHKey", mock.Anything, internal.SSHKey{UserName: userName, ID: 2}).Return(nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err = c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	saved, err := c.SaveSSHKey(ctx, SSHKey{Name: &name, PrivateKey: &privateKey})
+	assert.NoError(t, err)
+	assert.Equal(t, stored, saved)
+
+	keys, err := c.GetSSHKeys(ctx, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []SSHKey{stored}, keys)
+
+	assert.NoError(t, c.DeleteSSHKey(ctx, saved.ID))
+}
@@ -0,0 +1,44 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+)
+
+// SaveTOTP saves the authenticator and returns it with the id assigned by the server.
+// Either the otpauth:// URI or the secret must be set.
+func (c *Client) SaveTOTP(ctx context.Context, totp TOTP) (TOTP, error) {
+	var saved TOTP
+	if err := c.call(ctx, http.MethodPost, "/api/v2/totp", totp, &saved); err != nil {
+		return TOTP{}, err
+	}
+	return saved, nil
+}
+
+// GetTOTP returns the authenticators ordered by name, empty name matches all of them.
+func (c *Client) GetTOTP(ctx context.Context, name string) ([]TOTP, error) {
+	query := url.Values{}
+	setQuery(query, "name", name)
+
+	var authenticators []TOTP
+	if err := c.call(ctx, http.MethodGet, withQuery("/api/v2/totp", query), nil, &authenticators); err != nil {
+		return nil, err
+	}
+	return authenticators, nil
+}
+
+// GetTOTPCode returns the current one-time password of the authenticator by id and the seconds it remains valid.
+func (c *Client) GetTOTPCode(ctx context.Context, id int64) (TOTPCode, error) {
+	var code TOTPCode
+	if err := c.call(ctx, http.MethodGet, fmt.Sprintf("/api/v2/totp/%d/code", id), nil, &code); err != nil {
+		return TOTPCode{}, err
+	}
+	return code, nil
+}
+
+// DeleteTOTP deletes the authenticator by id.
+func (c *Client) DeleteTOTP(ctx context.Context, id int64) error {
+	return c.call(ctx, http.MethodDelete, fmt.Sprintf("/api/v2/totp/%d", id), nil, nil)
+}
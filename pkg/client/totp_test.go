@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"github.com/kontik-pk/goph-keeper/internal"
+	"github.com/kontik-pk/goph-keeper/internal/database"
+	"github.com/kontik-pk/goph-keeper/internal/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+)
+
+func TestClient_TOTP(t *testing.T) {
+	userName := "samwell"
+	password := "the citadel"
+	name := "GitHub"
+	secret := "JBSWY3DPEHPK3PXP"
+	stored := internal.TOTP{ID: 3, UserName: userName, Name: &name, Issuer: "GitHub", Account: "sam", Secret: &secret,
+		Digits: 6, Period: 30, Algorithm: "SHA1"}
+
+	mockedStorage := mocks.NewStorage(t)
+	mockedStorage.On("Login", mock.Anything, userName, password).Return(nil)
+	mockedStorage.On("GetTwoFactor", mock.Anything, userName).Return(internal.TwoFactor{}, database.ErrNoData)
+	mockedStorage.On("SaveTOTP", mock.Anything, internal.TOTP{UserName: userName, Name: &name, Issuer: "GitHub", Account: "sam",
+		Secret: &secret, Digits: 6, Period: 30, Algorithm: "SHA1"}).Return(int64(3), nil)
+	mockedStorage.On("GetTOTP", mock.Anything, internal.TOTP{UserName: userName, Name: &name}).Return([]internal.TOTP{stored}, nil)
+	mockedStorage.On("GetTOTP", mock.Anything, internal.TOTP{UserName: userName, ID: 3}).Return([]internal.TOTP{stored}, nil)
+	mockedStorage.On("DeleteTOTP", mock.Anything, internal.TOTP{UserName: userName, ID: 3}).Return(nil)
+	c := newTestServer(t, mockedStorage)
+	ctx := context.Background()
+
+	_, err := c.Login(ctx, userName, password)
+	assert.NoError(t, err)
+	saved, err := c.SaveTOTP(ctx, TOTP{URI: "otpauth://totp/GitHub:sam?secret=JBSWY3DPEHPK3PXP"})
+	assert.NoError(t, err)
+	assert.Equal(t, stored, saved)
+
+	authenticators, err := c.GetTOTP(ctx, name)
+	assert.NoError(t, err)
+	assert.Equal(t, []TOTP{stored}, authenticators)
+
+	code, err := c.GetTOTPCode(ctx, saved.ID)
+	assert.NoError(t, err)
+	assert.Len(t, code.Code, 6)
+
+	assert.NoError(t, c.DeleteTOTP(ctx, saved.ID))
+}
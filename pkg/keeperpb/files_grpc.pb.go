@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.4.0
+// - protoc             (unknown)
+// source: files.proto
+
+package keeperpb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+	emptypb "google.golang.org/protobuf/types/known/emptypb"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.62.0 or later.
+const _ = grpc.SupportPackageIsVersion8
+
+const (
+	FilesService_Upload_FullMethodName   = "/keeper.v1.FilesService/Upload"
+	FilesService_Download_FullMethodName = "/keeper.v1.FilesService/Download"
+	FilesService_List_FullMethodName     = "/keeper.v1.FilesService/List"
+	FilesService_Delete_FullMethodName   = "/keeper.v1.FilesService/Delete"
+)
+
+// FilesServiceClient is the client API for FilesService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// FilesService manages binary files of the authorized user, the content is streamed in chunks.
+type FilesServiceClient interface {
+	// Upload saves the file, the first message must contain the file info and the following ones its content.
+	Upload(ctx context.Context, opts ...grpc.CallOption) (FilesService_UploadClient, error)
+	// Download streams the content of the file.
+	Download(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (FilesService_DownloadClient, error)
+	// List returns files without their content.
+	List(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error)
+	Delete(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error)
+}
+
+type filesServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewFilesServiceClient(cc grpc.ClientConnInterface) FilesServiceClient {
+	return &filesServiceClient{cc}
+}
+
+func (c *filesServiceClient) Upload(ctx context.Context, opts ...grpc.CallOption) (FilesService_UploadClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FilesService_ServiceDesc.Streams[0], FilesService_Upload_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &filesServiceUploadClient{ClientStream: stream}
+	return x, nil
+}
+
+type FilesService_UploadClient interface {
+	Send(*UploadRequest) error
+	CloseAndRecv() (*File, error)
+	grpc.ClientStream
+}
+
+type filesServiceUploadClient struct {
+	grpc.ClientStream
+}
+
+func (x *filesServiceUploadClient) Send(m *UploadRequest) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *filesServiceUploadClient) CloseAndRecv() (*File, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(File)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *filesServiceClient) Download(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (FilesService_DownloadClient, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &FilesService_ServiceDesc.Streams[1], FilesService_Download_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &filesServiceDownloadClient{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type FilesService_DownloadClient interface {
+	Recv() (*FileChunk, error)
+	grpc.ClientStream
+}
+
+type filesServiceDownloadClient struct {
+	grpc.ClientStream
+}
+
+func (x *filesServiceDownloadClient) Recv() (*FileChunk, error) {
+	m := new(FileChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *filesServiceClient) List(ctx context.Context, in *ListFilesRequest, opts ...grpc.CallOption) (*ListFilesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListFilesResponse)
+	err := c.cc.Invoke(ctx, FilesService_List_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *filesServiceClient) Delete(ctx context.Context, in *ItemRequest, opts ...grpc.CallOption) (*emptypb.Empty, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(emptypb.Empty)
+	err := c.cc.Invoke(ctx, FilesService_Delete_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// FilesServiceServer is the server API for FilesService service.
+// All implementations must embed UnimplementedFilesServiceServer
+// for forward compatibility
+//
+// FilesService manages binary files of the authorized user, the content is streamed in chunks.
+type FilesServiceServer interface {
+	// Upload saves the file, the first message must contain the file info and the following ones its content.
+	Upload(FilesService_UploadServer) error
+	// Download streams the content of the file.
+	Download(*ItemRequest, FilesService_DownloadServer) error
+	// List returns files without their content.
+	List(context.Context, *ListFilesRequest) (*ListFilesResponse, error)
+	Delete(context.Context, *ItemRequest) (*emptypb.Empty, error)
+	mustEmbedUnimplementedFilesServiceServer()
+}
+
+// UnimplementedFilesServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedFilesServiceServer struct {
+}
+
+func (UnimplementedFilesServiceServer) Upload(FilesService_UploadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Upload not implemented")
+}
+func (UnimplementedFilesServiceServer) Download(*ItemRequest, FilesService_DownloadServer) error {
+	return status.Errorf(codes.Unimplemented, "method Download not implemented")
+}
+func (UnimplementedFilesServiceServer) List(context.Context, *ListFilesRequest) (*ListFilesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method List not implemented")
+}
+func (UnimplementedFilesServiceServer) Delete(context.Context, *ItemRequest) (*emptypb.Empty, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Delete not implemented")
+}
+func (UnimplementedFilesServiceServer) mustEmbedUnimplementedFilesServiceServer() {}
+
+// UnsafeFilesServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to FilesServiceServer will
+// result in compilation errors.
+type UnsafeFilesServiceServer interface {
+	mustEmbedUnimplementedFilesServiceServer()
+}
+
+func RegisterFilesServiceServer(s grpc.ServiceRegistrar, srv FilesServiceServer) {
+	s.RegisterService(&FilesService_ServiceDesc, srv)
+}
+
+func _FilesService_Upload_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(FilesServiceServer).Upload(&filesServiceUploadServer{ServerStream: stream})
+}
+
+type FilesService_UploadServer interface {
+	SendAndClose(*File) error
+	Recv() (*UploadRequest, error)
+	grpc.ServerStream
+}
+
+type filesServiceUploadServer struct {
+	grpc.ServerStream
+}
+
+func (x *filesServiceUploadServer) SendAndClose(m *File) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *filesServiceUploadServer) Recv() (*UploadRequest, error) {
+	m := new(UploadRequest)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _FilesService_Download_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(ItemRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(FilesServiceServer).Download(m, &filesServiceDownloadServer{ServerStream: stream})
+}
+
+type FilesService_DownloadServer interface {
+	Send(*FileChunk) error
+	grpc.ServerStream
+}
+
+type filesServiceDownloadServer struct {
+	grpc.ServerStream
+}
+
+func (x *filesServiceDownloadServer) Send(m *FileChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _FilesService_List_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListFilesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilesServiceServer).List(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilesService_List_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilesServiceServer).List(ctx, req.(*ListFilesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _FilesService_Delete_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ItemRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(FilesServiceServer).Delete(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: FilesService_Delete_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(FilesServiceServer).Delete(ctx, req.(*ItemRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// FilesService_ServiceDesc is the grpc.ServiceDesc for FilesService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var FilesService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "keeper.v1.FilesService",
+	HandlerType: (*FilesServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "List",
+			Handler:    _FilesService_List_Handler,
+		},
+		{
+			MethodName: "Delete",
+			Handler:    _FilesService_Delete_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "Upload",
+			Handler:       _FilesService_Upload_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "Download",
+			Handler:       _FilesService_Download_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "files.proto",
+}